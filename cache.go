@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Bounds on the cache: results in memory, and the age and total size of results saved to disk
+const (
+	defaultCacheEntries  = 2000
+	defaultCacheMaxAge   = 30 * 24 * time.Hour
+	defaultCacheMaxBytes = 512 << 20
+)
+
+// Kinds of cached result, so different calculations for the same strategy never share a key
+const (
+	cacheKindSimulation   = "simulation"    // RunSimulation
+	cacheKindSimulationV2 = "simulation-v2" // RunSimulationV2
+	cacheKindDepletion    = "depletion"     // CalculateDepletionIncome
+	cacheKindPensionOnly  = "pension-only"  // CalculatePensionOnlyDepletionIncome
+)
+
+// ResultCache keeps strategy results keyed by a hash of everything that determines them
+// Results are held in memory and, if a directory is set, saved to disk for later runs
+// Results are held encoded, so every lookup returns a copy the caller may modify
+type ResultCache struct {
+	dir        string // Directory results are saved to ("" = memory only)
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string][]byte // Encoded cacheEntry by key
+	order   []string          // Keys oldest first, for eviction
+}
+
+// cacheEntry is one cached result, a simulation or a depletion search, with the config it was calculated for
+type cacheEntry struct {
+	Simulation *SimulationResult
+	Depletion  *DepletionResult
+	ConfigJSON []byte // Config the result was calculated for, as saved by ResultKey
+}
+
+// Config returns the config the result was calculated for
+func (e cacheEntry) Config() (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(e.ConfigJSON, &config); err != nil {
+		return nil, fmt.Errorf("cached result has no usable config: %w", err)
+	}
+	return &config, nil
+}
+
+// NewResultCache creates a cache, saving results under dir if it is not empty
+// Results saved by a different build of the program are ignored, and old results are pruned
+func NewResultCache(dir string) *ResultCache {
+	c := &ResultCache{
+		maxEntries: defaultCacheEntries,
+		entries:    make(map[string][]byte),
+	}
+	if dir != "" {
+		pruneCacheDir(dir, defaultCacheMaxAge, defaultCacheMaxBytes)
+		fingerprint := buildFingerprint()
+		if fingerprint == "" {
+			log.Printf("Warning: result cache kept in memory only: cannot identify this build")
+			return c
+		}
+		c.dir = filepath.Join(dir, fingerprint)
+		if err := os.MkdirAll(c.dir, 0755); err != nil {
+			log.Printf("Warning: result cache kept in memory only: %v", err)
+			c.dir = ""
+		}
+	}
+	return c
+}
+
+// ResultKey returns the canonical hash of a strategy calculation of kind against config
+// Settings that cannot change a result (concurrency, run control, strategy metadata) are left out
+func ResultKey(kind string, config *Config, params SimulationParams) string {
+	params.SourceCombo = nil
+
+	h := sha256.New()
+	io.WriteString(h, kind)
+	h.Write(canonicalConfigJSON(config))
+	json.NewEncoder(h).Encode(params)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalConfigJSON encodes config without the settings that cannot change a result
+func canonicalConfigJSON(config *Config) []byte {
+	canonical := *config
+	canonical.Simulation.Concurrency = 0
+	canonical.Simulation.CacheDir = ""
+	data, _ := json.Marshal(&canonical)
+	return append(data, '\n')
+}
+
+// validResultKey reports whether key is a hash from ResultKey, so it is safe to use as a file name
+func validResultKey(key string) bool {
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	for _, r := range key {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Simulation returns the cached simulation for key, running and caching it for config on a miss
+func (c *ResultCache) Simulation(key string, config *Config, run func() SimulationResult) SimulationResult {
+	if entry, ok := c.get(key); ok && entry.Simulation != nil {
+		return *entry.Simulation
+	}
+	result := run()
+	result.ResultKey = key
+	c.put(key, cacheEntry{Simulation: &result, ConfigJSON: canonicalConfigJSON(config)})
+	return result
+}
+
+// Depletion returns the cached depletion search for key, running and caching it for config on a miss
+func (c *ResultCache) Depletion(key string, config *Config, run func() DepletionResult) DepletionResult {
+	if entry, ok := c.get(key); ok && entry.Depletion != nil {
+		return *entry.Depletion
+	}
+	result := run()
+	result.SimulationResult.ResultKey = key
+	c.put(key, cacheEntry{Depletion: &result, ConfigJSON: canonicalConfigJSON(config)})
+	return result
+}
+
+// Lookup returns the cached result for key, from memory or disk
+func (c *ResultCache) Lookup(key string) (cacheEntry, bool) {
+	if c == nil || key == "" {
+		return cacheEntry{}, false
+	}
+	return c.get(key)
+}
+
+// get decodes a fresh copy of the result for key, from memory or disk
+// Keys that are not hashes from ResultKey are never looked up
+func (c *ResultCache) get(key string) (cacheEntry, bool) {
+	if !validResultKey(key) {
+		return cacheEntry{}, false
+	}
+	c.mu.Lock()
+	data, ok := c.entries[key]
+	c.mu.Unlock()
+	if !ok {
+		if c.dir == "" {
+			return cacheEntry{}, false
+		}
+		var err error
+		if data, err = os.ReadFile(c.path(key)); err != nil {
+			return cacheEntry{}, false
+		}
+		now := time.Now()
+		os.Chtimes(c.path(key), now, now) // Recently used results are pruned last
+	}
+
+	var entry cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		log.Printf("Warning: ignoring unreadable cached result %s: %v", key, err)
+		return cacheEntry{}, false
+	}
+	if !ok {
+		c.remember(key, data)
+	}
+	return entry, true
+}
+
+func (c *ResultCache) put(key string, entry cacheEntry) {
+	if !validResultKey(key) {
+		return
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		log.Printf("Warning: result not cached: %v", err)
+		return
+	}
+	c.remember(key, buf.Bytes())
+	if c.dir == "" {
+		return
+	}
+
+	// Write then rename so a reader never sees a partial file
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		log.Printf("Warning: result not saved to cache: %v", err)
+		return
+	}
+	_, err = tmp.Write(buf.Bytes())
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		log.Printf("Warning: result not saved to cache: %v", err)
+	}
+}
+
+// remember holds an encoded entry in memory, evicting the oldest entries beyond maxEntries
+func (c *ResultCache) remember(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = data
+	for len(c.order) > c.maxEntries {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *ResultCache) path(key string) string {
+	return filepath.Join(c.dir, key+".gob")
+}
+
+// pruneCacheDir deletes results saved under dir, by any build, that are older than maxAge,
+// then the least recently used until the rest fit in maxBytes
+func pruneCacheDir(dir string, maxAge time.Duration, maxBytes int64) {
+	type savedResult struct {
+		path string
+		size int64
+		used time.Time
+	}
+	var saved []savedResult
+	var builds []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir {
+				builds = append(builds, path)
+			}
+			return nil
+		}
+		if info, err := d.Info(); err == nil && filepath.Ext(path) == ".gob" {
+			saved = append(saved, savedResult{path, info.Size(), info.ModTime()})
+		}
+		return nil
+	})
+
+	sort.Slice(saved, func(i, j int) bool { return saved[i].used.After(saved[j].used) })
+	var total int64
+	for _, r := range saved {
+		total += r.size
+		if time.Since(r.used) > maxAge || total > maxBytes {
+			os.Remove(r.path)
+		}
+	}
+	for _, build := range builds {
+		os.Remove(build) // Only succeeds once a build's results are all gone
+	}
+}
+
+var (
+	fingerprintOnce sync.Once
+	fingerprint     string
+)
+
+// buildFingerprint identifies the running build, so results saved by other builds are not reused
+// Returns "" if the executable cannot be read
+func buildFingerprint() string {
+	fingerprintOnce.Do(func() {
+		exe, err := os.Executable()
+		if err != nil {
+			return
+		}
+		f, err := os.Open(exe)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err == nil {
+			fingerprint = hex.EncodeToString(h.Sum(nil))[:16]
+		}
+	})
+	return fingerprint
+}
+
+func init() {
+	// Strategy factor values are held as interfaces in a result's params
+	gob.Register(Strategy(0))
+	gob.Register(DrawdownOrder(0))
+	gob.Register(MortgageOption(0))
+	gob.Register(WithdrawalRule(0))
+	gob.Register(TrancheTiming(0))
+	gob.Register(ConfigOverride{})
+	gob.Register(PersonCrystallisation{})
+	gob.Register(PersonDrawdown{})
+}
+
+// runCachedSimulation runs RunSimulation through config.Cache
+func runCachedSimulation(params SimulationParams, config *Config) SimulationResult {
+	if config.Cache == nil {
+		return RunSimulation(params, config)
+	}
+	return config.Cache.Simulation(ResultKey(cacheKindSimulation, config, params), config, func() SimulationResult {
+		return RunSimulation(params, config)
+	})
+}
+
+// runCachedSimulationV2 runs RunSimulationV2 through config.Cache
+func runCachedSimulationV2(params SimulationParams, config *Config) SimulationResult {
+	if config.Cache == nil {
+		return RunSimulationV2(params, config)
+	}
+	return config.Cache.Simulation(ResultKey(cacheKindSimulationV2, config, params), config, func() SimulationResult {
+		return RunSimulationV2(params, config)
+	})
+}
+
+// runCachedDepletion runs a depletion search of kind through config.Cache
+func runCachedDepletion(kind string, params SimulationParams, config *Config, search func(SimulationParams, *Config) DepletionResult) DepletionResult {
+	if config.Cache == nil {
+		return search(params, config)
+	}
+	return config.Cache.Depletion(ResultKey(kind, config, params), config, func() DepletionResult {
+		return search(params, config)
+	})
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestResultKey verifies keys change with anything that affects a result and nothing else
+func TestResultKey(t *testing.T) {
+	config := createTestConfig()
+	params := GetStrategiesForConfig(config)[0]
+	key := ResultKey(cacheKindSimulation, config, params)
+
+	if ResultKey(cacheKindSimulation, createTestConfig(), params) != key {
+		t.Error("Expected the same key for an identical config")
+	}
+	if ResultKey(cacheKindSimulationV2, config, params) == key {
+		t.Error("Expected a different key for a different calculation")
+	}
+
+	changed := createTestConfig()
+	changed.Financial.PensionGrowthRate += 0.01
+	if ResultKey(cacheKindSimulation, changed, params) == key {
+		t.Error("Expected a different key when the growth rate changes")
+	}
+	other := params
+	other.StatePensionDeferYears = 2
+	if ResultKey(cacheKindSimulation, config, other) == key {
+		t.Error("Expected a different key for different strategy parameters")
+	}
+
+	// Run settings and strategy metadata do not change results
+	same := createTestConfig()
+	same.Simulation.Concurrency = 3
+	same.Simulation.CacheDir = t.TempDir()
+	same.Control = NewRunControl(context.Background())
+	same.Cache = NewResultCache("")
+	meta := params
+	meta.SourceCombo = &StrategyCombo{}
+	if ResultKey(cacheKindSimulation, same, meta) != key {
+		t.Error("Expected run settings and strategy metadata to leave the key unchanged")
+	}
+}
+
+// TestResultCache_Simulation verifies a cached result is returned without running again
+func TestResultCache_Simulation(t *testing.T) {
+	config := createTestConfig()
+	config.Cache = NewResultCache("")
+	params := GetStrategiesForConfig(config)[0]
+
+	first := runCachedSimulation(params, config)
+	second := config.Cache.Simulation(first.ResultKey, config, func() SimulationResult {
+		t.Fatal("Expected the cached result to be used")
+		return SimulationResult{}
+	})
+	if first.ResultKey == "" || second.ResultKey != first.ResultKey || second.TotalTaxPaid != first.TotalTaxPaid {
+		t.Errorf("Expected the cached result, got key %q and £%.0f tax", second.ResultKey, second.TotalTaxPaid)
+	}
+
+	direct := RunSimulation(params, createTestConfig())
+	if direct.TotalTaxPaid != first.TotalTaxPaid || getTotalFinalBalance(direct) != getTotalFinalBalance(first) {
+		t.Error("Expected the cached result to match an uncached run")
+	}
+
+	// Each lookup is a copy, so changing one result leaves the cache alone
+	second.Years[0].TotalTaxPaid = -1
+	for name := range second.FinalBalances {
+		second.FinalBalances[name] = PersonBalances{}
+	}
+	third, _ := config.Cache.Lookup(first.ResultKey)
+	if third.Simulation.Years[0].TotalTaxPaid != first.Years[0].TotalTaxPaid || getTotalFinalBalance(*third.Simulation) != getTotalFinalBalance(first) {
+		t.Error("Expected a changed result not to change the cache")
+	}
+}
+
+// TestResultCache_Disk verifies results saved to disk are found by a new cache
+func TestResultCache_Disk(t *testing.T) {
+	dir := t.TempDir()
+	config := createDepletionTestConfig()
+	config.Cache = NewResultCache(dir)
+	params := GetDepletionStrategiesForConfig(config)[0]
+	saved := runCachedDepletion(cacheKindDepletion, params, config, CalculateDepletionIncome)
+
+	entry, ok := NewResultCache(dir).Lookup(saved.SimulationResult.ResultKey)
+	if !ok || entry.Depletion == nil {
+		t.Fatal("Expected the depletion result to be read back from disk")
+	}
+	loaded := entry.Depletion
+	if loaded.MonthlyBeforeAge != saved.MonthlyBeforeAge || loaded.SimulationResult.TotalTaxPaid != saved.SimulationResult.TotalTaxPaid ||
+		len(loaded.SimulationResult.Years) != len(saved.SimulationResult.Years) {
+		t.Errorf("Expected £%.2f/month and £%.0f tax, got £%.2f/month and £%.0f tax", saved.MonthlyBeforeAge,
+			saved.SimulationResult.TotalTaxPaid, loaded.MonthlyBeforeAge, loaded.SimulationResult.TotalTaxPaid)
+	}
+
+	if _, ok := NewResultCache("").Lookup(saved.SimulationResult.ResultKey); ok {
+		t.Error("Expected a memory-only cache not to read from disk")
+	}
+}
+
+// TestResultCache_DiskFactorCombo verifies results of factor combinations can be saved to disk
+func TestResultCache_DiskFactorCombo(t *testing.T) {
+	dir := t.TempDir()
+	config := createTestConfig()
+	config.Cache = NewResultCache(dir)
+	params := GetStrategiesForConfigV2(config, ModeComprehensive)[0]
+	saved := runCachedSimulationV2(params, config)
+
+	entry, ok := NewResultCache(dir).Lookup(saved.ResultKey)
+	if !ok || entry.Simulation == nil || entry.Simulation.Params.SourceCombo == nil {
+		t.Fatal("Expected the result and its factor combination to be read back from disk")
+	}
+	if entry.Simulation.Params.ShortName() != saved.Params.ShortName() {
+		t.Errorf("Expected strategy %s, got %s", saved.Params.ShortName(), entry.Simulation.Params.ShortName())
+	}
+}
+
+// TestResultCache_Eviction verifies the oldest results are dropped beyond the limit
+func TestResultCache_Eviction(t *testing.T) {
+	c := NewResultCache("")
+	c.maxEntries = 2
+	config := createTestConfig()
+	keys := []string{strings.Repeat("a", 64), strings.Repeat("b", 64), strings.Repeat("c", 64)}
+	for _, key := range keys {
+		c.Simulation(key, config, func() SimulationResult { return SimulationResult{} })
+	}
+	if _, ok := c.Lookup(keys[0]); ok {
+		t.Error("Expected the oldest result to be evicted")
+	}
+	if _, ok := c.Lookup(keys[2]); !ok {
+		t.Error("Expected the newest result to be kept")
+	}
+}
+
+// TestResultCache_InvalidKey verifies keys that are not result hashes never reach the disk
+func TestResultCache_InvalidKey(t *testing.T) {
+	dir := t.TempDir()
+	c := NewResultCache(dir)
+	if c.dir == "" {
+		t.Skip("Results cannot be saved to disk for this build")
+	}
+	outside := filepath.Join(dir, "outside.gob")
+	if err := os.WriteFile(outside, []byte("not a result"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", "../outside", "../../" + strings.Repeat("a", 58), strings.Repeat("A", 64), strings.Repeat("a", 63)} {
+		ran := false
+		c.Simulation(key, createTestConfig(), func() SimulationResult { ran = true; return SimulationResult{} })
+		if !ran {
+			t.Errorf("Expected %q to miss the cache", key)
+		}
+		if _, ok := c.Lookup(key); ok {
+			t.Errorf("Expected %q not to be cached", key)
+		}
+	}
+	if files, _ := filepath.Glob(filepath.Join(c.dir, "*")); len(files) > 0 {
+		t.Errorf("Expected nothing saved for invalid keys, got %v", files)
+	}
+}
+
+// TestPruneCacheDir verifies old results and those over the size limit are deleted, least recently used first
+func TestPruneCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	build := filepath.Join(dir, "oldbuild")
+	os.MkdirAll(build, 0755)
+	save := func(name string, age time.Duration) string {
+		path := filepath.Join(build, name+".gob")
+		os.WriteFile(path, make([]byte, 100), 0644)
+		used := time.Now().Add(-age)
+		os.Chtimes(path, used, used)
+		return path
+	}
+	stale := save("stale", 48*time.Hour)
+	older := save("older", 2*time.Hour)
+	newer := save("newer", time.Hour)
+
+	pruneCacheDir(dir, 24*time.Hour, 150)
+	for path, kept := range map[string]bool{stale: false, older: false, newer: true} {
+		if _, err := os.Stat(path); (err == nil) != kept {
+			t.Errorf("Expected %s kept=%v", filepath.Base(path), kept)
+		}
+	}
+
+	pruneCacheDir(dir, time.Minute, 150)
+	if _, err := os.Stat(build); err == nil {
+		t.Error("Expected an emptied build directory to be removed")
+	}
+}
+
+// TestExportResult_MatchesDisplayed verifies exports use exactly the result the simulation displayed
+func TestExportResult_MatchesDisplayed(t *testing.T) {
+	ws := NewWebServer(createDepletionTestConfig(), "")
+	req := APIPDFExportRequest{APISimulationRequest: APISimulationRequest{Mode: "depletion"}}
+	response := ws.runDepletionSimulation(ws.buildConfig(&req.APISimulationRequest), OptimizeIncome)
+	best := response.Best
+	if best == nil || best.ResultKey == "" {
+		t.Fatal("Expected the best result to carry its cache key")
+	}
+
+	// By key, and by strategy index as older clients send
+	req.ResultKey = best.ResultKey
+	byKey, keyResult, err := ws.exportResult(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.ResultKey = ""
+	req.StrategyIdx = best.StrategyIdx
+	byIdx, idxResult, err := ws.exportResult(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, got := range []struct {
+		config *Config
+		result SimulationResult
+	}{{byKey, keyResult}, {byIdx, idxResult}} {
+		if got.result.ResultKey != best.ResultKey || got.result.TotalTaxPaid != best.TotalTaxPaid {
+			t.Errorf("Expected result %s with £%.0f tax, got %s with £%.0f", best.ResultKey, best.TotalTaxPaid,
+				got.result.ResultKey, got.result.TotalTaxPaid)
+		}
+		if got.config.IncomeRequirements.MonthlyBeforeAge != best.MonthlyIncome {
+			t.Errorf("Expected the PDF to show £%.2f/month, got £%.2f", best.MonthlyIncome, got.config.IncomeRequirements.MonthlyBeforeAge)
+		}
+	}
+
+	req.StrategyIdx = 999
+	if _, _, err := ws.exportResult(&req); err == nil {
+		t.Error("Expected an error for an unknown strategy")
+	}
+
+	// The config the result was calculated for is exported, whatever the request says
+	req.ResultKey = best.ResultKey
+	req.Financial = createDepletionTestConfig().Financial
+	req.Financial.PensionGrowthRate = 0.5
+	config, _, err := ws.exportResult(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := createDepletionTestConfig().Financial.PensionGrowthRate; config.Financial.PensionGrowthRate != want {
+		t.Errorf("Expected the cached config's %.3f pension growth, got %.3f", want, config.Financial.PensionGrowthRate)
+	}
+}
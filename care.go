@@ -0,0 +1,159 @@
+package main
+
+import (
+	"math"
+)
+
+// DefaultCareStartAge is used by care scenarios when nobody has a care start age configured
+const DefaultCareStartAge = 85
+
+// HomeState tracks the family home so it can be sold to fund care
+type HomeState struct {
+	Sold     bool
+	SaleYear int
+}
+
+// ApplyCareCosts works out the household's care costs for a tax year
+// It sells the home if the funding rules require it (proceeds are split equally into ISAs)
+// and applies the local authority means test to each person in care.
+// Results are recorded on the YearState (CareCost is what the household must fund).
+func ApplyCareCosts(state *YearState, people []*Person, config *Config, home *HomeState, year int) {
+	care := config.Care
+	duration := care.GetDurationYears()
+
+	var inCare []*Person
+	nobodyAtHome := true
+	for _, p := range people {
+		if p.IsInCare(year, duration) {
+			inCare = append(inCare, p)
+		}
+		if !p.HasStartedCare(year) {
+			nobodyAtHome = false
+		}
+	}
+	if len(inCare) == 0 {
+		return
+	}
+
+	yearsFromStart := year - config.Simulation.StartYear
+	if yearsFromStart < 0 {
+		yearsFromStart = 0
+	}
+
+	// Sell the home once the funding rule is met
+	// The home is disregarded by the means test while a partner still lives in it,
+	// so "all_in_care" waits until nobody is left at home
+	if !home.Sold && care.HomeValue > 0 {
+		sell := false
+		switch care.GetHomeSale() {
+		case HomeSaleFirstInCare:
+			sell = true
+		case HomeSaleAllInCare:
+			sell = nobodyAtHome
+		}
+		if sell {
+			homeValue := care.HomeValue * math.Pow(1+care.HomeGrowthRate, float64(yearsFromStart))
+			proceeds := homeValue * (1 - care.HomeSaleCosts)
+			for _, p := range people {
+				p.SaveTaxFree(proceeds / float64(len(people)))
+			}
+			state.HomeSaleProceeds = proceeds
+			home.Sold = true
+			home.SaleYear = year
+		}
+	}
+
+	careInflation := math.Pow(1+care.GetInflationRate(config.Financial.IncomeInflationRate), float64(yearsFromStart))
+	for _, p := range inCare {
+		cost := p.CareAnnualCost * careInflation
+		personPays := cost
+
+		// Means test: below the self-funding threshold the council pays everything
+		// except tariff income of £1/week for every £250 of capital above the lower limit
+		// Income is not assessed here - state and DB pensions already reduce withdrawals
+		if care.SelfFundingThreshold > 0 && p.TotalWealth() <= care.SelfFundingThreshold {
+			tariff := math.Max(0, p.TotalWealth()-care.LowerCapitalLimit) / 250 * 52
+			personPays = math.Min(cost, tariff)
+		}
+
+		state.CareCostByPerson[p.Name] = personPays
+		state.CareCost += personPays
+		state.CareFundedByCouncil += cost - personPays
+	}
+}
+
+// ==========================================================================
+// Care Scenarios
+// ==========================================================================
+
+// CareScenarioResult compares a strategy with and without a care episode
+type CareScenarioResult struct {
+	Params           SimulationParams
+	Baseline         SimulationResult // Without care costs
+	WithCare         SimulationResult // With the care episode applied
+	SurvivesBaseline bool
+	SurvivesCare     bool
+	CareCost         float64 // Total care paid by the household
+	CouncilFunded    float64 // Total care met by the local authority
+	HomeSaleProceeds float64
+}
+
+// ApplyCareEpisode returns a copy of the config with a care episode applied
+// People keep their configured care start age; if nobody has one, the simulation
+// reference person goes into care at DefaultCareStartAge
+func ApplyCareEpisode(config *Config) *Config {
+	newConfig := *config
+	newConfig.People = make([]PersonConfig, len(config.People))
+	copy(newConfig.People, config.People)
+
+	if !newConfig.HasCareCosts() && len(newConfig.People) > 0 {
+		refName := newConfig.Simulation.ReferencePerson
+		idx := 0
+		for i, p := range newConfig.People {
+			if p.Name == refName {
+				idx = i
+				break
+			}
+		}
+		newConfig.People[idx].CareStartAge = DefaultCareStartAge
+	}
+	return &newConfig
+}
+
+// withoutCareCosts returns a copy of the config with all care start ages cleared
+func withoutCareCosts(config *Config) *Config {
+	newConfig := *config
+	newConfig.People = make([]PersonConfig, len(config.People))
+	copy(newConfig.People, config.People)
+	for i := range newConfig.People {
+		newConfig.People[i].CareStartAge = 0
+	}
+	return &newConfig
+}
+
+// RunCareScenarios runs each strategy with and without a care episode
+// to show which strategies survive the cost of later-life care
+func RunCareScenarios(config *Config, strategies []SimulationParams) []CareScenarioResult {
+	baseConfig := withoutCareCosts(config)
+	careConfig := ApplyCareEpisode(config)
+
+	return parallelMap(config.Control, len(strategies), config.Simulation.GetConcurrency(), func(i int) CareScenarioResult {
+		params := strategies[i]
+		baseline := RunSimulationV2(params, baseConfig)
+		withCare := RunSimulationV2(params, careConfig)
+
+		r := CareScenarioResult{
+			Params:           params,
+			Baseline:         baseline,
+			WithCare:         withCare,
+			SurvivesBaseline: !baseline.RanOutOfMoney,
+			SurvivesCare:     !withCare.RanOutOfMoney,
+			CareCost:         withCare.TotalCareCost,
+		}
+		for _, year := range withCare.Years {
+			r.CouncilFunded += year.CareFundedByCouncil
+			r.HomeSaleProceeds += year.HomeSaleProceeds
+		}
+		return r
+	})
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// createCareTestConfig creates a single-person config with care from age 80
+func createCareTestConfig() *Config {
+	careInflation := 0.05
+	return &Config{
+		People: []PersonConfig{
+			{Name: "Alice", BirthDate: "1960-01-01", RetirementAge: 65, PensionAccessAge: 55, StatePensionAge: 67,
+				TaxFreeSavings: 200000, Pension: 500000, CareStartAge: 80},
+		},
+		Financial: FinancialConfig{
+			PensionGrowthRate:     0.04,
+			SavingsGrowthRate:     0.04,
+			IncomeInflationRate:   0.02,
+			StatePensionInflation: 0.02,
+			StatePensionAmount:    11500,
+		},
+		IncomeRequirements: IncomeConfig{
+			MonthlyBeforeAge: 2000,
+			MonthlyAfterAge:  2000,
+			AgeThreshold:     67,
+			ReferencePerson:  "Alice",
+		},
+		Simulation: SimulationConfig{
+			StartYear:       2026,
+			EndAge:          90,
+			ReferencePerson: "Alice",
+		},
+		TaxBands: testTaxBands,
+		Care: CareConfig{
+			AnnualCost:    50000,
+			InflationRate: &careInflation,
+			DurationYears: 5,
+		},
+	}
+}
+
+// TestCareCost_AppliedDuringEpisode verifies care costs only apply for the episode and inflate at the care rate
+func TestCareCost_AppliedDuringEpisode(t *testing.T) {
+	config := createCareTestConfig()
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: SavingsFirst}
+	result := RunSimulation(params, config)
+
+	careYears := 0
+	for _, year := range result.Years {
+		age := year.Ages["Alice"]
+		inEpisode := age >= 80 && age < 85
+		if inEpisode {
+			careYears++
+			expected := 50000 * math.Pow(1.05, float64(year.Year-config.Simulation.StartYear))
+			if math.Abs(year.CareCost-expected) > 1 {
+				t.Errorf("Year %d (age %d): expected care cost £%.0f, got £%.0f", year.Year, age, expected, year.CareCost)
+			}
+			if year.TotalRequired < year.RequiredIncome+year.CareCost-1 {
+				t.Errorf("Year %d: care cost not included in total required", year.Year)
+			}
+		} else if year.CareCost != 0 {
+			t.Errorf("Year %d (age %d): expected no care cost, got £%.0f", year.Year, age, year.CareCost)
+		}
+	}
+
+	if careYears != 5 {
+		t.Errorf("Expected 5 care years, got %d", careYears)
+	}
+	if result.TotalCareCost <= 0 {
+		t.Error("Expected TotalCareCost to be recorded")
+	}
+}
+
+// TestCareCost_ZeroInflation verifies a care inflation rate of 0 keeps care costs flat rather than
+// falling back to income inflation, which only applies when the rate is not set
+func TestCareCost_ZeroInflation(t *testing.T) {
+	config := createCareTestConfig()
+	if err := yaml.Unmarshal([]byte("annual_cost: 50000\ninflation_rate: 0\n"), &config.Care); err != nil {
+		t.Fatal(err)
+	}
+	if errs := ValidateConfig(config); len(errs) > 0 {
+		t.Fatalf("Expected the config to be valid, got:\n%v", errs)
+	}
+	result := RunSimulation(SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: SavingsFirst}, config)
+	for _, year := range result.Years {
+		if year.CareCost != 0 && math.Abs(year.CareCost-50000) > 1 {
+			t.Errorf("Year %d: expected a flat £50,000 care cost, got £%.0f", year.Year, year.CareCost)
+		}
+	}
+	if result.TotalCareCost <= 0 {
+		t.Error("Expected care costs to be recorded")
+	}
+
+	config.Care.InflationRate = nil
+	if got := config.Care.GetInflationRate(0.02); got != 0.02 {
+		t.Errorf("Expected income inflation when the rate is not set, got %g", got)
+	}
+}
+
+// TestCareCost_MeansTest verifies the council funds care below the self-funding threshold
+func TestCareCost_MeansTest(t *testing.T) {
+	config := createCareTestConfig()
+	config.Care.SelfFundingThreshold = 23250
+	config.Care.LowerCapitalLimit = 14250
+
+	tests := []struct {
+		name        string
+		wealth      float64
+		expectPays  float64
+		expectFully bool
+	}{
+		{"above threshold pays in full", 100000, 50000, true},
+		{"below lower limit pays nothing", 10000, 0, false},
+		{"between limits pays tariff", 19250, 5000.0 / 250 * 52, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			person := &Person{Name: "Alice", BirthYear: 1944, CareStartAge: 80, CareAnnualCost: 50000,
+				TaxFreeSavings: tc.wealth}
+			state := NewYearState(config.Simulation.StartYear)
+			ApplyCareCosts(&state, []*Person{person}, config, &HomeState{}, config.Simulation.StartYear)
+
+			if math.Abs(state.CareCost-tc.expectPays) > 1 {
+				t.Errorf("Expected household to pay £%.0f, got £%.0f", tc.expectPays, state.CareCost)
+			}
+			if math.Abs(state.CareCost+state.CareFundedByCouncil-50000) > 1 {
+				t.Errorf("Household + council should equal full cost, got £%.0f + £%.0f",
+					state.CareCost, state.CareFundedByCouncil)
+			}
+			if tc.expectFully && state.CareFundedByCouncil != 0 {
+				t.Errorf("Expected no council funding, got £%.0f", state.CareFundedByCouncil)
+			}
+		})
+	}
+}
+
+// TestCareCost_HomeSaleRules verifies when the home is sold and where the proceeds go
+func TestCareCost_HomeSaleRules(t *testing.T) {
+	tests := []struct {
+		rule       string
+		expectSold bool
+	}{
+		{HomeSaleNever, false},
+		{HomeSaleFirstInCare, true},
+		{HomeSaleAllInCare, false}, // Partner still at home
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.rule, func(t *testing.T) {
+			config := createCareTestConfig()
+			config.Care.HomeValue = 300000
+			config.Care.HomeSaleCosts = 0.02
+			config.Care.HomeSale = tc.rule
+
+			inCare := &Person{Name: "Alice", BirthYear: 1944, CareStartAge: 80, CareAnnualCost: 50000}
+			atHome := &Person{Name: "Bob", BirthYear: 1945, CareStartAge: 90, CareAnnualCost: 50000}
+			state := NewYearState(config.Simulation.StartYear)
+			home := &HomeState{}
+			ApplyCareCosts(&state, []*Person{inCare, atHome}, config, home, config.Simulation.StartYear)
+
+			if home.Sold != tc.expectSold {
+				t.Fatalf("Expected home sold=%v, got %v", tc.expectSold, home.Sold)
+			}
+			if tc.expectSold {
+				expectedProceeds := 300000 * 0.98
+				if math.Abs(state.HomeSaleProceeds-expectedProceeds) > 1 {
+					t.Errorf("Expected proceeds £%.0f, got £%.0f", expectedProceeds, state.HomeSaleProceeds)
+				}
+				if math.Abs(inCare.Savings()-expectedProceeds/2) > 1 || math.Abs(atHome.Savings()-expectedProceeds/2) > 1 {
+					t.Errorf("Expected proceeds split equally, got £%.0f and £%.0f", inCare.Savings(), atHome.Savings())
+				}
+			}
+		})
+	}
+}
+
+// TestApplyCareEpisode_DefaultsToReferencePerson verifies the scenario puts the reference person into care
+func TestApplyCareEpisode_DefaultsToReferencePerson(t *testing.T) {
+	config := createTestConfig()
+	careConfig := ApplyCareEpisode(config)
+
+	if careConfig.People[0].CareStartAge != DefaultCareStartAge {
+		t.Errorf("Expected reference person care start age %d, got %d", DefaultCareStartAge, careConfig.People[0].CareStartAge)
+	}
+	if careConfig.People[1].CareStartAge != 0 {
+		t.Errorf("Expected other person to have no care, got %d", careConfig.People[1].CareStartAge)
+	}
+	if config.People[0].CareStartAge != 0 {
+		t.Error("ApplyCareEpisode should not modify the original config")
+	}
+}
+
+// TestRunCareScenarios verifies care scenarios report survival with and without care
+func TestRunCareScenarios(t *testing.T) {
+	config := createCareTestConfig()
+	strategies := []SimulationParams{
+		{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: SavingsFirst},
+		{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized},
+	}
+
+	results := RunCareScenarios(config, strategies)
+	if len(results) != len(strategies) {
+		t.Fatalf("Expected %d results, got %d", len(strategies), len(results))
+	}
+
+	for _, r := range results {
+		if !r.SurvivesBaseline {
+			t.Errorf("%s: expected baseline to survive", r.Params.ShortName())
+		}
+		if r.Baseline.TotalCareCost != 0 {
+			t.Errorf("%s: baseline should have no care costs", r.Params.ShortName())
+		}
+		if r.CareCost <= 0 {
+			t.Errorf("%s: expected care costs in care scenario", r.Params.ShortName())
+		}
+		if getTotalFinalBalance(r.WithCare) >= getTotalFinalBalance(r.Baseline) {
+			t.Errorf("%s: care should reduce the final balance", r.Params.ShortName())
+		}
+	}
+
+	// A very expensive episode should exhaust the funds
+	config.Care.AnnualCost = 400000
+	results = RunCareScenarios(config, strategies)
+	for _, r := range results {
+		if r.SurvivesCare {
+			t.Errorf("%s: expected to run out with £400k/year care", r.Params.ShortName())
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// ComplianceWarning is a pension or ISA rule a simulated plan breaks, or risks breaking
+type ComplianceWarning struct {
+	Rule     string `json:"rule"`     // One of the Rule constants
+	Severity string `json:"severity"` // SeverityBreach or SeverityRisk
+	Person   string `json:"person"`
+	Year     int    `json:"year"`  // First tax year affected
+	Years    int    `json:"years"` // Tax years affected
+	Message  string `json:"message"`
+}
+
+// Rules checked by CheckCompliance
+const (
+	RuleRecycling          = "recycling"           // Tax-free cash recycled into pension contributions
+	RuleMPAA               = "mpaa"                // Money purchase annual allowance after flexible access
+	RuleISASubscription    = "isa_subscription"    // ISA or Lifetime ISA paid in over the allowance
+	RuleRelevantEarnings   = "relevant_earnings"   // Personal contributions over relevant UK earnings
+	RuleMinimumPensionAge  = "minimum_pension_age" // Pension accessed before the normal minimum pension age
+	SeverityBreach         = "breach"
+	SeverityRisk           = "risk"
+	recyclingCashThreshold = 7500.0  // Tax-free cash in a year below which recycling is ignored
+	recyclingShare         = 0.30    // Share of the cash that counts as a significant increase in contributions
+	recyclingWindow        = 2       // Tax years either side of the cash that contributions are counted in
+	mpaaLimit              = 10000.0 // Money purchase annual allowance
+	reliefMinimum          = 3600.0  // Gross contribution that gets relief without earnings
+)
+
+// String describes the warning with the years it affects
+func (w ComplianceWarning) String() string {
+	if w.Years > 1 {
+		return fmt.Sprintf("%s (%d, and %d later years)", w.Message, w.Year, w.Years-1)
+	}
+	return fmt.Sprintf("%s (%d)", w.Message, w.Year)
+}
+
+// complianceLog collects warnings, one per rule, severity and person
+type complianceLog []ComplianceWarning
+
+// add records a warning for year, or counts year against the same warning already recorded
+func (l *complianceLog) add(rule, severity, person string, year int, message string) {
+	for i := range *l {
+		w := &(*l)[i]
+		if w.Rule == rule && w.Severity == severity && w.Person == person {
+			w.Years++
+			return
+		}
+	}
+	*l = append(*l, ComplianceWarning{Rule: rule, Severity: severity, Person: person, Year: year, Years: 1, Message: message})
+}
+
+// CheckCompliance scans result for pension and ISA rules the plan breaks or risks breaking:
+// recycling tax-free cash, the MPAA, ISA subscriptions, contributions over relevant earnings
+// and access before the minimum pension age
+// It is only run for the results that are reported, not for every strategy simulated
+func CheckCompliance(result SimulationResult, config *Config) []ComplianceWarning {
+	var warnings complianceLog
+	for _, p := range InitializePeople(config) {
+		checkRecycling(&warnings, result, p)
+		checkMPAA(&warnings, result, p)
+		for i, year := range result.Years {
+			checkISASubscription(&warnings, year, p)
+			checkRelevantEarnings(&warnings, year, p, config, i)
+			checkMinimumPensionAge(&warnings, year, p)
+		}
+	}
+	return warnings
+}
+
+// checkRecycling warns when pension contributions around a year's tax-free cash rise well above the
+// person's regular contributions: an increase of more than 30% of over £7,500 in the two tax years
+// either side breaks the recycling rule. Regular saving into the pension is the baseline, so only
+// contributions over it (e.g. ISA to SIPP transfers) count
+func checkRecycling(log *complianceLog, result SimulationResult, p *Person) {
+	for i, year := range result.Years {
+		cash := year.TaxFreeCashTaken[p.Name]
+		if cash <= recyclingCashThreshold {
+			continue
+		}
+		increase := 0.0
+		for j := max(0, i-recyclingWindow); j <= min(len(result.Years)-1, i+recyclingWindow); j++ {
+			other := result.Years[j]
+			increase += math.Max(0, other.PensionContributions[p.Name]-regularContribution(p, other.Year))
+		}
+		switch {
+		case increase > cash*recyclingShare:
+			log.add(RuleRecycling, SeverityBreach, p.Name, year.Year, fmt.Sprintf(
+				"%s takes %s tax-free cash with %s of pension contributions over their regular saving within two tax years, over 30%% of it (recycling rule)",
+				p.Name, FormatMoney(cash), FormatMoney(increase)))
+		case increase > 0:
+			log.add(RuleRecycling, SeverityRisk, p.Name, year.Year, fmt.Sprintf(
+				"%s pays %s more than their regular saving into the pension within two tax years of taking %s tax-free cash, which may look like recycling",
+				p.Name, FormatMoney(increase), FormatMoney(cash)))
+		}
+	}
+}
+
+// regularContribution returns the gross pension contribution the person's extra saving makes in a tax year,
+// as AddSaving pays it in
+func regularContribution(p *Person, year int) float64 {
+	if !p.IsSaving(year) || !p.ExtraSavingToPension {
+		return 0
+	}
+	gross := p.ExtraMonthlySaving * 12 / (1 - pensionBasicRateRelief)
+	if allowance := p.PensionAnnualAllowance - p.EmployerContribution; allowance > 0 {
+		gross = math.Min(gross, allowance)
+	}
+	return gross
+}
+
+// checkMPAA warns about money purchase contributions over £10,000 once taxable income has been
+// drawn from a pension, which triggers the MPAA. In the year it is triggered it depends on the timing
+func checkMPAA(log *complianceLog, result SimulationResult, p *Person) {
+	triggered := 0
+	for _, year := range result.Years {
+		if triggered == 0 && year.Withdrawals.TaxableFromPension[p.Name] > 0 {
+			triggered = year.Year
+		}
+		if triggered == 0 {
+			continue
+		}
+		contributions := year.PensionContributions[p.Name]
+		if p.IsWorking(year.Year) {
+			contributions += p.EmployerContribution
+		}
+		if contributions <= mpaaLimit {
+			continue
+		}
+		severity := SeverityBreach
+		if year.Year == triggered {
+			severity = SeverityRisk
+		}
+		log.add(RuleMPAA, severity, p.Name, year.Year, fmt.Sprintf(
+			"%s pays %s into pensions after drawing taxable pension income in %d, over the %s money purchase annual allowance",
+			p.Name, FormatMoney(contributions), triggered, FormatMoney(mpaaLimit)))
+	}
+}
+
+// checkISASubscription warns when more than the allowance is paid into the ISA or Lifetime ISA in a tax year
+func checkISASubscription(log *complianceLog, year YearState, p *Person) {
+	if subscribed := year.EndBalances[p.Name].ISASubscribed; subscribed > p.ISAAnnualLimit+0.01 {
+		log.add(RuleISASubscription, SeverityBreach, p.Name, year.Year, fmt.Sprintf(
+			"%s pays %s into ISAs, over the %s allowance", p.Name, FormatMoney(subscribed), FormatMoney(p.ISAAnnualLimit)))
+	}
+	if paid := year.LISAContributions[p.Name]; paid > LISAAnnualLimit+0.01 {
+		log.add(RuleISASubscription, SeverityBreach, p.Name, year.Year, fmt.Sprintf(
+			"%s pays %s into the Lifetime ISA, over the %s limit", p.Name, FormatMoney(paid), FormatMoney(LISAAnnualLimit)))
+	}
+}
+
+// checkRelevantEarnings warns when personal contributions are over the person's relevant UK earnings
+// (work and part-time income), or £3,600 without them, so tax relief would not be given on all of them
+func checkRelevantEarnings(log *complianceLog, year YearState, p *Person, config *Config, yearsFromStart int) {
+	contributions := year.PensionContributions[p.Name]
+	if contributions <= 0 {
+		return
+	}
+	earnings := year.WorkIncomeByPerson[p.Name]
+	if p.IsReceivingPartTimeIncome(year.Year) {
+		earnings += p.PartTimeIncome * math.Pow(1+config.Financial.IncomeInflationRate, float64(yearsFromStart))
+	}
+	if limit := math.Max(earnings, reliefMinimum); contributions > limit+1 {
+		log.add(RuleRelevantEarnings, SeverityBreach, p.Name, year.Year, fmt.Sprintf(
+			"%s contributes %s gross to pensions with %s of relevant earnings, so relief is only due on %s",
+			p.Name, FormatMoney(contributions), FormatMoney(earnings), FormatMoney(limit)))
+	}
+}
+
+// checkMinimumPensionAge warns when a pension is drawn, crystallised or commuted before the person's
+// minimum pension age in that tax year
+func checkMinimumPensionAge(log *complianceLog, year YearState, p *Person) {
+	age, ok := year.Ages[p.Name]
+	if !ok || age >= p.MinimumPensionAge(year.Year) {
+		return
+	}
+	accessed := year.Withdrawals.TaxFreeFromPension[p.Name] + year.Withdrawals.TaxableFromPension[p.Name] +
+		year.TrancheCrystallised[p.Name] + year.TaxFreeCashTaken[p.Name]
+	if accessed > 0 {
+		log.add(RuleMinimumPensionAge, SeverityBreach, p.Name, year.Year, fmt.Sprintf(
+			"%s accesses %s of pension at %d, before the minimum pension age of %d (an unauthorised payment)",
+			p.Name, FormatMoney(accessed), age, p.MinimumPensionAge(year.Year)))
+	}
+}
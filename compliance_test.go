@@ -0,0 +1,161 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// complianceResult builds a result for one person aged age in the first of years tax years from 2030
+func complianceResult(name string, age, years int) SimulationResult {
+	var result SimulationResult
+	for i := 0; i < years; i++ {
+		state := NewYearState(2030 + i)
+		state.Ages[name] = age + i
+		result.Years = append(result.Years, state)
+	}
+	return result
+}
+
+// findWarning returns the warning for rule and severity, if there is one
+func findWarning(warnings []ComplianceWarning, rule, severity string) (ComplianceWarning, bool) {
+	for _, w := range warnings {
+		if w.Rule == rule && w.Severity == severity {
+			return w, true
+		}
+	}
+	return ComplianceWarning{}, false
+}
+
+// TestCheckCompliance_Recycling verifies contributions near tax-free cash are flagged by their share of it
+func TestCheckCompliance_Recycling(t *testing.T) {
+	config := createTestConfig()
+	result := complianceResult("James", 58, 6)
+	result.Years[2].TaxFreeCashTaken["James"] = 40000
+	result.Years[0].PensionContributions["James"] = 8000
+	result.Years[4].PensionContributions["James"] = 8000
+	result.Years[5].PensionContributions["James"] = 50000 // Outside the window
+
+	w, ok := findWarning(CheckCompliance(result, config), RuleRecycling, SeverityBreach)
+	if !ok || w.Person != "James" || w.Year != 2032 || !strings.Contains(w.Message, "£16k") {
+		t.Errorf("Expected a recycling breach for £16,000 of contributions in 2032, got %+v", w)
+	}
+
+	// Smaller contributions are only a risk, and small amounts of cash are ignored
+	result.Years[4].PensionContributions["James"] = 0
+	if _, ok := findWarning(CheckCompliance(result, config), RuleRecycling, SeverityRisk); !ok {
+		t.Error("Expected a recycling risk for £8,000 of contributions")
+	}
+	result.Years[2].TaxFreeCashTaken["James"] = 7500
+	if w, ok := findWarning(CheckCompliance(result, config), RuleRecycling, SeverityRisk); ok {
+		t.Errorf("Expected no recycling warning for £7,500 of cash, got %+v", w)
+	}
+
+	// Regular saving into the pension is the baseline: only contributions over it count
+	result.Years[2].TaxFreeCashTaken["James"] = 40000
+	config.People[0].RetirementAge = 70
+	config.People[0].ExtraMonthlySaving = 1000
+	config.People[0].ExtraSavingWrapper = SavingWrapperPension // £15,000 a year gross
+	if w, ok := findWarning(CheckCompliance(result, config), RuleRecycling, SeverityRisk); ok {
+		t.Errorf("Expected no recycling warning for contributions within regular saving, got %+v", w)
+	}
+	result.Years[0].PensionContributions["James"] = 30000
+	w, ok = findWarning(CheckCompliance(result, config), RuleRecycling, SeverityBreach)
+	if !ok || !strings.Contains(w.Message, "£15k of pension contributions over their regular saving") {
+		t.Errorf("Expected a recycling breach for the £15,000 over regular saving, got %+v", w)
+	}
+}
+
+// TestCheckCompliance_MPAA verifies contributions over £10k after taxable drawdown are flagged
+func TestCheckCompliance_MPAA(t *testing.T) {
+	config := createTestConfig()
+	result := complianceResult("James", 58, 4)
+	result.Years[0].PensionContributions["James"] = 20000 // Before flexible access
+	result.Years[1].Withdrawals.TaxableFromPension["James"] = 5000
+	result.Years[1].PensionContributions["James"] = 12000
+	result.Years[2].PensionContributions["James"] = 15000
+	result.Years[3].PensionContributions["James"] = 15000
+
+	warnings := CheckCompliance(result, config)
+	if w, ok := findWarning(warnings, RuleMPAA, SeverityRisk); !ok || w.Year != 2031 || w.Years != 1 {
+		t.Errorf("Expected a risk in the year the MPAA is triggered, got %+v", w)
+	}
+	w, ok := findWarning(warnings, RuleMPAA, SeverityBreach)
+	if !ok || w.Year != 2032 || w.Years != 2 {
+		t.Errorf("Expected a breach from 2032 for two years, got %+v", w)
+	}
+	if got := w.String(); !strings.Contains(got, "(2032, and 1 later years)") {
+		t.Errorf("Expected the later years in the description, got %q", got)
+	}
+}
+
+// TestCheckCompliance_ISAAndEarnings verifies ISA over-subscription and contributions over earnings are flagged
+func TestCheckCompliance_ISAAndEarnings(t *testing.T) {
+	config := createTestConfig()
+	result := complianceResult("Delphine", 50, 2)
+	result.Years[0].EndBalances["Delphine"] = PersonBalances{ISASubscribed: 25000}
+	result.Years[1].LISAContributions["Delphine"] = 5000
+	result.Years[0].PensionContributions["Delphine"] = 3600 // Relief without earnings
+	result.Years[1].PensionContributions["Delphine"] = 30000
+	result.Years[1].WorkIncomeByPerson["Delphine"] = 25000
+
+	warnings := CheckCompliance(result, config)
+	if w, ok := findWarning(warnings, RuleISASubscription, SeverityBreach); !ok || w.Years != 2 {
+		t.Errorf("Expected ISA breaches in both years, got %+v", w)
+	}
+	w, ok := findWarning(warnings, RuleRelevantEarnings, SeverityBreach)
+	if !ok || w.Year != 2031 || w.Years != 1 || !strings.Contains(w.Message, "£25k of relevant earnings") {
+		t.Errorf("Expected contributions over earnings in 2031 only, got %+v", w)
+	}
+}
+
+// TestCheckCompliance_MinimumPensionAge verifies access before 57 is flagged after April 2028
+func TestCheckCompliance_MinimumPensionAge(t *testing.T) {
+	config := createTestConfig()
+	result := complianceResult("James", 55, 3)
+	result.Years[1].TrancheCrystallised["James"] = 40000
+	result.Years[2].Withdrawals.TaxableFromPension["James"] = 10000
+
+	w, ok := findWarning(CheckCompliance(result, config), RuleMinimumPensionAge, SeverityBreach)
+	if !ok || w.Year != 2031 || w.Years != 1 || !strings.Contains(w.Message, "at 56, before the minimum pension age of 57") {
+		t.Errorf("Expected access at 56 flagged and 57 allowed, got %+v", w)
+	}
+
+	// A protected pension age keeps access at 55
+	config.People[0].ProtectedPensionAge = 55
+	if w, ok := findWarning(CheckCompliance(result, config), RuleMinimumPensionAge, SeverityBreach); ok {
+		t.Errorf("Expected no warning with a protected pension age, got %+v", w)
+	}
+}
+
+// TestSimulation_Compliance verifies the warnings for simulated plans that break the rules,
+// and that the web results only check the results they keep
+func TestSimulation_Compliance(t *testing.T) {
+	config := createTestConfig()
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: SavingsFirst, MortgageOpt: MortgageNormal}
+	for _, w := range CheckCompliance(RunSimulationV2(params, config), config) {
+		if w.Severity == SeverityBreach {
+			t.Errorf("Expected no breaches in the test plan, got %s", w)
+		}
+	}
+
+	// Moving the ISA into the pension while working, around tax-free cash, is recycling
+	config.People[0].RetirementAge = 60
+	config.People[0].PensionAccessAge = 55
+	config.People[0].WorkIncome = 60000
+	config.People[0].ISAToSIPPEnabled = true
+	config.TaxFreeCash = []TaxFreeCashConfig{{Person: "James", Year: 2027, Purpose: TaxFreeCashISA, Amount: 50000}}
+	params.ISAToSIPPEnabled = true
+	result := RunSimulationV2(params, config)
+	warnings := CheckCompliance(result, config)
+	if w, ok := findWarning(warnings, RuleRecycling, SeverityBreach); !ok || w.Person != "James" || w.Year != 2027 {
+		t.Errorf("Expected a recycling breach for James in 2027, got %+v", warnings)
+	}
+
+	tracker := newTopNTracker(1, config.Strategy.GetScoringWeights(OptimizeBalance), config.Financial.IncomeInflationRate)
+	if !tracker.add(convertToAPISummary(result, false, 0), result, config) || len(tracker.results[0].Warnings) != len(warnings) {
+		t.Errorf("Expected the warnings in the kept summary, got %+v", tracker.results)
+	}
+	if summary := convertToAPISummary(result, false, 0); tracker.admits(tracker.calcScore(result)) || len(summary.Warnings) > 0 {
+		t.Error("Expected an equal result not to be kept, and no warnings before a result is kept")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"os"
 	"regexp"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +23,7 @@ type PersonConfig struct {
 	RetirementDate   string  `yaml:"retirement_date" json:"retirement_date"`       // Date when you stop working (YYYY-MM-DD) - preferred over retirement_age
 	RetirementAge    int     `yaml:"retirement_age" json:"retirement_age"`         // Age when income requirements start (legacy, use retirement_date instead)
 	PensionAccessAge int     `yaml:"pension_access_age" json:"pension_access_age"` // Age when DC pension can be accessed (default: same as retirement_age)
+	ProtectedPensionAge int     `yaml:"protected_pension_age,omitempty" json:"protected_pension_age,omitempty"` // Protected pension age below the minimum (e.g. 55 kept after April 2028)
 	StatePensionAge  int     `yaml:"state_pension_age" json:"state_pension_age"`
 	TaxFreeSavings   float64 `yaml:"tax_free_savings" json:"tax_free_savings"`
 	Pension          float64 `yaml:"pension" json:"pension"`
@@ -56,6 +58,22 @@ type PersonConfig struct {
 	EmployerContribution   float64 `yaml:"employer_contribution" json:"employer_contribution"`           // Annual employer pension contribution (reduces available allowance)
 	ISAToSIPPMaxPercent    float64 `yaml:"isa_to_sipp_max_percent" json:"isa_to_sipp_max_percent"`       // Max % of remaining allowance to use (default 100%)
 	ISAToSIPPPreserveMonths int    `yaml:"isa_to_sipp_preserve_months" json:"isa_to_sipp_preserve_months"` // Months of expenses to preserve in ISA (default 12)
+
+	// Extra saving before retirement (use -required-saving to find the amount needed)
+	ExtraMonthlySaving float64 `yaml:"extra_monthly_saving,omitempty" json:"extra_monthly_saving,omitempty"` // Additional saving per month until retirement
+	ExtraLumpSum       float64 `yaml:"extra_lump_sum,omitempty" json:"extra_lump_sum,omitempty"`             // One-off addition at the start of the simulation
+	ExtraSavingWrapper string  `yaml:"extra_saving_wrapper,omitempty" json:"extra_saving_wrapper,omitempty"` // "isa" (default) or "pension" (net contribution, basic-rate relief added)
+
+	// Later-life care (costs and funding rules are in the care section)
+	CareStartAge   int     `yaml:"care_start_age,omitempty" json:"care_start_age,omitempty"`     // Age when long-term care starts (0 = no care modelled)
+	CareAnnualCost float64 `yaml:"care_annual_cost,omitempty" json:"care_annual_cost,omitempty"` // Per-person override of care.annual_cost (today's money)
+
+	// Tax-free cash already taken from pensions, counted against the lump sum allowance
+	LumpSumAllowanceUsed float64 `yaml:"lump_sum_allowance_used,omitempty" json:"lump_sum_allowance_used,omitempty"`
+
+	// Lifetime ISA (25% bonus on contributions until 50, tax-free from 60)
+	LifetimeISA      float64 `yaml:"lifetime_isa,omitempty" json:"lifetime_isa,omitempty"`           // Lifetime ISA balance
+	LISAContribution float64 `yaml:"lisa_contribution,omitempty" json:"lisa_contribution,omitempty"` // Paid in each year from surplus work income (max 4000)
 }
 
 // FinancialConfig holds growth and inflation rates
@@ -98,10 +116,35 @@ type FinancialConfig struct {
 type IncomeTier struct {
 	StartAge           *int    `yaml:"start_age,omitempty" json:"start_age,omitempty"`               // Age when tier starts (nil = from retirement)
 	EndAge             *int    `yaml:"end_age,omitempty" json:"end_age,omitempty"`                   // Age when tier ends (nil = until simulation end)
-	MonthlyAmount      float64 `yaml:"monthly_amount" json:"monthly_amount"`                         // Monthly income (£) or percentage if IsPercentage
-	Ratio              float64 `yaml:"ratio" json:"ratio"`                                           // For depletion mode: ratio relative to other tiers
+	MonthlyAmount      float64 `yaml:"monthly_amount,omitempty" json:"monthly_amount"`               // Monthly income (£) or percentage if IsPercentage
+	Ratio              float64 `yaml:"ratio,omitempty" json:"ratio"`                                 // For depletion mode: ratio relative to other tiers
 	IsPercentage       bool    `yaml:"is_percentage,omitempty" json:"is_percentage"`                 // If true, MonthlyAmount is annual % of initial portfolio
 	IsInvestmentGains  bool    `yaml:"is_investment_gains,omitempty" json:"is_investment_gains"`     // If true, income = investment gains after inflation
+	EssentialMonthly   float64 `yaml:"essential_monthly,omitempty" json:"essential_monthly,omitempty"` // Essential floor within this tier (£/month, never cut by guardrails)
+
+	// Multi-variable depletion: solve this tier independently of the shared ratio multiplier
+	Solve      bool    `yaml:"solve,omitempty" json:"solve,omitempty"`             // For depletion mode: solve this tier's amount as its own unknown
+	MinMonthly float64 `yaml:"min_monthly,omitempty" json:"min_monthly,omitempty"` // For depletion mode: lowest monthly amount allowed (today's money)
+	MaxMonthly float64 `yaml:"max_monthly,omitempty" json:"max_monthly,omitempty"` // For depletion mode: highest monthly amount allowed (0 = no cap)
+	Priority   int     `yaml:"priority,omitempty" json:"priority,omitempty"`       // For depletion mode: lower priorities are maximised first
+}
+
+// AgeRange returns a human-readable description of the ages a tier covers
+func (t *IncomeTier) AgeRange() string {
+	if t.StartAge == nil && t.EndAge != nil {
+		return "until " + strconv.Itoa(*t.EndAge)
+	} else if t.StartAge != nil && t.EndAge == nil {
+		return strconv.Itoa(*t.StartAge) + "+"
+	} else if t.StartAge != nil && t.EndAge != nil {
+		return strconv.Itoa(*t.StartAge) + "-" + strconv.Itoa(*t.EndAge)
+	}
+	return "all ages"
+}
+
+// IsSolved returns true if depletion mode solves this tier's amount as its own unknown
+// Percentage and investment gains tiers are never solved
+func (t *IncomeTier) IsSolved() bool {
+	return t.Solve && !t.IsPercentage && !t.IsInvestmentGains
 }
 
 // IncomeConfig holds income requirement settings
@@ -118,15 +161,57 @@ type IncomeConfig struct {
 	IncomeRatioPhase1  float64 `yaml:"income_ratio_phase1" json:"income_ratio_phase1"`   // Legacy: e.g., 5.0 for 5:3 ratio (used if Tiers empty)
 	IncomeRatioPhase2  float64 `yaml:"income_ratio_phase2" json:"income_ratio_phase2"`   // Legacy: e.g., 3.0 for 5:3 ratio (used if Tiers empty)
 
+	// Terminal target for depletion mode - leave an estate or keep wrapper floors instead of depleting to £0
+	TargetLegacy       float64 `yaml:"target_legacy,omitempty" json:"target_legacy,omitempty"`               // Total ISA + pension to leave at target_depletion_age
+	TargetLegacyReal   bool    `yaml:"target_legacy_real,omitempty" json:"target_legacy_real,omitempty"`     // Targets are in today's money (inflated by income_inflation_rate)
+	TargetISAFloor     float64 `yaml:"target_isa_floor,omitempty" json:"target_isa_floor,omitempty"`         // Minimum total ISA balance at target_depletion_age
+	TargetPensionFloor float64 `yaml:"target_pension_floor,omitempty" json:"target_pension_floor,omitempty"` // Minimum total pension balance at target_depletion_age
+
 	// Guardrails Strategy (Guyton-Klinger) - dynamic withdrawal adjustments
-	GuardrailsEnabled    bool    `yaml:"guardrails_enabled" json:"guardrails_enabled"`         // Enable guardrails adjustments
-	GuardrailsUpperLimit float64 `yaml:"guardrails_upper_limit" json:"guardrails_upper_limit"` // Upper guardrail (e.g., 1.20 = 120% of initial rate)
-	GuardrailsLowerLimit float64 `yaml:"guardrails_lower_limit" json:"guardrails_lower_limit"` // Lower guardrail (e.g., 0.80 = 80% of initial rate)
-	GuardrailsAdjustment float64 `yaml:"guardrails_adjustment" json:"guardrails_adjustment"`   // Adjustment percentage (e.g., 0.10 = 10%)
+	GuardrailsEnabled     bool    `yaml:"guardrails_enabled" json:"guardrails_enabled"`                               // Enable guardrails adjustments
+	GuardrailsUpperLimit  float64 `yaml:"guardrails_upper_limit" json:"guardrails_upper_limit"`                       // Upper guardrail (e.g., 1.20 = 120% of initial rate)
+	GuardrailsLowerLimit  float64 `yaml:"guardrails_lower_limit" json:"guardrails_lower_limit"`                       // Lower guardrail (e.g., 0.80 = 80% of initial rate)
+	GuardrailsAdjustment  float64 `yaml:"guardrails_adjustment" json:"guardrails_adjustment"`                         // Adjustment percentage (e.g., 0.10 = 10%)
+	GuardrailsCutRate     float64 `yaml:"guardrails_cut_rate,omitempty" json:"guardrails_cut_rate,omitempty"`         // Capital preservation cut (default: guardrails_adjustment)
+	GuardrailsRaiseRate   float64 `yaml:"guardrails_raise_rate,omitempty" json:"guardrails_raise_rate,omitempty"`     // Prosperity raise (default: guardrails_adjustment)
+	GuardrailsExemptYears int     `yaml:"guardrails_exempt_years,omitempty" json:"guardrails_exempt_years,omitempty"` // No cuts in the final N years of the plan (default: 15)
+
+	// Dynamic withdrawal rule - "guyton_klinger", "vanguard", "vpw" or "constant_percent"
+	// guardrails_enabled is shorthand for guyton_klinger
+	WithdrawalRule      string  `yaml:"withdrawal_rule,omitempty" json:"withdrawal_rule,omitempty"`
+	VanguardCeiling     float64 `yaml:"vanguard_ceiling,omitempty" json:"vanguard_ceiling,omitempty"`           // Max real rise per year (default: 0.05 = 5%)
+	VanguardFloor       float64 `yaml:"vanguard_floor,omitempty" json:"vanguard_floor,omitempty"`               // Max real fall per year (default: 0.025 = 2.5%)
+	VPWEnabled          bool    `yaml:"vpw_enabled,omitempty" json:"vpw_enabled,omitempty"`                     // Shorthand for withdrawal_rule: vpw
+	VPWFloor            float64 `yaml:"vpw_floor,omitempty" json:"vpw_floor,omitempty"`                         // Minimum annual withdrawal in today's money (0 = none)
+	VPWCeiling          float64 `yaml:"vpw_ceiling,omitempty" json:"vpw_ceiling,omitempty"`                     // Maximum as a multiple of the floor (0 = none)
+	VPWRealReturn       float64 `yaml:"vpw_real_return,omitempty" json:"vpw_real_return,omitempty"`             // Expected real return for the VPW table (default: 0.03)
+	VPWEndAge           int     `yaml:"vpw_end_age,omitempty" json:"vpw_end_age,omitempty"`                     // Age the VPW table plans to (default: 100)
+	ConstantPercentRate float64 `yaml:"constant_percent_rate,omitempty" json:"constant_percent_rate,omitempty"` // Share of portfolio withdrawn each year (default: initial withdrawal rate)
 
 	// Legacy common field (used when Tiers is empty)
 	AgeThreshold    int    `yaml:"age_threshold,omitempty" json:"age_threshold,omitempty"`
 	ReferencePerson string `yaml:"reference_person" json:"reference_person"`
+
+	// Spending profile - shapes tier spending over time and sets bucket inflation rates
+	SpendingProfile SpendingProfileConfig `yaml:"spending_profile,omitempty" json:"spending_profile,omitempty"`
+}
+
+// Spending profile types
+const (
+	SpendingProfileFlat    = "flat"    // Constant real spending (default)
+	SpendingProfileSmile   = "smile"   // Blanchett "retirement spending smile"
+	SpendingProfileDecline = "decline" // Fixed real decline per year after an age
+)
+
+// SpendingProfileConfig shapes retirement spending in real terms
+// Spending is split into essential and discretionary buckets which can inflate at different rates
+type SpendingProfileConfig struct {
+	Type                   string  `yaml:"type,omitempty" json:"type,omitempty"`                                       // "flat" (default), "smile" or "decline"
+	DeclineStartAge        int     `yaml:"decline_start_age,omitempty" json:"decline_start_age,omitempty"`             // Age real decline starts (default: retirement age)
+	DeclineRate            float64 `yaml:"decline_rate,omitempty" json:"decline_rate,omitempty"`                       // Real decline per year (e.g., 0.01 = 1%)
+	EssentialFraction      float64 `yaml:"essential_fraction,omitempty" json:"essential_fraction,omitempty"`           // Share of spending that is essential (0-1)
+	EssentialInflation     float64 `yaml:"essential_inflation,omitempty" json:"essential_inflation,omitempty"`         // Essential spending inflation (default: income_inflation_rate)
+	DiscretionaryInflation float64 `yaml:"discretionary_inflation,omitempty" json:"discretionary_inflation,omitempty"` // Discretionary spending inflation (default: income_inflation_rate)
 }
 
 // IsDepletionMode returns true if depletion mode is configured
@@ -134,11 +219,26 @@ func (ic *IncomeConfig) IsDepletionMode() bool {
 	return ic.TargetDepletionAge > 0
 }
 
+// HasTerminalTarget returns true if depletion should leave a legacy or wrapper floor rather than £0
+func (ic *IncomeConfig) HasTerminalTarget() bool {
+	return ic.TargetLegacy > 0 || ic.TargetISAFloor > 0 || ic.TargetPensionFloor > 0
+}
+
 // HasTiers returns true if tiered income is configured
 func (ic *IncomeConfig) HasTiers() bool {
 	return len(ic.Tiers) > 0
 }
 
+// HasSolvedTiers returns true if any tier is solved independently in depletion mode
+func (ic *IncomeConfig) HasSolvedTiers() bool {
+	for i := range ic.Tiers {
+		if ic.Tiers[i].IsSolved() {
+			return true
+		}
+	}
+	return false
+}
+
 // HasPercentageTiers returns true if any tier uses percentage-based income
 func (ic *IncomeConfig) HasPercentageTiers() bool {
 	for _, tier := range ic.Tiers {
@@ -159,6 +259,46 @@ func (ic *IncomeConfig) HasInvestmentGainsTiers() bool {
 	return false
 }
 
+// GetWithdrawalRule returns the configured dynamic withdrawal rule
+// withdrawal_rule takes precedence over the guardrails_enabled and vpw_enabled shorthands
+func (ic *IncomeConfig) GetWithdrawalRule() WithdrawalRule {
+	switch {
+	case ic.WithdrawalRule != "":
+		return ParseWithdrawalRule(ic.WithdrawalRule)
+	case ic.GuardrailsEnabled:
+		return WithdrawalGuytonKlinger
+	case ic.VPWEnabled:
+		return WithdrawalVPW
+	default:
+		return WithdrawalFixed
+	}
+}
+
+// HasEssentialFloor returns true if any spending is marked as essential
+func (ic *IncomeConfig) HasEssentialFloor() bool {
+	if ic.SpendingProfile.GetEssentialFraction() > 0 {
+		return true
+	}
+	for _, tier := range ic.Tiers {
+		if tier.EssentialMonthly > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEssentialFractionForAge returns the share of annualIncome that is essential at an age
+// A tier's essential_monthly takes precedence over the spending profile's essential_fraction
+func (ic *IncomeConfig) GetEssentialFractionForAge(age int, annualIncome float64) float64 {
+	if ic.HasTiers() {
+		tier := ic.GetTierForAge(age)
+		if tier != nil && tier.EssentialMonthly > 0 && annualIncome > 0 {
+			return math.Min(1, tier.EssentialMonthly*12/annualIncome)
+		}
+	}
+	return ic.SpendingProfile.GetEssentialFraction()
+}
+
 // GetTierForAge returns the income tier applicable for a given age
 // Returns nil if no tier matches (shouldn't happen with properly configured tiers)
 func (ic *IncomeConfig) GetTierForAge(age int) *IncomeTier {
@@ -259,29 +399,27 @@ func (ic *IncomeConfig) GetIncomeAmounts(multiplier float64) (beforeAge, afterAg
 	return ic.IncomeRatioPhase1 * multiplier, ic.IncomeRatioPhase2 * multiplier
 }
 
-// ConvertLegacyToTiers converts legacy before/after age fields to tiered format
-// This is useful for migration and consistent internal handling
+// ConvertLegacyToTiers converts legacy before/after age fields to tiers and clears them
+// Each tier keeps both the fixed amount and the depletion ratio, so either mode gives the same income
+// Used by the config migration to schema version 2
 func (ic *IncomeConfig) ConvertLegacyToTiers() {
 	if ic.HasTiers() {
 		return // Already has tiers
 	}
 
-	threshold := ic.AgeThreshold
-	if threshold == 0 {
-		threshold = 67 // Default
-	}
-
-	if ic.IsDepletionMode() {
-		ic.Tiers = []IncomeTier{
-			{EndAge: &threshold, Ratio: ic.IncomeRatioPhase1},
-			{StartAge: &threshold, Ratio: ic.IncomeRatioPhase2},
-		}
+	before := IncomeTier{MonthlyAmount: ic.MonthlyBeforeAge, Ratio: ic.IncomeRatioPhase1}
+	after := IncomeTier{MonthlyAmount: ic.MonthlyAfterAge, Ratio: ic.IncomeRatioPhase2}
+	if ic.AgeThreshold > 0 {
+		endAge, startAge := ic.AgeThreshold, ic.AgeThreshold
+		before.EndAge = &endAge
+		after.StartAge = &startAge
+		ic.Tiers = []IncomeTier{before, after}
 	} else {
-		ic.Tiers = []IncomeTier{
-			{EndAge: &threshold, MonthlyAmount: ic.MonthlyBeforeAge},
-			{StartAge: &threshold, MonthlyAmount: ic.MonthlyAfterAge},
-		}
+		ic.Tiers = []IncomeTier{after} // Without a threshold every age is after it
 	}
+
+	ic.MonthlyBeforeAge, ic.MonthlyAfterAge = 0, 0
+	ic.IncomeRatioPhase1, ic.IncomeRatioPhase2 = 0, 0
 }
 
 // DescribeTiers returns a human-readable description of the income tiers
@@ -292,19 +430,18 @@ func (ic *IncomeConfig) DescribeTiers(initialPortfolio float64) string {
 
 	var parts []string
 	for _, tier := range ic.Tiers {
-		var ageRange string
-		if tier.StartAge == nil && tier.EndAge != nil {
-			ageRange = "until " + strconv.Itoa(*tier.EndAge)
-		} else if tier.StartAge != nil && tier.EndAge == nil {
-			ageRange = strconv.Itoa(*tier.StartAge) + "+"
-		} else if tier.StartAge != nil && tier.EndAge != nil {
-			ageRange = strconv.Itoa(*tier.StartAge) + "-" + strconv.Itoa(*tier.EndAge)
-		} else {
-			ageRange = "all ages"
-		}
+		ageRange := tier.AgeRange()
 
 		var amount string
-		if ic.IsDepletionMode() && tier.Ratio > 0 {
+		if ic.IsDepletionMode() && tier.IsSolved() {
+			amount = "solved"
+			if tier.MinMonthly > 0 {
+				amount += " ≥ £" + formatDefaultMoney(tier.MinMonthly) + "/mo"
+			}
+			if tier.MaxMonthly > 0 {
+				amount += " ≤ £" + formatDefaultMoney(tier.MaxMonthly) + "/mo"
+			}
+		} else if ic.IsDepletionMode() && tier.Ratio > 0 {
 			amount = strconv.FormatFloat(tier.Ratio, 'f', 1, 64) + "x"
 		} else if tier.IsPercentage {
 			amount = strconv.FormatFloat(tier.MonthlyAmount, 'f', 1, 64) + "%"
@@ -346,6 +483,16 @@ type SimulationConfig struct {
 	StartYear       int    `yaml:"start_year" json:"start_year"`
 	EndAge          int    `yaml:"end_age" json:"end_age"`
 	ReferencePerson string `yaml:"reference_person" json:"reference_person"`
+	Concurrency     int    `yaml:"concurrency,omitempty" json:"concurrency,omitempty"` // Strategies simulated at once (0 = one per CPU, 1 = one at a time)
+	CacheDir        string `yaml:"cache_dir,omitempty" json:"cache_dir,omitempty"`     // Directory to save strategy results to for later runs (empty = memory only)
+}
+
+// GetConcurrency returns how many strategies to simulate at once (default: one per CPU)
+func (s *SimulationConfig) GetConcurrency() int {
+	if s.Concurrency > 0 {
+		return s.Concurrency
+	}
+	return runtime.NumCPU()
 }
 
 // SensitivityConfig holds sensitivity analysis parameters
@@ -363,6 +510,77 @@ type StrategyConfig struct {
 	// people's ISA allowances, even if the second person can't access their pension yet.
 	// This is beneficial for couples where one retires earlier. Default: true
 	MaximizeCoupleISA *bool `yaml:"maximize_couple_isa" json:"maximize_couple_isa"`
+
+	// Optimal drawdown: a year-by-year plan found by dynamic programming
+	IncludeOptimal   bool   `yaml:"include_optimal,omitempty" json:"include_optimal,omitempty"`     // Add Optimal strategies to the heuristics compared
+	OptimalObjective string `yaml:"optimal_objective,omitempty" json:"optimal_objective,omitempty"` // "estate" (default) or "tax"
+
+	// Scoring weights used to choose the best strategy (replace the built-in goals when set)
+	Scoring *ScoringWeights `yaml:"scoring,omitempty" json:"scoring,omitempty"`
+
+	// Planned crystallisation tranches, for the tranche crystallisation strategy
+	Tranche *TrancheConfig `yaml:"tranche,omitempty" json:"tranche,omitempty"`
+
+	// Extra factors and rules for the generated strategy combinations
+	Factors     []UserFactorConfig `yaml:"factors,omitempty" json:"factors,omitempty"`         // Config settings to vary, e.g. each person's retirement date
+	Constraints []string           `yaml:"constraints,omitempty" json:"constraints,omitempty"` // Rules such as "if drawdown=pension_only then maximize_couple_isa=off"
+}
+
+// UserFactorConfig declares a strategy factor that sets a config path to each of its values
+type UserFactorConfig struct {
+	ID     string        `yaml:"id" json:"id"`                             // Factor ID, used in constraints
+	Name   string        `yaml:"name,omitempty" json:"name,omitempty"`     // Display name (default: the ID)
+	Path   string        `yaml:"path" json:"path"`                         // Setting to vary, e.g. people.James.retirement_date or people.*.state_pension_defer_years
+	Values []interface{} `yaml:"values" json:"values"`                     // Values to try; the first is the default
+	Labels []string      `yaml:"labels,omitempty" json:"labels,omitempty"` // Short label for each value in strategy names (default: id=value)
+}
+
+// TrancheConfig sizes and times the tranches crystallised by the tranche strategy
+type TrancheConfig struct {
+	Percent float64 `yaml:"percent,omitempty" json:"percent,omitempty"` // Share of the uncrystallised pot in each tranche (0 = enough to fill the ISA allowance)
+	Years   []int   `yaml:"years,omitempty" json:"years,omitempty"`     // Tax years for planned timing, e.g. [2027, 2030]
+}
+
+// ScoringWeights combine the measures of a strategy's outcome into one score (higher is better)
+// Money measures are in pounds, so a weight of 1 counts each £1 as one point
+type ScoringWeights struct {
+	Tax            float64 `yaml:"tax,omitempty" json:"tax,omitempty"`                         // Per £ of lifetime tax (subtracted)
+	Income         float64 `yaml:"income,omitempty" json:"income,omitempty"`                   // Per £ of net income received, in today's money
+	FinalBalance   float64 `yaml:"final_balance,omitempty" json:"final_balance,omitempty"`     // Per £ of final ISA + pension balance, in today's money
+	ISAShare       float64 `yaml:"isa_share,omitempty" json:"isa_share,omitempty"`             // Points for an estate held entirely in ISAs (scaled by the ISA share)
+	MinYearIncome  float64 `yaml:"min_year_income,omitempty" json:"min_year_income,omitempty"` // Per £ of the lowest year's net income, in today's money
+	ShortfallYears float64 `yaml:"shortfall_years,omitempty" json:"shortfall_years,omitempty"` // Points per year spending is not met (subtracted, default: 1e9)
+}
+
+// IsSet returns true if any weight is configured
+func (w *ScoringWeights) IsSet() bool {
+	return w != nil && *w != ScoringWeights{}
+}
+
+// GetScoringWeights returns the configured scoring weights, or the preset for goal if none are set
+func (s *StrategyConfig) GetScoringWeights(goal OptimizationGoal) ScoringWeights {
+	if !s.Scoring.IsSet() {
+		return goal.Weights()
+	}
+	w := *s.Scoring
+	if w.ShortfallYears == 0 {
+		w.ShortfallYears = defaultShortfallYearWeight
+	}
+	return w
+}
+
+// Objectives for the Optimal drawdown plan
+const (
+	OptimalObjectiveEstate = "estate" // Maximise the final ISA + pension balance
+	OptimalObjectiveTax    = "tax"    // Minimise lifetime tax
+)
+
+// GetOptimalObjective returns the objective for the Optimal drawdown plan (default: estate)
+func (s *StrategyConfig) GetOptimalObjective() string {
+	if s.OptimalObjective == OptimalObjectiveTax {
+		return OptimalObjectiveTax
+	}
+	return OptimalObjectiveEstate
 }
 
 // ShouldMaximizeCoupleISA returns whether to maximize ISA transfers for couples (default: true)
@@ -373,6 +591,30 @@ func (s *StrategyConfig) ShouldMaximizeCoupleISA() bool {
 	return *s.MaximizeCoupleISA
 }
 
+// Purposes tax-free cash can be taken for
+const (
+	TaxFreeCashMortgage = "mortgage" // Pay off the mortgage that year
+	TaxFreeCashExpense  = "expense"  // Pay a one-off expense of the same amount that year
+	TaxFreeCashISA      = "isa"      // Save it in the ISA, and in the GIA over the ISA allowance
+)
+
+// TaxFreeCashConfig plans taking tax-free cash from one person's pension for a purpose
+type TaxFreeCashConfig struct {
+	Person      string  `yaml:"person" json:"person"`
+	Year        int     `yaml:"year" json:"year"`                                   // Tax year the cash is taken
+	Purpose     string  `yaml:"purpose" json:"purpose"`                             // mortgage, expense or isa
+	Amount      float64 `yaml:"amount,omitempty" json:"amount,omitempty"`           // Tax-free cash to take (0 = 25% of the whole pot, as a PCLS)
+	Description string  `yaml:"description,omitempty" json:"description,omitempty"` // Shown in reports, e.g. "New car"
+}
+
+// GetTranche returns the tranche settings (default: every tranche fills the ISA allowance)
+func (s *StrategyConfig) GetTranche() TrancheConfig {
+	if s.Tranche == nil {
+		return TrancheConfig{}
+	}
+	return *s.Tranche
+}
+
 // TaxBand represents a tax band from configuration
 type TaxBand struct {
 	Name  string  `yaml:"name" json:"name"`
@@ -390,6 +632,26 @@ type TaxConfig struct {
 	TaperingThreshold float64 `yaml:"tapering_threshold" json:"tapering_threshold"`
 	// TaperingRate is how much allowance is lost per £1 over threshold (2024/25: £0.50, so £1 lost per £2 earned)
 	TaperingRate float64 `yaml:"tapering_rate" json:"tapering_rate"`
+	// LumpSumAllowance is the most tax-free cash each person can take from pensions in their lifetime (£268,275)
+	LumpSumAllowance float64 `yaml:"lump_sum_allowance,omitempty" json:"lump_sum_allowance,omitempty"`
+	// GIATaxRate is the tax charged on growth in a GIA each year, standing in for savings, dividend and capital gains tax (default 20%)
+	GIATaxRate float64 `yaml:"gia_tax_rate,omitempty" json:"gia_tax_rate,omitempty"`
+}
+
+// GetGIATaxRate returns the tax rate on GIA growth, using default if not set
+func (tc *TaxConfig) GetGIATaxRate() float64 {
+	if tc.GIATaxRate <= 0 {
+		return 0.20 // Basic rate on savings income
+	}
+	return tc.GIATaxRate
+}
+
+// GetLumpSumAllowance returns the lump sum allowance, using default if not set
+func (tc *TaxConfig) GetLumpSumAllowance() float64 {
+	if tc.LumpSumAllowance <= 0 {
+		return 268275.0 // Since April 2024
+	}
+	return tc.LumpSumAllowance
 }
 
 // GetPersonalAllowance returns the personal allowance, using default if not set
@@ -436,8 +698,65 @@ func DefaultTaxConfig() TaxConfig {
 	}
 }
 
+// Home sale rules for funding care
+const (
+	HomeSaleNever       = "never"         // Home is never sold
+	HomeSaleFirstInCare = "first_in_care" // Sell when the first person enters care
+	HomeSaleAllInCare   = "all_in_care"   // Sell once nobody is left living in the home
+)
+
+// CareConfig holds later-life care cost and funding settings
+// Care costs are in today's money and inflate at their own rate from the simulation start year
+type CareConfig struct {
+	AnnualCost           float64  `yaml:"annual_cost" json:"annual_cost"`                           // Annual care cost per person in care (default £60,000)
+	InflationRate        *float64 `yaml:"inflation_rate,omitempty" json:"inflation_rate,omitempty"` // Care cost inflation, may be 0 or negative (not set = income_inflation_rate)
+	DurationYears        int      `yaml:"duration_years" json:"duration_years"`                     // Length of a care episode in years (default 5)
+	HomeValue            float64  `yaml:"home_value" json:"home_value"`                             // Current home value (today's money)
+	HomeGrowthRate       float64  `yaml:"home_growth_rate" json:"home_growth_rate"`                 // Annual house price growth
+	HomeSale             string   `yaml:"home_sale" json:"home_sale"`                               // "never" (default), "first_in_care" or "all_in_care"
+	HomeSaleCosts        float64  `yaml:"home_sale_costs" json:"home_sale_costs"`                   // Selling costs as a fraction of value (e.g., 0.02 = 2%)
+	SelfFundingThreshold float64  `yaml:"self_funding_threshold" json:"self_funding_threshold"`     // Upper capital limit for council support (England: £23,250, 0 = always self-fund)
+	LowerCapitalLimit    float64  `yaml:"lower_capital_limit" json:"lower_capital_limit"`           // Capital below this is ignored by the means test (England: £14,250)
+}
+
+// GetAnnualCost returns the annual care cost, using default if not set
+func (cc *CareConfig) GetAnnualCost() float64 {
+	if cc.AnnualCost <= 0 {
+		return 60000.0 // Typical UK residential care with nursing
+	}
+	return cc.AnnualCost
+}
+
+// GetInflationRate returns the care inflation rate, falling back to income inflation if not set
+// A rate of 0 or below is kept, for flat or falling care costs
+func (cc *CareConfig) GetInflationRate(incomeInflation float64) float64 {
+	if cc.InflationRate == nil {
+		return incomeInflation
+	}
+	return *cc.InflationRate
+}
+
+// GetDurationYears returns the length of a care episode, using default if not set
+func (cc *CareConfig) GetDurationYears() int {
+	if cc.DurationYears <= 0 {
+		return 5
+	}
+	return cc.DurationYears
+}
+
+// GetHomeSale returns the home sale rule, using default if not set
+func (cc *CareConfig) GetHomeSale() string {
+	switch cc.HomeSale {
+	case HomeSaleFirstInCare, HomeSaleAllInCare:
+		return cc.HomeSale
+	default:
+		return HomeSaleNever
+	}
+}
+
 // Config holds the complete configuration
 type Config struct {
+	SchemaVersion      int               `yaml:"schema_version,omitempty" json:"schema_version,omitempty"` // Config format version (see CurrentSchemaVersion)
 	People             []PersonConfig    `yaml:"people" json:"people"`
 	Financial          FinancialConfig   `yaml:"financial" json:"financial"`
 	IncomeRequirements IncomeConfig      `yaml:"income_requirements" json:"income_requirements"`
@@ -447,27 +766,41 @@ type Config struct {
 	Strategy           StrategyConfig    `yaml:"strategy" json:"strategy"`
 	TaxBands           []TaxBand         `yaml:"tax_bands" json:"tax_bands"`
 	Tax                TaxConfig         `yaml:"tax" json:"tax"`
+	Care               CareConfig        `yaml:"care" json:"care"`
+	TaxFreeCash        []TaxFreeCashConfig `yaml:"tax_free_cash,omitempty" json:"tax_free_cash,omitempty"` // Planned uses of tax-free cash
+
+	Control *RunControl  `yaml:"-" json:"-"` // Progress and cancellation of the current run (not saved)
+	Cache   *ResultCache `yaml:"-" json:"-"` // Strategy results already calculated (not saved)
+
+	Migrated []string `yaml:"-" json:"-"` // Changes made upgrading an older config when it was loaded (not saved)
 }
 
 // LoadConfig loads configuration from a YAML file
+// It handles percentage format (e.g., "5%" -> 0.05)
 func LoadConfig(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
 
-	var config Config
-	err = yaml.Unmarshal(data, &config)
-	if err != nil {
-		return nil, err
+	config, errs := ParseConfig([]byte(preprocessPercentages(string(data))))
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // SaveConfig saves configuration to a YAML file
+// Legacy settings are migrated, so the file is always written at CurrentSchemaVersion
 func SaveConfig(config *Config, filename string) error {
-	data, err := yaml.Marshal(config)
+	saved := *config
+	saved.SchemaVersion = 0 // Run every migration, in case legacy settings were set in code
+	data, err := yaml.Marshal(&saved)
+	if err != nil {
+		return err
+	}
+	data, _, _, err = MigrateConfigData(data)
 	if err != nil {
 		return err
 	}
@@ -483,23 +816,21 @@ func SaveConfig(config *Config, filename string) error {
 # FIXED INCOME MODE (default):
 #   You specify monthly income needs, simulator shows how long funds last.
 #   Key settings:
-#     income_requirements.monthly_before_age: £/month before age threshold
-#     income_requirements.monthly_after_age:  £/month after age threshold
+#     income_requirements.tiers: monthly_amount (£/month) for each age range
 #   Question answered: "Can I afford £X/month? How long will funds last?"
 #
 # DEPLETION MODE (-depletion flag):
 #   You specify target age, simulator calculates maximum sustainable income.
 #   Key settings:
 #     income_requirements.target_depletion_age: Age to deplete funds by
-#     income_requirements.income_ratio_phase1:  Ratio before threshold (e.g., 5)
-#     income_requirements.income_ratio_phase2:  Ratio after threshold (e.g., 3)
+#     income_requirements.tiers: ratio for each age range (e.g., 5 then 3)
 #   Question answered: "How much can I spend to last until age X?"
-#   Note: Ratio 5:3 means income before threshold is 5/3 times income after.
+#   Note: Ratios 5 then 3 mean income in the first tier is 5/3 times income in the second.
 #
 # ═══════════════════════════════════════════════════════════════════════════════
 # VALUE FORMATS
 # ═══════════════════════════════════════════════════════════════════════════════
-#   Percentages: 0.05 = 5% (enter as decimal)
+#   Percentages: 0.05 or 5% (unquoted)
 #   Money: values are in GBP (e.g., 500000 = £500k)
 #   Dates: YYYY-MM-DD format (e.g., 1975-06-15)
 #
@@ -527,20 +858,19 @@ func LoadDefaultConfig() (*Config, error) {
 	// Use embedded default config (compiled into binary)
 	content := preprocessPercentages(defaultConfigYAML)
 
-	var config Config
-	err := yaml.Unmarshal([]byte(content), &config)
-	if err != nil {
-		return nil, err
+	config, errs := ParseConfig([]byte(content))
+	if len(errs) > 0 {
+		return nil, errs
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // preprocessPercentages converts percentage values like "5%" to decimal "0.05"
 func preprocessPercentages(content string) string {
-	// Match patterns like: key: 5% or key: 3.89%
+	// Match patterns like: key: 5%, key: 3.89% or key: -1%
 	// But not inside strings (already quoted)
-	re := regexp.MustCompile(`(:\s*)(\d+\.?\d*)%`)
+	re := regexp.MustCompile(`(:\s*)(-?\d+\.?\d*)%`)
 	return re.ReplaceAllStringFunc(content, func(match string) string {
 		// Extract the number before %
 		parts := re.FindStringSubmatch(match)
@@ -573,13 +903,14 @@ func GetDefaultValue(fieldPath string, defaultConfig *Config) string {
 		}
 	case "person.retirement_age":
 		if len(defaultConfig.People) > 0 {
-			return strconv.Itoa(defaultConfig.People[0].RetirementAge)
+			_, age := defaultConfig.People[0].GetRetirementInfo()
+			return strconv.Itoa(age)
 		}
 	case "person.pension_access_age":
 		if len(defaultConfig.People) > 0 {
 			age := defaultConfig.People[0].PensionAccessAge
 			if age <= 0 {
-				age = defaultConfig.People[0].RetirementAge
+				_, age = defaultConfig.People[0].GetRetirementInfo()
 			}
 			return strconv.Itoa(age)
 		}
@@ -607,13 +938,14 @@ func GetDefaultValue(fieldPath string, defaultConfig *Config) string {
 		}
 	case "person2.retirement_age":
 		if len(defaultConfig.People) > 1 {
-			return strconv.Itoa(defaultConfig.People[1].RetirementAge)
+			_, age := defaultConfig.People[1].GetRetirementInfo()
+			return strconv.Itoa(age)
 		}
 	case "person2.pension_access_age":
 		if len(defaultConfig.People) > 1 {
 			age := defaultConfig.People[1].PensionAccessAge
 			if age <= 0 {
-				age = defaultConfig.People[1].RetirementAge
+				_, age = defaultConfig.People[1].GetRetirementInfo()
 			}
 			return strconv.Itoa(age)
 		}
@@ -794,6 +1126,28 @@ func (p *PersonConfig) GetRetirementInfo() (int, int) {
 	return taxYear, p.RetirementAge
 }
 
+// GetAnnualWorkIncome returns the effective annual work income, as Person.GetAnnualWorkIncome does
+func (p *PersonConfig) GetAnnualWorkIncome() float64 {
+	if p.WorkIncomeNet > 0 {
+		return p.WorkIncomeNet * 12
+	}
+	return p.WorkIncome
+}
+
+// Extra saving wrappers
+const (
+	SavingWrapperISA     = "isa"
+	SavingWrapperPension = "pension"
+)
+
+// GetExtraSavingWrapper returns the wrapper extra saving goes into, using default if not set
+func (p *PersonConfig) GetExtraSavingWrapper() string {
+	if p.ExtraSavingWrapper == SavingWrapperPension {
+		return SavingWrapperPension
+	}
+	return SavingWrapperISA
+}
+
 // FindPerson finds a person by name in the config
 func (c *Config) FindPerson(name string) *PersonConfig {
 	for i := range c.People {
@@ -814,6 +1168,16 @@ func (c *Config) GetSimulationReferencePerson() *PersonConfig {
 	return c.FindPerson(c.Simulation.ReferencePerson)
 }
 
+// HasCareCosts returns true if any person has a care start age configured
+func (c *Config) HasCareCosts() bool {
+	for _, p := range c.People {
+		if p.CareStartAge > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // GetGrowthDeclineReferencePerson returns the reference person for growth decline
 // Defaults to simulation reference person if not specified
 func (c *Config) GetGrowthDeclineReferencePerson() *PersonConfig {
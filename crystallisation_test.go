@@ -83,7 +83,7 @@ func TestTakePCLSLumpSum_BasicSplit(t *testing.T) {
 
 			// Check person's state updated correctly
 			assertCrystalEquals(t, 0, person.UncrystallisedPot, "Uncrystallised pot should be empty")
-			assertCrystalEquals(t, tc.expectedTaxFree, person.TaxFreeSavings, "ISA should have tax-free amount")
+			assertCrystalEquals(t, tc.expectedTaxFree, person.Savings(), "ISA should have tax-free amount")
 			assertCrystalEquals(t, tc.expectedTaxable, person.CrystallisedPot, "Crystallised pot should have taxable amount")
 
 			// Verify 25/75 split mathematically
@@ -106,7 +106,7 @@ func TestTakePCLSLumpSum_AddToExistingISA(t *testing.T) {
 
 	// ISA should now have original 50k + 25k (25% of 100k)
 	expectedISA := 75000.0
-	assertCrystalEquals(t, expectedISA, person.TaxFreeSavings, "ISA should include existing balance + tax-free")
+	assertCrystalEquals(t, expectedISA, person.Savings(), "ISA should include existing balance + tax-free")
 }
 
 func TestTakePCLSLumpSum_ZeroPot(t *testing.T) {
@@ -136,7 +136,7 @@ func TestTakePCLSLumpSum_PreservesTotal(t *testing.T) {
 
 	TakePCLSLumpSum(person)
 
-	totalAfter := person.UncrystallisedPot + person.TaxFreeSavings + person.CrystallisedPot
+	totalAfter := person.UncrystallisedPot + person.Savings() + person.CrystallisedPot
 
 	if math.Abs(totalAfter-initialPot) > 0.01 {
 		t.Errorf("Total assets should be preserved. Before: £%.2f, After: £%.2f",
@@ -230,7 +230,7 @@ func TestWithdrawFromISA(t *testing.T) {
 	withdrawn := WithdrawFromISA(person, 20000)
 
 	assertCrystalEquals(t, 20000, withdrawn, "Should withdraw requested amount")
-	assertCrystalEquals(t, 30000, person.TaxFreeSavings, "ISA should have remainder")
+	assertCrystalEquals(t, 30000, person.Savings(), "ISA should have remainder")
 }
 
 func TestWithdrawFromISA_ExceedsBalance(t *testing.T) {
@@ -242,7 +242,7 @@ func TestWithdrawFromISA_ExceedsBalance(t *testing.T) {
 	withdrawn := WithdrawFromISA(person, 50000)
 
 	assertCrystalEquals(t, 20000, withdrawn, "Should only withdraw available")
-	assertCrystalEquals(t, 0, person.TaxFreeSavings, "ISA should be empty")
+	assertCrystalEquals(t, 0, person.Savings(), "ISA should be empty")
 }
 
 func TestWithdrawFromISA_ZeroBalance(t *testing.T) {
@@ -311,7 +311,7 @@ func TestScenario_RetirementCrystallisation(t *testing.T) {
 	assertCrystalEquals(t, 300000, result.TaxablePortion, "Taxable portion")
 
 	// Total ISA should now be £150k (original £50k + £100k tax-free)
-	assertCrystalEquals(t, 150000, person.TaxFreeSavings, "Total ISA balance")
+	assertCrystalEquals(t, 150000, person.Savings(), "Total ISA balance")
 
 	// Crystallised pot should be £300k
 	assertCrystalEquals(t, 300000, person.CrystallisedPot, "Crystallised pot")
@@ -399,11 +399,11 @@ func TestCrystallisationInvariant_TotalPreserved(t *testing.T) {
 			CrystallisedPot:   tc.existingCryst,
 		}
 
-		totalBefore := person.UncrystallisedPot + person.TaxFreeSavings + person.CrystallisedPot
+		totalBefore := person.UncrystallisedPot + person.Savings() + person.CrystallisedPot
 
 		TakePCLSLumpSum(person)
 
-		totalAfter := person.UncrystallisedPot + person.TaxFreeSavings + person.CrystallisedPot
+		totalAfter := person.UncrystallisedPot + person.Savings() + person.CrystallisedPot
 
 		if math.Abs(totalAfter-totalBefore) > 0.01 {
 			t.Errorf("Assets not preserved. Before: £%.2f, After: £%.2f", totalBefore, totalAfter)
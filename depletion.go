@@ -11,17 +11,53 @@ type DepletionResult struct {
 	MonthlyBeforeAge      float64          // Calculated monthly income before age threshold
 	MonthlyAfterAge       float64          // Calculated monthly income after age threshold
 	SimulationResult      SimulationResult // Full simulation result with calculated income
-	ConvergenceError      float64          // Remaining balance at target age (ideally close to 0)
+	ConvergenceError      float64          // Remaining balance (or surplus over the terminal target) at target age (ideally close to 0)
+	TerminalTarget        TerminalTarget   // Nominal balances to leave at target age (zero = deplete fully)
+	LegacyCostPer100k     float64          // Monthly income (before age) given up for each extra £100k of legacy
+	TierAmounts           []float64        // Monthly amount (today's money) for each income tier, tiered configs only
+	MinimumsUnmet         bool             // Tier min_monthly amounts can't all be paid to the target age
+}
+
+// legacyCostStep is the extra legacy used to measure how much income a legacy costs
+const legacyCostStep = 100000.0
+
+// TerminalTarget holds the nominal balances depletion mode should leave at the target age
+type TerminalTarget struct {
+	Total   float64 // Total ISA + pension
+	ISA     float64 // Minimum ISA balance
+	Pension float64 // Minimum pension balance
+}
+
+// GetTerminalTarget returns the terminal target in money of the target year
+// Targets in today's money are inflated from the simulation start year
+func (c *Config) GetTerminalTarget(targetYear int) TerminalTarget {
+	ic := c.IncomeRequirements
+	inflation := 1.0
+	if ic.TargetLegacyReal && targetYear > c.Simulation.StartYear {
+		inflation = math.Pow(1+c.Financial.IncomeInflationRate, float64(targetYear-c.Simulation.StartYear))
+	}
+	return TerminalTarget{
+		Total:   ic.TargetLegacy * inflation,
+		ISA:     ic.TargetISAFloor * inflation,
+		Pension: ic.TargetPensionFloor * inflation,
+	}
 }
 
 // CalculateDepletionIncome uses binary search to find the sustainable income
-// that depletes funds at exactly the target age
+// that depletes funds at exactly the target age, or leaves the terminal target if one is set
 func CalculateDepletionIncome(params SimulationParams, config *Config) DepletionResult {
 	ic := config.IncomeRequirements
 
 	// Get reference person to calculate target year
 	refPerson := config.GetSimulationReferencePerson()
 	targetYear := GetBirthYear(refPerson.BirthDate) + ic.TargetDepletionAge
+	hasTarget := ic.HasTerminalTarget()
+	target := config.GetTerminalTarget(targetYear)
+
+	// Tiers solved independently need one unknown per tier
+	if ic.HasSolvedTiers() {
+		return calculateMultiTierDepletion(params, config, targetYear, target)
+	}
 
 	// Binary search bounds for multiplier
 	// The multiplier is applied to the ratio (e.g., multiplier 1000 with ratio 5:3 = 5000:3000/month)
@@ -44,28 +80,21 @@ func CalculateDepletionIncome(params SimulationParams, config *Config) Depletion
 		// Run simulation
 		result := RunSimulation(params, testConfig)
 
-		// Find the balance at end of target age year
+		// Find the balance at end of target age year (or the surplus over the terminal target)
 		balanceAtTarget := getBalanceAtYear(result, targetYear)
+		if hasTarget {
+			balanceAtTarget = getSurplusOverTarget(result, targetYear, target)
+		}
 
 		// Store best result so far (one with lowest absolute balance at target)
 		// Calculate display values based on tiered vs legacy config
 		var beforeAge, afterAge float64
+		var tierAmounts []float64
 		if ic.HasTiers() {
-			// For tiered config, get first and last tier values using multiplier
-			if len(ic.Tiers) > 0 {
-				firstTier := ic.Tiers[0]
-				if firstTier.Ratio > 0 {
-					beforeAge = firstTier.Ratio * midMultiplier
-				} else {
-					beforeAge = firstTier.MonthlyAmount
-				}
-				lastTier := ic.Tiers[len(ic.Tiers)-1]
-				if lastTier.Ratio > 0 {
-					afterAge = lastTier.Ratio * midMultiplier
-				} else {
-					afterAge = lastTier.MonthlyAmount
-				}
-			}
+			// For tiered config, use the first and last tier values
+			tierAmounts = depletionTierAmounts(ic, midMultiplier, nil)
+			beforeAge = tierAmounts[0]
+			afterAge = tierAmounts[len(tierAmounts)-1]
 		} else {
 			beforeAge = ic.IncomeRatioPhase1 * midMultiplier
 			afterAge = ic.IncomeRatioPhase2 * midMultiplier
@@ -78,6 +107,8 @@ func CalculateDepletionIncome(params SimulationParams, config *Config) Depletion
 			MonthlyAfterAge:       afterAge,
 			SimulationResult:      result,
 			ConvergenceError:      balanceAtTarget,
+			TerminalTarget:        target,
+			TierAmounts:           tierAmounts,
 		}
 
 		// Keep track of result closest to zero
@@ -170,10 +201,12 @@ func cloneConfigWithMultiplier(config *Config, multiplier float64) *Config {
 	// Disable depletion mode in the cloned config to use fixed mode in simulation
 	newConfig.IncomeRequirements.TargetDepletionAge = 0
 
-	// Disable Guardrails which would override the multiplier-based income
+	// Disable Guardrails and other withdrawal rules which would override the multiplier-based income
 	// This feature dynamically adjusts withdrawals based on portfolio performance,
 	// which interferes with the binary search for sustainable income
 	newConfig.IncomeRequirements.GuardrailsEnabled = false
+	newConfig.IncomeRequirements.VPWEnabled = false
+	newConfig.IncomeRequirements.WithdrawalRule = ""
 
 	// Income inflation is now enabled in depletion mode for consistency with fixed income mode.
 	// Both modes apply the same income inflation rate to required income over time.
@@ -225,8 +258,59 @@ func getBalanceAtYear(result SimulationResult, targetYear int) float64 {
 	return float64(yearsLate * 10000)
 }
 
+// getSurplusOverTarget returns how far balances at the end of the target year exceed the terminal target
+// Returns the smallest surplus across the total and each wrapper floor (negative = income too high)
+func getSurplusOverTarget(result SimulationResult, targetYear int, target TerminalTarget) float64 {
+	if len(result.Years) == 0 {
+		return 1000000 // Large positive = need more income
+	}
+
+	// Use the target year, or the last simulated year if the simulation ends first
+	yearState := result.Years[len(result.Years)-1]
+	for _, ys := range result.Years {
+		if ys.Year == targetYear {
+			yearState = ys
+			break
+		}
+	}
+
+	isa, pension := 0.0, 0.0
+	for _, bal := range yearState.EndBalances {
+		isa += bal.Savings()
+		pension += bal.UncrystallisedPot + bal.CrystallisedPot
+	}
+	surplus := isa + pension - target.Total
+	if target.ISA > 0 {
+		surplus = math.Min(surplus, isa-target.ISA)
+	}
+	if target.Pension > 0 {
+		surplus = math.Min(surplus, pension-target.Pension)
+	}
+
+	// Running out of money before the target is always a miss, however small the target
+	if result.RanOutOfMoney && result.RanOutYear <= targetYear {
+		surplus = math.Min(surplus, float64(-(targetYear-result.RanOutYear+1)*10000))
+	}
+	return surplus
+}
+
+// CalculateLegacyCost returns the monthly income (before age) given up for an extra £100k of legacy
+// The extra legacy is in the same terms (today's money or nominal) as target_legacy
+func CalculateLegacyCost(params SimulationParams, config *Config, base DepletionResult) float64 {
+	legacyConfig := *config
+	legacyConfig.IncomeRequirements.TargetLegacy += legacyCostStep
+	withLegacy := runCachedDepletion(cacheKindDepletion, params, &legacyConfig, CalculateDepletionIncome)
+	return base.MonthlyBeforeAge - withLegacy.MonthlyBeforeAge
+}
+
 // RunAllDepletionCalculations runs depletion calculation for all strategies
+// and reports how much income each extra £100k of legacy costs
 func RunAllDepletionCalculations(config *Config) []DepletionResult {
+	return runDepletionCalculations(config, true)
+}
+
+// runDepletionCalculations runs depletion calculation for all strategies, with their legacy costs if asked
+func runDepletionCalculations(config *Config, legacyCost bool) []DepletionResult {
 	// Get strategies based on whether there's a mortgage
 	strategies := GetDepletionStrategiesForConfig(config)
 
@@ -236,18 +320,20 @@ func RunAllDepletionCalculations(config *Config) []DepletionResult {
 		strategies[i].MaximizeCoupleISA = maximizeCoupleISA
 	}
 
-	results := make([]DepletionResult, len(strategies))
-	for i, params := range strategies {
-		results[i] = CalculateDepletionIncome(params, config)
-	}
-
-	return results
+	return parallelMap(config.Control, len(strategies), config.Simulation.GetConcurrency(), func(i int) DepletionResult {
+		result := runCachedDepletion(cacheKindDepletion, strategies[i], config, CalculateDepletionIncome)
+		if legacyCost {
+			result.LegacyCostPer100k = CalculateLegacyCost(strategies[i], config, result)
+		}
+		return result
+	})
 }
 
 // FindBestDepletionStrategy returns the index of the best strategy
-// Best = highest sustainable income among strategies that actually converge
-// Convergence = balance at target is between -£5k and £50k (small overshoot or undershoot)
-func FindBestDepletionStrategy(results []DepletionResult) int {
+// Best = highest score up to the target age among strategies that actually converge, using
+// the configured scoring weights or the income goal (highest income, then lowest tax)
+// Convergence = balance at target is between -£20k and £50k (small overshoot or undershoot)
+func FindBestDepletionStrategy(results []DepletionResult, config *Config) int {
 	if len(results) == 0 {
 		return -1
 	}
@@ -256,7 +342,8 @@ func FindBestDepletionStrategy(results []DepletionResult) int {
 	convergedIndices := []int{}
 	for i, r := range results {
 		// Converged if balance is between -£20k (up to 2 years overshoot) and £50k (small undershoot)
-		if r.ConvergenceError >= -20000 && r.ConvergenceError <= 50000 {
+		// and the tier minimums are met
+		if r.ConvergenceError >= -20000 && r.ConvergenceError <= 50000 && !r.MinimumsUnmet {
 			convergedIndices = append(convergedIndices, i)
 		}
 	}
@@ -275,19 +362,14 @@ func FindBestDepletionStrategy(results []DepletionResult) int {
 		return bestIdx
 	}
 
-	// Among converged strategies, select highest income (then lowest tax as tiebreaker)
-	bestIdx := convergedIndices[0]
-	bestIncome := results[bestIdx].MonthlyBeforeAge
-	bestTax := results[bestIdx].SimulationResult.TotalTaxPaid
-
-	for _, i := range convergedIndices[1:] {
-		income := results[i].MonthlyBeforeAge
-		tax := results[i].SimulationResult.TotalTaxPaid
-
-		// Higher income is better; if same income, lower tax is better
-		if income > bestIncome || (income == bestIncome && tax < bestTax) {
-			bestIncome = income
-			bestTax = tax
+	// Among converged strategies, select the highest score
+	weights := config.Strategy.GetScoringWeights(OptimizeIncome)
+	bestIdx := -1
+	bestScore := 0.0
+	for _, i := range convergedIndices {
+		score := weights.ScoreDepletionResult(results[i], config)
+		if bestIdx < 0 || score > bestScore {
+			bestScore = score
 			bestIdx = i
 		}
 	}
@@ -326,11 +408,11 @@ func RunDepletionSensitivityAnalysis(config *Config) DepletionSensitivityAnalysi
 			// Clone config with these growth rates
 			testConfig := cloneConfigForSensitivity(config, pensionGrowth, savingsGrowth)
 
-			// Run all depletion calculations
-			results := RunAllDepletionCalculations(testConfig)
+			// Run all depletion calculations (legacy costs are not needed for the grid)
+			results := runDepletionCalculations(testConfig, false)
 
 			// Find best strategy
-			bestIdx := FindBestDepletionStrategy(results)
+			bestIdx := FindBestDepletionStrategy(results, testConfig)
 			bestIncome := 0.0
 			bestName := ""
 			if bestIdx >= 0 {
@@ -511,12 +593,9 @@ func RunPensionOnlyDepletionCalculations(config *Config) []DepletionResult {
 	// Get strategies based on whether there's a mortgage
 	strategies := GetPensionOnlyStrategiesForConfig(config)
 
-	results := make([]DepletionResult, len(strategies))
-	for i, params := range strategies {
-		results[i] = CalculatePensionOnlyDepletionIncome(params, config)
-	}
-
-	return results
+	return parallelMap(config.Control, len(strategies), config.Simulation.GetConcurrency(), func(i int) DepletionResult {
+		return runCachedDepletion(cacheKindPensionOnly, strategies[i], config, CalculatePensionOnlyDepletionIncome)
+	})
 }
 
 // RunPensionToISADepletionCalculations runs depletion using PensionToISA strategy
@@ -531,12 +610,9 @@ func RunPensionToISADepletionCalculations(config *Config) []DepletionResult {
 		strategies[i].MaximizeCoupleISA = maximizeCoupleISA
 	}
 
-	results := make([]DepletionResult, len(strategies))
-	for i, params := range strategies {
-		results[i] = CalculateDepletionIncome(params, config)
-	}
-
-	return results
+	return parallelMap(config.Control, len(strategies), config.Simulation.GetConcurrency(), func(i int) DepletionResult {
+		return runCachedDepletion(cacheKindDepletion, strategies[i], config, CalculateDepletionIncome)
+	})
 }
 
 // PensionToISASensitivityResult holds results for one growth rate combination with PensionToISA strategy
@@ -575,7 +651,7 @@ func RunPensionToISASensitivityAnalysis(config *Config) PensionToISASensitivityA
 			results := RunPensionToISADepletionCalculations(testConfig)
 
 			// Find best strategy
-			bestIdx := FindBestDepletionStrategy(results)
+			bestIdx := FindBestDepletionStrategy(results, testConfig)
 			bestIncome := 0.0
 			bestName := ""
 			finalISA := 0.0
@@ -586,7 +662,7 @@ func RunPensionToISASensitivityAnalysis(config *Config) PensionToISASensitivityA
 				if len(results[bestIdx].SimulationResult.Years) > 0 {
 					lastYear := results[bestIdx].SimulationResult.Years[len(results[bestIdx].SimulationResult.Years)-1]
 					for _, balances := range lastYear.EndBalances {
-						finalISA += balances.TaxFreeSavings
+						finalISA += balances.Savings()
 					}
 				}
 			}
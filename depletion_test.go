@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// createDepletionTestConfig creates a config for depletion to age 85 with a flat 1:1 income ratio
+func createDepletionTestConfig() *Config {
+	config := createTestConfig()
+	config.Mortgage = MortgageConfig{}
+	config.IncomeRequirements.TargetDepletionAge = 85
+	config.IncomeRequirements.IncomeRatioPhase1 = 1
+	config.IncomeRequirements.IncomeRatioPhase2 = 1
+	return config
+}
+
+// balancesAtYear returns total ISA and pension balances at the end of a year
+func balancesAtYear(result SimulationResult, year int) (isa, pension float64) {
+	for _, ys := range result.Years {
+		if ys.Year != year {
+			continue
+		}
+		for _, bal := range ys.EndBalances {
+			isa += bal.TaxFreeSavings
+			pension += bal.UncrystallisedPot + bal.CrystallisedPot
+		}
+	}
+	return isa, pension
+}
+
+// TestDepletion_TargetLegacy verifies the solver leaves the target legacy at the target age
+func TestDepletion_TargetLegacy(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	config := createDepletionTestConfig()
+	zero := CalculateDepletionIncome(params, config)
+
+	config.IncomeRequirements.TargetLegacy = 200000
+	legacy := CalculateDepletionIncome(params, config)
+	if legacy.MonthlyBeforeAge >= zero.MonthlyBeforeAge {
+		t.Errorf("Expected a legacy to reduce income: zero £%.0f, legacy £%.0f", zero.MonthlyBeforeAge, legacy.MonthlyBeforeAge)
+	}
+
+	targetYear := GetBirthYear(config.People[0].BirthDate) + 85
+	isa, pension := balancesAtYear(legacy.SimulationResult, targetYear)
+	if math.Abs(isa+pension-200000) > 1000 {
+		t.Errorf("Expected £200,000 left at target age, got £%.0f", isa+pension)
+	}
+	if legacy.TerminalTarget.Total != 200000 {
+		t.Errorf("Expected nominal target £200,000, got £%.0f", legacy.TerminalTarget.Total)
+	}
+}
+
+// TestDepletion_TargetLegacyReal verifies targets in today's money are inflated to the target year
+func TestDepletion_TargetLegacyReal(t *testing.T) {
+	config := createDepletionTestConfig()
+	config.IncomeRequirements.TargetLegacy = 100000
+	config.IncomeRequirements.TargetISAFloor = 50000
+	config.IncomeRequirements.TargetLegacyReal = true
+
+	target := config.GetTerminalTarget(2036)
+	inflation := math.Pow(1.03, 10)
+	if math.Abs(target.Total-100000*inflation) > 0.01 || math.Abs(target.ISA-50000*inflation) > 0.01 {
+		t.Errorf("Expected targets inflated by %.4f, got total £%.0f ISA £%.0f", inflation, target.Total, target.ISA)
+	}
+
+	config.IncomeRequirements.TargetLegacyReal = false
+	if target := config.GetTerminalTarget(2036); target.Total != 100000 {
+		t.Errorf("Expected nominal target to be unchanged, got £%.0f", target.Total)
+	}
+}
+
+// TestDepletion_ISAFloor verifies a per-wrapper floor is kept at the target age
+func TestDepletion_ISAFloor(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: PensionFirst}
+
+	config := createDepletionTestConfig()
+	config.IncomeRequirements.TargetISAFloor = 150000
+	result := CalculateDepletionIncome(params, config)
+
+	targetYear := GetBirthYear(config.People[0].BirthDate) + 85
+	isa, _ := balancesAtYear(result.SimulationResult, targetYear)
+	if isa < 150000-1000 {
+		t.Errorf("Expected at least £150,000 in ISAs at target age, got £%.0f", isa)
+	}
+	if math.Abs(result.ConvergenceError) > 1000 {
+		t.Errorf("Expected the solver to converge on the floor, surplus £%.0f", result.ConvergenceError)
+	}
+}
+
+// TestDepletion_LegacyCost verifies every strategy reports the income cost of an extra £100k legacy
+func TestDepletion_LegacyCost(t *testing.T) {
+	config := createDepletionTestConfig()
+	config.IncomeRequirements.TargetLegacy = 100000
+
+	results := RunAllDepletionCalculations(config)
+	if len(results) == 0 {
+		t.Fatal("Expected depletion results")
+	}
+	for _, r := range results {
+		// £100k at 85 costs some income, but far less than £100k spread over the retirement
+		if r.LegacyCostPer100k <= 0 || r.LegacyCostPer100k > 100000/12 {
+			t.Errorf("%s: expected a positive monthly legacy cost, got £%.0f", r.Params.ShortName(), r.LegacyCostPer100k)
+		}
+		if cost := CalculateLegacyCost(r.Params, config, r); math.Abs(cost-r.LegacyCostPer100k) > 0.01 {
+			t.Errorf("%s: expected the same legacy cost solved alone, got £%.0f and £%.0f", r.Params.ShortName(), cost, r.LegacyCostPer100k)
+		}
+	}
+	if grid := runDepletionCalculations(config, false); len(grid) > 0 && grid[0].LegacyCostPer100k != 0 {
+		t.Errorf("Expected no legacy cost when not asked for, got £%.0f", grid[0].LegacyCostPer100k)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// Search bounds for multi-variable depletion when a tier sets no max_monthly
+const (
+	maxSolvedTierMonthly = 250000.0 // £250k/month
+	maxSolvedMultiplier  = 50000.0  // Same ceiling as single-multiplier depletion
+)
+
+// Search tolerances for multi-variable depletion
+const (
+	solvedTierTolerance       = 0.5  // Within 50p/month for a solved tier
+	solvedMultiplierTolerance = 0.01 // Within 0.01 of the shared ratio multiplier
+)
+
+// depletionVariable is one unknown in multi-variable depletion: either a solved tier's
+// monthly amount or the multiplier shared by every ratio tier
+type depletionVariable struct {
+	Tier     int     // Index of the solved tier (-1 = shared ratio multiplier)
+	Priority int     // Lower priorities are maximised first
+	Min      float64 // Lowest value allowed (monthly amount or multiplier)
+	Max      float64 // Highest value searched
+}
+
+// buildDepletionVariables returns the unknowns for multi-variable depletion in solve order
+// Ratio tiers share one multiplier; each solved tier is its own unknown. Ties in priority
+// keep tier order, with the ratio multiplier placed at its first ratio tier.
+func buildDepletionVariables(ic IncomeConfig) []depletionVariable {
+	var vars []depletionVariable
+	ratioIdx := -1
+
+	for i, tier := range ic.Tiers {
+		if tier.IsSolved() {
+			v := depletionVariable{Tier: i, Priority: tier.Priority, Min: tier.MinMonthly, Max: maxSolvedTierMonthly}
+			if tier.MaxMonthly > 0 {
+				v.Max = math.Max(tier.MaxMonthly, tier.MinMonthly)
+			}
+			vars = append(vars, v)
+			continue
+		}
+		if tier.Ratio <= 0 {
+			continue // Fixed tier
+		}
+
+		// Limits on a ratio tier constrain the shared multiplier
+		if ratioIdx < 0 {
+			ratioIdx = len(vars)
+			vars = append(vars, depletionVariable{Tier: -1, Priority: tier.Priority, Max: maxSolvedMultiplier})
+		}
+		v := &vars[ratioIdx]
+		if tier.Priority < v.Priority {
+			v.Priority = tier.Priority
+		}
+		if tier.MinMonthly > 0 {
+			v.Min = math.Max(v.Min, tier.MinMonthly/tier.Ratio)
+		}
+		if tier.MaxMonthly > 0 {
+			v.Max = math.Min(v.Max, tier.MaxMonthly/tier.Ratio)
+		}
+	}
+
+	for i := range vars {
+		vars[i].Max = math.Max(vars[i].Max, vars[i].Min)
+	}
+	sort.SliceStable(vars, func(a, b int) bool { return vars[a].Priority < vars[b].Priority })
+	return vars
+}
+
+// calculateMultiTierDepletion solves depletion with several unknown tiers
+// Every unknown starts at its minimum; each is then raised in priority order as far as the plan
+// allows while earlier unknowns stay fixed, so surplus goes to the highest priority tier first
+// and later tiers only rise once earlier ones reach their max_monthly
+func calculateMultiTierDepletion(params SimulationParams, config *Config, targetYear int, target TerminalTarget) DepletionResult {
+	ic := config.IncomeRequirements
+	hasTarget := ic.HasTerminalTarget()
+	vars := buildDepletionVariables(ic)
+
+	values := make([]float64, len(vars))
+	for i, v := range vars {
+		values[i] = v.Min
+	}
+
+	run := func() (SimulationResult, float64) {
+		result := RunSimulation(params, cloneConfigWithTierValues(config, vars, values))
+		if hasTarget {
+			return result, getSurplusOverTarget(result, targetYear, target)
+		}
+		return result, getBalanceAtYear(result, targetYear)
+	}
+
+	// The minimums must be affordable before any tier can be raised
+	// If they aren't, the result is left at the minimums and flagged
+	result, balance := run()
+	minimumsUnmet := balance < 0
+	if !minimumsUnmet {
+		for i, v := range vars {
+			low, high := values[i], v.Max
+
+			// Check the ceiling first - if it is affordable this unknown is capped
+			values[i] = high
+			if sim, bal := run(); bal >= 0 {
+				result, balance = sim, bal
+				continue
+			}
+
+			tolerance := solvedTierTolerance
+			if v.Tier < 0 {
+				tolerance = solvedMultiplierTolerance
+			}
+			for iter := 0; iter < 100 && high-low > tolerance; iter++ {
+				values[i] = (low + high) / 2
+				if sim, bal := run(); bal >= 0 {
+					// Still have money at target age - can afford more income
+					low = values[i]
+					result, balance = sim, bal
+				} else {
+					high = values[i]
+				}
+			}
+			values[i] = low
+		}
+	}
+
+	multiplier := 0.0
+	solved := make(map[int]float64)
+	for i, v := range vars {
+		if v.Tier < 0 {
+			multiplier = values[i]
+		} else {
+			solved[v.Tier] = values[i]
+		}
+	}
+	tierAmounts := depletionTierAmounts(ic, multiplier, solved)
+
+	return DepletionResult{
+		Params:                params,
+		SustainableMultiplier: multiplier,
+		MonthlyBeforeAge:      tierAmounts[0],
+		MonthlyAfterAge:       tierAmounts[len(tierAmounts)-1],
+		SimulationResult:      result,
+		ConvergenceError:      balance,
+		TerminalTarget:        target,
+		TierAmounts:           tierAmounts,
+		MinimumsUnmet:         minimumsUnmet,
+	}
+}
+
+// cloneConfigWithTierValues creates a copy of config with every unknown tier set to its value
+func cloneConfigWithTierValues(config *Config, vars []depletionVariable, values []float64) *Config {
+	multiplier := 0.0
+	for i, v := range vars {
+		if v.Tier < 0 {
+			multiplier = values[i]
+		}
+	}
+
+	newConfig := cloneConfigWithMultiplier(config, multiplier)
+	for i, v := range vars {
+		if v.Tier >= 0 {
+			newConfig.IncomeRequirements.Tiers[v.Tier].MonthlyAmount = values[i]
+			newConfig.IncomeRequirements.Tiers[v.Tier].Ratio = 0
+		}
+	}
+	return newConfig
+}
+
+// depletionTierAmounts returns the monthly amount of every tier in today's money
+// Solved tiers use their solved amount, ratio tiers the multiplier and fixed tiers their
+// configured amount (a percentage for percentage tiers)
+func depletionTierAmounts(ic IncomeConfig, multiplier float64, solved map[int]float64) []float64 {
+	amounts := make([]float64, len(ic.Tiers))
+	for i, tier := range ic.Tiers {
+		if amount, ok := solved[i]; ok {
+			amounts[i] = amount
+		} else if tier.Ratio > 0 {
+			amounts[i] = tier.Ratio * multiplier
+		} else {
+			amounts[i] = tier.MonthlyAmount
+		}
+	}
+	return amounts
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// createTierDepletionTestConfig creates a depletion config with a solved early tier
+// and a solved later-life tier with a £2,500/month floor
+func createTierDepletionTestConfig() *Config {
+	config := createDepletionTestConfig()
+	age67, age80 := 67, 80
+	config.IncomeRequirements.Tiers = []IncomeTier{
+		{EndAge: &age67, Solve: true, Priority: 1},
+		{StartAge: &age67, EndAge: &age80, Solve: true, MinMonthly: 2500, Priority: 2},
+		{StartAge: &age80, MonthlyAmount: 2000},
+	}
+	return config
+}
+
+// TestBuildDepletionVariables verifies unknowns are ordered by priority and ratio tiers share one
+func TestBuildDepletionVariables(t *testing.T) {
+	age67, age75 := 67, 75
+	ic := IncomeConfig{Tiers: []IncomeTier{
+		{EndAge: &age67, Ratio: 2, MinMonthly: 3000},
+		{StartAge: &age67, EndAge: &age75, Solve: true, MaxMonthly: 4000, Priority: -1},
+		{StartAge: &age75, Ratio: 1, MaxMonthly: 2500},
+	}}
+
+	vars := buildDepletionVariables(ic)
+	if len(vars) != 2 {
+		t.Fatalf("Expected 2 unknowns, got %d", len(vars))
+	}
+	if vars[0].Tier != 1 || vars[0].Max != 4000 {
+		t.Errorf("Expected solved tier 1 first with max £4,000, got tier %d max %.0f", vars[0].Tier, vars[0].Max)
+	}
+	// Ratio limits become multiplier limits: 3000/2 minimum and 2500/1 maximum
+	if vars[1].Tier != -1 || vars[1].Min != 1500 || vars[1].Max != 2500 {
+		t.Errorf("Expected shared multiplier 1500-2500, got tier %d %.0f-%.0f", vars[1].Tier, vars[1].Min, vars[1].Max)
+	}
+}
+
+// TestMultiTierDepletion_Floor verifies the priority tier is maximised while the later tier keeps its floor
+func TestMultiTierDepletion_Floor(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+	config := createTierDepletionTestConfig()
+
+	result := CalculateDepletionIncome(params, config)
+	if len(result.TierAmounts) != 3 {
+		t.Fatalf("Expected an amount for every tier, got %v", result.TierAmounts)
+	}
+	if result.TierAmounts[1] != 2500 || result.TierAmounts[2] != 2000 {
+		t.Errorf("Expected later tiers at £2,500 floor and £2,000 fixed, got %v", result.TierAmounts)
+	}
+	if result.TierAmounts[0] <= 2500 || result.MonthlyBeforeAge != result.TierAmounts[0] {
+		t.Errorf("Expected early income above the floor, got £%.0f", result.TierAmounts[0])
+	}
+	if result.ConvergenceError < 0 || result.ConvergenceError > 20000 {
+		t.Errorf("Expected funds to last to the target age, balance £%.0f", result.ConvergenceError)
+	}
+
+	// Spending more early on runs out before the target age
+	vars := buildDepletionVariables(config.IncomeRequirements)
+	more := RunSimulation(params, cloneConfigWithTierValues(config, vars, []float64{result.TierAmounts[0] + 100, 2500}))
+	targetYear := GetBirthYear(config.People[0].BirthDate) + 85
+	if getBalanceAtYear(more, targetYear) >= 0 {
+		t.Error("Expected £100/month more early income to deplete before the target age")
+	}
+}
+
+// TestMultiTierDepletion_MaxCap verifies surplus flows to the next tier once a tier reaches its cap
+func TestMultiTierDepletion_MaxCap(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+	config := createTierDepletionTestConfig()
+	uncapped := CalculateDepletionIncome(params, config)
+
+	config.IncomeRequirements.Tiers[0].MaxMonthly = math.Floor(uncapped.TierAmounts[0] - 1000)
+	capped := CalculateDepletionIncome(params, config)
+	if capped.TierAmounts[0] != config.IncomeRequirements.Tiers[0].MaxMonthly {
+		t.Errorf("Expected early tier at its £%.0f cap, got £%.0f", config.IncomeRequirements.Tiers[0].MaxMonthly, capped.TierAmounts[0])
+	}
+	if capped.TierAmounts[1] <= 2500 {
+		t.Errorf("Expected the later tier to rise above its floor, got £%.0f", capped.TierAmounts[1])
+	}
+}
+
+// TestMultiTierDepletion_InfeasibleFloor verifies an unaffordable floor is flagged and not recommended
+func TestMultiTierDepletion_InfeasibleFloor(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+	config := createTierDepletionTestConfig()
+	if result := CalculateDepletionIncome(params, config); result.MinimumsUnmet {
+		t.Error("Expected an affordable floor not to be flagged")
+	}
+	config.IncomeRequirements.Tiers[1].MinMonthly = 50000
+
+	result := CalculateDepletionIncome(params, config)
+	if result.ConvergenceError >= 0 || !result.MinimumsUnmet {
+		t.Errorf("Expected a flagged shortfall, got balance £%.0f, flagged %v", result.ConvergenceError, result.MinimumsUnmet)
+	}
+	if result.TierAmounts[0] != 0 || result.TierAmounts[1] != 50000 {
+		t.Errorf("Expected tiers left at their minimums, got %v", result.TierAmounts)
+	}
+}
+
+// TestDepletion_TierAmountsRatio verifies ratio-only tiers report an amount for every tier
+func TestDepletion_TierAmountsRatio(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+	config := createDepletionTestConfig()
+	age67 := 67
+	config.IncomeRequirements.Tiers = []IncomeTier{
+		{EndAge: &age67, Ratio: 5},
+		{StartAge: &age67, Ratio: 3},
+	}
+
+	result := CalculateDepletionIncome(params, config)
+	if len(result.TierAmounts) != 2 {
+		t.Fatalf("Expected 2 tier amounts, got %v", result.TierAmounts)
+	}
+	if math.Abs(result.TierAmounts[0]*3-result.TierAmounts[1]*5) > 0.01 {
+		t.Errorf("Expected amounts in a 5:3 ratio, got %v", result.TierAmounts)
+	}
+}
+
+// TestFindBestDepletionStrategy_MinimumsUnmet verifies a strategy that can't pay the tier minimums isn't recommended
+func TestFindBestDepletionStrategy_MinimumsUnmet(t *testing.T) {
+	config := createTierDepletionTestConfig()
+	results := []DepletionResult{
+		{MonthlyBeforeAge: 5000, ConvergenceError: -5000, MinimumsUnmet: true},
+		{MonthlyBeforeAge: 3000, ConvergenceError: 1000},
+	}
+	if best := FindBestDepletionStrategy(results, config); best != 1 {
+		t.Errorf("Expected the strategy meeting its minimums to be best, got %d", best)
+	}
+}
@@ -23,6 +23,7 @@ func NewFactorRegistry() *FactorRegistry {
 		Values: []FactorValue{
 			{ID: "gradual", Name: "Gradual Crystallisation", ShortName: "Grad", Value: GradualCrystallisation},
 			{ID: "ufpls", Name: "UFPLS", ShortName: "UFPLS", Value: UFPLSStrategy},
+			{ID: "tranche", Name: "Tranches", ShortName: "Tranche", Value: TrancheCrystallisation},
 		},
 		DefaultValueID: "gradual",
 	})
@@ -88,10 +89,13 @@ func NewFactorRegistry() *FactorRegistry {
 	r.Register(&Factor{
 		ID:          FactorGuardrails,
 		Name:        "Guardrails",
-		Description: "Guyton-Klinger dynamic withdrawal adjustments",
+		Description: "Dynamic withdrawal rule that adjusts spending to portfolio performance",
 		Values: []FactorValue{
-			{ID: "off", Name: "Disabled", ShortName: "Off", Value: false},
-			{ID: "on", Name: "Enabled", ShortName: "On", Value: true},
+			{ID: "off", Name: "Disabled", ShortName: "Off", Value: WithdrawalFixed},
+			{ID: "guyton_klinger", Name: "Guyton-Klinger", ShortName: "GK", Value: WithdrawalGuytonKlinger},
+			{ID: "vanguard", Name: "Vanguard Dynamic", ShortName: "Vang", Value: WithdrawalVanguard},
+			{ID: "vpw", Name: "Variable Percentage", ShortName: "VPW", Value: WithdrawalVPW},
+			{ID: "constant_percent", Name: "Constant Percentage", ShortName: "Const%", Value: WithdrawalConstantPercent},
 		},
 		DefaultValueID: "off",
 	})
@@ -109,6 +113,33 @@ func NewFactorRegistry() *FactorRegistry {
 		DefaultValueID: "0y",
 	})
 
+	// Register tranche timing factor
+	r.Register(&Factor{
+		ID:          FactorTrancheTiming,
+		Name:        "Tranche Timing",
+		Description: "When the tranche strategy crystallises part of the pension",
+		Values: []FactorValue{
+			{ID: "annual", Name: "Every Year", ShortName: "T", Value: TrancheAnnual},
+			{ID: "every_3_years", Name: "Every 3 Years", ShortName: "T3y", Value: TrancheEvery3Years},
+			{ID: "at_access", Name: "Once At Access", ShortName: "T1", Value: TrancheAtAccess},
+			{ID: "planned", Name: "Planned Years", ShortName: "TPlan", Value: TranchePlanned},
+		},
+		DefaultValueID: "annual",
+		DependsOn:      []FactorID{FactorCrystallisation},
+	})
+
+	// Register tax-free cash factor (only when tax_free_cash plans uses for it)
+	r.Register(&Factor{
+		ID:          FactorTaxFreeCash,
+		Name:        "Planned Tax-Free Cash",
+		Description: "Take the tax-free cash planned in tax_free_cash, or keep it in the pension",
+		Values: []FactorValue{
+			{ID: "take", Name: "Take As Planned", ShortName: "Take", Value: false},
+			{ID: "keep", Name: "Keep In Pension", ShortName: "KeepTFC", Value: true},
+		},
+		DefaultValueID: "take",
+	})
+
 	return r
 }
 
@@ -142,6 +173,17 @@ func (r *FactorRegistry) GetApplicableFactors(config *Config) []*Factor {
 			if f.ID == FactorMortgage {
 				f = r.filterMortgageFactorByConfig(f, config)
 			}
+			// Planned tranches need their years in the config
+			if f.ID == FactorTrancheTiming && len(config.Strategy.GetTranche().Years) == 0 {
+				f = &Factor{
+					ID:             f.ID,
+					Name:           f.Name,
+					Description:    f.Description,
+					DefaultValueID: f.DefaultValueID,
+					Values:         filterValues(f.Values, []string{"annual", "every_3_years", "at_access"}),
+					DependsOn:      f.DependsOn,
+				}
+			}
 			result = append(result, f)
 		}
 	}
@@ -196,7 +238,7 @@ func (r *FactorRegistry) isFactorApplicable(f *Factor, config *Config) bool {
 	case FactorISAToSIPP:
 		// Only applicable if someone has work income
 		for _, p := range config.People {
-			if p.WorkIncome > 0 {
+			if p.GetAnnualWorkIncome() > 0 {
 				return true
 			}
 		}
@@ -204,6 +246,9 @@ func (r *FactorRegistry) isFactorApplicable(f *Factor, config *Config) bool {
 	case FactorMaximizeCoupleISA:
 		// Only applicable for couples
 		return len(config.People) >= 2
+	case FactorTaxFreeCash:
+		// Only applicable if tax-free cash is planned
+		return len(config.TaxFreeCash) > 0
 	default:
 		return true
 	}
@@ -226,6 +271,14 @@ func (r *FactorRegistry) GetFactorsByMode(config *Config, mode PermutationMode)
 
 // filterFactorByMode limits which values are used based on mode
 func (r *FactorRegistry) filterFactorByMode(f *Factor, mode PermutationMode) *Factor {
+	if f.UserDefined {
+		// The user asked for every value
+		return f
+	}
+	if f.Person != "" && mode != ModeAdaptive {
+		// Per-person choices multiply the space too far to run every combination
+		return nil
+	}
 	switch mode {
 	case ModeQuick:
 		// Only essential factors with default values
@@ -236,8 +289,8 @@ func (r *FactorRegistry) filterFactorByMode(f *Factor, mode PermutationMode) *Fa
 	case ModeThorough:
 		// More factors but not exhaustive - targets ~200-300 combinations
 		return r.limitToThoroughValues(f)
-	case ModeComprehensive:
-		// All factors, all values
+	case ModeComprehensive, ModeAdaptive:
+		// All factors, all values (adaptive mode searches them rather than running them all)
 		return f
 	default:
 		return f
@@ -278,6 +331,9 @@ func (r *FactorRegistry) limitToEssentialFactors(f *Factor) *Factor {
 			DefaultValueID: f.DefaultValueID,
 			Values:         filterValues(f.Values, []string{"normal", "early"}),
 		}
+	case FactorTaxFreeCash:
+		// The user planned the cash, so always compare taking it with keeping it
+		return f
 	default:
 		// Other factors not included in Quick mode
 		return nil
@@ -289,8 +345,14 @@ func (r *FactorRegistry) limitToEssentialFactors(f *Factor) *Factor {
 func (r *FactorRegistry) limitToCommonValues(f *Factor) *Factor {
 	switch f.ID {
 	case FactorCrystallisation:
-		// Both crystallisation options in Standard
-		return f
+		// Gradual and UFPLS in Standard (tranches only in Comprehensive)
+		return &Factor{
+			ID:             f.ID,
+			Name:           f.Name,
+			Description:    f.Description,
+			DefaultValueID: f.DefaultValueID,
+			Values:         filterValues(f.Values, []string{"gradual", "ufpls"}),
+		}
 	case FactorDrawdown:
 		// Include most drawdown orders except proactive variants
 		return &Factor{
@@ -307,8 +369,14 @@ func (r *FactorRegistry) limitToCommonValues(f *Factor) *Factor {
 		// All mortgage options in Standard
 		return f
 	case FactorGuardrails:
-		// Include guardrails in Standard
-		return f
+		// Only off and Guyton-Klinger in Standard
+		return &Factor{
+			ID:             f.ID,
+			Name:           f.Name,
+			Description:    f.Description,
+			DefaultValueID: f.DefaultValueID,
+			Values:         filterValues(f.Values, []string{"off", "guyton_klinger"}),
+		}
 	case FactorISAToSIPP:
 		// Only include ISAToSIPP off in Standard (enabled only in Comprehensive)
 		return &Factor{
@@ -330,6 +398,9 @@ func (r *FactorRegistry) limitToCommonValues(f *Factor) *Factor {
 			DefaultValueID: f.DefaultValueID,
 			Values:         filterValues(f.Values, []string{"0y"}),
 		}
+	case FactorTrancheTiming:
+		// No tranches in Standard
+		return nil
 	default:
 		return f
 	}
@@ -340,8 +411,14 @@ func (r *FactorRegistry) limitToCommonValues(f *Factor) *Factor {
 func (r *FactorRegistry) limitToThoroughValues(f *Factor) *Factor {
 	switch f.ID {
 	case FactorCrystallisation:
-		// Both crystallisation options
-		return f
+		// Gradual and UFPLS (tranches only in Comprehensive)
+		return &Factor{
+			ID:             f.ID,
+			Name:           f.Name,
+			Description:    f.Description,
+			DefaultValueID: f.DefaultValueID,
+			Values:         filterValues(f.Values, []string{"gradual", "ufpls"}),
+		}
 	case FactorDrawdown:
 		// Include all main drawdown orders (excluding proactive)
 		return &Factor{
@@ -358,8 +435,14 @@ func (r *FactorRegistry) limitToThoroughValues(f *Factor) *Factor {
 		// All mortgage options
 		return f
 	case FactorGuardrails:
-		// Include guardrails
-		return f
+		// Guardrails and VPW (Vanguard and constant percentage only in Comprehensive)
+		return &Factor{
+			ID:             f.ID,
+			Name:           f.Name,
+			Description:    f.Description,
+			DefaultValueID: f.DefaultValueID,
+			Values:         filterValues(f.Values, []string{"off", "guyton_klinger", "vpw"}),
+		}
 	case FactorISAToSIPP:
 		// Include ISA to SIPP in Thorough
 		return f
@@ -375,6 +458,9 @@ func (r *FactorRegistry) limitToThoroughValues(f *Factor) *Factor {
 			DefaultValueID: f.DefaultValueID,
 			Values:         filterValues(f.Values, []string{"0y", "2y"}),
 		}
+	case FactorTrancheTiming:
+		// No tranches in Thorough
+		return nil
 	default:
 		return f
 	}
@@ -8,7 +8,7 @@ import (
 func TestFactorRegistryCreation(t *testing.T) {
 	registry := NewFactorRegistry()
 
-	// Verify all 7 factors are registered
+	// Verify all 9 factors are registered
 	expectedFactors := []FactorID{
 		FactorCrystallisation,
 		FactorDrawdown,
@@ -17,6 +17,8 @@ func TestFactorRegistryCreation(t *testing.T) {
 		FactorISAToSIPP,
 		FactorGuardrails,
 		FactorStatePensionDefer,
+		FactorTrancheTiming,
+		FactorTaxFreeCash,
 	}
 
 	for _, factorID := range expectedFactors {
@@ -28,8 +30,8 @@ func TestFactorRegistryCreation(t *testing.T) {
 
 	// Verify total count
 	allFactors := registry.GetAll()
-	if len(allFactors) != 7 {
-		t.Errorf("Expected 7 factors, got %d", len(allFactors))
+	if len(allFactors) != 9 {
+		t.Errorf("Expected 9 factors, got %d", len(allFactors))
 	}
 }
 
@@ -55,7 +55,7 @@ func TestApplyGrowth_SingleYear(t *testing.T) {
 				UncrystallisedPot: tc.initial,
 			}
 
-			ApplyGrowth(person, tc.rate, tc.rate)
+			ApplyGrowth(person, tc.rate, tc.rate, 0.20)
 
 			assertGrowthEquals(t, tc.expected, person.TaxFreeSavings, "ISA")
 			assertGrowthEquals(t, tc.expected, person.CrystallisedPot, "Crystallised")
@@ -123,7 +123,7 @@ func TestCompoundGrowth_MultiYear(t *testing.T) {
 
 			// Apply growth for n years
 			for i := 0; i < tc.years; i++ {
-				ApplyGrowth(person, tc.rate, tc.rate)
+				ApplyGrowth(person, tc.rate, tc.rate, 0.20)
 			}
 
 			assertGrowthEquals(t, tc.expected, person.TaxFreeSavings, tc.description)
@@ -205,7 +205,7 @@ func TestApplyGrowth_DifferentRates(t *testing.T) {
 	savingsRate := 0.04 // 4% for ISA
 	pensionRate := 0.06 // 6% for pension
 
-	ApplyGrowth(person, savingsRate, pensionRate)
+	ApplyGrowth(person, savingsRate, pensionRate, 0.20)
 
 	// ISA should grow at 4%
 	assertGrowthEquals(t, 104000, person.TaxFreeSavings, "ISA @ 4%")
@@ -226,7 +226,7 @@ func TestApplyGrowth_ZeroBalance(t *testing.T) {
 		UncrystallisedPot: 0,
 	}
 
-	ApplyGrowth(person, 0.05, 0.05)
+	ApplyGrowth(person, 0.05, 0.05, 0.20)
 
 	if person.TaxFreeSavings != 0 {
 		t.Errorf("Zero balance should remain zero, got £%.2f", person.TaxFreeSavings)
@@ -239,7 +239,7 @@ func TestApplyGrowth_NegativeRate(t *testing.T) {
 		TaxFreeSavings: 100000,
 	}
 
-	ApplyGrowth(person, -0.10, -0.10) // -10% loss
+	ApplyGrowth(person, -0.10, -0.10, 0.20) // -10% loss
 
 	assertGrowthEquals(t, 90000, person.TaxFreeSavings, "10% market loss")
 }
@@ -259,7 +259,7 @@ func TestRealWorldScenario_PensionGrowthToRetirement(t *testing.T) {
 
 	// 15 years of growth at 5%
 	for i := 0; i < 15; i++ {
-		ApplyGrowth(person, 0.05, 0.05)
+		ApplyGrowth(person, 0.05, 0.05, 0.20)
 	}
 
 	expected := 200000 * math.Pow(1.05, 15) // 415786.27
@@ -279,7 +279,7 @@ func TestRealWorldScenario_CoupleRetirementSavings(t *testing.T) {
 	}
 
 	for i := 0; i < 12; i++ {
-		ApplyGrowth(person, 0.04, 0.04)
+		ApplyGrowth(person, 0.04, 0.04, 0.20)
 	}
 
 	expected := 1000000 * math.Pow(1.04, 12) // 1601032.22
@@ -301,7 +301,7 @@ func TestGrowthInvariant_PositiveRateIncreasesValue(t *testing.T) {
 	for _, initial := range testCases {
 		for _, rate := range rates {
 			person := &Person{TaxFreeSavings: initial}
-			ApplyGrowth(person, rate, rate)
+			ApplyGrowth(person, rate, rate, 0.20)
 
 			if person.TaxFreeSavings <= initial {
 				t.Errorf("Growth at %.0f%% should increase value. Initial: £%.2f, Final: £%.2f",
@@ -317,7 +317,7 @@ func TestGrowthInvariant_ZeroRateMaintainsValue(t *testing.T) {
 
 	for _, initial := range testCases {
 		person := &Person{TaxFreeSavings: initial}
-		ApplyGrowth(person, 0, 0)
+		ApplyGrowth(person, 0, 0, 0.20)
 
 		if person.TaxFreeSavings != initial {
 			t.Errorf("Zero growth should maintain value. Initial: £%.2f, Final: £%.2f",
@@ -335,16 +335,16 @@ func TestGrowthInvariant_CompoundingOrder(t *testing.T) {
 	// Method 1: 10 years at once
 	person1 := &Person{TaxFreeSavings: initial}
 	for i := 0; i < 10; i++ {
-		ApplyGrowth(person1, rate, rate)
+		ApplyGrowth(person1, rate, rate, 0.20)
 	}
 
 	// Method 2: 5 years, then another 5 years
 	person2 := &Person{TaxFreeSavings: initial}
 	for i := 0; i < 5; i++ {
-		ApplyGrowth(person2, rate, rate)
+		ApplyGrowth(person2, rate, rate, 0.20)
 	}
 	for i := 0; i < 5; i++ {
-		ApplyGrowth(person2, rate, rate)
+		ApplyGrowth(person2, rate, rate, 0.20)
 	}
 
 	if math.Abs(person1.TaxFreeSavings-person2.TaxFreeSavings) > 0.01 {
@@ -1,77 +1,247 @@
 package main
 
-// GuardrailsState tracks the state needed for Guyton-Klinger guardrails strategy
+import "math"
+
+// GuardrailsState tracks the state needed for dynamic withdrawal rules:
+// Guyton-Klinger guardrails, Vanguard dynamic spending, VPW and constant percentage
+// Only discretionary spending is adjusted; the essential floor is never cut
 type GuardrailsState struct {
-	InitialWithdrawalRate float64 // The withdrawal rate in year 1 (withdrawal / portfolio)
-	InitialPortfolioValue float64 // Portfolio value at start of retirement
-	CurrentWithdrawal     float64 // Current year's withdrawal amount (adjusted)
-	UpperLimit            float64 // Upper guardrail (e.g., 1.20 = 120%)
-	LowerLimit            float64 // Lower guardrail (e.g., 0.80 = 80%)
-	AdjustmentRate        float64 // How much to adjust (e.g., 0.10 = 10%)
+	Rule                  WithdrawalRule // Which dynamic withdrawal rule to apply
+	InitialWithdrawalRate float64        // The withdrawal rate in year 1 (total withdrawal / portfolio)
+	InitialPortfolioValue float64        // Portfolio value at start of retirement
+	CurrentWithdrawal     float64        // Current year's discretionary withdrawal amount (adjusted)
+
+	// Guyton-Klinger
+	UpperLimit  float64 // Upper guardrail (e.g., 1.20 = 120%)
+	LowerLimit  float64 // Lower guardrail (e.g., 0.80 = 80%)
+	CutRate     float64 // Capital preservation cut (e.g., 0.10 = 10%)
+	RaiseRate   float64 // Prosperity raise (e.g., 0.10 = 10%)
+	ExemptYears int     // No capital preservation cuts in the final N years
+
+	// Vanguard dynamic spending
+	VanguardCeiling float64 // Max real rise per year (e.g., 0.05 = 5%)
+	VanguardFloor   float64 // Max real fall per year (e.g., 0.025 = 2.5%)
+
+	// Variable Percentage Withdrawal
+	VPWRealReturn float64 // Expected real return used for the withdrawal table
+	VPWEndAge     int     // Age the table plans to
+	VPWFloor      float64 // Minimum total withdrawal (nominal, inflated each year)
+	VPWCeiling    float64 // Maximum as a multiple of the floor (0 = none)
+
+	// Constant percentage
+	ConstantPercentRate float64 // Share of portfolio withdrawn (0 = initial withdrawal rate)
+}
+
+// GuardrailsYear holds the inputs a withdrawal rule needs for one year
+type GuardrailsYear struct {
+	PortfolioValue    float64 // Portfolio value at the start of the year
+	Essential         float64 // This year's essential floor (counts towards the withdrawal rate, never cut)
+	BaseDiscretionary float64 // Discretionary spending without any adjustment
+	Inflation         float64 // Discretionary inflation rate for the year
+	PortfolioReturn   float64 // Portfolio return over the previous year
+	Age               int     // Reference person's age
+	YearsRemaining    int     // Years left in the plan, including this one
 }
 
 // NewGuardrailsState creates a new guardrails state with default values
 func NewGuardrailsState(config *Config) *GuardrailsState {
-	upperLimit := config.IncomeRequirements.GuardrailsUpperLimit
+	ic := config.IncomeRequirements
+	upperLimit := ic.GuardrailsUpperLimit
 	if upperLimit <= 0 {
 		upperLimit = 1.20 // Default: 120% of initial rate
 	}
-	lowerLimit := config.IncomeRequirements.GuardrailsLowerLimit
+	lowerLimit := ic.GuardrailsLowerLimit
 	if lowerLimit <= 0 {
 		lowerLimit = 0.80 // Default: 80% of initial rate
 	}
-	adjustmentRate := config.IncomeRequirements.GuardrailsAdjustment
+	adjustmentRate := ic.GuardrailsAdjustment
 	if adjustmentRate <= 0 {
 		adjustmentRate = 0.10 // Default: 10% adjustment
 	}
+	cutRate := ic.GuardrailsCutRate
+	if cutRate <= 0 {
+		cutRate = adjustmentRate
+	}
+	raiseRate := ic.GuardrailsRaiseRate
+	if raiseRate <= 0 {
+		raiseRate = adjustmentRate
+	}
+	exemptYears := ic.GuardrailsExemptYears
+	if exemptYears <= 0 {
+		exemptYears = 15 // Guyton-Klinger: no cuts within 15 years of the end of the plan
+	}
+	vanguardCeiling := ic.VanguardCeiling
+	if vanguardCeiling <= 0 {
+		vanguardCeiling = 0.05
+	}
+	vanguardFloor := ic.VanguardFloor
+	if vanguardFloor <= 0 {
+		vanguardFloor = 0.025
+	}
+	vpwRealReturn := ic.VPWRealReturn
+	if vpwRealReturn <= 0 {
+		vpwRealReturn = 0.03
+	}
+	vpwEndAge := ic.VPWEndAge
+	if vpwEndAge <= 0 {
+		vpwEndAge = 100
+	}
 
 	return &GuardrailsState{
-		UpperLimit:     upperLimit,
-		LowerLimit:     lowerLimit,
-		AdjustmentRate: adjustmentRate,
+		Rule:                ic.GetWithdrawalRule(),
+		UpperLimit:          upperLimit,
+		LowerLimit:          lowerLimit,
+		CutRate:             cutRate,
+		RaiseRate:           raiseRate,
+		ExemptYears:         exemptYears,
+		VanguardCeiling:     vanguardCeiling,
+		VanguardFloor:       vanguardFloor,
+		VPWRealReturn:       vpwRealReturn,
+		VPWEndAge:           vpwEndAge,
+		VPWFloor:            ic.VPWFloor,
+		VPWCeiling:          ic.VPWCeiling,
+		ConstantPercentRate: ic.ConstantPercentRate,
 	}
 }
 
 // Initialize sets up the initial state based on first year values
-func (g *GuardrailsState) Initialize(portfolioValue, withdrawal float64) {
+// essential is the spending floor, discretionary the part guardrails may flex
+func (g *GuardrailsState) Initialize(portfolioValue, essential, discretionary float64) {
 	g.InitialPortfolioValue = portfolioValue
-	g.CurrentWithdrawal = withdrawal
+	g.CurrentWithdrawal = discretionary
 	if portfolioValue > 0 {
-		g.InitialWithdrawalRate = withdrawal / portfolioValue
+		g.InitialWithdrawalRate = (essential + discretionary) / portfolioValue
+	}
+}
+
+// Apply runs the configured withdrawal rule for a year after the first year of retirement
+// Returns the adjusted discretionary withdrawal and the direction of any change
+// (-1 = reduced, 0 = no change, 1 = increased)
+func (g *GuardrailsState) Apply(in GuardrailsYear) (float64, int) {
+	if g.InitialWithdrawalRate <= 0 || in.PortfolioValue <= 0 {
+		return in.BaseDiscretionary, 0
+	}
+
+	switch g.Rule {
+	case WithdrawalVanguard:
+		return g.applyVanguard(in)
+	case WithdrawalVPW:
+		return g.applyVPW(in)
+	case WithdrawalConstantPercent:
+		return g.applyConstantPercent(in)
+	default:
+		return g.applyGuytonKlinger(in)
+	}
+}
+
+// applyGuytonKlinger applies the full Guyton-Klinger decision rules
+func (g *GuardrailsState) applyGuytonKlinger(in GuardrailsYear) (float64, int) {
+	// Inflation rule: no inflation increase after a year with a negative portfolio return
+	// if the withdrawal rate is above its initial level
+	if in.PortfolioReturn >= 0 || g.GetCurrentRate(in.PortfolioValue, in.Essential) <= g.InitialWithdrawalRate {
+		g.CurrentWithdrawal *= 1 + in.Inflation
+	}
+
+	triggered := g.IsTriggered(in.PortfolioValue, in.Essential, in.YearsRemaining)
+	return g.CalculateAdjustedWithdrawal(in.PortfolioValue, in.Essential, in.BaseDiscretionary, in.YearsRemaining), triggered
+}
+
+// applyVanguard targets the initial withdrawal rate on the current portfolio,
+// but limits the real change from last year to between -floor and +ceiling
+func (g *GuardrailsState) applyVanguard(in GuardrailsYear) (float64, int) {
+	previous := in.Essential + g.CurrentWithdrawal*(1+in.Inflation)
+	target := in.PortfolioValue * g.InitialWithdrawalRate
+	total := math.Max(previous*(1-g.VanguardFloor), math.Min(previous*(1+g.VanguardCeiling), target))
+	return g.setTotal(total, previous, in.Essential)
+}
+
+// applyVPW withdraws the portfolio over the years left to VPWEndAge at the expected real return
+// An optional floor and ceiling bound the total withdrawal
+func (g *GuardrailsState) applyVPW(in GuardrailsYear) (float64, int) {
+	previous := in.Essential + g.CurrentWithdrawal*(1+in.Inflation)
+	total := in.PortfolioValue * VPWRate(g.VPWRealReturn, g.VPWEndAge-in.Age+1)
+
+	if g.VPWFloor > 0 {
+		g.VPWFloor *= 1 + in.Inflation
+		total = math.Max(total, g.VPWFloor)
+		if g.VPWCeiling > 0 {
+			total = math.Min(total, g.VPWFloor*g.VPWCeiling)
+		}
+	}
+	return g.setTotal(total, previous, in.Essential)
+}
+
+// applyConstantPercent withdraws a fixed share of the current portfolio
+func (g *GuardrailsState) applyConstantPercent(in GuardrailsYear) (float64, int) {
+	previous := in.Essential + g.CurrentWithdrawal*(1+in.Inflation)
+	rate := g.ConstantPercentRate
+	if rate <= 0 {
+		rate = g.InitialWithdrawalRate
+	}
+	return g.setTotal(in.PortfolioValue*rate, previous, in.Essential)
+}
+
+// setTotal records a rule's total withdrawal, keeping the essential floor intact
+// Returns the discretionary part and the direction of change from previous
+func (g *GuardrailsState) setTotal(total, previous, essential float64) (float64, int) {
+	g.CurrentWithdrawal = math.Max(0, total-essential)
+
+	newTotal := essential + g.CurrentWithdrawal
+	switch {
+	case newTotal < previous-1:
+		return g.CurrentWithdrawal, -1
+	case newTotal > previous+1:
+		return g.CurrentWithdrawal, 1
+	default:
+		return g.CurrentWithdrawal, 0
+	}
+}
+
+// VPWRate returns the Variable Percentage Withdrawal rate for the years remaining
+// This is the annuity-due payment that spends the portfolio over the period at the real return
+func VPWRate(realReturn float64, yearsRemaining int) float64 {
+	if yearsRemaining <= 1 {
+		return 1.0
+	}
+	n := float64(yearsRemaining)
+	if realReturn == 0 {
+		return 1 / n
 	}
+	return realReturn / ((1 + realReturn) * (1 - math.Pow(1+realReturn, -n)))
 }
 
-// CalculateAdjustedWithdrawal applies guardrails logic to determine the withdrawal for the current year
+// CalculateAdjustedWithdrawal applies guardrails logic to determine the discretionary withdrawal for the current year
 // portfolioValue: current total portfolio value
-// baseWithdrawal: what the withdrawal would be without guardrails (inflation-adjusted from initial)
-// Returns: adjusted withdrawal amount
-func (g *GuardrailsState) CalculateAdjustedWithdrawal(portfolioValue, baseWithdrawal float64) float64 {
+// essential: this year's essential spending floor (counts towards the withdrawal rate, never cut)
+// baseDiscretionary: discretionary spending without guardrails (inflation-adjusted from initial)
+// yearsRemaining: years left in the plan (capital preservation cuts stop within ExemptYears of the end)
+// Returns: adjusted discretionary withdrawal amount
+func (g *GuardrailsState) CalculateAdjustedWithdrawal(portfolioValue, essential, baseDiscretionary float64, yearsRemaining int) float64 {
 	// If not initialized, just return base withdrawal
 	if g.InitialWithdrawalRate <= 0 || portfolioValue <= 0 {
-		return baseWithdrawal
+		return baseDiscretionary
 	}
 
 	// Start with the current adjusted withdrawal (not base)
-	// Apply inflation to current withdrawal
+	// Inflation has already been applied to the current withdrawal
 	currentWithdrawal := g.CurrentWithdrawal
 	if currentWithdrawal <= 0 {
-		currentWithdrawal = baseWithdrawal
+		currentWithdrawal = baseDiscretionary
 	}
 
-	// Calculate current withdrawal rate
-	currentRate := currentWithdrawal / portfolioValue
+	// Calculate current withdrawal rate (essential + discretionary)
+	currentRate := (essential + currentWithdrawal) / portfolioValue
 
 	// Calculate ratio of current rate to initial rate
 	rateRatio := currentRate / g.InitialWithdrawalRate
 
 	// Apply guardrails
-	if rateRatio > g.UpperLimit {
-		// Portfolio has fallen significantly - reduce withdrawal by adjustment rate
-		// Only reduce if we're not already at a very low level
-		currentWithdrawal = currentWithdrawal * (1 - g.AdjustmentRate)
+	if rateRatio > g.UpperLimit && yearsRemaining > g.ExemptYears {
+		// Capital preservation: portfolio has fallen significantly - cut spending
+		currentWithdrawal = currentWithdrawal * (1 - g.CutRate)
 	} else if rateRatio < g.LowerLimit {
-		// Portfolio has grown significantly - can increase withdrawal
-		currentWithdrawal = currentWithdrawal * (1 + g.AdjustmentRate)
+		// Prosperity: portfolio has grown significantly - can increase withdrawal
+		currentWithdrawal = currentWithdrawal * (1 + g.RaiseRate)
 	}
 
 	// Update state
@@ -80,25 +250,25 @@ func (g *GuardrailsState) CalculateAdjustedWithdrawal(portfolioValue, baseWithdr
 	return currentWithdrawal
 }
 
-// GetCurrentRate returns the current withdrawal rate
-func (g *GuardrailsState) GetCurrentRate(portfolioValue float64) float64 {
+// GetCurrentRate returns the current withdrawal rate including the essential floor
+func (g *GuardrailsState) GetCurrentRate(portfolioValue, essential float64) float64 {
 	if portfolioValue <= 0 {
 		return 0
 	}
-	return g.CurrentWithdrawal / portfolioValue
+	return (essential + g.CurrentWithdrawal) / portfolioValue
 }
 
 // IsTriggered returns whether guardrails were triggered and in which direction
 // Returns: -1 if reduced, 0 if no change, 1 if increased
-func (g *GuardrailsState) IsTriggered(portfolioValue float64) int {
+func (g *GuardrailsState) IsTriggered(portfolioValue, essential float64, yearsRemaining int) int {
 	if g.InitialWithdrawalRate <= 0 || portfolioValue <= 0 {
 		return 0
 	}
 
-	currentRate := g.CurrentWithdrawal / portfolioValue
+	currentRate := (essential + g.CurrentWithdrawal) / portfolioValue
 	rateRatio := currentRate / g.InitialWithdrawalRate
 
-	if rateRatio > g.UpperLimit {
+	if rateRatio > g.UpperLimit && yearsRemaining > g.ExemptYears {
 		return -1 // Will reduce
 	} else if rateRatio < g.LowerLimit {
 		return 1 // Will increase
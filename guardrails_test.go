@@ -0,0 +1,199 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// newTestGuardrails creates an initialised state with a 4% initial withdrawal rate
+func newTestGuardrails(rule WithdrawalRule) *GuardrailsState {
+	config := createTestConfig()
+	config.IncomeRequirements.WithdrawalRule = rule.ID()
+	g := NewGuardrailsState(config)
+	g.Initialize(1000000, 0, 40000)
+	return g
+}
+
+// TestGuytonKlinger_InflationRule verifies there is no inflation rise after a losing year
+// when the withdrawal rate is above its initial level
+func TestGuytonKlinger_InflationRule(t *testing.T) {
+	tests := []struct {
+		name      string
+		portfolio float64
+		ret       float64
+		expected  float64
+	}{
+		{"gain year inflates", 1000000, 0.05, 40000 * 1.03},
+		{"loss year with rate above initial skips inflation", 950000, -0.05, 40000},
+		{"loss year with rate below initial inflates", 1100000, -0.05, 40000 * 1.03},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGuardrails(WithdrawalGuytonKlinger)
+			got, _ := g.Apply(GuardrailsYear{PortfolioValue: tc.portfolio, BaseDiscretionary: 40000,
+				Inflation: 0.03, PortfolioReturn: tc.ret, YearsRemaining: 30})
+			if math.Abs(got-tc.expected) > 0.01 {
+				t.Errorf("Expected £%.0f, got £%.0f", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestGuytonKlinger_ExemptionAndRates verifies separate cut/raise rates and the final-years exemption
+func TestGuytonKlinger_ExemptionAndRates(t *testing.T) {
+	config := createTestConfig()
+	config.IncomeRequirements.GuardrailsEnabled = true
+	config.IncomeRequirements.GuardrailsCutRate = 0.10
+	config.IncomeRequirements.GuardrailsRaiseRate = 0.05
+
+	g := NewGuardrailsState(config)
+	if g.Rule != WithdrawalGuytonKlinger || g.ExemptYears != 15 {
+		t.Fatalf("Expected Guyton-Klinger with 15 exempt years, got %v/%d", g.Rule, g.ExemptYears)
+	}
+
+	// Portfolio falls 30%: capital preservation cut with more than 15 years left
+	g.Initialize(1000000, 0, 40000)
+	got, triggered := g.Apply(GuardrailsYear{PortfolioValue: 700000, BaseDiscretionary: 40000, YearsRemaining: 20})
+	if triggered != -1 || math.Abs(got-36000) > 0.01 {
+		t.Errorf("Expected 10%% cut to £36,000, got £%.0f (triggered %d)", got, triggered)
+	}
+
+	// Same fall within 15 years of the end: no cut
+	g.Initialize(1000000, 0, 40000)
+	got, triggered = g.Apply(GuardrailsYear{PortfolioValue: 700000, BaseDiscretionary: 40000, YearsRemaining: 15})
+	if triggered != 0 || math.Abs(got-40000) > 0.01 {
+		t.Errorf("Expected no cut in exempt years, got £%.0f (triggered %d)", got, triggered)
+	}
+
+	// Portfolio rises 40%: prosperity raise (applies in any year)
+	g.Initialize(1000000, 0, 40000)
+	got, triggered = g.Apply(GuardrailsYear{PortfolioValue: 1400000, BaseDiscretionary: 40000, YearsRemaining: 5})
+	if triggered != 1 || math.Abs(got-42000) > 0.01 {
+		t.Errorf("Expected 5%% raise to £42,000, got £%.0f (triggered %d)", got, triggered)
+	}
+}
+
+// TestVanguardDynamicSpending verifies the ceiling and floor limit the yearly change
+func TestVanguardDynamicSpending(t *testing.T) {
+	tests := []struct {
+		name      string
+		portfolio float64
+		expected  float64
+	}{
+		{"within band follows portfolio", 1010000, 40400},
+		{"large gain capped at ceiling", 1500000, 42000},
+		{"large loss limited by floor", 500000, 39000},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g := newTestGuardrails(WithdrawalVanguard)
+			got, _ := g.Apply(GuardrailsYear{PortfolioValue: tc.portfolio, BaseDiscretionary: 40000, YearsRemaining: 30})
+			if math.Abs(got-tc.expected) > 0.01 {
+				t.Errorf("Expected £%.0f, got £%.0f", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestVPW verifies the VPW rate table and the optional floor and ceiling
+func TestVPW(t *testing.T) {
+	if r := VPWRate(0.03, 1); r != 1.0 {
+		t.Errorf("Expected 100%% in final year, got %.4f", r)
+	}
+	if r := VPWRate(0, 20); math.Abs(r-0.05) > 1e-9 {
+		t.Errorf("Expected 5%% with zero return over 20 years, got %.4f", r)
+	}
+	if VPWRate(0.03, 46) >= VPWRate(0.03, 16) {
+		t.Error("Expected VPW rate to rise as years remaining fall")
+	}
+
+	g := newTestGuardrails(WithdrawalVPW)
+	got, _ := g.Apply(GuardrailsYear{PortfolioValue: 1000000, Age: 65, YearsRemaining: 30})
+	expected := 1000000 * VPWRate(0.03, 36)
+	if math.Abs(got-expected) > 0.01 {
+		t.Errorf("Expected £%.0f, got £%.0f", expected, got)
+	}
+
+	// Floor holds spending up after a crash; ceiling caps it after a boom
+	g = newTestGuardrails(WithdrawalVPW)
+	g.VPWFloor = 30000
+	g.VPWCeiling = 1.5
+	if got, _ := g.Apply(GuardrailsYear{PortfolioValue: 300000, Age: 65}); math.Abs(got-30000) > 0.01 {
+		t.Errorf("Expected floor of £30,000, got £%.0f", got)
+	}
+	if got, _ := g.Apply(GuardrailsYear{PortfolioValue: 5000000, Age: 65}); math.Abs(got-45000) > 0.01 {
+		t.Errorf("Expected ceiling of £45,000, got £%.0f", got)
+	}
+}
+
+// TestConstantPercent verifies a fixed share of the current portfolio is withdrawn
+// and the essential floor is never cut
+func TestConstantPercent(t *testing.T) {
+	g := newTestGuardrails(WithdrawalConstantPercent)
+	if got, _ := g.Apply(GuardrailsYear{PortfolioValue: 800000}); math.Abs(got-32000) > 0.01 {
+		t.Errorf("Expected initial 4%% of £800k = £32,000, got £%.0f", got)
+	}
+
+	g.ConstantPercentRate = 0.05
+	g.Initialize(1000000, 30000, 10000)
+	got, triggered := g.Apply(GuardrailsYear{PortfolioValue: 500000, Essential: 30000})
+	if got != 0 || triggered != -1 {
+		t.Errorf("Expected discretionary cut to zero above the essential floor, got £%.0f (triggered %d)", got, triggered)
+	}
+}
+
+// TestWithdrawalRuleFactor verifies each rule is a value of the guardrails factor
+func TestWithdrawalRuleFactor(t *testing.T) {
+	factor := NewFactorRegistry().Get(FactorGuardrails)
+
+	rules := make(map[WithdrawalRule]bool)
+	for _, v := range factor.Values {
+		rule, ok := v.Value.(WithdrawalRule)
+		if !ok {
+			t.Fatalf("Value %s is not a WithdrawalRule", v.ID)
+		}
+		rules[rule] = true
+
+		params := StrategyCombo{Values: map[FactorID]FactorValue{FactorGuardrails: v}}.ToSimulationParams()
+		if params.GetWithdrawalRule() != rule {
+			t.Errorf("%s: expected params rule %v, got %v", v.ID, rule, params.GetWithdrawalRule())
+		}
+		if rule != WithdrawalFixed && ParseWithdrawalRule(rule.ID()) != rule {
+			t.Errorf("%s: rule ID does not round trip", v.ID)
+		}
+	}
+	for _, rule := range []WithdrawalRule{WithdrawalFixed, WithdrawalGuytonKlinger, WithdrawalVanguard, WithdrawalVPW, WithdrawalConstantPercent} {
+		if !rules[rule] {
+			t.Errorf("Rule %v not registered", rule)
+		}
+	}
+}
+
+// TestWithdrawalRules_Simulation verifies every rule runs through the simulation
+func TestWithdrawalRules_Simulation(t *testing.T) {
+	config := createTestConfig()
+	config.IncomeRequirements.SpendingProfile.EssentialFraction = 0.5
+
+	for _, rule := range []WithdrawalRule{WithdrawalGuytonKlinger, WithdrawalVanguard, WithdrawalVPW, WithdrawalConstantPercent} {
+		params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized, WithdrawalRule: rule}
+		result := RunSimulationV2(params, config)
+
+		adjusted := false
+		for _, year := range result.Years {
+			if year.RequiredIncome < year.EssentialIncome-0.01 {
+				t.Errorf("%v year %d: income £%.0f below essential floor £%.0f", rule, year.Year, year.RequiredIncome, year.EssentialIncome)
+			}
+			if year.GuardrailsAdjusted > 0 {
+				adjusted = true
+			}
+		}
+		if !adjusted {
+			t.Errorf("%v: expected the rule to set adjusted income", rule)
+		}
+		if params.ShortName() == (SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}).ShortName() {
+			t.Errorf("%v: expected the short name to include the rule", rule)
+		}
+	}
+}
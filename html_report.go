@@ -138,7 +138,7 @@ func getYearEvents(year YearState, config *Config, mortgagePayoffYear int, prevY
 		age := year.Ages[name]
 
 		// Get retirement info
-		retirementTaxYear, retirementAge := pc.GetRetirementInfo()
+		retirementTaxYear, _ := pc.GetRetirementInfo()
 
 		// Retirement (stop working) - check if this is the first year of retirement
 		if year.Year == retirementTaxYear {
@@ -146,10 +146,7 @@ func getYearEvents(year YearState, config *Config, mortgagePayoffYear int, prevY
 		}
 
 		// Pension access starts - check if this is the first year they can access pension
-		pensionAccessAge := pc.PensionAccessAge
-		if pensionAccessAge <= 0 {
-			pensionAccessAge = retirementAge
-		}
+		pensionAccessAge := pc.GetPensionAccessAge()
 		if age == pensionAccessAge {
 			// Only show if they have a pension
 			if pc.Pension > 0 {
@@ -194,8 +191,8 @@ func getYearEvents(year YearState, config *Config, mortgagePayoffYear int, prevY
 
 		// ISA depleted
 		if prevYearState != nil {
-			prevISA := prevYearState.EndBalances[name].TaxFreeSavings
-			currISA := year.EndBalances[name].TaxFreeSavings
+			prevISA := prevYearState.EndBalances[name].Savings()
+			currISA := year.EndBalances[name].Savings()
 			if prevISA > 0 && currISA <= 0 {
 				events = append(events, fmt.Sprintf("%s ISA depleted", name))
 			}
@@ -438,7 +435,7 @@ func GenerateHTMLReport(result SimulationResult, config *Config, filename string
 	// Summary metrics
 	var totalRemaining float64
 	for _, b := range result.FinalBalances {
-		totalRemaining += b.TaxFreeSavings + b.UncrystallisedPot + b.CrystallisedPot
+		totalRemaining += b.Savings() + b.UncrystallisedPot + b.CrystallisedPot
 	}
 
 	ranOutClass := "success"
@@ -475,6 +472,7 @@ func GenerateHTMLReport(result SimulationResult, config *Config, filename string
 
 	// Strategy description
 	writeStrategyDescription(f, result.Params)
+	writeComplianceWarnings(f, CheckCompliance(result, config))
 
 	// Configuration summary
 	fmt.Fprintf(f, `
@@ -488,12 +486,10 @@ func GenerateHTMLReport(result SimulationResult, config *Config, filename string
 `)
 	for _, p := range config.People {
 		birthYear := GetBirthYear(p.BirthDate)
-		pensionAccessAge := p.PensionAccessAge
-		if pensionAccessAge <= 0 {
-			pensionAccessAge = p.RetirementAge
-		}
+		pensionAccessAge := p.GetPensionAccessAge()
+		_, retirementAge := p.GetRetirementInfo()
 		fmt.Fprintf(f, "                        <tr><td>%s</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%s</td><td>%s</td></tr>\n",
-			p.Name, birthYear, p.RetirementAge, pensionAccessAge, p.StatePensionAge,
+			p.Name, birthYear, retirementAge, pensionAccessAge, p.StatePensionAge,
 			FormatMoney(p.TaxFreeSavings), FormatMoney(p.Pension))
 	}
 	fmt.Fprintf(f, `                    </table>
@@ -554,9 +550,9 @@ func GenerateHTMLReport(result SimulationResult, config *Config, filename string
                 <tr><th>Person</th><th>Tax-Free (ISA+PCLS)</th><th>Crystallised Pension</th><th>Uncrystallised Pension</th><th>Total</th></tr>
 `, config.Simulation.EndAge)
 	for name, bal := range result.FinalBalances {
-		total := bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+		total := bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
 		fmt.Fprintf(f, "                <tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td><strong>%s</strong></td></tr>\n",
-			name, FormatMoney(bal.TaxFreeSavings), FormatMoney(bal.CrystallisedPot),
+			name, FormatMoney(bal.Savings()), FormatMoney(bal.CrystallisedPot),
 			FormatMoney(bal.UncrystallisedPot), FormatMoney(total))
 	}
 	fmt.Fprintf(f, "                <tr class=\"balance-row\"><td><strong>Total</strong></td><td></td><td></td><td></td><td><strong>%s</strong></td></tr>\n",
@@ -596,10 +592,7 @@ func GenerateHTMLReport(result SimulationResult, config *Config, filename string
 `)
 
 	// Determine which year to highlight (mortgage payoff year)
-	mortgagePayoffYear := config.Mortgage.EndYear
-	if result.Params.EarlyMortgagePayoff {
-		mortgagePayoffYear = config.Mortgage.EarlyPayoffYear
-	}
+	mortgagePayoffYear := getMortgagePayoffYear(config, result.Params)
 
 	// Calculate colspan for details row (Year + Events + ages + 9 columns + 2 per person for balances)
 	colspan := 2 + len(names) + 9 + len(names)*2
@@ -635,7 +628,7 @@ func GenerateHTMLReport(result SimulationResult, config *Config, filename string
 		fmt.Fprintf(f, "                        <td class=\"positive\">%s</td>\n", FormatMoney(year.NetIncomeReceived))
 		for _, name := range names {
 			bal := year.EndBalances[name]
-			fmt.Fprintf(f, "                        <td>%s</td>\n", FormatMoney(bal.TaxFreeSavings))
+			fmt.Fprintf(f, "                        <td>%s</td>\n", FormatMoney(bal.Savings()))
 			fmt.Fprintf(f, "                        <td>%s</td>\n", FormatMoney(bal.CrystallisedPot+bal.UncrystallisedPot))
 		}
 		fmt.Fprintf(f, "                    </tr>\n")
@@ -734,30 +727,9 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
 		names = append(names, p.Name)
 	}
 
-	// Find best strategy (highest final balance among those that don't run out)
-	// This properly accounts for investment returns vs mortgage costs
-	var bestIdx int = -1
-	var bestBalance float64 = -1
-	for i, r := range results {
-		if !r.RanOutOfMoney {
-			finalBalance := getTotalFinalBalance(r)
-			if bestBalance < 0 || finalBalance > bestBalance {
-				bestBalance = finalBalance
-				bestIdx = i
-			}
-		}
-	}
-
-	// If all run out, find the one that lasts longest
-	if bestIdx < 0 {
-		longestYear := 0
-		for i, r := range results {
-			if r.RanOutYear > longestYear {
-				longestYear = r.RanOutYear
-				bestIdx = i
-			}
-		}
-	}
+	// Find best strategy (highest score: by default the highest final balance among those
+	// that don't run out, which accounts for investment returns vs mortgage costs)
+	bestIdx := FindBestResult(results, config)
 
 	// Write HTML header with tabs
 	fmt.Fprintf(f, `<!DOCTYPE html>
@@ -1030,7 +1002,7 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
 	for i, r := range results {
 		var total float64
 		for _, b := range r.FinalBalances {
-			total += b.TaxFreeSavings + b.UncrystallisedPot + b.CrystallisedPot
+			total += b.Savings() + b.UncrystallisedPot + b.CrystallisedPot
 		}
 		fmt.Fprintf(f, "                        <td onclick=\"showTab('strategy%d')\">%s</td>\n", i, FormatMoney(total))
 	}
@@ -1047,6 +1019,15 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
 		}
 		fmt.Fprintf(f, "                        <td class=\"%s\" onclick=\"showTab('strategy%d')\">%s</td>\n", class, i, status)
 	}
+	if anyDiscretionaryCuts(results) {
+		fmt.Fprintf(f, `                    </tr>
+                    <tr>
+                        <td>Years With Discretionary Cuts</td>
+`)
+		for i, r := range results {
+			fmt.Fprintf(f, "                        <td onclick=\"showTab('strategy%d')\">%d (%.0f%%)</td>\n", i, r.DiscretionaryCutYears, r.CutYearsPercent())
+		}
+	}
 	fmt.Fprintf(f, `                    </tr>
                 </table>
             </div>
@@ -1061,12 +1042,10 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
 `)
 	for _, p := range config.People {
 		birthYear := GetBirthYear(p.BirthDate)
-		pensionAccessAge := p.PensionAccessAge
-		if pensionAccessAge <= 0 {
-			pensionAccessAge = p.RetirementAge
-		}
+		pensionAccessAge := p.GetPensionAccessAge()
+		_, retirementAge := p.GetRetirementInfo()
 		fmt.Fprintf(f, "                            <tr><td>%s</td><td>%d</td><td>%d</td><td>%d</td><td>%d</td><td>%s</td><td>%s</td></tr>\n",
-			p.Name, birthYear, p.RetirementAge, pensionAccessAge, p.StatePensionAge,
+			p.Name, birthYear, retirementAge, pensionAccessAge, p.StatePensionAge,
 			FormatMoney(p.TaxFreeSavings), FormatMoney(p.Pension))
 	}
 	fmt.Fprintf(f, `                        </table>
@@ -1097,20 +1076,21 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
             <div class="card">
                 <h2>Recommendation</h2>
                 <p><strong>Best Strategy:</strong> %s</p>
-                <p>This strategy results in the highest final balance (%s) with total tax of %s`,
-			best.Params.String(), FormatMoney(bestFinalBalance), FormatMoney(best.TotalTaxPaid))
+                <p>This strategy %s (%s) with total tax of %s`,
+			best.Params.String(), recommendationReason(config), FormatMoney(bestFinalBalance), FormatMoney(best.TotalTaxPaid))
 		if best.RanOutOfMoney {
 			fmt.Fprintf(f, ", running out of money in %d.</p>\n", best.RanOutYear)
 		} else {
 			fmt.Fprintf(f, ".</p>\n")
 		}
-		if !best.Params.EarlyMortgagePayoff {
+		if !best.Params.PaysOffMortgageEarly() {
 			fmt.Fprintf(f, `                <p><em>Normal mortgage payoff is better because investment growth (5%%+) exceeds mortgage interest (~4%%).</em></p>
 `)
 		}
 		fmt.Fprintf(f, `            </div>
 `)
 	}
+	fmt.Fprint(f, paretoFrontierHTML(results, config))
 
 	fmt.Fprintf(f, `        </div>
     </div>
@@ -1120,7 +1100,7 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
 	for i, result := range results {
 		var totalRemaining float64
 		for _, b := range result.FinalBalances {
-			totalRemaining += b.TaxFreeSavings + b.UncrystallisedPot + b.CrystallisedPot
+			totalRemaining += b.Savings() + b.UncrystallisedPot + b.CrystallisedPot
 		}
 
 		ranOutClass := "success"
@@ -1165,6 +1145,7 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
 
 		// Strategy description
 		writeStrategyDescription(f, result.Params)
+		writeComplianceWarnings(f, CheckCompliance(result, config))
 
 		// Final balances
 		fmt.Fprintf(f, `
@@ -1174,9 +1155,9 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
                     <tr><th>Person</th><th>Tax-Free (ISA+PCLS)</th><th>Crystallised</th><th>Uncrystallised</th><th>Total</th></tr>
 `, config.Simulation.EndAge)
 		for name, bal := range result.FinalBalances {
-			total := bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+			total := bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
 			fmt.Fprintf(f, "                    <tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td><strong>%s</strong></td></tr>\n",
-				name, FormatMoney(bal.TaxFreeSavings), FormatMoney(bal.CrystallisedPot),
+				name, FormatMoney(bal.Savings()), FormatMoney(bal.CrystallisedPot),
 				FormatMoney(bal.UncrystallisedPot), FormatMoney(total))
 		}
 		fmt.Fprintf(f, "                    <tr class=\"balance-row\"><td><strong>Total</strong></td><td></td><td></td><td></td><td><strong>%s</strong></td></tr>\n",
@@ -1216,10 +1197,7 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
 `)
 
 		// Determine which year to highlight (mortgage payoff year)
-		mortgagePayoffYear := config.Mortgage.EndYear
-		if result.Params.EarlyMortgagePayoff {
-			mortgagePayoffYear = config.Mortgage.EarlyPayoffYear
-		}
+		mortgagePayoffYear := getMortgagePayoffYear(config, result.Params)
 
 		// Calculate colspan for details row (Year + Events + ages + 9 columns + 2 per person for balances)
 		colspan := 2 + len(names) + 9 + len(names)*2
@@ -1258,7 +1236,7 @@ func GenerateCombinedHTMLReport(results []SimulationResult, config *Config, file
 			fmt.Fprintf(f, "                            <td class=\"positive\">%s</td>\n", FormatMoney(year.NetIncomeReceived))
 			for _, name := range names {
 				bal := year.EndBalances[name]
-				fmt.Fprintf(f, "                            <td>%s</td>\n", FormatMoney(bal.TaxFreeSavings))
+				fmt.Fprintf(f, "                            <td>%s</td>\n", FormatMoney(bal.Savings()))
 				fmt.Fprintf(f, "                            <td>%s</td>\n", FormatMoney(bal.CrystallisedPot+bal.UncrystallisedPot))
 			}
 			fmt.Fprintf(f, "                        </tr>\n")
@@ -1335,6 +1313,12 @@ func getStrategyDescription(params SimulationParams) string {
 		pension pot is taken as a tax-free <em>Pension Commencement Lump Sum (PCLS)</em> to pay off the mortgage.
 		The remaining 75% becomes taxable crystallised pension. After taking PCLS, future pension withdrawals
 		are 100% taxable (no further 25% tax-free allowance).</p>`
+	} else if params.CrystallisationStrategy == TrancheCrystallisation {
+		desc = fmt.Sprintf(`<p><strong>Tranche Crystallisation (%s):</strong> Part of the pension is crystallised
+		in planned tranches. The 25%% tax-free cash from each tranche is paid into the ISA up to the annual
+		allowance, and any excess is held in a general account and moved into the ISA in later tax years.
+		The rest of the pension stays uncrystallised, and withdrawals between tranches crystallise gradually.</p>`,
+			params.TrancheTiming.Description())
 	} else {
 		desc = `<p><strong>Gradual Crystallisation:</strong> Pension is crystallised only as needed each year.
 		When crystallising, 25% becomes tax-free cash (PCLS) and 75% becomes taxable. This approach keeps
@@ -1364,6 +1348,18 @@ func getStrategyDescription(params SimulationParams) string {
 		Only the deliberate excess transfers are limited to £20k/year per person.</em></p>`
 	}
 
+	// Planned tax-free cash left in the pension
+	if params.KeepTaxFreeCash {
+		desc += `<p><strong>Tax-Free Cash Kept:</strong> The planned tax-free cash is not taken, so the pension keeps
+		its 25% tax-free allowance for later withdrawals. Planned one-off expenses are paid from savings and pension instead.</p>`
+	}
+
+	// People who draw differently from the household
+	if descriptions := params.personStrategyDescriptions(); len(descriptions) > 0 {
+		desc += `<p><strong>Per-person choices:</strong> ` + joinStrings(descriptions, "; ") + `. The household's
+		income need is shared by what each person can draw, and anything one cannot provide is drawn by the other.</p>`
+	}
+
 	return desc
 }
 
@@ -1380,6 +1376,29 @@ func writeStrategyDescription(f *os.File, params SimulationParams) {
 `, desc)
 }
 
+// writeComplianceWarnings writes a card listing the pension and ISA rules the plan breaks or risks breaking
+func writeComplianceWarnings(f *os.File, warnings []ComplianceWarning) {
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Fprintf(f, `
+        <div class="card" style="border-left: 4px solid var(--danger);">
+            <h2>Compliance Warnings</h2>
+            <table>
+                <tr><th>Severity</th><th>Rule</th><th>Warning</th></tr>
+`)
+	for _, w := range warnings {
+		class := "negative"
+		if w.Severity == SeverityRisk {
+			class = ""
+		}
+		fmt.Fprintf(f, "                <tr><td class=\"%s\">%s</td><td>%s</td><td>%s</td></tr>\n", class, w.Severity, w.Rule, w.String())
+	}
+	fmt.Fprintf(f, `            </table>
+        </div>
+`)
+}
+
 // sanitizeFilename replaces characters that are not safe in filenames
 func sanitizeFilename(name string) string {
 	result := make([]byte, 0, len(name))
@@ -1630,7 +1649,7 @@ func writeYearDetailsContent(f *os.File, year YearState, names []string) {
 
 	for _, name := range names {
 		bal := year.EndBalances[name]
-		total := bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+		total := bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot + bal.LISA
 		fmt.Fprintf(f, `                                        <tr>
                                             <td style="text-align:left; font-weight:600">%s</td>
                                             <td>%s</td>
@@ -1638,13 +1657,49 @@ func writeYearDetailsContent(f *os.File, year YearState, names []string) {
                                             <td>%s</td>
                                             <td><strong>%s</strong></td>
                                         </tr>
-`, name, FormatMoney(bal.TaxFreeSavings), FormatMoney(bal.CrystallisedPot), FormatMoney(bal.UncrystallisedPot), FormatMoney(total))
+`, name, FormatMoney(bal.Savings()), FormatMoney(bal.CrystallisedPot), FormatMoney(bal.UncrystallisedPot), FormatMoney(total))
 	}
 
 	fmt.Fprintf(f, `                                    </table>
                                 </div>
                             </div>
 `)
+
+	// ISA subscriptions this tax year, and savings over the allowance kept in the GIA
+	fmt.Fprintf(f, `                            <div style="margin-top: 1rem;">
+                                <strong>ISA Subscriptions</strong>
+                                <table class="detail-table">
+                                    <tr>
+                                        <th style="text-align:left">Person</th>
+                                        <th>Subscribed</th>
+                                        <th>Allowance Left</th>
+                                        <th>Saved to GIA</th>
+                                        <th>GIA Balance</th>
+                                        <th>LISA Bonus</th>
+                                        <th>LISA Balance</th>
+                                    </tr>
+`)
+	for _, name := range names {
+		bal := year.EndBalances[name]
+		fmt.Fprintf(f, `                                    <tr>
+                                        <td style="text-align:left; font-weight:600">%s</td>
+                                        <td>%s</td>
+                                        <td>%s</td>
+                                        <td>%s</td>
+                                        <td>%s</td>
+                                        <td class="positive">%s</td>
+                                        <td>%s</td>
+                                    </tr>
+`, name, formatOrDash(bal.ISASubscribed), FormatMoney(bal.ISAAllowanceLeft), formatOrDash(bal.SavedToGIA), formatOrDash(bal.GIA),
+			formatOrDash(year.LISABonus[name]), formatOrDash(bal.LISA))
+		if penalty := year.LISAPenalty[name]; penalty > 0 {
+			fmt.Fprintf(f, `                                    <tr><td colspan="7" style="text-align:left" class="negative">%s drew from the Lifetime ISA before 60, losing %s to the withdrawal penalty</td></tr>
+`, name, FormatMoney(penalty))
+		}
+	}
+	fmt.Fprintf(f, `                                </table>
+                            </div>
+`)
 }
 
 // writeDrawdownDetailsHTML writes detailed year-by-year extraction breakdown
@@ -1810,7 +1865,7 @@ func writeDrawdownDetailsHTML(f *os.File, result SimulationResult, config *Confi
 
 		for _, name := range names {
 			bal := year.EndBalances[name]
-			total := bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+			total := bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
 			fmt.Fprintf(f, `                            <tr>
                                 <td style="text-align: left;">%s</td>
                                 <td>%s</td>
@@ -1818,7 +1873,7 @@ func writeDrawdownDetailsHTML(f *os.File, result SimulationResult, config *Confi
                                 <td>%s</td>
                                 <td><strong>%s</strong></td>
                             </tr>
-`, name, FormatMoney(bal.TaxFreeSavings), FormatMoney(bal.CrystallisedPot),
+`, name, FormatMoney(bal.Savings()), FormatMoney(bal.CrystallisedPot),
 				FormatMoney(bal.UncrystallisedPot), FormatMoney(total))
 		}
 
@@ -1841,6 +1896,45 @@ func formatOrDash(amount float64) string {
 	return FormatMoney(amount)
 }
 
+// recommendationReason explains why the recommended strategy was chosen
+func recommendationReason(config *Config) string {
+	if config.Strategy.Scoring.IsSet() {
+		return "scores highest on your scoring weights, with a final balance"
+	}
+	return "results in the highest final balance"
+}
+
+// paretoFrontierHTML returns a card listing the strategies on the Pareto frontier
+// (no other strategy is better on tax, income, final balance and shortfall together)
+func paretoFrontierHTML(results []SimulationResult, config *Config) string {
+	frontier := ResultsFrontier(results, config.Financial.IncomeInflationRate)
+	if len(frontier) == 0 {
+		return ""
+	}
+
+	out := `            <div class="card">
+                <h2>Trade-offs (Pareto Frontier)</h2>
+                <p style="margin-bottom: 1rem; color: var(--text-muted); font-size: 0.9rem;">
+                    No other strategy beats these on tax, income, final balance and shortfall together.
+                    Choosing between them means giving up one objective for another.
+                </p>
+                <table>
+                    <tr><th>Strategy</th><th>Total Tax</th><th>Income (today's money)</th><th>Final Balance</th><th>Runs Out</th></tr>
+`
+	for _, p := range frontier {
+		runsOut := "Never"
+		if p.ShortfallYear > 0 {
+			runsOut = fmt.Sprintf("%d", p.ShortfallYear)
+		}
+		out += fmt.Sprintf("                    <tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			results[p.Index].Params.String(), FormatMoney(p.TotalTax), FormatMoney(p.TotalIncome),
+			FormatMoney(p.FinalBalance), runsOut)
+	}
+	return out + `                </table>
+            </div>
+`
+}
+
 // GenerateDepletionHTMLReports generates HTML reports for depletion mode
 func GenerateDepletionHTMLReports(results []DepletionResult, config *Config, outputDir string, timestamp string) (string, error) {
 	// Create output directory
@@ -1868,6 +1962,19 @@ func GenerateDepletionHTMLReports(results []DepletionResult, config *Config, out
 	return combinedPath, nil
 }
 
+// tierAmountsHTML lists the depletion amount of every income tier (empty for untiered configs)
+func tierAmountsHTML(ic IncomeConfig, amounts []float64) string {
+	lines := describeTierAmounts(ic, amounts)
+	if len(lines) == 0 {
+		return ""
+	}
+	out := "                <h3 style=\"margin-top: 1rem;\">Income by Tier (today's money)</h3>\n                <ul>\n"
+	for _, line := range lines {
+		out += "                    <li>" + line + "</li>\n"
+	}
+	return out + "                </ul>\n"
+}
+
 // generateDepletionCombinedReport generates the combined HTML report for depletion mode
 func generateDepletionCombinedReport(results []DepletionResult, config *Config, filename string) error {
 	f, err := os.Create(filename)
@@ -1876,9 +1983,13 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
 	}
 	defer f.Close()
 
-	bestIdx := FindBestDepletionStrategy(results)
+	bestIdx := FindBestDepletionStrategy(results, config)
 	ic := config.IncomeRequirements
 	refPerson := config.GetSimulationReferencePerson()
+	terminalTargetBanner := ""
+	if ic.HasTerminalTarget() {
+		terminalTargetBanner = "<br>\n        Terminal Target: " + describeTerminalTarget(ic)
+	}
 
 	// Get names for display
 	names := make([]string, len(config.People))
@@ -2087,12 +2198,12 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
     </div>
     <div class="depletion-banner">
         <strong>Target: Deplete by age %d</strong> (%s)<br>
-        Income Ratio: %.0f:%.0f (before/after age %d)
+        Income Ratio: %.0f:%.0f (before/after age %d)%s
     </div>
     <div class="tabs">
         <button class="tab active" onclick="showTab('comparison')">Comparison</button>
 `, time.Now().Format("2 January 2006 at 15:04"), ic.TargetDepletionAge, refPerson.Name,
-		ic.IncomeRatioPhase1, ic.IncomeRatioPhase2, ic.AgeThreshold)
+		ic.IncomeRatioPhase1, ic.IncomeRatioPhase2, ic.AgeThreshold, terminalTargetBanner)
 
 	// Add tabs for each strategy
 	for i, r := range results {
@@ -2123,6 +2234,7 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
                             <th>Monthly (After %d)</th>
                             <th>Annual (Phase 1)</th>
                             <th>Total Lifetime Tax</th>
+                            <th>Income Cost per £100k Legacy</th>
                         </tr>
                     </thead>
                     <tbody>
@@ -2140,10 +2252,12 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
                             <td>%s</td>
                             <td>%s</td>
                             <td>%s</td>
+                            <td>%s/month</td>
                         </tr>
 `, bestClass, i, r.Params.ShortName(),
 			FormatMoney(r.MonthlyBeforeAge), FormatMoney(r.MonthlyAfterAge),
-			FormatMoney(r.MonthlyBeforeAge*12), FormatMoney(r.SimulationResult.TotalTaxPaid))
+			FormatMoney(r.MonthlyBeforeAge*12), FormatMoney(r.SimulationResult.TotalTaxPaid),
+			FormatMoney(r.LegacyCostPer100k))
 	}
 
 	fmt.Fprintf(f, `                    </tbody>
@@ -2154,6 +2268,15 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
 	// Add recommendation card
 	if bestIdx >= 0 {
 		best := results[bestIdx]
+		legacyCostHTML := ""
+		if best.MinimumsUnmet {
+			legacyCostHTML = `                <p style="margin-top: 1rem; color: var(--danger);"><strong>Tier minimums can't be met:</strong> no strategy can pay every tier's min_monthly to the target age, so income is shown at the minimums, which run short.</p>
+`
+		}
+		if best.LegacyCostPer100k > 0 {
+			legacyCostHTML += fmt.Sprintf(`                <p style="margin-top: 1rem; color: var(--text-muted);">Each extra £100k of legacy costs %s/month</p>
+`, FormatMoney(best.LegacyCostPer100k))
+		}
 		fmt.Fprintf(f, `            <div class="card" style="border-left: 4px solid var(--success);">
                 <h2 style="color: var(--success);">Recommended: %s</h2>
                 <div class="grid grid-4" style="margin-top: 1rem;">
@@ -2174,11 +2297,18 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
                         <div class="metric-label">Total Tax Paid</div>
                     </div>
                 </div>
-            </div>
+%s%s            </div>
 `, best.Params.String(), FormatMoney(best.MonthlyBeforeAge), ic.AgeThreshold,
 			FormatMoney(best.MonthlyAfterAge), ic.AgeThreshold,
-			FormatMoney(best.MonthlyBeforeAge*12), FormatMoney(best.SimulationResult.TotalTaxPaid))
+			FormatMoney(best.MonthlyBeforeAge*12), FormatMoney(best.SimulationResult.TotalTaxPaid),
+			legacyCostHTML, tierAmountsHTML(ic, best.TierAmounts))
+	}
+
+	simResults := make([]SimulationResult, len(results))
+	for i, r := range results {
+		simResults[i] = r.SimulationResult
 	}
+	fmt.Fprint(f, paretoFrontierHTML(simResults, config))
 
 	fmt.Fprintf(f, `        </div>
     </div>
@@ -2247,10 +2377,7 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
 		targetYear := GetBirthYear(refPerson.BirthDate) + ic.TargetDepletionAge
 
 		// Determine mortgage payoff year
-		mortgagePayoffYear := config.Mortgage.EndYear
-		if result.Params.EarlyMortgagePayoff {
-			mortgagePayoffYear = config.Mortgage.EarlyPayoffYear
-		}
+		mortgagePayoffYear := getMortgagePayoffYear(config, result.Params)
 
 		// Calculate colspan: Year + Events + ages (len(names)) + 9 data columns
 		colspan := 2 + len(names) + 9
@@ -2405,7 +2532,7 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
 			// Per-person balances
 			for _, name := range names {
 				bal := year.EndBalances[name]
-				total := bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+				total := bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
 				fmt.Fprintf(f, `                                            <tr>
                                                 <td style="text-align:left; font-weight:600">%s</td>
                                                 <td>%s</td>
@@ -2413,7 +2540,7 @@ func generateDepletionCombinedReport(results []DepletionResult, config *Config,
                                                 <td>%s</td>
                                                 <td><strong>%s</strong></td>
                                             </tr>
-`, name, FormatMoney(bal.TaxFreeSavings), FormatMoney(bal.CrystallisedPot), FormatMoney(bal.UncrystallisedPot), FormatMoney(total))
+`, name, FormatMoney(bal.Savings()), FormatMoney(bal.CrystallisedPot), FormatMoney(bal.UncrystallisedPot), FormatMoney(total))
 			}
 
 			fmt.Fprintf(f, `                                        </table>
@@ -2802,14 +2929,14 @@ func generatePensionOnlyCombinedReport(results []DepletionResult, config *Config
 		return err
 	}
 	defer f.Close()
-	bestIdx := FindBestDepletionStrategy(results)
+	bestIdx := FindBestDepletionStrategy(results, config)
 	ic := config.IncomeRequirements
 	refPerson := config.GetSimulationReferencePerson()
 	finalISA := 0.0
 	if bestIdx >= 0 && len(results[bestIdx].SimulationResult.Years) > 0 {
 		lastYear := results[bestIdx].SimulationResult.Years[len(results[bestIdx].SimulationResult.Years)-1]
 		for _, bal := range lastYear.EndBalances {
-			finalISA += bal.TaxFreeSavings
+			finalISA += bal.Savings()
 		}
 	}
 	fmt.Fprintf(f, `<!DOCTYPE html><html lang="en"><head><meta charset="UTF-8"><title>Pension-Only Depletion</title>
@@ -2829,7 +2956,7 @@ func generatePensionOnlyCombinedReport(results []DepletionResult, config *Config
 		if len(r.SimulationResult.Years) > 0 {
 			lastYear := r.SimulationResult.Years[len(r.SimulationResult.Years)-1]
 			for _, bal := range lastYear.EndBalances {
-				fISA += bal.TaxFreeSavings
+				fISA += bal.Savings()
 			}
 		}
 		fmt.Fprintf(f, `<tr%s><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>
@@ -2866,14 +2993,14 @@ func generatePensionToISACombinedReport(results []DepletionResult, config *Confi
 		return err
 	}
 	defer f.Close()
-	bestIdx := FindBestDepletionStrategy(results)
+	bestIdx := FindBestDepletionStrategy(results, config)
 	ic := config.IncomeRequirements
 	refPerson := config.GetSimulationReferencePerson()
 	finalISA := 0.0
 	if bestIdx >= 0 && len(results[bestIdx].SimulationResult.Years) > 0 {
 		lastYear := results[bestIdx].SimulationResult.Years[len(results[bestIdx].SimulationResult.Years)-1]
 		for _, bal := range lastYear.EndBalances {
-			finalISA += bal.TaxFreeSavings
+			finalISA += bal.Savings()
 		}
 	}
 	fmt.Fprintf(f, `<!DOCTYPE html><html lang="en"><head><meta charset="UTF-8"><title>Pension-to-ISA Depletion</title>
@@ -2893,7 +3020,7 @@ func generatePensionToISACombinedReport(results []DepletionResult, config *Confi
 		if len(r.SimulationResult.Years) > 0 {
 			lastYear := r.SimulationResult.Years[len(r.SimulationResult.Years)-1]
 			for _, bal := range lastYear.EndBalances {
-				fISA += bal.TaxFreeSavings
+				fISA += bal.Savings()
 			}
 		}
 		fmt.Fprintf(f, `<tr%s><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>
@@ -2965,3 +3092,138 @@ func GeneratePensionToISASensitivityReport(analysis PensionToISASensitivityAnaly
 `, refPerson.Name, ic.TargetDepletionAge, FormatMoney(minIncome), FormatMoney(maxIncome), time.Now().Format("2006-01-02 15:04:05"))
 	return filename, nil
 }
+
+// GenerateRetirementSearchHTMLReports generates HTML reports for the earliest retirement date search
+func GenerateRetirementSearchHTMLReports(results []RetirementSearchResult, config *Config, outputDir string, timestamp string) (string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	combinedPath := filepath.Join(outputDir, "summary.html")
+	if err := generateRetirementSearchCombinedReport(results, config, combinedPath); err != nil {
+		return "", err
+	}
+	for _, r := range results {
+		if !r.Found {
+			continue
+		}
+		filename := fmt.Sprintf("report_%s.html", sanitizeFilename(r.Params.ShortName()))
+		reportPath := filepath.Join(outputDir, filename)
+		if err := GenerateHTMLReport(r.SimulationResult, config, reportPath); err != nil {
+			return "", err
+		}
+	}
+	return combinedPath, nil
+}
+
+func generateRetirementSearchCombinedReport(results []RetirementSearchResult, config *Config, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bestIdx := FindBestRetirementSearch(results)
+	refPerson := config.GetSimulationReferencePerson()
+	targetAge := GetRetirementSearchTargetAge(config)
+	if len(results) > 0 {
+		targetAge = results[0].TargetAge
+	}
+	earliest, vsPlan, bestName := "Not viable", "-", "-"
+	if bestIdx >= 0 {
+		earliest = formatRetirementDates(results[bestIdx], config)
+		vsPlan = formatYearsEarlier(results[bestIdx].YearsEarlier)
+		bestName = results[bestIdx].Params.ShortName()
+	}
+	fmt.Fprintf(f, `<!DOCTYPE html><html lang="en"><head><meta charset="UTF-8"><title>Earliest Retirement Date</title>
+<style>body{font-family:system-ui;background:#f1f5f9;margin:0;padding:0}.header{background:linear-gradient(135deg,#2563eb,#1d4ed8);color:#fff;padding:2rem;text-align:center}.container{max-width:1200px;margin:0 auto;padding:1.5rem}.card{background:#fff;border-radius:8px;box-shadow:0 1px 3px rgba(0,0,0,.1);padding:1.5rem;margin-bottom:1.5rem}.metrics{display:grid;grid-template-columns:repeat(auto-fit,minmax(200px,1fr));gap:1rem;margin-bottom:1.5rem}.metric{text-align:center;padding:1rem;background:#f1f5f9;border-radius:8px}.metric-value{font-size:1.25rem;font-weight:700;color:#2563eb}.metric-label{font-size:.875rem;color:#64748b}table{width:100%%;border-collapse:collapse}th,td{padding:.75rem;text-align:left;border-bottom:1px solid #e2e8f0}th{background:#f1f5f9}.highlight{background:#dbeafe}.footer{text-align:center;padding:1rem;color:#64748b}</style></head>
+<body><div class="header"><h1>When Can I Retire?</h1><p>Earliest retirement date that funds spending until age %d (%s)</p></div>
+<div class="container"><div class="metrics"><div class="metric"><div class="metric-value">%s</div><div class="metric-label">Earliest Retirement</div></div>
+<div class="metric"><div class="metric-value">%s</div><div class="metric-label">Compared to Plan</div></div>
+<div class="metric"><div class="metric-value">%s</div><div class="metric-label">Best Strategy</div></div></div>
+<div class="card"><h2>Strategy Comparison</h2><table><thead><tr><th>Strategy</th><th>Earliest Retirement</th><th>vs Plan</th><th>Total Tax</th><th>Final Balance</th></tr></thead><tbody>
+`, targetAge, refPerson.Name, earliest, vsPlan, bestName)
+	for i, r := range results {
+		rowClass := ""
+		if i == bestIdx {
+			rowClass = ` class="highlight"`
+		}
+		if !r.Found {
+			fmt.Fprintf(f, `<tr%s><td>%s</td><td>Not viable by age %d</td><td>-</td><td>-</td><td>-</td></tr>
+`, rowClass, r.Params.ShortName(), maxSearchRetirementAge)
+			continue
+		}
+		fmt.Fprintf(f, `<tr%s><td><a href="report_%s.html">%s</a></td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>
+`, rowClass, sanitizeFilename(r.Params.ShortName()), r.Params.ShortName(), formatRetirementDates(r, config), formatYearsEarlier(r.YearsEarlier),
+			FormatMoney(r.SimulationResult.TotalTaxPaid), FormatMoney(getTotalFinalBalance(r.SimulationResult)))
+	}
+	fmt.Fprintf(f, `</tbody></table></div><div class="card"><h2>How It Works</h2><ul><li><strong>Search:</strong> Retirement dates are moved a whole year at a time, keeping the same day and month</li><li><strong>Viable:</strong> The plan funds the configured spending until age %d without running out of money</li><li><strong>Pension Access:</strong> Pension access age stays as planned when the retirement date moves</li></ul></div>
+<div class="footer">Generated on %s | Earliest Retirement Date</div></div></body></html>
+`, targetAge, time.Now().Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// GenerateSavingsSearchHTMLReports generates HTML reports for the required extra saving search
+func GenerateSavingsSearchHTMLReports(results []SavingsSearchResult, config *Config, outputDir string, timestamp string) (string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	combinedPath := filepath.Join(outputDir, "summary.html")
+	if err := generateSavingsSearchCombinedReport(results, config, combinedPath); err != nil {
+		return "", err
+	}
+	for _, r := range results {
+		if !r.Found {
+			continue
+		}
+		filename := fmt.Sprintf("report_%s.html", sanitizeFilename(r.Params.ShortName()))
+		reportPath := filepath.Join(outputDir, filename)
+		if err := GenerateHTMLReport(r.SimulationResult, config, reportPath); err != nil {
+			return "", err
+		}
+	}
+	return combinedPath, nil
+}
+
+func generateSavingsSearchCombinedReport(results []SavingsSearchResult, config *Config, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	bestIdx := FindBestSavingsSearch(results)
+	refPerson := config.GetSimulationReferencePerson()
+	description, least, total, bestName := "-", "Not achievable", "-", "-"
+	if len(results) > 0 {
+		description = describeSavingSearch(results[0])
+	}
+	if bestIdx >= 0 {
+		least = formatSavingAmount(results[bestIdx])
+		total = FormatMoney(totalExtraSaving(results[bestIdx]))
+		bestName = results[bestIdx].Params.ShortName()
+	}
+	fmt.Fprintf(f, `<!DOCTYPE html><html lang="en"><head><meta charset="UTF-8"><title>Required Extra Saving</title>
+<style>body{font-family:system-ui;background:#f1f5f9;margin:0;padding:0}.header{background:linear-gradient(135deg,#ea580c,#c2410c);color:#fff;padding:2rem;text-align:center}.container{max-width:1200px;margin:0 auto;padding:1.5rem}.card{background:#fff;border-radius:8px;box-shadow:0 1px 3px rgba(0,0,0,.1);padding:1.5rem;margin-bottom:1.5rem}.metrics{display:grid;grid-template-columns:repeat(auto-fit,minmax(200px,1fr));gap:1rem;margin-bottom:1.5rem}.metric{text-align:center;padding:1rem;background:#f1f5f9;border-radius:8px}.metric-value{font-size:1.5rem;font-weight:700;color:#ea580c}.metric-label{font-size:.875rem;color:#64748b}table{width:100%%;border-collapse:collapse}th,td{padding:.75rem;text-align:left;border-bottom:1px solid #e2e8f0}th{background:#f1f5f9}.highlight{background:#ffedd5}.footer{text-align:center;padding:1rem;color:#64748b}</style></head>
+<body><div class="header"><h1>Required Extra Saving</h1><p>Minimum saving for the plan to last until age %d (%s) | %s</p></div>
+<div class="container"><div class="metrics"><div class="metric"><div class="metric-value">%s</div><div class="metric-label">Least Extra Saving</div></div>
+<div class="metric"><div class="metric-value">%s</div><div class="metric-label">Total Saved</div></div>
+<div class="metric"><div class="metric-value">%s</div><div class="metric-label">Best Strategy</div></div></div>
+<div class="card"><h2>Strategy Comparison</h2><table><thead><tr><th>Strategy</th><th>Extra Saving</th><th>Total Saved</th><th>Without Extra Saving</th><th>Total Tax</th><th>Final Balance</th></tr></thead><tbody>
+`, config.Simulation.EndAge, refPerson.Name, description, least, total, bestName)
+	for i, r := range results {
+		rowClass := ""
+		if i == bestIdx {
+			rowClass = ` class="highlight"`
+		}
+		if !r.Found {
+			fmt.Fprintf(f, `<tr%s><td>%s</td><td>Not achievable</td><td>-</td><td>%s</td><td>-</td><td>-</td></tr>
+`, rowClass, r.Params.ShortName(), formatSavingBaseline(r))
+			continue
+		}
+		fmt.Fprintf(f, `<tr%s><td><a href="report_%s.html">%s</a></td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>
+`, rowClass, sanitizeFilename(r.Params.ShortName()), r.Params.ShortName(), formatSavingAmount(r), FormatMoney(totalExtraSaving(r)),
+			formatSavingBaseline(r), FormatMoney(r.SimulationResult.TotalTaxPaid), FormatMoney(getTotalFinalBalance(r.SimulationResult)))
+	}
+	fmt.Fprintf(f, `</tbody></table></div><div class="card"><h2>How It Works</h2><ul><li><strong>Search:</strong> The smallest extra saving that stops the plan running out of money before the end age</li><li><strong>Monthly Saving:</strong> Paid each tax year until retirement, at a fixed amount</li><li><strong>Pension:</strong> Amounts are net; 20%% basic-rate relief is added, capped at the annual allowance</li></ul></div>
+<div class="footer">Generated on %s | Required Extra Saving</div></div></body></html>
+`, time.Now().Format("2006-01-02 15:04:05"))
+	return nil
+}
@@ -501,7 +501,7 @@ func (b *InteractiveConfigBuilder) BuildDepletionConfig() *Config {
 	// Check for current employment (work income before retirement)
 	hasWork1 := b.promptString("  Currently employed? (y/n)", "n")
 	if strings.ToLower(hasWork1) == "y" || strings.ToLower(hasWork1) == "yes" {
-		person1.WorkIncome = b.promptMoney("    Annual salary (gross)", b.getDefaultMoney("person.work_income", 50000))
+		person1.WorkIncomeNet = b.promptMoney("    Monthly take-home pay", b.getDefaultMoney("person.work_income_net", 3000))
 	}
 	b.config.People = append(b.config.People, person1)
 
@@ -530,7 +530,7 @@ func (b *InteractiveConfigBuilder) BuildDepletionConfig() *Config {
 		// Check for current employment (work income before retirement)
 		hasWork2 := b.promptString("  Currently employed? (y/n)", "n")
 		if strings.ToLower(hasWork2) == "y" || strings.ToLower(hasWork2) == "yes" {
-			person2.WorkIncome = b.promptMoney("    Annual salary (gross)", b.getDefaultMoney("person2.work_income", 40000))
+			person2.WorkIncomeNet = b.promptMoney("    Monthly take-home pay", b.getDefaultMoney("person2.work_income_net", 2500))
 		}
 		b.config.People = append(b.config.People, person2)
 	}
@@ -656,7 +656,7 @@ func (b *InteractiveConfigBuilder) BuildFixedIncomeConfig() *Config {
 	// Check for current employment (work income before retirement)
 	hasWork1 := b.promptString("  Currently employed? (y/n)", "n")
 	if strings.ToLower(hasWork1) == "y" || strings.ToLower(hasWork1) == "yes" {
-		person1.WorkIncome = b.promptMoney("    Annual salary (gross)", b.getDefaultMoney("person.work_income", 50000))
+		person1.WorkIncomeNet = b.promptMoney("    Monthly take-home pay", b.getDefaultMoney("person.work_income_net", 3000))
 	}
 	b.config.People = append(b.config.People, person1)
 
@@ -685,7 +685,7 @@ func (b *InteractiveConfigBuilder) BuildFixedIncomeConfig() *Config {
 		// Check for current employment (work income before retirement)
 		hasWork2 := b.promptString("  Currently employed? (y/n)", "n")
 		if strings.ToLower(hasWork2) == "y" || strings.ToLower(hasWork2) == "yes" {
-			person2.WorkIncome = b.promptMoney("    Annual salary (gross)", b.getDefaultMoney("person2.work_income", 40000))
+			person2.WorkIncomeNet = b.promptMoney("    Monthly take-home pay", b.getDefaultMoney("person2.work_income_net", 2500))
 		}
 		b.config.People = append(b.config.People, person2)
 	}
@@ -797,11 +797,14 @@ func ValidateDepletionConfig(config *Config) []string {
 	if config.IncomeRequirements.TargetDepletionAge <= 0 {
 		missing = append(missing, "target_depletion_age")
 	}
-	if config.IncomeRequirements.IncomeRatioPhase1 <= 0 {
-		missing = append(missing, "income_ratio_phase1")
-	}
-	if config.IncomeRequirements.IncomeRatioPhase2 <= 0 {
-		missing = append(missing, "income_ratio_phase2")
+	// Check income ratios: either tiers or legacy values must be configured
+	if !config.IncomeRequirements.HasTiers() {
+		if config.IncomeRequirements.IncomeRatioPhase1 <= 0 {
+			missing = append(missing, "income_ratio_phase1 (or income tiers)")
+		}
+		if config.IncomeRequirements.IncomeRatioPhase2 <= 0 {
+			missing = append(missing, "income_ratio_phase2 (or income tiers)")
+		}
 	}
 
 	return missing
@@ -0,0 +1,74 @@
+package main
+
+import "math"
+
+// Each person's ISA subscriptions are counted per tax year in ISASubscribed. Everything saved
+// goes through SaveTaxFree, so money over the allowance is kept in the GIA (a taxable account,
+// separate from the ISA in TaxFreeSavings) and moved into the ISA as later allowances allow
+
+// ISAAllowanceLeft returns how much more can be paid into the person's ISA this tax year
+func (p *Person) ISAAllowanceLeft() float64 {
+	return math.Max(0, p.ISAAnnualLimit-p.ISASubscribed)
+}
+
+// Savings returns the person's ISA and GIA together
+func (p *Person) Savings() float64 {
+	return p.TaxFreeSavings + p.GIA
+}
+
+// SaveTaxFree adds money to the person's savings: into the ISA up to the allowance left,
+// the rest into the GIA. Returns the amount kept in the GIA
+func (p *Person) SaveTaxFree(amount float64) float64 {
+	if amount <= 0 {
+		return 0
+	}
+	toISA := math.Min(amount, p.ISAAllowanceLeft())
+	p.ISASubscribed += toISA
+	p.TaxFreeSavings += toISA
+	p.GIA += amount - toISA
+	p.SavedToGIA += amount - toISA
+	return amount - toISA
+}
+
+// StartTaxYear renews the ISA allowance and moves as much of the GIA into the ISA as it allows
+func (p *Person) StartTaxYear() {
+	p.ISASubscribed = 0
+	p.SavedToGIA = 0
+	moved := math.Min(p.GIA, p.ISAAnnualLimit)
+	p.GIA -= moved
+	p.TaxFreeSavings += moved
+	p.ISASubscribed += moved
+	p.ISAFromGIA = moved
+}
+
+// spendSavings takes amount out of the person's savings, from the GIA first
+func (p *Person) spendSavings(amount float64) {
+	fromGIA := math.Min(math.Max(p.GIA, 0), amount)
+	p.GIA -= fromGIA
+	p.TaxFreeSavings -= amount - fromGIA
+}
+
+// applyGIAGrowth grows the GIA at rate, less tax at taxRate on the gain, and returns the tax
+// Gains in a GIA are taxed each year as they arise, unlike those in the ISA
+func (p *Person) applyGIAGrowth(rate, taxRate float64) float64 {
+	gain := p.GIA * rate
+	tax := math.Max(0, gain*taxRate)
+	p.GIA += gain - tax
+	return tax
+}
+
+// saveExcess saves net income drawn over the need, shared equally between people: into each
+// ISA as far as this year's allowance allows, the rest into the GIA. Records it in breakdown
+func saveExcess(people []*Person, excess float64, breakdown *WithdrawalBreakdown) {
+	if excess <= 0.01 || len(people) == 0 {
+		return
+	}
+	share := excess / float64(len(people))
+	for _, p := range people {
+		toGIA := p.SaveTaxFree(share)
+		breakdown.ISADeposits[p.Name] += share - toGIA
+		breakdown.TotalISADeposits += share - toGIA
+		breakdown.GIADeposits[p.Name] += toGIA
+		breakdown.TotalGIADeposits += toGIA
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSaveExcess verifies excess income fills the ISA allowances left and the rest is saved in the GIA
+func TestSaveExcess(t *testing.T) {
+	james := &Person{Name: "James", ISAAnnualLimit: 20000, ISASubscribed: 15000}
+	delphine := &Person{Name: "Delphine", ISAAnnualLimit: 20000}
+	breakdown := NewWithdrawalBreakdown()
+
+	saveExcess([]*Person{james, delphine}, 30000, &breakdown)
+	if breakdown.ISADeposits["James"] != 5000 || breakdown.GIADeposits["James"] != 10000 {
+		t.Errorf("Expected James to save £5000 in the ISA and £10000 in the GIA, got £%.0f and £%.0f", breakdown.ISADeposits["James"], breakdown.GIADeposits["James"])
+	}
+	if breakdown.ISADeposits["Delphine"] != 15000 || breakdown.GIADeposits["Delphine"] != 0 {
+		t.Errorf("Expected Delphine to save £15000 in the ISA, got £%.0f", breakdown.ISADeposits["Delphine"])
+	}
+	if james.ISAAllowanceLeft() != 0 || james.SavedToGIA != 10000 || james.TaxFreeSavings != 5000 || james.GIA != 10000 {
+		t.Errorf("Expected James's allowance used and £10000 saved to the GIA, got £%.0f left, £%.0f ISA and £%.0f GIA",
+			james.ISAAllowanceLeft(), james.TaxFreeSavings, james.GIA)
+	}
+
+	// The next tax year renews the allowance and moves the GIA into the ISA
+	james.StartTaxYear()
+	if james.GIA != 0 || james.TaxFreeSavings != 15000 || james.ISASubscribed != 10000 || james.SavedToGIA != 0 {
+		t.Errorf("Expected the GIA moved into the new allowance, got £%.0f GIA, £%.0f ISA and £%.0f subscribed", james.GIA, james.TaxFreeSavings, james.ISASubscribed)
+	}
+}
+
+// TestApplyGrowth_GIATaxed verifies the same surplus ends lower in a GIA than in an ISA, as GIA growth is taxed
+func TestApplyGrowth_GIATaxed(t *testing.T) {
+	inISA := &Person{Name: "James", ISAAnnualLimit: 100000}
+	inGIA := &Person{Name: "Delphine"} // No ISA allowance, so the surplus stays in the GIA
+	inISA.SaveTaxFree(50000)
+	inGIA.SaveTaxFree(50000)
+	if inISA.TaxFreeSavings != 50000 || inGIA.GIA != 50000 || inGIA.TaxFreeSavings != 0 {
+		t.Fatalf("Expected £50000 in the ISA and £50000 in the GIA, got £%.0f and £%.0f", inISA.TaxFreeSavings, inGIA.GIA)
+	}
+
+	var tax float64
+	for year := 0; year < 10; year++ {
+		if got := ApplyGrowth(inISA, 0.05, 0.05, 0.20); got != 0 {
+			t.Errorf("Expected no tax on ISA growth, got £%.2f", got)
+		}
+		tax += ApplyGrowth(inGIA, 0.05, 0.05, 0.20)
+		inISA.StartTaxYear()
+		inGIA.StartTaxYear()
+	}
+
+	// 5% growth less 20% tax leaves 4% a year in the GIA
+	if want := 50000 * math.Pow(1.05, 10); math.Abs(inISA.Savings()-want) > 0.01 {
+		t.Errorf("Expected £%.2f in the ISA, got £%.2f", want, inISA.Savings())
+	}
+	if want := 50000 * math.Pow(1.04, 10); math.Abs(inGIA.Savings()-want) > 0.01 {
+		t.Errorf("Expected £%.2f in the GIA, got £%.2f", want, inGIA.Savings())
+	}
+	if inGIA.Savings() >= inISA.Savings() || tax <= 0 {
+		t.Errorf("Expected the GIA to end lower than the ISA after £%.0f tax, got £%.0f and £%.0f", tax, inGIA.Savings(), inISA.Savings())
+	}
+}
+
+// TestTakePCLSLumpSum_ISAAllowance verifies a PCLS over the ISA allowance is saved in the GIA
+func TestTakePCLSLumpSum_ISAAllowance(t *testing.T) {
+	person := &Person{Name: "James", UncrystallisedPot: 400000, ISAAnnualLimit: 20000}
+	TakePCLSLumpSum(person)
+	if person.TaxFreeSavings != 20000 || person.ISASubscribed != 20000 || person.GIA != 80000 {
+		t.Errorf("Expected £20000 in the ISA and £80000 in the GIA, got £%.0f and £%.0f", person.TaxFreeSavings, person.GIA)
+	}
+}
+
+// TestSimulation_ISALedger verifies no path subscribes more than the allowance in a tax year
+func TestSimulation_ISALedger(t *testing.T) {
+	config := createTestConfig()
+	config.People[0].ExtraLumpSum = 100000
+	config.People[1].DBPensionAmount = 20000
+	config.People[1].DBPensionStartAge = 60
+	config.People[1].DBPensionCommutation = 0.25
+	config.People[1].DBPensionCommuteFactor = 12
+
+	for _, params := range []SimulationParams{
+		{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: PensionToISA, MortgageOpt: PCLSMortgagePayoff},
+		{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: PensionToISAProactive, MortgageOpt: MortgageNormal},
+		{CrystallisationStrategy: UFPLSStrategy, DrawdownOrder: TaxOptimized, MortgageOpt: MortgageEarly},
+	} {
+		result := RunSimulationV2(params, config)
+		var saved float64
+		for _, year := range result.Years {
+			for name, bal := range year.EndBalances {
+				if bal.ISASubscribed > 20000.01 {
+					t.Errorf("%s %d: %s subscribed £%.0f, over the allowance", params.ShortName(), year.Year, name, bal.ISASubscribed)
+				}
+				if math.Abs(bal.ISASubscribed+bal.ISAAllowanceLeft-20000) > 0.01 {
+					t.Errorf("%s %d: expected subscriptions and allowance left to make £20000, got £%.0f and £%.0f", params.ShortName(), year.Year, bal.ISASubscribed, bal.ISAAllowanceLeft)
+				}
+				saved += bal.SavedToGIA
+			}
+		}
+		first := result.Years[0].EndBalances["James"]
+		if first.SavedToGIA < 80000 {
+			t.Errorf("%s: expected the lump sum over the allowance saved in the GIA, got £%.0f", params.ShortName(), first.SavedToGIA)
+		}
+		if saved < 80000 {
+			t.Errorf("%s: expected savings over the allowance in the GIA, got £%.0f", params.ShortName(), saved)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	jobRetention     = 10 * time.Minute       // How long a finished job's result stays available
+	jobEventInterval = 500 * time.Millisecond // How often progress is streamed to watchers
+)
+
+// jobAbandonDelay is how long a job keeps running with nobody watching (a variable so tests can shorten it)
+var jobAbandonDelay = 5 * time.Second
+
+// Job states
+const (
+	JobRunning   = "running"
+	JobDone      = "done"
+	JobCancelled = "cancelled"
+	JobFailed    = "failed"
+)
+
+// simulationJob is a simulation running in the background for the web UI
+type simulationJob struct {
+	id      string
+	control *RunControl
+	cancel  context.CancelFunc
+	done    chan struct{} // Closed when the job finishes
+
+	mu        sync.Mutex
+	status    string
+	result    any
+	err       string
+	best      string        // Best strategy seen so far
+	bestScore float64       // Score of the best strategy seen so far
+	bestIdx   int           // Index of the best strategy seen so far
+	watchers  int           // Open event streams
+	elapsed   time.Duration // Run time, once finished
+}
+
+// APIJobStatus reports a job's progress and, once it is done, its result
+type APIJobStatus struct {
+	Success        bool    `json:"success"`
+	Error          string  `json:"error,omitempty"`
+	JobID          string  `json:"job_id,omitempty"`
+	Status         string  `json:"status,omitempty"` // "running", "done", "cancelled", "failed"
+	Done           int     `json:"done"`             // Strategies (or searches) finished
+	Total          int     `json:"total"`            // Strategies (or searches) expected
+	Best           string  `json:"best,omitempty"`   // Best strategy so far (not for sensitivity)
+	ElapsedSeconds float64 `json:"elapsed_seconds"`  // Time since the job started
+	ETASeconds     float64 `json:"eta_seconds"`      // Estimated time remaining
+	Result         any     `json:"result,omitempty"` // Same response as the matching /api/simulate endpoint
+}
+
+// jobStore holds the running and recently finished jobs
+type jobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*simulationJob
+}
+
+func (s *jobStore) add(job *simulationJob) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobs == nil {
+		s.jobs = make(map[string]*simulationJob)
+	}
+	s.jobs[job.id] = job
+}
+
+func (s *jobStore) get(id string) *simulationJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[id]
+}
+
+func (s *jobStore) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+}
+
+// newJobID returns a random job ID
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newSimulationJob creates a job whose progress and cancellation are reported through config
+func newSimulationJob(config *Config) *simulationJob {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &simulationJob{
+		id:      newJobID(),
+		control: NewRunControl(ctx),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		status:  JobRunning,
+	}
+	config.Control = job.control
+	return job
+}
+
+// startJob runs fn for the job in the background
+// The job is cancelled if no event stream opens within jobAbandonDelay, and forgotten jobRetention after it finishes
+func (ws *WebServer) startJob(job *simulationJob, fn func() any) {
+	ws.jobs.add(job)
+	job.scheduleAbandonCheck()
+	go func() {
+		job.run(fn)
+		time.AfterFunc(jobRetention, func() { ws.jobs.remove(job.id) })
+	}()
+}
+
+// run runs fn and records how it finished
+func (j *simulationJob) run(fn func() any) {
+	defer close(j.done)
+	defer j.cancel()
+	defer func() {
+		// A job has no HTTP handler to recover a panic, so keep the server up
+		if r := recover(); r != nil {
+			log.Printf("Job %s failed: %v", j.id, r)
+			j.finish(JobFailed, nil, fmt.Sprintf("Simulation failed: %v", r))
+		}
+	}()
+
+	result := fn()
+	if j.control.Err() != nil {
+		j.finish(JobCancelled, nil, "Simulation cancelled")
+	} else {
+		j.finish(JobDone, result, "")
+	}
+}
+
+// finish records how the job ended
+func (j *simulationJob) finish(status string, result any, err string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status, j.result, j.err = status, result, err
+	j.elapsed = j.control.Progress().Elapsed
+}
+
+// trackBest reports the best strategy so far, scored as the final ranking will be
+// Ties go to the earlier strategy, as they do in topNTracker
+func (j *simulationJob) trackBest(config *Config, weights ScoringWeights) {
+	j.control.Observe(func(i int, result any) {
+		var name string
+		var score float64
+		switch r := result.(type) {
+		case SimulationResult:
+			name, score = r.Params.ShortName(), weights.ScoreResult(r, config.Financial.IncomeInflationRate)
+		case DepletionResult:
+			name, score = r.Params.ShortName(), weights.ScoreDepletionResult(r, config)
+		default:
+			return
+		}
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		if j.best == "" || score > j.bestScore || (score == j.bestScore && i < j.bestIdx) {
+			j.best, j.bestScore, j.bestIdx = name, score, i
+		}
+	})
+}
+
+// snapshot returns the job's progress, with its result once it is done
+func (j *simulationJob) snapshot() APIJobStatus {
+	progress := j.control.Progress()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := APIJobStatus{
+		Success:        j.status != JobFailed && j.status != JobCancelled,
+		Error:          j.err,
+		JobID:          j.id,
+		Status:         j.status,
+		Done:           progress.Done,
+		Total:          progress.Total,
+		Best:           j.best,
+		ElapsedSeconds: progress.Elapsed.Seconds(),
+		ETASeconds:     progress.ETA.Seconds(),
+		Result:         j.result,
+	}
+	if j.status != JobRunning {
+		status.ElapsedSeconds = j.elapsed.Seconds()
+		status.ETASeconds = 0
+	}
+	if j.status == JobDone {
+		status.Done = status.Total
+	}
+	return status
+}
+
+// watch records an event stream opening; the returned function records it closing
+// A running job is cancelled jobAbandonDelay after its last event stream closes
+func (j *simulationJob) watch() func() {
+	j.mu.Lock()
+	j.watchers++
+	j.mu.Unlock()
+
+	return func() {
+		j.mu.Lock()
+		j.watchers--
+		j.mu.Unlock()
+		j.scheduleAbandonCheck()
+	}
+}
+
+// scheduleAbandonCheck cancels the job if it is still running with nobody watching after jobAbandonDelay
+func (j *simulationJob) scheduleAbandonCheck() {
+	time.AfterFunc(jobAbandonDelay, func() {
+		j.mu.Lock()
+		abandoned := j.watchers == 0 && j.status == JobRunning
+		j.mu.Unlock()
+		if abandoned {
+			log.Printf("Job %s abandoned, cancelling", j.id)
+			j.cancel()
+		}
+	})
+}
+
+// handleStartSimulationJob starts a simulation job for any mode accepted by /api/simulate
+func (ws *WebServer) handleStartSimulationJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req APISimulationRequest
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(APIJobStatus{Success: false, Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	config := ws.buildConfig(&req)
+
+	// Save config to config.yaml for persistence
+	if err := SaveConfig(config, "config.yaml"); err != nil {
+		log.Printf("Warning: failed to save config: %v", err)
+	}
+
+	job := newSimulationJob(config)
+	switch req.Mode {
+	case "depletion", "pension-only", "pension-to-isa":
+		job.trackBest(config, config.Strategy.GetScoringWeights(parseOptimizationGoal(req.OptimizationGoal)))
+	default:
+		job.trackBest(config, config.Strategy.GetScoringWeights(OptimizeBalance))
+	}
+	ws.startJob(job, func() any { return ws.runSimulationRequest(config, &req) })
+
+	json.NewEncoder(w).Encode(job.snapshot())
+}
+
+// handleStartSensitivityJob starts a sensitivity grid job
+func (ws *WebServer) handleStartSensitivityJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req APISensitivityRequest
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		json.NewEncoder(w).Encode(APIJobStatus{Success: false, Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	config := ws.buildSensitivityConfig(&req)
+
+	// Save config to config.yaml for persistence
+	if err := SaveConfig(config, "config.yaml"); err != nil {
+		log.Printf("Warning: failed to save config: %v", err)
+	}
+
+	goal := parseOptimizationGoal(req.OptimizationGoal)
+	job := newSimulationJob(config)
+
+	// Each grid cell runs every strategy
+	cells := len(buildGrowthRates(config.Sensitivity.PensionGrowthMin, config.Sensitivity.PensionGrowthMax, config.Sensitivity.StepSize)) *
+		len(buildGrowthRates(config.Sensitivity.SavingsGrowthMin, config.Sensitivity.SavingsGrowthMax, config.Sensitivity.StepSize))
+	if req.Mode == "depletion" || req.Mode == "pension-only" || req.Mode == "pension-to-isa" {
+		job.control.Plan(cells * len(GetDepletionStrategiesForConfig(config)))
+	} else {
+		job.control.Plan(cells * len(GetStrategiesForConfig(config)))
+	}
+	ws.startJob(job, func() any { return ws.runSensitivityGrid(config, req.Mode, goal) })
+
+	json.NewEncoder(w).Encode(job.snapshot())
+}
+
+// handleJobStatus returns a job's progress, with its result once it is done
+func (ws *WebServer) handleJobStatus(w http.ResponseWriter, r *http.Request) {
+	job := ws.jobs.get(r.PathValue("id"))
+	w.Header().Set("Content-Type", "application/json")
+	if job == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIJobStatus{Success: false, Error: "Unknown job"})
+		return
+	}
+	json.NewEncoder(w).Encode(job.snapshot())
+}
+
+// handleCancelJob asks a running job to stop and returns without waiting for it
+// The job's event stream reports when it has stopped
+func (ws *WebServer) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	job := ws.jobs.get(r.PathValue("id"))
+	w.Header().Set("Content-Type", "application/json")
+	if job == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIJobStatus{Success: false, Error: "Unknown job"})
+		return
+	}
+	job.cancel()
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job.snapshot())
+}
+
+// handleJobEvents streams a job's progress as Server-Sent Events
+// "progress" events are sent while it runs, then one "done", "cancelled" or "failed" event
+func (ws *WebServer) handleJobEvents(w http.ResponseWriter, r *http.Request) {
+	job := ws.jobs.get(r.PathValue("id"))
+	if job == nil {
+		http.Error(w, "Unknown job", http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	defer job.watch()()
+
+	send := func(event string, status APIJobStatus) {
+		data, _ := json.Marshal(status)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+	}
+
+	ticker := time.NewTicker(jobEventInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-job.done:
+			status := job.snapshot()
+			send(status.Status, status)
+			return
+		case <-ticker.C:
+			send("progress", job.snapshot())
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
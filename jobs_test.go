@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSimulationJob_Done verifies a finished job reports its result and the same best strategy
+func TestSimulationJob_Done(t *testing.T) {
+	ws := &WebServer{}
+	config := createTestConfig()
+	req := &APISimulationRequest{Mode: "fixed"}
+
+	job := newSimulationJob(config)
+	job.trackBest(config, config.Strategy.GetScoringWeights(OptimizeBalance))
+	ws.startJob(job, func() any { return ws.runSimulationRequest(config, req) })
+	<-job.done
+
+	status := job.snapshot()
+	if status.Status != JobDone || !status.Success || status.Done != status.Total || status.Total == 0 {
+		t.Fatalf("Expected a successful job with all work done, got %s %d of %d", status.Status, status.Done, status.Total)
+	}
+	response, ok := status.Result.(APISimulationResponse)
+	if !ok || response.Best == nil {
+		t.Fatalf("Expected a simulation response, got %T", status.Result)
+	}
+	if status.Best != response.Best.ShortName {
+		t.Errorf("Expected best so far %s to match the final best %s", status.Best, response.Best.ShortName)
+	}
+	if ws.jobs.get(job.id) != job {
+		t.Error("Expected the finished job to be kept")
+	}
+}
+
+// TestSimulationJob_Cancel verifies cancelling a job stops it before all work is done
+func TestSimulationJob_Cancel(t *testing.T) {
+	ws := &WebServer{}
+	config := createTestConfig()
+	config.Simulation.Concurrency = 2
+
+	job := newSimulationJob(config)
+	ws.startJob(job, func() any {
+		return parallelMap(config.Control, 1000, config.Simulation.GetConcurrency(), func(i int) int {
+			time.Sleep(time.Millisecond)
+			return i
+		})
+	})
+	time.Sleep(20 * time.Millisecond)
+	job.cancel()
+	<-job.done
+
+	status := job.snapshot()
+	if status.Status != JobCancelled || status.Success || status.Result != nil {
+		t.Fatalf("Expected a cancelled job without a result, got %s", status.Status)
+	}
+	if status.Done >= 1000 {
+		t.Errorf("Expected the job to stop early, %d of 1000 done", status.Done)
+	}
+}
+
+// TestSimulationJob_Abandoned verifies a job nobody subscribes to is cancelled
+func TestSimulationJob_Abandoned(t *testing.T) {
+	defer func(delay time.Duration) { jobAbandonDelay = delay }(jobAbandonDelay)
+	jobAbandonDelay = 20 * time.Millisecond
+
+	ws := &WebServer{}
+	config := createTestConfig()
+	job := newSimulationJob(config)
+	ws.startJob(job, func() any {
+		<-config.Control.ctx.Done()
+		return nil
+	})
+
+	select {
+	case <-job.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected an unwatched job to be cancelled")
+	}
+	if status := job.snapshot(); status.Status != JobCancelled {
+		t.Errorf("Expected an abandoned job to be cancelled, got %s", status.Status)
+	}
+}
+
+// TestHandleCancelJob verifies cancelling returns at once and the job stops afterwards
+func TestHandleCancelJob(t *testing.T) {
+	ws := &WebServer{}
+	config := createTestConfig()
+	job := newSimulationJob(config)
+	release := make(chan struct{})
+	ws.startJob(job, func() any {
+		<-release // Stands in for a simulation slow to notice the cancel
+		return nil
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/jobs/{id}/cancel", ws.handleCancelJob)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/jobs/"+job.id+"/cancel", nil))
+
+	var status APIJobStatus
+	json.NewDecoder(rec.Body).Decode(&status)
+	if rec.Code != http.StatusAccepted || status.Status != JobRunning {
+		t.Errorf("Expected 202 while the job is still stopping, got %d %s", rec.Code, status.Status)
+	}
+
+	close(release)
+	<-job.done
+	if status := job.snapshot(); status.Status != JobCancelled {
+		t.Errorf("Expected the job to finish cancelled, got %s", status.Status)
+	}
+}
+
+// TestSimulationJob_Panic verifies a failing job is reported instead of stopping the server
+func TestSimulationJob_Panic(t *testing.T) {
+	ws := &WebServer{}
+	job := newSimulationJob(createTestConfig())
+	ws.startJob(job, func() any { panic("bad config") })
+	<-job.done
+
+	if status := job.snapshot(); status.Status != JobFailed || !strings.Contains(status.Error, "bad config") {
+		t.Errorf("Expected a failed job reporting the panic, got %s: %s", status.Status, status.Error)
+	}
+}
+
+// TestHandleJobEvents verifies a job started over the API streams progress then its result
+func TestHandleJobEvents(t *testing.T) {
+	t.Chdir(t.TempDir()) // Starting a job saves config.yaml
+	ws := NewWebServer(createTestConfig(), "")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/jobs/simulate", ws.handleStartSimulationJob)
+	mux.HandleFunc("/api/jobs/{id}", ws.handleJobStatus)
+	mux.HandleFunc("/api/jobs/{id}/events", ws.handleJobEvents)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	res, err := http.Post(server.URL+"/api/jobs/simulate", "application/json", strings.NewReader(`{"mode":"fixed"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var start APIJobStatus
+	json.NewDecoder(res.Body).Decode(&start)
+	res.Body.Close()
+	if !start.Success || start.JobID == "" || start.Status != JobRunning {
+		t.Fatalf("Expected a running job, got %+v", start)
+	}
+
+	events, err := http.Get(server.URL + "/api/jobs/" + start.JobID + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer events.Body.Close()
+	if ct := events.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %s", ct)
+	}
+
+	// Read events until the final one
+	var event string
+	var final struct {
+		APIJobStatus
+		Result APISimulationResponse `json:"result"`
+	}
+	scanner := bufio.NewScanner(events.Body)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event: ") {
+			event = strings.TrimPrefix(line, "event: ")
+		} else if strings.HasPrefix(line, "data: ") && event != "progress" {
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &final); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+	if event != JobDone || !final.Result.Success || len(final.Result.Results) == 0 {
+		t.Fatalf("Expected a done event with results, got %q", event)
+	}
+
+	missing, err := http.Get(server.URL + "/api/jobs/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing.Body.Close()
+	if missing.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown job, got %d", missing.StatusCode)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Lifetime ISA rules
+const (
+	LISAAnnualLimit   = 4000.0 // Most that can be paid in each tax year, within the ISA allowance
+	LISABonusRate     = 0.25   // Government bonus on contributions
+	LISAPenaltyRate   = 0.25   // Charge on withdrawals before LISAAccessAge
+	LISAContributeAge = 50     // No contributions from this age
+	LISAOpenAge       = 40     // Must be opened before this age
+	LISAAccessAge     = 60     // Withdrawals are tax-free from this age
+)
+
+// ageInTaxYear returns the person's age in tax year year
+func (p *Person) ageInTaxYear(year int) int {
+	if p.BirthDate != "" {
+		return GetAgeInTaxYear(p.BirthDate, year)
+	}
+	return year - p.BirthYear
+}
+
+// CanAccessLISA returns true if the person's Lifetime ISA can be drawn without the penalty in tax year year
+func (p *Person) CanAccessLISA(year int) bool {
+	return p.ageInTaxYear(year) >= LISAAccessAge
+}
+
+// ContributeLISA pays up to available of this year's surplus into the person's Lifetime ISA,
+// within their planned contribution, the £4k limit and the ISA allowance left
+// The contribution counts against the ISA allowance and earns the 25% bonus, so savings moved
+// from the GIA into the ISA this year give way to it
+// Returns the contribution and the bonus
+func (p *Person) ContributeLISA(available float64, year int) (float64, float64) {
+	if p.LISAContribution <= 0 || p.ageInTaxYear(year) >= LISAContributeAge {
+		return 0, 0
+	}
+	room := p.ISAAllowanceLeft() + p.ISAFromGIA
+	contribution := math.Min(available, math.Min(math.Min(p.LISAContribution, LISAAnnualLimit), room))
+	if contribution <= 0 {
+		return 0, 0
+	}
+	if over := contribution - p.ISAAllowanceLeft(); over > 0 {
+		p.ISAFromGIA -= over
+		p.ISASubscribed -= over
+		p.TaxFreeSavings -= over
+		p.GIA += over
+	}
+	bonus := contribution * LISABonusRate
+	p.ISASubscribed += contribution
+	p.LISA += contribution + bonus
+	return contribution, bonus
+}
+
+// releaseLISA moves the person's Lifetime ISA into their tax-free savings once it can be drawn
+// without the penalty, so every drawdown strategy can use it
+func (p *Person) releaseLISA(year int) {
+	if p.LISA > 0 && p.CanAccessLISA(year) {
+		p.TaxFreeSavings += p.LISA
+		p.LISA = 0
+	}
+}
+
+// withdrawEarlyLISA meets what is still short of the need from Lifetime ISAs not yet accessible,
+// losing the withdrawal penalty. It is a last resort once everything else is spent
+func withdrawEarlyLISA(people []*Person, short float64, state *YearState) {
+	for _, p := range people {
+		if short <= 0.01 {
+			return
+		}
+		if p.LISA <= 0 {
+			continue
+		}
+		withdrawal := math.Min(p.LISA, short/(1-LISAPenaltyRate))
+		penalty := withdrawal * LISAPenaltyRate
+		p.LISA -= withdrawal
+		received := withdrawal - penalty
+
+		state.Withdrawals.TaxFreeFromISA[p.Name] += received
+		state.Withdrawals.TotalTaxFree += received
+		state.LISAPenalty[p.Name] += penalty
+		short -= received
+	}
+}
+
+// ValidateLifetimeISA checks each person's Lifetime ISA settings
+func ValidateLifetimeISA(config *Config) ConfigErrors {
+	var errs ConfigErrors
+	for i, pc := range config.People {
+		path := fmt.Sprintf("people[%d]", i)
+		if pc.LifetimeISA < 0 {
+			errs = append(errs, ConfigError{Path: path + ".lifetime_isa",
+				Message: fmt.Sprintf("expected a balance of 0 or more, got %g", pc.LifetimeISA)})
+		}
+		if pc.LISAContribution < 0 || pc.LISAContribution > LISAAnnualLimit {
+			errs = append(errs, ConfigError{Path: path + ".lisa_contribution",
+				Message: fmt.Sprintf("expected 0 to %g a year, got %g", LISAAnnualLimit, pc.LISAContribution)})
+		}
+		if pc.LISAContribution > 0 && pc.LifetimeISA == 0 && pc.BirthDate != "" && config.Simulation.StartYear > 0 &&
+			GetAgeInTaxYear(pc.BirthDate, config.Simulation.StartYear) >= LISAOpenAge {
+			errs = append(errs, ConfigError{Path: path + ".lisa_contribution",
+				Message: fmt.Sprintf("a Lifetime ISA must be opened before %d", LISAOpenAge)})
+		}
+	}
+	return errs
+}
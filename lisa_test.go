@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestContributeLISA verifies contributions earn the bonus within the £4k limit and the ISA allowance
+func TestContributeLISA(t *testing.T) {
+	p := &Person{Name: "James", BirthYear: 1991, ISAAnnualLimit: 20000, LISAContribution: 4000}
+	if paid, bonus := p.ContributeLISA(10000, 2026); paid != 4000 || bonus != 1000 || p.LISA != 5000 || p.ISASubscribed != 4000 {
+		t.Errorf("Expected £4000 paid in with a £1000 bonus, got £%.0f and £%.0f", paid, bonus)
+	}
+
+	// Only the ISA allowance left can be used
+	p.StartTaxYear()
+	p.ISASubscribed = 18000
+	if paid, bonus := p.ContributeLISA(10000, 2027); paid != 2000 || bonus != 500 {
+		t.Errorf("Expected £2000 within the ISA allowance, got £%.0f", paid)
+	}
+
+	// Savings moved from the GIA this year give way to the contribution
+	p.StartTaxYear()
+	p.GIA = 30000
+	p.StartTaxYear()
+	if paid, _ := p.ContributeLISA(10000, 2028); paid != 4000 || p.GIA != 14000 || p.ISASubscribed != 20000 {
+		t.Errorf("Expected £4000 paid in and £14000 left in the GIA, got £%.0f and £%.0f", paid, p.GIA)
+	}
+
+	// No contributions from 50
+	p.StartTaxYear()
+	if paid, _ := p.ContributeLISA(10000, 2041); paid != 0 {
+		t.Errorf("Expected no contributions at 50, got £%.0f", paid)
+	}
+}
+
+// TestLISAAccess verifies the Lifetime ISA joins savings at 60 and earlier withdrawals lose the penalty
+func TestLISAAccess(t *testing.T) {
+	p := &Person{Name: "James", BirthYear: 1970, LISA: 40000, TaxFreeSavings: 1000}
+	p.releaseLISA(2029)
+	if p.LISA != 40000 || p.CanAccessLISA(2029) {
+		t.Error("Expected the Lifetime ISA locked at 59")
+	}
+
+	state := NewYearState(2029)
+	withdrawEarlyLISA([]*Person{p}, 7500, &state)
+	if p.LISA != 30000 || state.LISAPenalty["James"] != 2500 || state.Withdrawals.TaxFreeFromISA["James"] != 7500 {
+		t.Errorf("Expected £10000 drawn for £7500 with a £2500 penalty, got £%.0f left and £%.0f penalty", p.LISA, state.LISAPenalty["James"])
+	}
+
+	p.releaseLISA(2030)
+	if p.LISA != 0 || p.TaxFreeSavings != 31000 {
+		t.Errorf("Expected the Lifetime ISA in savings at 60, got £%.0f savings", p.TaxFreeSavings)
+	}
+}
+
+// TestSimulation_LISA verifies surplus work income is paid into the Lifetime ISA until 50 and drawn from 60
+func TestSimulation_LISA(t *testing.T) {
+	config := &Config{
+		People: []PersonConfig{
+			{Name: "Worker", BirthDate: "1991-06-15", RetirementAge: 55, PensionAccessAge: 57, StatePensionAge: 68,
+				TaxFreeSavings: 20000, Pension: 200000, WorkIncome: 60000, LISAContribution: 4000},
+		},
+		Financial: FinancialConfig{
+			PensionGrowthRate:     0.04,
+			SavingsGrowthRate:     0.04,
+			IncomeInflationRate:   0.025,
+			StatePensionInflation: 0.025,
+			StatePensionAmount:    11500,
+		},
+		IncomeRequirements: IncomeConfig{MonthlyBeforeAge: 2000, MonthlyAfterAge: 2000, AgeThreshold: 68, ReferencePerson: "Worker"},
+		Simulation:         SimulationConfig{StartYear: 2026, EndAge: 65, ReferencePerson: "Worker"},
+		TaxBands:           ukTaxBands2024,
+	}
+	if err := ValidateLifetimeISA(config); err != nil {
+		t.Fatalf("Expected the config to be valid, got %v", err)
+	}
+
+	result := RunSimulationV2(SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: SavingsFirst}, config)
+	var bonus float64
+	for _, year := range result.Years {
+		age := year.Ages["Worker"]
+		paid := year.LISAContributions["Worker"]
+		if age < 50 && paid != 4000 {
+			t.Errorf("%d (age %d): expected £4000 paid in, got £%.0f", year.Year, age, paid)
+		}
+		if age >= 50 && paid != 0 {
+			t.Errorf("%d (age %d): expected no contributions, got £%.0f", year.Year, age, paid)
+		}
+		if lisa := year.EndBalances["Worker"].LISA; (lisa > 0) != (age < 60) {
+			t.Errorf("%d (age %d): unexpected Lifetime ISA balance £%.0f", year.Year, age, lisa)
+		}
+		if year.EndBalances["Worker"].ISASubscribed > 20000.01 {
+			t.Errorf("%d: expected the contributions within the ISA allowance, got £%.0f", year.Year, year.EndBalances["Worker"].ISASubscribed)
+		}
+		bonus += year.LISABonus["Worker"]
+	}
+	if bonus != 15*1000 {
+		t.Errorf("Expected 15 years of £1000 bonus, got £%.0f", bonus)
+	}
+}
+
+// TestValidateLifetimeISA verifies Lifetime ISA settings are checked
+func TestValidateLifetimeISA(t *testing.T) {
+	config := createTestConfig()
+	config.People[0].LISAContribution = 5000
+	config.People[1].LISAContribution = 4000
+	err := ValidateLifetimeISA(config)
+	for _, want := range []string{"people[0].lisa_contribution: expected 0 to 4000", "people[1].lisa_contribution: a Lifetime ISA must be opened before 40"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	}
+
+	config.People[1].LifetimeISA = 10000
+	config.People[0].LISAContribution = 0
+	if err := ValidateLifetimeISA(config); err != nil {
+		t.Errorf("Expected an opened Lifetime ISA to take contributions, got %v", err)
+	}
+}
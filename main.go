@@ -61,6 +61,8 @@ Examples:
   %s -ui                       Embedded browser mode (webview window)
   %s -web                      Web server mode (opens external browser)
   %s -web -addr :8080          Web server on specific port
+  %s -validate                 Check config.yaml for mistakes without running anything
+  %s -migrate                  Upgrade config.yaml to the current format (keeps a .bak copy)
 
   Fixed Income Mode:
   %s -html                     Generate HTML reports (how long funds last)
@@ -72,6 +74,24 @@ Examples:
   %s -depletion -html          Generate HTML reports with sustainable income
   %s -depletion -sensitivity   Sensitivity analysis (income vs growth rates)
 
+  Care Scenario:
+  %s -care                     Which strategies survive a later-life care episode
+
+  Retirement Date:
+  %s -earliest-retirement      Earliest viable retirement date (everyone moves together)
+  %s -earliest-retirement -retire-person James   Move one person's retirement date only
+
+  Required Saving:
+  %s -required-saving          Minimum extra monthly ISA saving before retirement
+  %s -required-saving -saving-type lump_sum -saving-wrapper pension   One-off pension top-up
+
+  Optimal Plan:
+  %s -optimal                  How far each strategy is from the best achievable plan
+  %s -optimal -optimal-objective tax -details   Minimise lifetime tax and show the plan
+
+  Adaptive Search:
+  %s -adaptive                 Search every strategy combination and per-person choice, running only the promising ones
+
 Configuration:
   Edit config.yaml to customize people, assets, income needs, and growth rates.
 
@@ -89,7 +109,7 @@ Configuration:
     sensitivity.pension_growth_min/max: Range for pension growth rates
     sensitivity.savings_growth_min/max: Range for ISA growth rates
     sensitivity.step_size: Increment between rates (e.g., 0.01 = 1%%)
-`, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+`, os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
 	}
 
 	// Command line flags
@@ -102,12 +122,34 @@ Configuration:
 	runDepletion := flag.Bool("depletion", false, "Run depletion mode: calculate sustainable income to deplete by target_depletion_age")
 	runPensionOnly := flag.Bool("pension-only", false, "Pension-only depletion: deplete pensions only, preserve ISAs")
 	runPensionToISA := flag.Bool("pension-to-isa", false, "PensionToISA depletion: efficiently move excess pension to ISAs")
+	runCare := flag.Bool("care", false, "Care scenario: check which strategies survive a later-life care episode")
+	runRetirementSearch := flag.Bool("earliest-retirement", false, "Find the earliest viable retirement date for each strategy")
+	retirePerson := flag.String("retire-person", "", "Person whose retirement date is searched (default: everyone jointly)")
+	runSavingsSearch := flag.Bool("required-saving", false, "Find the minimum extra saving before retirement for the plan to last")
+	savingPerson := flag.String("saving-person", "", "Person making the extra saving (default: simulation reference person)")
+	savingType := flag.String("saving-type", SavingTypeMonthly, "Extra saving type: monthly or lump_sum")
+	savingWrapper := flag.String("saving-wrapper", SavingWrapperISA, "Extra saving wrapper: isa or pension")
+	runOptimal := flag.Bool("optimal", false, "Compare every strategy with the Optimal year-by-year withdrawal plan")
+	optimalObjective := flag.String("optimal-objective", "", "Optimal plan objective: estate or tax (default: config optimal_objective)")
+	runAdaptive := flag.Bool("adaptive", false, "Search every strategy combination and per-person choice adaptively, running only the promising ones")
 	consoleMode := flag.Bool("console", false, "Use console interface instead of GUI (default is GUI)")
 	webMode := flag.Bool("web", false, "Start web server mode (opens external browser)")
 	uiMode := flag.Bool("ui", false, "Start embedded browser mode (webview window)")
 	webAddr := flag.String("addr", "localhost:0", "Web server address (for -web mode, use :0 for auto port)")
+	validateOnly := flag.Bool("validate", false, "Check the config file for unknown settings and invalid values, then exit")
+	migrateOnly := flag.Bool("migrate", false, "Upgrade the config file to the current format in place, keeping a backup, then exit")
 	flag.Parse()
 
+	// Validate the config file only
+	if *validateOnly {
+		os.Exit(runValidate(*configFile))
+	}
+
+	// Upgrade the config file only
+	if *migrateOnly {
+		os.Exit(runMigrate(*configFile))
+	}
+
 	// Embedded browser mode
 	if *uiMode {
 		err := runEmbeddedUI(*configFile)
@@ -122,9 +164,10 @@ Configuration:
 	if *webMode {
 		config, err := LoadConfig(*configFile)
 		if err != nil && !os.IsNotExist(err) {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error loading %s:\n%v\n", *configFile, err)
 			os.Exit(1)
 		}
+		printMigrationNote(*configFile, config)
 		server := NewWebServer(config, *webAddr)
 		if err := server.Start(); err != nil {
 			fmt.Fprintf(os.Stderr, "Web server error: %v\n", err)
@@ -137,11 +180,14 @@ Configuration:
 	// - Explicit -console flag, OR
 	// - Any output/mode flags set (for automation/scripting)
 	useConsole := *consoleMode || *runDepletion || *runSensitivity || *generateHTML ||
-		*showDetails || *showDrawdown || *yearDetail > 0 || *runPensionOnly || *runPensionToISA
+		*showDetails || *showDrawdown || *yearDetail > 0 || *runPensionOnly || *runPensionToISA || *runCare || *runRetirementSearch ||
+		*runSavingsSearch || *runOptimal || *runAdaptive
 
 	if useConsole {
 		runConsoleMode(*configFile, *showDetails, *showDrawdown, *yearDetail, *generateHTML,
-			*runSensitivity, *runDepletion, *runPensionOnly, *runPensionToISA)
+			*runSensitivity, *runDepletion, *runPensionOnly, *runPensionToISA, *runCare,
+			*runRetirementSearch, *retirePerson, *runSavingsSearch, *savingPerson, *savingType, *savingWrapper,
+			*runOptimal, *optimalObjective, *runAdaptive)
 		return
 	}
 
@@ -152,25 +198,98 @@ Configuration:
 		// Fall back to console mode if GUI fails
 		fmt.Println("Falling back to console mode...")
 		runConsoleMode(*configFile, *showDetails, *showDrawdown, *yearDetail, *generateHTML,
-			*runSensitivity, *runDepletion, *runPensionOnly, *runPensionToISA)
+			*runSensitivity, *runDepletion, *runPensionOnly, *runPensionToISA, *runCare,
+			*runRetirementSearch, *retirePerson, *runSavingsSearch, *savingPerson, *savingType, *savingWrapper,
+			*runOptimal, *optimalObjective, *runAdaptive)
+	}
+}
+
+// runValidate checks configFile and prints each problem as file:line: path: message,
+// returning the exit code (0 = valid)
+func runValidate(configFile string) int {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+		return 1
+	}
+	config, errs := ParseConfig([]byte(preprocessPercentages(string(data))))
+	if len(errs) == 0 {
+		fmt.Printf("%s: OK\n", configFile)
+		printMigrationNote(configFile, config)
+		return 0
+	}
+	printConfigErrors(configFile, errs)
+	return 1
+}
+
+// printConfigErrors prints each problem in a config file as file:line: path: message
+func printConfigErrors(configFile string, errs ConfigErrors) {
+	for _, e := range errs {
+		location := configFile
+		if e.Line > 0 {
+			location = fmt.Sprintf("%s:%d", configFile, e.Line)
+		}
+		if e.Path != "" {
+			fmt.Fprintf(os.Stderr, "%s: %s: %s\n", location, e.Path, e.Message)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", location, e.Message)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d problem(s) found\n", len(errs))
+}
+
+// runMigrate upgrades configFile to the current schema version in place, keeping a backup
+// of the original, and returns the exit code (0 = upgraded or already current)
+func runMigrate(configFile string) int {
+	backup, from, changes, err := MigrateConfigFile(configFile)
+	if err != nil {
+		if errs, ok := err.(ConfigErrors); ok {
+			printConfigErrors(configFile, errs)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error migrating config: %v\n", err)
+		}
+		return 1
+	}
+	if backup == "" {
+		fmt.Printf("%s: already at schema version %d\n", configFile, CurrentSchemaVersion)
+		return 0
+	}
+	fmt.Printf("%s: upgraded from schema version %d to %d (original saved as %s)\n", configFile, from, CurrentSchemaVersion, backup)
+	for _, change := range changes {
+		fmt.Printf("  - %s\n", change)
+	}
+	return 0
+}
+
+// printMigrationNote tells the user when their config uses legacy settings that were upgraded on load
+func printMigrationNote(configFile string, config *Config) {
+	if config == nil || len(config.Migrated) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Note: %s uses legacy settings, upgraded on load (run with -migrate to update the file):\n", configFile)
+	for _, change := range config.Migrated {
+		fmt.Fprintf(os.Stderr, "  - %s\n", change)
 	}
 }
 
 // runConsoleMode runs the application in console/terminal mode
 func runConsoleMode(configFile string, showDetails, showDrawdown bool, yearDetail int,
-	generateHTML, runSensitivity, runDepletion, runPensionOnly, runPensionToISA bool) {
+	generateHTML, runSensitivity, runDepletion, runPensionOnly, runPensionToISA, runCare bool,
+	runRetirementSearch bool, retirePerson string, runSavingsSearch bool, savingPerson, savingType, savingWrapper string,
+	runOptimal bool, optimalObjective string, runAdaptive bool) {
 
 	// Load configuration
 	config, err := LoadConfig(configFile)
 	configMissing := os.IsNotExist(err)
 
 	if err != nil && !configMissing {
-		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error loading %s:\n%v\n", configFile, err)
 		os.Exit(1)
 	}
+	printMigrationNote(configFile, config)
 
 	// If no specific mode flags set, ask user which mode they want
-	if !runDepletion && !runSensitivity && !generateHTML && !showDetails && !showDrawdown && yearDetail == 0 && !runPensionOnly && !runPensionToISA {
+	if !runDepletion && !runSensitivity && !generateHTML && !showDetails && !showDrawdown && yearDetail == 0 && !runPensionOnly && !runPensionToISA && !runCare && !runRetirementSearch && !runSavingsSearch && !runOptimal && !runAdaptive {
 		mode := promptForModeInitial(config, configMissing)
 		switch mode {
 		case "depletion":
@@ -222,6 +341,11 @@ func runConsoleMode(configFile string, showDetails, showDrawdown bool, yearDetai
 		fmt.Println()
 	}
 
+	// Reuse strategy results saved by earlier runs
+	if config.Simulation.CacheDir != "" {
+		config.Cache = NewResultCache(config.Simulation.CacheDir)
+	}
+
 	// Check if pension-only depletion mode is enabled
 	if runPensionOnly {
 		// Validate depletion config
@@ -285,14 +409,45 @@ func runConsoleMode(configFile string, showDetails, showDrawdown bool, yearDetai
 		return
 	}
 
+	// Check if care scenario mode is enabled
+	if runCare {
+		runCareMode(config, showDetails)
+		return
+	}
+
+	// Check if retirement date search mode is enabled
+	if runRetirementSearch {
+		runRetirementSearchMode(config, retirePerson, showDetails, generateHTML)
+		return
+	}
+
+	// Check if required saving search mode is enabled
+	if runSavingsSearch {
+		runSavingsSearchMode(config, savingPerson, savingType, savingWrapper, showDetails, generateHTML)
+		return
+	}
+
+	// Check if optimal plan comparison mode is enabled
+	if runOptimal {
+		runOptimalMode(config, optimalObjective, showDetails)
+		return
+	}
+
 	// Print header with configuration summary
 	PrintHeader(config)
 
 	// Get strategies based on whether there's a mortgage
-	strategies := GetStrategiesForConfig(config)
+	// The adaptive search generates only the factor combinations it runs
+	var strategies []SimulationParams
+	if !runAdaptive {
+		strategies = GetStrategiesForConfig(config)
+	}
 
 	// Run all strategies
-	if config.HasMortgage() {
+	if runAdaptive {
+		fmt.Println("Searching strategy combinations adaptively...")
+		fmt.Println()
+	} else if config.HasMortgage() {
 		fmt.Println("Running 16 scenarios (4 strategies × 4 mortgage options)...")
 		fmt.Println()
 		annualPayment := config.GetTotalAnnualPayment()
@@ -312,12 +467,14 @@ func runConsoleMode(configFile string, showDetails, showDrawdown bool, yearDetai
 		fmt.Println("Running 4 scenarios (no mortgage)...")
 		fmt.Println()
 	}
-	fmt.Println("  Drawdown Strategies:")
-	fmt.Println("    1. Savings First (ISA → Pension)")
-	fmt.Println("    2. Pension First (Pension → ISA)")
-	fmt.Println("    3. Tax Optimized (minimize tax)")
-	fmt.Println("    4. Pension to ISA (overdraw to fill tax bands)")
-	fmt.Println()
+	if !runAdaptive {
+		fmt.Println("  Drawdown Strategies:")
+		fmt.Println("    1. Savings First (ISA → Pension)")
+		fmt.Println("    2. Pension First (Pension → ISA)")
+		fmt.Println("    3. Tax Optimized (minimize tax)")
+		fmt.Println("    4. Pension to ISA (overdraw to fill tax bands)")
+		fmt.Println()
+	}
 
 	// Apply config settings to strategies
 	maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
@@ -326,9 +483,13 @@ func runConsoleMode(configFile string, showDetails, showDrawdown bool, yearDetai
 	}
 
 	var results []SimulationResult
-	for _, params := range strategies {
-		result := RunSimulation(params, config)
-		results = append(results, result)
+	if runAdaptive {
+		found := AdaptiveSearch(config, config.Strategy.GetScoringWeights(OptimizeBalance))
+		results = found.Results
+		fmt.Printf("Adaptive search ran %d of %d combinations\n", found.Evaluated, found.SpaceSize)
+		fmt.Println()
+	} else {
+		results = RunSimulations(strategies, config)
 	}
 
 	// Print individual results if details requested
@@ -339,36 +500,15 @@ func runConsoleMode(configFile string, showDetails, showDrawdown bool, yearDetai
 	}
 
 	// Print comparison of all strategies
-	PrintAllComparison(results)
+	PrintAllComparison(results, config)
+	PrintParetoFrontier(results, config)
 
 	// Show detailed drawdown breakdown if requested
 	if showDrawdown {
-		// Find best strategy (highest final balance among those that don't run out)
-		// This matches the criterion used in output.go and html_report.go
-		bestIdx := -1
-		bestBalance := -1.0
-		for i, r := range results {
-			if !r.RanOutOfMoney {
-				finalBalance := getTotalFinalBalance(r)
-				if bestBalance < 0 || finalBalance > bestBalance {
-					bestBalance = finalBalance
-					bestIdx = i
-				}
-			}
-		}
-		if bestIdx >= 0 {
+		// Find best strategy - the same choice as output.go and html_report.go
+		// (when all run out, the highest score is the one that lasts longest)
+		if bestIdx := FindBestResult(results, config); bestIdx >= 0 {
 			PrintDrawdownDetails(results[bestIdx], config)
-		} else {
-			// If all run out, show the one that lasts longest
-			longestIdx := 0
-			longestYear := 0
-			for i, r := range results {
-				if r.RanOutYear > longestYear {
-					longestYear = r.RanOutYear
-					longestIdx = i
-				}
-			}
-			PrintDrawdownDetails(results[longestIdx], config)
 		}
 	}
 
@@ -474,6 +614,137 @@ func runDepletionMode(config *Config, showDetails bool, generateHTML bool) {
 	}
 }
 
+// runCareMode runs every strategy with and without a later-life care episode
+func runCareMode(config *Config, showDetails bool) {
+	PrintHeader(config)
+
+	strategies := GetStrategiesForConfig(config)
+	maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
+	for i := range strategies {
+		strategies[i].MaximizeCoupleISA = maximizeCoupleISA
+	}
+
+	fmt.Printf("Running care scenario for %d strategies...\n", len(strategies))
+	results := RunCareScenarios(config, strategies)
+
+	if showDetails {
+		for _, r := range results {
+			PrintResultSummary(r.WithCare, config)
+		}
+	}
+
+	PrintCareScenarioComparison(results, config)
+}
+
+// runRetirementSearchMode finds the earliest viable retirement date for each strategy
+func runRetirementSearchMode(config *Config, retirePerson string, showDetails bool, generateHTML bool) {
+	PrintHeader(config)
+
+	var people []string
+	if retirePerson != "" {
+		if config.FindPerson(retirePerson) == nil {
+			fmt.Fprintf(os.Stderr, "Unknown person for -retire-person: %s\n", retirePerson)
+			os.Exit(1)
+		}
+		people = []string{retirePerson}
+	}
+	targetAge := GetRetirementSearchTargetAge(config)
+
+	fmt.Println("Searching for the earliest viable retirement date...")
+	results := RunAllRetirementSearches(config, people, targetAge)
+
+	if showDetails {
+		for _, r := range results {
+			if r.Found {
+				PrintResultSummary(r.SimulationResult, config)
+			}
+		}
+	}
+
+	PrintRetirementSearchComparison(results, config)
+
+	if generateHTML {
+		timestamp := time.Now().Format("2006-01-02_1504")
+		outputDir := fmt.Sprintf("reports_%s_retirement_date", timestamp)
+		combinedReport, err := GenerateRetirementSearchHTMLReports(results, config, outputDir, timestamp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating HTML reports: %v\n", err)
+		} else {
+			fmt.Printf("\nGenerated reports in %s/\n", outputDir)
+			openBrowser(combinedReport)
+		}
+	}
+}
+
+// runSavingsSearchMode finds the minimum extra saving before retirement for each strategy
+func runSavingsSearchMode(config *Config, savingPerson, savingType, savingWrapper string, showDetails bool, generateHTML bool) {
+	PrintHeader(config)
+
+	if savingPerson != "" && config.FindPerson(savingPerson) == nil {
+		fmt.Fprintf(os.Stderr, "Unknown person for -saving-person: %s\n", savingPerson)
+		os.Exit(1)
+	}
+	if savingType != SavingTypeMonthly && savingType != SavingTypeLumpSum {
+		fmt.Fprintf(os.Stderr, "Invalid -saving-type: %s (use monthly or lump_sum)\n", savingType)
+		os.Exit(1)
+	}
+	if savingWrapper != SavingWrapperISA && savingWrapper != SavingWrapperPension {
+		fmt.Fprintf(os.Stderr, "Invalid -saving-wrapper: %s (use isa or pension)\n", savingWrapper)
+		os.Exit(1)
+	}
+
+	fmt.Println("Searching for the minimum extra saving...")
+	results := RunAllSavingsSearches(config, savingPerson, savingType, savingWrapper)
+
+	if showDetails {
+		for _, r := range results {
+			if r.Found {
+				PrintResultSummary(r.SimulationResult, config)
+			}
+		}
+	}
+
+	PrintSavingsSearchComparison(results, config)
+
+	if generateHTML {
+		timestamp := time.Now().Format("2006-01-02_1504")
+		outputDir := fmt.Sprintf("reports_%s_required_saving", timestamp)
+		combinedReport, err := GenerateSavingsSearchHTMLReports(results, config, outputDir, timestamp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating HTML reports: %v\n", err)
+		} else {
+			fmt.Printf("\nGenerated reports in %s/\n", outputDir)
+			openBrowser(combinedReport)
+		}
+	}
+}
+
+// runOptimalMode compares each heuristic strategy with the Optimal withdrawal plan
+func runOptimalMode(config *Config, objective string, showDetails bool) {
+	PrintHeader(config)
+
+	if objective != "" {
+		if objective != OptimalObjectiveEstate && objective != OptimalObjectiveTax {
+			fmt.Fprintf(os.Stderr, "Invalid -optimal-objective: %s (use estate or tax)\n", objective)
+			os.Exit(1)
+		}
+		config.Strategy.OptimalObjective = objective
+	}
+
+	fmt.Println("Planning optimal withdrawals and running all strategies...")
+	results := RunOptimalComparison(config)
+
+	if showDetails {
+		for _, r := range results {
+			if r.Params.DrawdownOrder == Optimal {
+				PrintOptimalPlan(r)
+			}
+		}
+	}
+
+	PrintOptimalComparison(results, config)
+}
+
 // runDepletionSensitivity runs depletion mode across all growth rate combinations
 func runDepletionSensitivity(config *Config) {
 	fmt.Println("╔══════════════════════════════════════════════════════════════════════════════╗")
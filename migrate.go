@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentSchemaVersion is the config schema version written by this release
+// Configs without schema_version are version 0 and are upgraded when they are loaded
+const CurrentSchemaVersion = 3
+
+// configMigration upgrades a config by one schema version
+// Steps edit the YAML in place so comments and the order of settings are kept
+type configMigration struct {
+	Version     int                            // Schema version after this step
+	Description string                         // What the step changes
+	Apply       func(root *yaml.Node) []string // Returns a description of each change made
+}
+
+// configMigrations lists every migration in schema version order
+var configMigrations = []configMigration{
+	{1, "people.retirement_age → retirement_date", migrateRetirementDate},
+	{2, "income_requirements legacy before/after amounts and ratios → tiers", migrateIncomeTiers},
+	{3, "people.work_income → work_income_net", migrateWorkIncomeNet},
+}
+
+// migrateConfigDoc upgrades a parsed config document to CurrentSchemaVersion,
+// returning the schema version it was written for and a description of each change
+func migrateConfigDoc(doc *yaml.Node) (int, []string, *ConfigError) {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return CurrentSchemaVersion, nil, nil // Empty, or not a section of settings (reported by the decoder)
+	}
+	root := doc.Content[0]
+
+	version := 0
+	if i := mappingIndex(root, "schema_version"); i >= 0 {
+		key, value := root.Content[i], root.Content[i+1]
+		v, err := strconv.Atoi(value.Value)
+		switch {
+		case err != nil || value.Kind != yaml.ScalarNode:
+			return 0, nil, &ConfigError{Path: "schema_version", Line: key.Line,
+				Message: fmt.Sprintf("expected a whole number, got %q", value.Value)}
+		case v > CurrentSchemaVersion:
+			return 0, nil, &ConfigError{Path: "schema_version", Line: key.Line,
+				Message: fmt.Sprintf("version %d was written by a newer release (this release reads up to version %d)", v, CurrentSchemaVersion)}
+		case v < 0:
+			return 0, nil, &ConfigError{Path: "schema_version", Line: key.Line,
+				Message: fmt.Sprintf("must be between 0 and %d, got %d", CurrentSchemaVersion, v)}
+		}
+		version = v
+	}
+
+	var changes []string
+	for _, m := range configMigrations {
+		if m.Version > version {
+			changes = append(changes, m.Apply(root)...)
+		}
+	}
+	if version < CurrentSchemaVersion {
+		setSchemaVersion(root)
+	}
+	return version, changes, nil
+}
+
+// MigrateConfigData upgrades YAML config data to CurrentSchemaVersion, returning the upgraded YAML,
+// the schema version the data was written for and a description of each change
+// Data already at the current version is returned unchanged
+func MigrateConfigData(data []byte) ([]byte, int, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, 0, nil, ConfigErrors{yamlSyntaxError(err)}
+	}
+	from, changes, migrateErr := migrateConfigDoc(&doc)
+	if migrateErr != nil {
+		return nil, 0, nil, ConfigErrors{*migrateErr}
+	}
+	if from == CurrentSchemaVersion {
+		return data, from, nil, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(configIndent(data))
+	if err := enc.Encode(&doc); err != nil {
+		return nil, 0, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, 0, nil, err
+	}
+	return buf.Bytes(), from, changes, nil
+}
+
+// MigrateConfigFile upgrades a config file in place, first copying the original to
+// <filename>.v<version>.bak. Returns the backup file name (empty if the file was already
+// current), the schema version the file was written for and a description of each change
+func MigrateConfigFile(filename string) (string, int, []string, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return "", 0, nil, err
+	}
+	migrated, from, changes, err := MigrateConfigData(data)
+	if err != nil || from == CurrentSchemaVersion {
+		return "", from, nil, err
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", filename, from)
+	if err := os.WriteFile(backup, data, info.Mode().Perm()); err != nil {
+		return "", from, nil, err
+	}
+	if err := os.WriteFile(filename, migrated, info.Mode().Perm()); err != nil {
+		return "", from, nil, err
+	}
+	return backup, from, changes, nil
+}
+
+// migrateRetirementDate replaces each person's retirement_age with the retirement_date of
+// that birthday, which falls in the same tax year and gives the same age
+func migrateRetirementDate(root *yaml.Node) []string {
+	people := mappingValue(root, "people")
+	if people == nil || people.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var changes []string
+	for i, person := range people.Content {
+		value := mappingValue(person, "retirement_age")
+		if person.Kind != yaml.MappingNode || value == nil || value.Kind != yaml.ScalarNode {
+			continue
+		}
+		age, err := strconv.Atoi(value.Value)
+		if err != nil {
+			continue // Left for the decoder to report
+		}
+		path := fmt.Sprintf("people[%d]", i)
+		retirementDate := scalarValue(person, "retirement_date")
+		birth, birthErr := time.Parse("2006-01-02", scalarValue(person, "birth_date"))
+
+		switch {
+		case age == 0:
+			deleteMappingKey(person, "retirement_age")
+			changes = append(changes, path+".retirement_age: removed (not set)")
+		case retirementDate != "":
+			deleteMappingKey(person, "retirement_age")
+			changes = append(changes, fmt.Sprintf("%s.retirement_age: removed (retirement_date %s is used instead)", path, retirementDate))
+		case birthErr != nil:
+			continue // The date can't be worked out without a birth date
+		default:
+			date := birth.AddDate(age, 0, 0).Format("2006-01-02")
+			deleteMappingKey(person, "retirement_date")
+			k := mappingIndex(person, "retirement_age")
+			person.Content[k].Value = "retirement_date"
+			person.Content[k].LineComment = ""
+			person.Content[k+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle,
+				Value: date, Line: value.Line, Column: value.Column}
+			changes = append(changes, fmt.Sprintf("%s.retirement_age %d → retirement_date %s", path, age, date))
+		}
+	}
+	return changes
+}
+
+// legacyIncomeKeys are the income settings replaced by tiers
+var legacyIncomeKeys = []string{"monthly_before_age", "monthly_after_age", "income_ratio_phase1", "income_ratio_phase2"}
+
+// migrateIncomeTiers replaces the before/after age amounts and depletion ratios with income tiers
+// age_threshold is kept as it labels the phases in depletion reports
+func migrateIncomeTiers(root *yaml.Node) []string {
+	income := mappingValue(root, "income_requirements")
+	if income == nil || income.Kind != yaml.MappingNode {
+		return nil
+	}
+	var present []string // In file order
+	for i := 0; i+1 < len(income.Content); i += 2 {
+		for _, key := range legacyIncomeKeys {
+			if income.Content[i].Value == key {
+				present = append(present, key)
+			}
+		}
+	}
+	if len(present) == 0 {
+		return nil
+	}
+	var ic IncomeConfig
+	if err := income.Decode(&ic); err != nil {
+		return nil // Left for the decoder to report
+	}
+
+	first := income.Content[mappingIndex(income, present[0])]
+	legacy := ic.MonthlyBeforeAge != 0 || ic.MonthlyAfterAge != 0 || ic.IncomeRatioPhase1 != 0 || ic.IncomeRatioPhase2 != 0
+	if ic.HasTiers() || !legacy {
+		for _, key := range present {
+			deleteMappingKey(income, key)
+		}
+		reason := "not set"
+		if ic.HasTiers() {
+			reason = "tiers are used instead"
+		}
+		return []string{fmt.Sprintf("income_requirements.%s: removed (%s)", strings.Join(present, ", "), reason)}
+	}
+
+	ic.ConvertLegacyToTiers()
+	var tiers yaml.Node
+	if err := tiers.Encode(ic.Tiers); err != nil {
+		return nil
+	}
+	setNodeLine(&tiers, first.Line)
+
+	// The tiers go where the first legacy setting was
+	deleteMappingKey(income, "tiers")
+	i := mappingIndex(income, present[0])
+	income.Content[i] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "tiers",
+		HeadComment: first.HeadComment, Line: first.Line, Column: first.Column}
+	income.Content[i+1] = &tiers
+	for _, key := range present[1:] {
+		deleteMappingKey(income, key)
+	}
+	return []string{fmt.Sprintf("income_requirements.%s → tiers (%s)", strings.Join(present, ", "), ic.DescribeTiers(0))}
+}
+
+// migrateWorkIncomeNet replaces each person's annual work_income with the same amount per month
+// as work_income_net. work_income was never taxed by the simulation, so income is unchanged
+func migrateWorkIncomeNet(root *yaml.Node) []string {
+	people := mappingValue(root, "people")
+	if people == nil || people.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	var changes []string
+	for i, person := range people.Content {
+		value := mappingValue(person, "work_income")
+		if person.Kind != yaml.MappingNode || value == nil || value.Kind != yaml.ScalarNode {
+			continue
+		}
+		annual, err := strconv.ParseFloat(value.Value, 64)
+		if err != nil {
+			continue // Left for the decoder to report
+		}
+		path := fmt.Sprintf("people[%d]", i)
+		net, _ := strconv.ParseFloat(scalarValue(person, "work_income_net"), 64)
+
+		switch {
+		case annual == 0:
+			deleteMappingKey(person, "work_income")
+			changes = append(changes, path+".work_income: removed (not set)")
+		case net > 0:
+			deleteMappingKey(person, "work_income")
+			changes = append(changes, fmt.Sprintf("%s.work_income: removed (work_income_net %s is used instead)", path, scalarValue(person, "work_income_net")))
+		default:
+			monthly := strconv.FormatFloat(annual/12, 'f', -1, 64)
+			deleteMappingKey(person, "work_income_net")
+			k := mappingIndex(person, "work_income")
+			person.Content[k].Value = "work_income_net"
+			person.Content[k].LineComment = ""
+			person.Content[k+1] = &yaml.Node{
+				Kind:        yaml.ScalarNode,
+				Value:       monthly,
+				LineComment: "# from work_income " + value.Value + " a year - check this is take-home pay",
+				Line:        value.Line,
+				Column:      value.Column,
+			}
+			changes = append(changes, fmt.Sprintf("%s.work_income %s a year → work_income_net %s a month", path, value.Value, monthly))
+		}
+	}
+	return changes
+}
+
+// setSchemaVersion sets schema_version to CurrentSchemaVersion, adding it as the first setting if missing
+func setSchemaVersion(root *yaml.Node) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(CurrentSchemaVersion)}
+	if i := mappingIndex(root, "schema_version"); i >= 0 {
+		value.Line, value.Column = root.Content[i+1].Line, root.Content[i+1].Column
+		root.Content[i+1] = value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schema_version"}
+	if len(root.Content) > 0 {
+		// Keep a comment at the top of the file above the new setting
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// mappingIndex returns the index of key's node in a mapping's content, or -1 if it is not set
+func mappingIndex(mapping *yaml.Node, key string) int {
+	if mapping.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mappingValue returns the value node for key in a mapping, or nil if it is not set
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(mapping, key); i >= 0 {
+		return mapping.Content[i+1]
+	}
+	return nil
+}
+
+// scalarValue returns the text of a scalar setting in a mapping (empty if not set)
+func scalarValue(mapping *yaml.Node, key string) string {
+	if value := mappingValue(mapping, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// deleteMappingKey removes key and its value from a mapping
+func deleteMappingKey(mapping *yaml.Node, key string) {
+	if i := mappingIndex(mapping, key); i >= 0 {
+		mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+	}
+}
+
+// setNodeLine sets the line of a node and everything inside it, so errors in
+// settings added by a migration point at the setting they replaced
+func setNodeLine(node *yaml.Node, line int) {
+	node.Line = line
+	for _, child := range node.Content {
+		setNodeLine(child, line)
+	}
+}
+
+// configIndent returns the indent used by the YAML in data (default 4, as written by SaveConfig)
+func configIndent(data []byte) int {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || trimmed == line || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if indent := len(line) - len(trimmed); indent >= 2 {
+			return indent
+		}
+		break
+	}
+	return 4
+}
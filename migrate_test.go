@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite the golden files in testdata")
+
+// migrationGoldenFiles names the golden file test for each migration, by the version it upgrades to
+var migrationGoldenFiles = map[int]string{
+	1: "v1_retirement_date",
+	2: "v2_income_tiers",
+	3: "v3_work_income_net",
+}
+
+// withChanges appends the changes made to migrated YAML as comments, so the golden file checks both
+func withChanges(data []byte, changes []string) []byte {
+	var buf bytes.Buffer
+	buf.Write(data)
+	buf.WriteString("# Changes:\n")
+	for _, change := range changes {
+		buf.WriteString("#   " + change + "\n")
+	}
+	return buf.Bytes()
+}
+
+// checkGolden compares got with the golden file, rewriting it when run with -update
+func checkGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -run %s -update to create it)", err, t.Name())
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match, got:\n%s", path, got)
+	}
+}
+
+// TestConfigMigrations_Golden verifies each migration step against its golden file
+func TestConfigMigrations_Golden(t *testing.T) {
+	for i, m := range configMigrations {
+		if m.Version != i+1 {
+			t.Fatalf("Expected migration %d to upgrade to version %d, got %d", i, i+1, m.Version)
+		}
+		name, ok := migrationGoldenFiles[m.Version]
+		if !ok {
+			t.Errorf("Expected a golden file test for the migration to version %d (%s)", m.Version, m.Description)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			input := filepath.Join("testdata", "migrations", name+".yaml")
+			data, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc yaml.Node
+			if err := yaml.Unmarshal(data, &doc); err != nil {
+				t.Fatal(err)
+			}
+			changes := m.Apply(doc.Content[0])
+			if len(changes) == 0 {
+				t.Error("Expected the input to exercise the migration")
+			}
+
+			var buf bytes.Buffer
+			enc := yaml.NewEncoder(&buf)
+			enc.SetIndent(configIndent(data))
+			if err := enc.Encode(&doc); err != nil {
+				t.Fatal(err)
+			}
+			enc.Close()
+			checkGolden(t, strings.TrimSuffix(input, ".yaml")+".golden.yaml", withChanges(buf.Bytes(), changes))
+		})
+	}
+	if len(configMigrations) != CurrentSchemaVersion {
+		t.Errorf("Expected %d migrations for schema version %d, got %d", CurrentSchemaVersion, CurrentSchemaVersion, len(configMigrations))
+	}
+}
+
+// TestMigrateConfigData_Golden verifies a config from before schema versions is upgraded by every step
+// and that upgrading again changes nothing
+func TestMigrateConfigData_Golden(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "migrations", "legacy.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated, from, changes, err := MigrateConfigData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != 0 {
+		t.Errorf("Expected a config without schema_version to be version 0, got %d", from)
+	}
+	checkGolden(t, filepath.Join("testdata", "migrations", "legacy.golden.yaml"), withChanges(migrated, changes))
+
+	again, from, changes, err := MigrateConfigData(migrated)
+	if err != nil || from != CurrentSchemaVersion || len(changes) > 0 || !bytes.Equal(again, migrated) {
+		t.Errorf("Expected an upgraded config to be left alone, got version %d, changes %v, error %v", from, changes, err)
+	}
+}
+
+// TestMigrateConfigData_SameResults verifies upgrading a legacy config doesn't change the forecast
+func TestMigrateConfigData_SameResults(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "migrations", "legacy.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var legacy Config
+	if err := yaml.Unmarshal(data, &legacy); err != nil {
+		t.Fatal(err)
+	}
+	migrated, errs := ParseConfig(data)
+	if len(errs) > 0 {
+		t.Fatalf("Expected the legacy config to load, got:\n%v", errs)
+	}
+	if migrated.SchemaVersion != CurrentSchemaVersion || len(migrated.Migrated) == 0 {
+		t.Errorf("Expected the config to be upgraded on load, got version %d with changes %v", migrated.SchemaVersion, migrated.Migrated)
+	}
+	if legacy.IncomeRequirements.HasTiers() || !migrated.IncomeRequirements.HasTiers() {
+		t.Fatal("Expected legacy income settings to become tiers")
+	}
+
+	for i := range legacy.People {
+		legacyYear, legacyAge := legacy.People[i].GetRetirementInfo()
+		year, age := migrated.People[i].GetRetirementInfo()
+		if year != legacyYear || age != legacyAge {
+			t.Errorf("%s: expected retirement in %d at %d, got %d at %d", legacy.People[i].Name, legacyYear, legacyAge, year, age)
+		}
+	}
+
+	// Depletion mode uses the ratios
+	legacyDepletion, migratedDepletion := legacy.IncomeRequirements, migrated.IncomeRequirements
+	legacyDepletion.TargetDepletionAge, migratedDepletion.TargetDepletionAge = 92, 92
+	for age := 50; age <= 100; age++ {
+		if got, want := migratedDepletion.GetMonthlyIncomeForAge(age, 0, 1000), legacyDepletion.GetMonthlyIncomeForAge(age, 0, 1000); got != want {
+			t.Errorf("Depletion income at %d: expected £%.0f, got £%.0f", age, want, got)
+		}
+	}
+
+	for _, params := range GetStrategiesForConfig(&legacy)[:4] {
+		want := RunSimulation(params, &legacy)
+		got := RunSimulation(params, migrated)
+		if math.Abs(got.TotalTaxPaid-want.TotalTaxPaid) > 0.01 || math.Abs(getTotalFinalBalance(got)-getTotalFinalBalance(want)) > 0.01 {
+			t.Errorf("%s: expected £%.2f tax and £%.2f final balance, got £%.2f and £%.2f", params.ShortName(),
+				want.TotalTaxPaid, getTotalFinalBalance(want), got.TotalTaxPaid, getTotalFinalBalance(got))
+		}
+	}
+}
+
+// TestMigrateConfigData_PDFReport verifies the PDF plan of an upgraded config shows the work income
+// now held as take-home pay (work_income_net)
+func TestMigrateConfigData_PDFReport(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "migrations", "legacy.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, errs := ParseConfig(data)
+	if len(errs) > 0 {
+		t.Fatalf("Expected the legacy config to load, got:\n%v", errs)
+	}
+	result := RunSimulation(GetStrategiesForConfig(config)[0], config)
+
+	report := newPDFActionPlanReport(config, result)
+	report.pdf.SetCompression(false) // So the text can be searched
+	pdf, err := report.render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// James (£4,000) and Delphine (£2,500) both work through 2026/27
+	if want := "(" + formatMonthlyMoney(6500) + ")"; !bytes.Contains(pdf, []byte(want)) {
+		t.Errorf("Expected £6,500 a month of work income in the monthly schedule")
+	}
+}
+
+// TestMigrateConfigData_Version verifies schema versions this release can't read are reported
+func TestMigrateConfigData_Version(t *testing.T) {
+	_, _, _, err := MigrateConfigData([]byte("schema_version: 99\npeople: []\n"))
+	if errs, ok := err.(ConfigErrors); !ok || len(errs) != 1 || errs[0].Path != "schema_version" || errs[0].Line != 1 {
+		t.Errorf("Expected a schema_version error on line 1, got %v", err)
+	}
+
+	_, errs := ParseConfig([]byte("schema_version: latest\n"))
+	if len(errs) != 1 || errs[0].Message != `expected a whole number, got "latest"` {
+		t.Errorf("Expected a schema_version type error, got %v", errs)
+	}
+}
+
+// TestMigrateConfigFile verifies a config file is upgraded in place with a backup of the original
+func TestMigrateConfigFile(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "migrations", "legacy.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(filename, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, from, changes, err := MigrateConfigFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup != filename+".v0.bak" || from != 0 || len(changes) == 0 {
+		t.Errorf("Expected a v0 backup and changes, got %q, version %d, changes %v", backup, from, changes)
+	}
+	if saved, _ := os.ReadFile(backup); !bytes.Equal(saved, data) {
+		t.Error("Expected the backup to hold the original config")
+	}
+	config, err := LoadConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.SchemaVersion != CurrentSchemaVersion || len(config.Migrated) > 0 {
+		t.Errorf("Expected the file to be at version %d, got %d with changes %v", CurrentSchemaVersion, config.SchemaVersion, config.Migrated)
+	}
+
+	if backup, _, _, err := MigrateConfigFile(filename); backup != "" || err != nil {
+		t.Errorf("Expected an upgraded file to be left alone, got backup %q, error %v", backup, err)
+	}
+}
+
+// TestSaveConfig_SchemaVersion verifies saved configs are written at the current version
+// without legacy settings, even when they were set in code
+func TestSaveConfig_SchemaVersion(t *testing.T) {
+	config := createTestConfig()
+	config.People[0].RetirementDate = ""
+	config.People[0].RetirementAge = 60
+	config.People[0].WorkIncome = 36000
+	config.IncomeRequirements.Tiers = nil
+	config.IncomeRequirements.MonthlyBeforeAge = 3000
+	config.IncomeRequirements.MonthlyAfterAge = 2000
+	config.IncomeRequirements.AgeThreshold = 70
+
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	if err := SaveConfig(config, filename); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, legacy := range []string{"retirement_age:", "work_income:", "monthly_before_age:", "income_ratio_phase1:"} {
+		if strings.Contains(string(data), legacy) {
+			t.Errorf("Expected %s not to be saved", legacy)
+		}
+	}
+
+	saved, errs := ParseConfig(data)
+	if len(errs) > 0 {
+		t.Fatalf("Expected the saved config to load, got:\n%v", errs)
+	}
+	if saved.SchemaVersion != CurrentSchemaVersion || len(saved.Migrated) > 0 {
+		t.Errorf("Expected version %d with nothing to migrate, got %d with changes %v", CurrentSchemaVersion, saved.SchemaVersion, saved.Migrated)
+	}
+	if _, age := saved.People[0].GetRetirementInfo(); age != 60 || saved.People[0].WorkIncomeNet != 3000 {
+		t.Errorf("Expected retirement at 60 and £3000/month take-home, got %d and £%.0f", age, saved.People[0].WorkIncomeNet)
+	}
+	if got := saved.IncomeRequirements.DescribeTiers(0); got != "until 70: £3k/mo, 70+: £2k/mo" {
+		t.Errorf("Expected the legacy amounts as tiers, got %s", got)
+	}
+}
@@ -0,0 +1,603 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Dynamic programming settings for the Optimal drawdown plan
+const (
+	optimalBucketsPerEFold = 20  // Pension balances are grouped into buckets roughly 5% wide
+	optimalMaxStates       = 200 // States kept per year after merging buckets
+)
+
+// OptimalPlan is a year-by-year withdrawal plan for the Optimal drawdown order
+type OptimalPlan struct {
+	Objective string            // "estate" or "tax"
+	Years     []OptimalPlanYear // One entry per simulated tax year
+	Shortfall float64           // Modelled spending the plan could not fund (0 = fully funded)
+}
+
+// OptimalPlanYear holds the planned withdrawals for one tax year
+type OptimalPlanYear struct {
+	Year               int
+	TaxableWithdrawal  map[string]float64 // Taxable pension income drawn per person
+	FromCrystallised   map[string]float64 // Withdrawn from crystallised pots per person
+	FromUncrystallised map[string]float64 // Crystallised (or taken as UFPLS) per person
+	FromISA            float64            // Withdrawn from savings (GIA, then ISAs) to meet spending
+	ToISA              float64            // Excess pension income saved in ISAs, up to the allowance left
+	ToGIA              float64            // Excess pension income over the ISA allowance, saved in the GIA
+	Tax                float64            // Modelled income tax for the year
+}
+
+// ForYear returns the plan for a tax year, or nil if the plan does not cover it
+func (p *OptimalPlan) ForYear(year int) *OptimalPlanYear {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Years {
+		if p.Years[i].Year == year {
+			return &p.Years[i]
+		}
+	}
+	return nil
+}
+
+// optimalYearInputs holds the flows for one year that do not depend on withdrawal decisions
+// They are taken from a baseline simulation
+type optimalYearInputs struct {
+	Year          int
+	NetRequired   float64   // After-tax amount needed from ISAs and pensions
+	PensionRate   float64   // Pension growth rate
+	SavingsRate   float64   // ISA growth rate
+	OtherTaxable  []float64 // State, DB, part-time and work income per person
+	CanAccess     []bool    // Whether each person can draw their pension
+	ISAInflow     float64   // Extra ISA saving, surplus work income, DB lump sums and home sale proceeds
+	PensionInflow []float64 // Extra pension saving per person
+	TaxBands      []TaxBand // Inflated tax bands
+	Breakpoints   []float64 // Taxable incomes where the marginal rate changes
+}
+
+// optimalState is one node of the dynamic programme: balances after a year's decision
+type optimalState struct {
+	Uncryst   []float64
+	Cryst     []float64
+	ISA       float64
+	GIA       float64 // Savings held outside the ISA, moved in as later allowances allow
+	Tax       float64 // Tax paid to date
+	Shortfall float64 // Spending not funded to date
+	Parent    *optimalState
+
+	// Decision that led to this state
+	Taxable     []float64
+	FromCryst   []float64
+	FromUncryst []float64
+	FromISA     float64
+	ToISA       float64
+	ToGIA       float64
+	YearTax     float64
+}
+
+// wealth returns the ISA and pension balances of a state
+func (s *optimalState) wealth() float64 {
+	total := s.ISA + s.GIA
+	for i := range s.Uncryst {
+		total += s.Uncryst[i] + s.Cryst[i]
+	}
+	return total
+}
+
+// deferredTax estimates basic rate tax still due on the taxable part of pensions, so states
+// that defer withdrawals are not favoured just because their tax has not been paid yet
+func (s *optimalState) deferredTax() float64 {
+	total := 0.0
+	for i := range s.Uncryst {
+		total += (s.Uncryst[i]*0.75 + s.Cryst[i]) * 0.2
+	}
+	return total
+}
+
+// optimalModel is the withdrawal model searched by the dynamic programme
+type optimalModel struct {
+	strategy  Strategy
+	objective string
+	names     []string
+	pclsTaken []bool
+	startYear int
+	allowance float64 // Combined ISA allowance each tax year
+	giaTax    float64 // Tax rate on GIA growth
+	years     []optimalYearInputs
+}
+
+// BuildOptimalPlan finds the year-by-year withdrawals that maximise the final estate
+// (or minimise lifetime tax) for the configured spending.
+//
+// Each year every person can draw taxable pension income up to one of the tax band
+// breakpoints (the kinks where a linear programme's optimum lies) or not at all. Any shortfall
+// is met from ISAs and then by topping up the cheapest pension; any excess is recycled to
+// ISAs up to the annual allowance. One more choice meets the need from pensions before ISAs.
+// States with similar pension balances are merged, keeping the best so far, and only the
+// most promising states are carried into the next year.
+func BuildOptimalPlan(params SimulationParams, config *Config) *OptimalPlan {
+	model, initial := newOptimalModel(params, config)
+
+	states := []*optimalState{initial}
+	for yi := range model.years {
+		in := &model.years[yi]
+		final := yi == len(model.years)-1
+		// Buckets keep insertion order so the plan does not depend on map iteration order
+		buckets := make(map[string]int)
+		var next []*optimalState
+		add := func(s *optimalState) {
+			key := model.bucketKey(s)
+			if idx, ok := buckets[key]; !ok {
+				buckets[key] = len(next)
+				next = append(next, s)
+			} else if model.better(s, next[idx], final) {
+				next[idx] = s
+			}
+		}
+
+		zeros := make([]float64, len(model.names))
+		for _, s := range states {
+			if in.NetRequired <= 0 {
+				add(model.step(s, in, zeros, true))
+				continue
+			}
+			for _, taxable := range model.decisions(s, in) {
+				add(model.step(s, in, taxable, true))
+			}
+			add(model.step(s, in, zeros, false))
+		}
+
+		states = next
+		sort.SliceStable(states, func(a, b int) bool { return model.better(states[a], states[b], final) })
+		if len(states) > optimalMaxStates {
+			states = states[:optimalMaxStates]
+		}
+	}
+
+	return model.plan(states[0])
+}
+
+// newOptimalModel builds the model inputs from a baseline tax optimized simulation
+func newOptimalModel(params SimulationParams, config *Config) (*optimalModel, *optimalState) {
+	baseParams := params
+	baseParams.DrawdownOrder = TaxOptimized
+	baseParams.OptimalPlan = nil
+	baseline := RunSimulation(baseParams, config)
+
+	people := InitializePeople(config)
+	n := len(people)
+	model := &optimalModel{
+		strategy:  params.CrystallisationStrategy,
+		objective: config.Strategy.GetOptimalObjective(),
+		names:     make([]string, n),
+		pclsTaken: make([]bool, n),
+		startYear: config.Simulation.StartYear,
+		giaTax:    config.Tax.GetGIATaxRate(),
+	}
+	initial := &optimalState{Uncryst: make([]float64, n), Cryst: make([]float64, n)}
+	for i, p := range people {
+		model.names[i] = p.Name
+		model.pclsTaken[i] = p.PCLSTaken
+		initial.Uncryst[i] = p.UncrystallisedPot
+		initial.Cryst[i] = p.CrystallisedPot
+		initial.ISA += p.TaxFreeSavings
+		initial.GIA += p.GIA
+		model.allowance += p.ISAAnnualLimit
+	}
+
+	taperingThreshold := config.Tax.GetTaperingThreshold()
+	for _, ys := range baseline.Years {
+		in := optimalYearInputs{
+			Year:          ys.Year,
+			NetRequired:   ys.NetRequired,
+			PensionRate:   ys.PensionGrowthRateUsed,
+			SavingsRate:   ys.SavingsGrowthRateUsed,
+			OtherTaxable:  make([]float64, n),
+			CanAccess:     make([]bool, n),
+			PensionInflow: make([]float64, n),
+			ISAInflow:     ys.TotalISAContributions + ys.HomeSaleProceeds,
+			TaxBands:      InflateTaxBands(config.TaxBands, config.Simulation.StartYear, ys.Year, config.Financial.TaxBandInflation),
+		}
+		for _, band := range in.TaxBands {
+			if band.Upper < 1e8 {
+				in.Breakpoints = append(in.Breakpoints, band.Upper)
+			}
+		}
+		in.Breakpoints = append(in.Breakpoints, taperingThreshold)
+
+		yearsFromStart := ys.Year - config.Simulation.StartYear
+		for i, p := range people {
+			other := ys.StatePensionByPerson[p.Name] + ys.DBPensionByPerson[p.Name]
+			if p.IsReceivingPartTimeIncome(ys.Year) {
+				other += p.PartTimeIncome * math.Pow(1+config.Financial.IncomeInflationRate, float64(yearsFromStart))
+			}
+			if p.IsWorking(ys.Year) {
+				other += ys.WorkIncomeByPerson[p.Name]
+			}
+			in.OtherTaxable[i] = other
+			in.CanAccess[i] = p.CanAccessPension(ys.Year)
+
+			if p.ExtraSavingToPension {
+				in.PensionInflow[i] = ys.ExtraSavingByPerson[p.Name]
+			} else {
+				in.ISAInflow += ys.ExtraSavingByPerson[p.Name]
+			}
+
+			// DB pension lump sums are paid into ISAs in the first year of the DB pension
+			if p.ReceivesDBPension(ys.Year) && p.DBPensionCommutation > 0 {
+				startYear := p.BirthYear + p.DBPensionStartAge
+				if p.BirthDate != "" {
+					startYear = GetTaxYearForAge(p.BirthDate, p.DBPensionStartAge)
+				}
+				if ys.Year == startYear {
+					in.ISAInflow += p.GetDBPensionLumpSum()
+				}
+			}
+		}
+		model.years = append(model.years, in)
+	}
+	return model, initial
+}
+
+// decisions returns the taxable pension income combinations to try for a state
+// Each person either draws nothing or fills their taxable income to a breakpoint
+func (m *optimalModel) decisions(s *optimalState, in *optimalYearInputs) [][]float64 {
+	combos := [][]float64{make([]float64, len(m.names))}
+	for i := range m.names {
+		levels := []float64{0}
+		if in.CanAccess[i] && s.Uncryst[i]+s.Cryst[i] > 1 {
+			for _, bp := range in.Breakpoints {
+				if bp > in.OtherTaxable[i]+1 {
+					levels = append(levels, bp-in.OtherTaxable[i])
+				}
+			}
+		}
+
+		var next [][]float64
+		for _, combo := range combos {
+			for _, level := range levels {
+				c := make([]float64, len(combo))
+				copy(c, combo)
+				c[i] = level
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// step applies one year to a state: growth, inflows, then the decision
+// taxable is the taxable pension income each person draws before any top-up
+// If useISA is false the need is met from pensions before ISAs
+func (m *optimalModel) step(s *optimalState, in *optimalYearInputs, taxable []float64, useISA bool) *optimalState {
+	n := len(m.names)
+	next := &optimalState{
+		Uncryst:     make([]float64, n),
+		Cryst:       make([]float64, n),
+		ISA:         s.ISA,
+		GIA:         s.GIA,
+		Tax:         s.Tax,
+		Shortfall:   s.Shortfall,
+		Parent:      s,
+		Taxable:     make([]float64, n),
+		FromCryst:   make([]float64, n),
+		FromUncryst: make([]float64, n),
+	}
+	copy(next.Uncryst, s.Uncryst)
+	copy(next.Cryst, s.Cryst)
+
+	// Growth at the start of the year (except the first), then saving and other inflows
+	// GIA growth is taxed as it arises, as in the simulation
+	if in.Year > m.startYear {
+		next.ISA *= 1 + in.SavingsRate
+		giaTax := math.Max(0, next.GIA*in.SavingsRate*m.giaTax)
+		next.GIA += next.GIA*in.SavingsRate - giaTax
+		next.Tax += giaTax
+		for i := 0; i < n; i++ {
+			next.Uncryst[i] *= 1 + in.PensionRate
+			next.Cryst[i] *= 1 + in.PensionRate
+		}
+	}
+	// The new allowance first moves the GIA into ISAs; anything saved over it stays in the GIA
+	allowanceLeft := m.allowance
+	moved := math.Min(next.GIA, allowanceLeft)
+	next.GIA -= moved
+	allowanceLeft -= moved
+	next.ISA += moved
+	toISA := math.Min(in.ISAInflow, allowanceLeft)
+	allowanceLeft -= toISA
+	next.ISA += toISA
+	next.GIA += in.ISAInflow - toISA
+	for i := 0; i < n; i++ {
+		next.Uncryst[i] += in.PensionInflow[i]
+	}
+
+	// Tax due on other income is also paid from withdrawals
+	net := 0.0
+	for i := 0; i < n; i++ {
+		net -= CalculatePersonTax(in.OtherTaxable[i], 0, in.TaxBands)
+	}
+	for i := 0; i < n; i++ {
+		if taxable[i] > 0 && in.CanAccess[i] {
+			net += m.draw(next, in, i, taxable[i])
+		}
+	}
+
+	if in.NetRequired <= 0 {
+		// Other income covers spending - nothing to withdraw
+	} else if net >= in.NetRequired {
+		// Recycle the excess to ISAs up to the allowance left, and the rest to the GIA
+		excess := net - in.NetRequired
+		next.ToISA = math.Min(excess, allowanceLeft)
+		next.ToGIA = excess - next.ToISA
+		next.ISA += next.ToISA
+		next.GIA += next.ToGIA
+	} else {
+		shortfall := in.NetRequired - net
+		if useISA {
+			shortfall = m.takeISA(next, shortfall)
+		}
+		shortfall = m.topUp(next, in, shortfall)
+		if !useISA {
+			shortfall = m.takeISA(next, shortfall)
+		}
+		if shortfall > 1 {
+			next.Shortfall += shortfall
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		next.YearTax += CalculatePersonTax(in.OtherTaxable[i], next.Taxable[i], in.TaxBands)
+	}
+	next.Tax += next.YearTax
+	return next
+}
+
+// taxableFraction returns the share of an uncrystallised withdrawal that is taxable
+func (m *optimalModel) taxableFraction(i int) float64 {
+	if m.strategy != UFPLSStrategy && m.pclsTaken[i] {
+		return 1
+	}
+	return 0.75
+}
+
+// crystallisedAvailable returns the crystallised pot person i can draw (UFPLS only uses uncrystallised funds)
+func (m *optimalModel) crystallisedAvailable(s *optimalState, i int) float64 {
+	if m.strategy == UFPLSStrategy {
+		return 0
+	}
+	return s.Cryst[i]
+}
+
+// draw takes taxable pension income for person i (crystallised pot first) and returns the net received
+func (m *optimalModel) draw(s *optimalState, in *optimalYearInputs, i int, taxable float64) float64 {
+	fromCryst := math.Min(taxable, m.crystallisedAvailable(s, i))
+	f := m.taxableFraction(i)
+	fromUncryst := math.Min((taxable-fromCryst)/f, s.Uncryst[i])
+	s.Cryst[i] -= fromCryst
+	s.Uncryst[i] -= fromUncryst
+
+	drawn := fromCryst + fromUncryst*f
+	taxFree := fromUncryst * (1 - f)
+	tax := CalculateMarginalTax(drawn, in.OtherTaxable[i]+s.Taxable[i], in.TaxBands)
+
+	s.Taxable[i] += drawn
+	s.FromCryst[i] += fromCryst
+	s.FromUncryst[i] += fromUncryst
+	return drawn + taxFree - tax
+}
+
+// takeISA meets as much of a shortfall as possible from savings, the GIA first, and returns what is left
+func (m *optimalModel) takeISA(s *optimalState, shortfall float64) float64 {
+	if shortfall <= 0 {
+		return 0
+	}
+	fromGIA := math.Min(s.GIA, shortfall)
+	s.GIA -= fromGIA
+	take := math.Min(s.ISA, shortfall-fromGIA)
+	s.ISA -= take
+	s.FromISA += fromGIA + take
+	return shortfall - fromGIA - take
+}
+
+// topUp meets a shortfall from pensions, lowest marginal tax rate first, and returns what is left
+func (m *optimalModel) topUp(s *optimalState, in *optimalYearInputs, shortfall float64) float64 {
+	order := make([]int, 0, len(m.names))
+	for i := range m.names {
+		if in.CanAccess[i] && s.Uncryst[i]+s.Cryst[i] > 1 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return GetMarginalTaxRate(in.OtherTaxable[order[a]]+s.Taxable[order[a]], in.TaxBands) <
+			GetMarginalTaxRate(in.OtherTaxable[order[b]]+s.Taxable[order[b]], in.TaxBands)
+	})
+
+	for _, i := range order {
+		if shortfall <= 1 {
+			break
+		}
+		f := m.taxableFraction(i)
+		existing := in.OtherTaxable[i] + s.Taxable[i]
+		cryst := m.crystallisedAvailable(s, i)
+		netFor := func(taxable float64) float64 {
+			fromUncryst := math.Max(0, taxable-cryst) / f
+			return taxable + fromUncryst*(1-f) - CalculateMarginalTax(taxable, existing, in.TaxBands)
+		}
+
+		// Find the taxable income that nets the shortfall (or everything available)
+		low, high := 0.0, cryst+s.Uncryst[i]*f
+		if netFor(high) > shortfall {
+			for iter := 0; iter < 50 && high-low > 0.01; iter++ {
+				mid := (low + high) / 2
+				if netFor(mid) < shortfall {
+					low = mid
+				} else {
+					high = mid
+				}
+			}
+		}
+		shortfall -= m.draw(s, in, i, high)
+	}
+	return math.Max(shortfall, 0)
+}
+
+// bucketKey groups states with similar pension balances for each person
+func (m *optimalModel) bucketKey(s *optimalState) string {
+	key := make([]byte, 0, 8*len(s.Uncryst))
+	for i := range s.Uncryst {
+		bucket := int(math.Log1p((s.Uncryst[i]+s.Cryst[i])/1000) * optimalBucketsPerEFold)
+		key = strconv.AppendInt(key, int64(bucket), 10)
+		key = append(key, ',')
+	}
+	return string(key)
+}
+
+// better returns true if state a beats state b: less unfunded spending first, then the objective
+// Before the final year states are ranked by wealth after deferred tax under either objective,
+// as ranking partial plans by tax so far favours those that put off withdrawals
+func (m *optimalModel) better(a, b *optimalState, final bool) bool {
+	if math.Abs(a.Shortfall-b.Shortfall) > 1 {
+		return a.Shortfall < b.Shortfall
+	}
+	if !final {
+		return a.wealth()-a.deferredTax() > b.wealth()-b.deferredTax()
+	}
+	if m.objective == OptimalObjectiveTax && math.Abs(a.Tax-b.Tax) > 1 {
+		return a.Tax < b.Tax
+	}
+	return a.wealth() > b.wealth()
+}
+
+// plan walks back from the final state to build the year-by-year plan
+func (m *optimalModel) plan(final *optimalState) *OptimalPlan {
+	plan := &OptimalPlan{
+		Objective: m.objective,
+		Years:     make([]OptimalPlanYear, len(m.years)),
+		Shortfall: final.Shortfall,
+	}
+	s := final
+	for yi := len(m.years) - 1; yi >= 0 && s != nil && s.Parent != nil; yi-- {
+		py := OptimalPlanYear{
+			Year:               m.years[yi].Year,
+			TaxableWithdrawal:  make(map[string]float64),
+			FromCrystallised:   make(map[string]float64),
+			FromUncrystallised: make(map[string]float64),
+			FromISA:            s.FromISA,
+			ToISA:              s.ToISA,
+			ToGIA:              s.ToGIA,
+			Tax:                s.YearTax,
+		}
+		for i, name := range m.names {
+			py.TaxableWithdrawal[name] = s.Taxable[i]
+			py.FromCrystallised[name] = s.FromCryst[i]
+			py.FromUncrystallised[name] = s.FromUncryst[i]
+		}
+		plan.Years[yi] = py
+		s = s.Parent
+	}
+	return plan
+}
+
+// ExecuteOptimalDrawdown follows the Optimal plan for a year
+// Each person draws their planned taxable pension income, any excess is recycled to ISAs and
+// any shortfall (if returns differ from the plan) is met from ISAs and then pensions
+func ExecuteOptimalDrawdown(people []*Person, netNeeded float64, plan *OptimalPlanYear, strategy Strategy, year int, statePensionByPerson map[string]float64, taxBands []TaxBand) WithdrawalBreakdown {
+	if plan == nil {
+		return ExecuteOptimizedDrawdown(people, netNeeded, strategy, year, statePensionByPerson, taxBands)
+	}
+	breakdown := NewWithdrawalBreakdown()
+
+	// Tax due on other income is also paid from withdrawals
+	netFromPension := 0.0
+	for _, p := range people {
+		netFromPension -= CalculatePersonTax(statePensionByPerson[p.Name], 0, taxBands)
+	}
+	for _, p := range people {
+		target := plan.TaxableWithdrawal[p.Name]
+		if target <= 0 || !p.CanAccessPension(year) {
+			continue
+		}
+		taxable, taxFree := withdrawTaxablePension(p, target, strategy)
+		breakdown.TaxableFromPension[p.Name] += taxable
+		breakdown.TotalTaxable += taxable
+		breakdown.TaxFreeFromPension[p.Name] += taxFree
+		breakdown.TotalTaxFree += taxFree
+		netFromPension += taxable + taxFree - CalculateMarginalTax(taxable, statePensionByPerson[p.Name], taxBands)
+	}
+
+	if excess := netFromPension - netNeeded; excess > 0 {
+		for _, p := range people {
+			if excess <= 0 {
+				break
+			}
+			isaDeposit := math.Min(excess, p.ISAAllowanceLeft())
+			p.SaveTaxFree(isaDeposit)
+			breakdown.ISADeposits[p.Name] = isaDeposit
+			breakdown.TotalISADeposits += isaDeposit
+			excess -= isaDeposit
+		}
+		saveExcess(people, excess, &breakdown)
+	} else {
+		remaining := withdrawFromISAs(people, -excess, &breakdown)
+		if remaining > 1 {
+			withdrawFromPensionGrossedUp(people, remaining, strategy, year, &breakdown, statePensionByPerson, taxBands)
+		}
+	}
+
+	return breakdown
+}
+
+// withdrawTaxablePension draws a taxable amount from a person's pension, crystallised pot first
+// Returns the taxable and tax-free amounts withdrawn
+func withdrawTaxablePension(p *Person, target float64, strategy Strategy) (float64, float64) {
+	taxable := 0.0
+	if strategy != UFPLSStrategy {
+		taxable = WithdrawFromCrystallised(p, target)
+	}
+
+	remaining := target - taxable
+	if remaining <= 0.01 {
+		return taxable, 0
+	}
+
+	var result CrystallisationResult
+	if strategy == UFPLSStrategy {
+		result = UFPLSWithdraw(p, remaining/0.75)
+	} else if p.PCLSTaken {
+		result = GradualCrystallise(p, remaining)
+	} else {
+		result = GradualCrystallise(p, remaining/0.75)
+	}
+	return taxable + result.TaxablePortion, result.TaxFreePortion
+}
+
+// RunOptimalComparison runs every heuristic strategy alongside the Optimal plan
+// Optimal strategies are added even if include_optimal is not set
+func RunOptimalComparison(config *Config) []SimulationResult {
+	optimalConfig := *config
+	optimalConfig.Strategy.IncludeOptimal = true
+	strategies := GetStrategiesForConfig(&optimalConfig)
+
+	// Apply config settings to strategies
+	maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
+	for i := range strategies {
+		strategies[i].MaximizeCoupleISA = maximizeCoupleISA
+	}
+	return RunSimulations(strategies, &optimalConfig)
+}
+
+// FindOptimalResult returns the index of the Optimal result for a mortgage option, or -1 if none
+func FindOptimalResult(results []SimulationResult, mortgageOpt MortgageOption) int {
+	for i, r := range results {
+		if r.Params.DrawdownOrder == Optimal && r.Params.MortgageOpt == mortgageOpt {
+			return i
+		}
+	}
+	return -1
+}
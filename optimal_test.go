@@ -0,0 +1,169 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestBuildOptimalPlan_CoversEveryYear verifies the plan has an entry for each simulated year
+func TestBuildOptimalPlan_CoversEveryYear(t *testing.T) {
+	config := createDepletionTestConfig()
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: Optimal, MortgageOpt: MortgageNormal}
+
+	plan := BuildOptimalPlan(params, config)
+	baseline := RunSimulation(SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}, config)
+	if len(plan.Years) != len(baseline.Years) {
+		t.Fatalf("Expected %d plan years, got %d", len(baseline.Years), len(plan.Years))
+	}
+	for i, py := range plan.Years {
+		if py.Year != baseline.Years[i].Year {
+			t.Errorf("Plan year %d: expected %d, got %d", i, baseline.Years[i].Year, py.Year)
+		}
+		if _, ok := py.TaxableWithdrawal["James"]; !ok {
+			t.Errorf("Year %d: expected a planned withdrawal for James", py.Year)
+		}
+	}
+	if plan.Shortfall != 0 {
+		t.Errorf("Expected a fully funded plan, got shortfall £%.0f", plan.Shortfall)
+	}
+	if plan.ForYear(1900) != nil || (*OptimalPlan)(nil).ForYear(2030) != nil {
+		t.Error("Expected no plan outside the simulated years")
+	}
+}
+
+// TestOptimal_BeatsHeuristics verifies the Optimal plan does at least as well as every heuristic
+func TestOptimal_BeatsHeuristics(t *testing.T) {
+	config := createDepletionTestConfig()
+	optimal := RunSimulation(SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: Optimal, MortgageOpt: MortgageNormal}, config)
+	if optimal.RanOutOfMoney {
+		t.Fatalf("Expected the Optimal plan to last, ran out in %d", optimal.RanOutYear)
+	}
+
+	for _, order := range []DrawdownOrder{SavingsFirst, PensionFirst, TaxOptimized, PensionToISA, FillBasicRate, StatePensionBridge} {
+		r := RunSimulation(SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: order, MortgageOpt: MortgageNormal}, config)
+		if getTotalFinalBalance(r) > getTotalFinalBalance(optimal) {
+			t.Errorf("%s: final balance £%.0f beats Optimal £%.0f", order, getTotalFinalBalance(r), getTotalFinalBalance(optimal))
+		}
+	}
+
+	// Every year's spending is met in full
+	for _, year := range optimal.Years {
+		if year.NetIncomeReceived < year.TotalRequired-1 {
+			t.Errorf("Year %d: received £%.0f of £%.0f required", year.Year, year.NetIncomeReceived, year.TotalRequired)
+		}
+	}
+}
+
+// TestOptimal_TaxObjective verifies the tax objective pays no more tax than the tax optimized heuristic
+func TestOptimal_TaxObjective(t *testing.T) {
+	config := createDepletionTestConfig()
+	config.Strategy.OptimalObjective = OptimalObjectiveTax
+
+	optimal := RunSimulation(SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: Optimal, MortgageOpt: MortgageNormal}, config)
+	if optimal.Params.OptimalPlan == nil || optimal.Params.OptimalPlan.Objective != OptimalObjectiveTax {
+		t.Fatal("Expected the result to carry a tax objective plan")
+	}
+	taxOpt := RunSimulation(SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized, MortgageOpt: MortgageNormal}, config)
+	if optimal.TotalTaxPaid > taxOpt.TotalTaxPaid {
+		t.Errorf("Expected Optimal tax £%.0f to be no more than Tax Optimized £%.0f", optimal.TotalTaxPaid, taxOpt.TotalTaxPaid)
+	}
+}
+
+// TestExecuteOptimalDrawdown verifies the executor draws the planned taxable income and recycles the excess
+func TestExecuteOptimalDrawdown(t *testing.T) {
+	person := &Person{Name: "James", UncrystallisedPot: 200000, CrystallisedPot: 10000, TaxFreeSavings: 50000,
+		ISAAnnualLimit: 20000, PensionAccessAge: 55, BirthYear: 1970}
+	plan := &OptimalPlanYear{Year: 2030, TaxableWithdrawal: map[string]float64{"James": 37700}}
+	bands := []TaxBand{{Upper: 12570, Rate: 0}, {Lower: 12570, Upper: 50270, Rate: 0.2}, {Lower: 50270, Upper: 125140, Rate: 0.4}}
+
+	breakdown := ExecuteOptimalDrawdown([]*Person{person}, 15000, plan, GradualCrystallisation, 2030,
+		map[string]float64{"James": 12570}, bands)
+
+	if math.Abs(breakdown.TaxableFromPension["James"]-37700) > 0.01 {
+		t.Errorf("Expected £37,700 taxable, got £%.2f", breakdown.TaxableFromPension["James"])
+	}
+	// £10k crystallised, then £27,700 taxable needs £36,933 crystallised (£9,233 tax-free)
+	if math.Abs(breakdown.TaxFreeFromPension["James"]-27700/3.0) > 0.01 {
+		t.Errorf("Expected £9,233 tax-free, got £%.2f", breakdown.TaxFreeFromPension["James"])
+	}
+	if person.CrystallisedPot != 0 {
+		t.Errorf("Expected the crystallised pot to be drawn first, £%.0f left", person.CrystallisedPot)
+	}
+	// Net £39,393 against £15,000 needed - the ISA allowance is filled and the rest saved in the GIA
+	if breakdown.TotalISADeposits != 20000 || person.TaxFreeSavings != 70000 {
+		t.Errorf("Expected £20,000 recycled to ISA, got £%.0f (ISA £%.0f)", breakdown.TotalISADeposits, person.TaxFreeSavings)
+	}
+	if math.Abs(breakdown.TotalGIADeposits-person.GIA) > 0.01 || math.Abs(person.GIA-4393) > 1 {
+		t.Errorf("Expected £4,393 over the allowance in the GIA, got £%.2f", person.GIA)
+	}
+}
+
+// TestGetStrategiesForConfig_IncludeOptimal verifies Optimal is added per mortgage option only when enabled
+func TestGetStrategiesForConfig_IncludeOptimal(t *testing.T) {
+	config := createTestConfig()
+	base := GetStrategiesForConfig(config)
+
+	config.Strategy.IncludeOptimal = true
+	withOptimal := GetStrategiesForConfig(config)
+
+	var optimal []SimulationParams
+	for _, s := range withOptimal {
+		if s.DrawdownOrder == Optimal {
+			optimal = append(optimal, s)
+		}
+	}
+	if len(withOptimal) != len(base)+len(optimal) || len(optimal) == 0 {
+		t.Fatalf("Expected Optimal strategies to be added, got %d of %d", len(optimal), len(withOptimal))
+	}
+	for _, s := range optimal {
+		if s.MortgageOpt == PCLSMortgagePayoff {
+			t.Error("Expected no Optimal strategy for PCLS mortgage payoff")
+		}
+	}
+
+	config.Mortgage = MortgageConfig{}
+	config.Strategy.IncludeOptimal = true
+	if n := len(GetStrategiesForConfig(config)); n != 13 {
+		t.Errorf("Expected 12 heuristics plus Optimal without a mortgage, got %d", n)
+	}
+}
+
+// TestBuildOptimalPlan_ISAAllowance verifies the plan recycles no more into ISAs than the allowance
+func TestBuildOptimalPlan_ISAAllowance(t *testing.T) {
+	config := createDepletionTestConfig()
+	for i := range config.People {
+		config.People[i].ISAAnnualLimit = 5000
+	}
+	allowance := 5000 * float64(len(config.People))
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: Optimal, MortgageOpt: MortgageNormal}
+
+	plan := BuildOptimalPlan(params, config)
+	recycled := 0.0
+	for _, py := range plan.Years {
+		if py.ToISA > allowance+0.01 {
+			t.Errorf("Year %d: expected at most the £%.0f allowance recycled to ISAs, got £%.0f", py.Year, allowance, py.ToISA)
+		}
+		recycled += py.ToISA + py.ToGIA
+	}
+	if recycled == 0 {
+		t.Error("Expected the plan to recycle some excess pension income")
+	}
+}
+
+// TestNewOptimalModel_TaperingThreshold verifies the configured tapering threshold is a breakpoint
+func TestNewOptimalModel_TaperingThreshold(t *testing.T) {
+	config := createDepletionTestConfig()
+	config.Tax.TaperingThreshold = 90000
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: Optimal, MortgageOpt: MortgageNormal}
+
+	model, _ := newOptimalModel(params, config)
+	for _, in := range model.years {
+		found := false
+		for _, bp := range in.Breakpoints {
+			found = found || bp == 90000
+		}
+		if !found {
+			t.Fatalf("Year %d: expected a £90,000 breakpoint, got %v", in.Year, in.Breakpoints)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 )
 
@@ -9,7 +11,7 @@ import (
 func getTotalFinalBalance(r SimulationResult) float64 {
 	total := 0.0
 	for _, bal := range r.FinalBalances {
-		total += bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+		total += bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
 	}
 	return total
 }
@@ -41,16 +43,14 @@ func PrintHeader(config *Config) {
 
 	for _, p := range config.People {
 		birthYear := GetBirthYear(p.BirthDate)
-		pensionAccessAge := p.PensionAccessAge
-		if pensionAccessAge <= 0 {
-			pensionAccessAge = p.RetirementAge
-		}
-		if pensionAccessAge != p.RetirementAge {
+		pensionAccessAge := p.GetPensionAccessAge()
+		_, retirementAge := p.GetRetirementInfo()
+		if pensionAccessAge != retirementAge {
 			fmt.Printf("  %s: Born %d, Stop work at %d, Pension access at %d, State Pension at %d\n",
-				p.Name, birthYear, p.RetirementAge, pensionAccessAge, p.StatePensionAge)
+				p.Name, birthYear, retirementAge, pensionAccessAge, p.StatePensionAge)
 		} else {
 			fmt.Printf("  %s: Born %d, Retire at %d, State Pension at %d\n",
-				p.Name, birthYear, p.RetirementAge, p.StatePensionAge)
+				p.Name, birthYear, retirementAge, p.StatePensionAge)
 		}
 		fmt.Printf("          ISA: %s, Pension: %s\n",
 			FormatMoney(p.TaxFreeSavings), FormatMoney(p.Pension))
@@ -82,6 +82,9 @@ func PrintHeader(config *Config) {
 			config.IncomeRequirements.MonthlyAfterAge,
 			config.IncomeRequirements.AgeThreshold)
 	}
+	if config.IncomeRequirements.SpendingProfile != (SpendingProfileConfig{}) {
+		fmt.Printf("  Spending Profile: %s\n", config.IncomeRequirements.SpendingProfile.Describe())
+	}
 	// Show mortgage details
 	totalPayoff := config.GetTotalPayoffAmount(config.Mortgage.EndYear)
 	annualPayment := config.GetTotalAnnualPayment()
@@ -165,17 +168,20 @@ func PrintResultSummary(result SimulationResult, config *Config) {
 	if result.RanOutOfMoney {
 		fmt.Printf("  ⚠️  WARNING: Ran out of money in tax year %s\n", TaxYearLabel(result.RanOutYear))
 	}
+	for _, w := range CheckCompliance(result, config) {
+		fmt.Printf("  ⚠️  %s: %s\n", strings.ToUpper(w.Severity), w)
+	}
 
 	// Final balances
 	fmt.Println()
 	fmt.Println("Final Balances:")
 	var totalRemaining float64
 	for name, balances := range result.FinalBalances {
-		total := balances.TaxFreeSavings + balances.UncrystallisedPot + balances.CrystallisedPot
+		total := balances.Savings() + balances.UncrystallisedPot + balances.CrystallisedPot
 		if total > 0 {
 			fmt.Printf("  %s: ISA %s, Pension %s (total %s)\n",
 				name,
-				FormatMoney(balances.TaxFreeSavings),
+				FormatMoney(balances.Savings()),
 				FormatMoney(balances.CrystallisedPot+balances.UncrystallisedPot),
 				FormatMoney(total))
 		}
@@ -185,7 +191,7 @@ func PrintResultSummary(result SimulationResult, config *Config) {
 }
 
 // PrintAllComparison prints a comparison of all 4 strategy combinations
-func PrintAllComparison(results []SimulationResult) {
+func PrintAllComparison(results []SimulationResult, config *Config) {
 	fmt.Println()
 	fmt.Println("╔════════════════════════════════════════════════════════════════════════════════════════════════════╗")
 	fmt.Println("║                                    STRATEGY COMPARISON                                             ║")
@@ -219,7 +225,7 @@ func PrintAllComparison(results []SimulationResult) {
 	for _, r := range results {
 		var total float64
 		for _, b := range r.FinalBalances {
-			total += b.TaxFreeSavings + b.UncrystallisedPot + b.CrystallisedPot
+			total += b.Savings() + b.UncrystallisedPot + b.CrystallisedPot
 		}
 		fmt.Printf(" │ %-18s", FormatMoney(total))
 	}
@@ -236,12 +242,21 @@ func PrintAllComparison(results []SimulationResult) {
 	}
 	fmt.Println()
 
+	// Years With Cuts (only when some strategy had to cut discretionary spending)
+	if anyDiscretionaryCuts(results) {
+		fmt.Printf("%-25s", "Years With Cuts")
+		for _, r := range results {
+			fmt.Printf(" │ %-18s", fmt.Sprintf("%d (%.0f%%)", r.DiscretionaryCutYears, r.CutYearsPercent()))
+		}
+		fmt.Println()
+	}
+
 	// Savings Remaining (total at end)
 	fmt.Printf("%-25s", "Savings Remaining")
 	for _, r := range results {
 		var total float64
 		for _, b := range r.FinalBalances {
-			total += b.TaxFreeSavings + b.UncrystallisedPot + b.CrystallisedPot
+			total += b.Savings() + b.UncrystallisedPot + b.CrystallisedPot
 		}
 		fmt.Printf(" │ %-18s", FormatMoney(total))
 	}
@@ -249,32 +264,11 @@ func PrintAllComparison(results []SimulationResult) {
 
 	fmt.Println(strings.Repeat("─", 25+len(results)*22))
 
-	// Find best strategy (highest final balance among those that don't run out)
-	// This properly accounts for the trade-off between paying off mortgages early vs investing
-	var bestIdx int = -1
-	var bestFinalBalance float64 = -1
-	for i, r := range results {
-		if !r.RanOutOfMoney {
-			finalBalance := getTotalFinalBalance(r)
-			if bestFinalBalance < 0 || finalBalance > bestFinalBalance {
-				bestFinalBalance = finalBalance
-				bestIdx = i
-			}
-		}
-	}
-
-	// If all run out, find the one that lasts longest
-	var longestIdx int = 0
-	var longestYear int = 0
-	allRunOut := bestIdx < 0
-	if allRunOut {
-		for i, r := range results {
-			if r.RanOutYear > longestYear {
-				longestYear = r.RanOutYear
-				longestIdx = i
-			}
-		}
-	}
+	// Find best strategy (highest score: by default the highest final balance among those
+	// that don't run out, which accounts for paying off mortgages early vs investing)
+	bestIdx := FindBestResult(results, config)
+	allRunOut := bestIdx >= 0 && results[bestIdx].RanOutOfMoney
+	customScoring := config.Strategy.Scoring.IsSet()
 
 	// Recommendation
 	fmt.Println()
@@ -286,7 +280,7 @@ func PrintAllComparison(results []SimulationResult) {
 	if allRunOut {
 		fmt.Println("  ⚠️  WARNING: All strategies run out of money before age 90!")
 		fmt.Println()
-		best := results[longestIdx]
+		best := results[bestIdx]
 		fmt.Printf("  BEST OPTION: %s\n", best.Params.String())
 		fmt.Printf("  Lasts until: %d\n", best.RanOutYear)
 		fmt.Printf("  Total Tax: %s\n", FormatMoney(best.TotalTaxPaid))
@@ -294,7 +288,7 @@ func PrintAllComparison(results []SimulationResult) {
 		fmt.Println("  Comparison (by year money runs out):")
 		for _, r := range results {
 			marker := "  "
-			if r.RanOutYear == longestYear {
+			if r.RanOutYear == best.RanOutYear {
 				marker = "→ "
 			}
 			fmt.Printf("    %s%s: %d\n", marker, r.Params.ShortName(), r.RanOutYear)
@@ -315,7 +309,11 @@ func PrintAllComparison(results []SimulationResult) {
 				otherBalance := getTotalFinalBalance(r)
 				balanceDiff := bestBalance - otherBalance
 				taxDiff := r.TotalTaxPaid - best.TotalTaxPaid
-				fmt.Printf("    vs %s: %s more wealth", r.Params.ShortName(), FormatMoney(balanceDiff))
+				if balanceDiff >= 0 {
+					fmt.Printf("    vs %s: %s more wealth", r.Params.ShortName(), FormatMoney(balanceDiff))
+				} else {
+					fmt.Printf("    vs %s: %s less wealth", r.Params.ShortName(), FormatMoney(-balanceDiff))
+				}
 				if taxDiff != 0 {
 					if taxDiff > 0 {
 						fmt.Printf(", %s less tax", FormatMoney(taxDiff))
@@ -329,7 +327,10 @@ func PrintAllComparison(results []SimulationResult) {
 
 		fmt.Println()
 		fmt.Println("  Why this strategy wins:")
-		if best.Params.EarlyMortgagePayoff {
+		if customScoring {
+			fmt.Println("  - Highest score on your scoring weights")
+		}
+		if best.Params.PaysOffMortgageEarly() {
 			fmt.Println("  - Early mortgage payoff frees cash flow sooner")
 		} else {
 			fmt.Println("  - Keeping investments growing beats mortgage interest cost")
@@ -385,10 +386,10 @@ func PrintDetailedYear(year YearState, config *Config) {
 
 	fmt.Println("\nEnd Balances:")
 	for name, bal := range year.EndBalances {
-		total := bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+		total := bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
 		fmt.Printf("  %s: %s (ISA: %s, Pension: %s)\n",
 			name, FormatMoney(total),
-			FormatMoney(bal.TaxFreeSavings),
+			FormatMoney(bal.Savings()),
 			FormatMoney(bal.CrystallisedPot+bal.UncrystallisedPot))
 	}
 }
@@ -565,7 +566,7 @@ func PrintDrawdownDetails(result SimulationResult, config *Config) {
 
 					if isaWithdraw > 0 {
 						fmt.Printf("│     Extract from ISA: %10s  → remaining ISA: %s\n",
-							FormatMoney(isaWithdraw), FormatMoney(bal.TaxFreeSavings))
+							FormatMoney(isaWithdraw), FormatMoney(bal.Savings()))
 					}
 
 					if penTaxFree > 0 {
@@ -601,10 +602,10 @@ func PrintDrawdownDetails(result SimulationResult, config *Config) {
 			fmt.Println("│ END OF YEAR BALANCES:")
 			for _, name := range names {
 				bal := year.EndBalances[name]
-				total := bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+				total := bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
 				fmt.Printf("│   %s: ISA %s | Pension (cryst) %s | Pension (uncryst) %s | TOTAL: %s\n",
 					name,
-					FormatMoney(bal.TaxFreeSavings),
+					FormatMoney(bal.Savings()),
 					FormatMoney(bal.CrystallisedPot),
 					FormatMoney(bal.UncrystallisedPot),
 					FormatMoney(total))
@@ -628,8 +629,14 @@ func PrintDepletionHeader(config *Config) {
 	refPerson := config.GetSimulationReferencePerson()
 
 	fmt.Printf("Target: Deplete funds by age %d (%s)\n", ic.TargetDepletionAge, refPerson.Name)
+	if ic.HasTerminalTarget() {
+		fmt.Printf("Terminal Target: %s\n", describeTerminalTarget(ic))
+	}
 	fmt.Printf("Income Ratio: %.0f:%.0f (before/after age %d)\n",
 		ic.IncomeRatioPhase1, ic.IncomeRatioPhase2, ic.AgeThreshold)
+	if ic.SpendingProfile != (SpendingProfileConfig{}) {
+		fmt.Printf("Spending Profile: %s\n", ic.SpendingProfile.Describe())
+	}
 	fmt.Println()
 
 	fmt.Println("Configuration:")
@@ -653,6 +660,14 @@ func PrintDepletionHeader(config *Config) {
 	fmt.Println()
 }
 
+// bestDepletionLegend describes how the best depletion strategy was chosen
+func bestDepletionLegend(config *Config, legend string) string {
+	if config.Strategy.Scoring.IsSet() {
+		return "* = Best strategy (highest score on your scoring weights)"
+	}
+	return legend
+}
+
 // PrintDepletionComparison prints comparison of depletion mode results
 func PrintDepletionComparison(results []DepletionResult, config *Config) {
 	fmt.Println()
@@ -662,30 +677,40 @@ func PrintDepletionComparison(results []DepletionResult, config *Config) {
 	fmt.Println()
 
 	// Header
-	fmt.Printf("%-25s │ %15s │ %15s │ %12s │ %12s\n",
-		"Strategy", "Monthly (Before)", "Monthly (After)", "Annual", "Total Tax")
-	fmt.Println(strings.Repeat("─", 95))
+	fmt.Printf("%-25s │ %15s │ %15s │ %12s │ %12s │ %14s\n",
+		"Strategy", "Monthly (Before)", "Monthly (After)", "Annual", "Total Tax", "£100k Legacy")
+	fmt.Println(strings.Repeat("─", 112))
 
 	// Find best for highlighting
-	bestIdx := FindBestDepletionStrategy(results)
+	bestIdx := FindBestDepletionStrategy(results, config)
 
 	for i, r := range results {
 		marker := "  "
 		if i == bestIdx {
 			marker = "* "
+		} else if r.MinimumsUnmet {
+			marker = "! "
 		}
 		annualBefore := r.MonthlyBeforeAge * 12
-		fmt.Printf("%s%-23s │ %15s │ %15s │ %12s │ %12s\n",
+		fmt.Printf("%s%-23s │ %15s │ %15s │ %12s │ %12s │ %14s\n",
 			marker,
 			r.Params.ShortName(),
 			FormatMoney(r.MonthlyBeforeAge),
 			FormatMoney(r.MonthlyAfterAge),
 			FormatMoney(annualBefore),
-			FormatMoney(r.SimulationResult.TotalTaxPaid))
+			FormatMoney(r.SimulationResult.TotalTaxPaid),
+			FormatMoney(r.LegacyCostPer100k)+"/mo")
 	}
 
 	fmt.Println()
-	fmt.Println("* = Best strategy (highest sustainable income)")
+	fmt.Println(bestDepletionLegend(config, "* = Best strategy (highest sustainable income)"))
+	fmt.Println("£100k Legacy = monthly income (before age) given up to leave an extra £100k at the target age")
+	for _, r := range results {
+		if r.MinimumsUnmet {
+			fmt.Println("! = Tier minimums can't be met: the plan runs short of the target even at every min_monthly")
+			break
+		}
+	}
 	fmt.Println()
 
 	// Print recommendation
@@ -704,11 +729,78 @@ func PrintDepletionComparison(results []DepletionResult, config *Config) {
 			FormatMoney(best.MonthlyBeforeAge*12),
 			FormatMoney(best.MonthlyAfterAge*12))
 		fmt.Printf("  Total Lifetime Tax: %s\n", FormatMoney(best.SimulationResult.TotalTaxPaid))
-		fmt.Printf("  Balance at target age: %s\n", FormatMoney(best.ConvergenceError))
+		if best.MinimumsUnmet {
+			fmt.Println("  WARNING: no strategy can pay every tier's min_monthly to the target age;")
+			fmt.Println("           income is shown at the minimums, which run short")
+		}
+		if config.IncomeRequirements.HasTerminalTarget() {
+			fmt.Printf("  Terminal target: %s (%s at target age), surplus %s\n",
+				describeTerminalTarget(config.IncomeRequirements), FormatMoney(best.TerminalTarget.Total), FormatMoney(best.ConvergenceError))
+		} else {
+			fmt.Printf("  Balance at target age: %s\n", FormatMoney(best.ConvergenceError))
+		}
+		if best.LegacyCostPer100k > 0 {
+			fmt.Printf("  Each extra £100k of legacy costs %s/month\n", FormatMoney(best.LegacyCostPer100k))
+		}
+		if lines := describeTierAmounts(config.IncomeRequirements, best.TierAmounts); len(lines) > 0 {
+			fmt.Println("  Income by tier (today's money):")
+			for _, line := range lines {
+				fmt.Printf("    %s\n", line)
+			}
+		}
 		fmt.Println()
 	}
 }
 
+// describeTierAmounts describes the depletion amount of every income tier, noting how it was set
+func describeTierAmounts(ic IncomeConfig, amounts []float64) []string {
+	if len(amounts) != len(ic.Tiers) {
+		return nil
+	}
+	lines := make([]string, len(amounts))
+	for i, tier := range ic.Tiers {
+		var amount, source string
+		switch {
+		case tier.IsInvestmentGains:
+			amount, source = "investment gains", "fixed"
+		case tier.IsPercentage:
+			amount, source = fmt.Sprintf("%.1f%% of portfolio", amounts[i]), "fixed"
+		case tier.IsSolved():
+			amount, source = FormatMoney(amounts[i])+"/month", "solved"
+			if tier.MinMonthly > 0 && amounts[i] <= tier.MinMonthly+solvedTierTolerance {
+				source = "solved, at minimum"
+			} else if tier.MaxMonthly > 0 && amounts[i] >= tier.MaxMonthly-solvedTierTolerance {
+				source = "solved, at maximum"
+			}
+		case tier.Ratio > 0:
+			amount, source = FormatMoney(amounts[i])+"/month", fmt.Sprintf("ratio %.1f", tier.Ratio)
+		default:
+			amount, source = FormatMoney(amounts[i])+"/month", "fixed"
+		}
+		lines[i] = fmt.Sprintf("%-10s %s (%s)", tier.AgeRange()+":", amount, source)
+	}
+	return lines
+}
+
+// describeTerminalTarget describes the legacy and wrapper floors depletion mode aims to leave
+func describeTerminalTarget(ic IncomeConfig) string {
+	var parts []string
+	if ic.TargetLegacy > 0 {
+		parts = append(parts, "legacy "+FormatMoney(ic.TargetLegacy))
+	}
+	if ic.TargetISAFloor > 0 {
+		parts = append(parts, "ISA floor "+FormatMoney(ic.TargetISAFloor))
+	}
+	if ic.TargetPensionFloor > 0 {
+		parts = append(parts, "pension floor "+FormatMoney(ic.TargetPensionFloor))
+	}
+	terms := "nominal"
+	if ic.TargetLegacyReal {
+		terms = "today's money"
+	}
+	return strings.Join(parts, ", ") + " (" + terms + ")"
+}
+
 // PrintPensionOnlyDepletionComparison prints comparison of pension-only depletion results
 func PrintPensionOnlyDepletionComparison(results []DepletionResult, config *Config) {
 	fmt.Println()
@@ -724,7 +816,7 @@ func PrintPensionOnlyDepletionComparison(results []DepletionResult, config *Conf
 	fmt.Println(strings.Repeat("─", 110))
 
 	// Find best for highlighting
-	bestIdx := FindBestDepletionStrategy(results)
+	bestIdx := FindBestDepletionStrategy(results, config)
 
 	for i, r := range results {
 		marker := "  "
@@ -738,7 +830,7 @@ func PrintPensionOnlyDepletionComparison(results []DepletionResult, config *Conf
 		if len(r.SimulationResult.Years) > 0 {
 			lastYear := r.SimulationResult.Years[len(r.SimulationResult.Years)-1]
 			for _, bal := range lastYear.EndBalances {
-				finalISA += bal.TaxFreeSavings
+				finalISA += bal.Savings()
 			}
 		}
 
@@ -753,7 +845,7 @@ func PrintPensionOnlyDepletionComparison(results []DepletionResult, config *Conf
 	}
 
 	fmt.Println()
-	fmt.Println("* = Best strategy (highest sustainable income from pensions only)")
+	fmt.Println(bestDepletionLegend(config, "* = Best strategy (highest sustainable income from pensions only)"))
 	fmt.Println()
 
 	// Print recommendation
@@ -765,7 +857,7 @@ func PrintPensionOnlyDepletionComparison(results []DepletionResult, config *Conf
 		if len(best.SimulationResult.Years) > 0 {
 			lastYear := best.SimulationResult.Years[len(best.SimulationResult.Years)-1]
 			for _, bal := range lastYear.EndBalances {
-				finalISA += bal.TaxFreeSavings
+				finalISA += bal.Savings()
 			}
 		}
 
@@ -805,7 +897,7 @@ func PrintPensionToISADepletionComparison(results []DepletionResult, config *Con
 	fmt.Println(strings.Repeat("─", 110))
 
 	// Find best for highlighting
-	bestIdx := FindBestDepletionStrategy(results)
+	bestIdx := FindBestDepletionStrategy(results, config)
 
 	for i, r := range results {
 		marker := "  "
@@ -819,7 +911,7 @@ func PrintPensionToISADepletionComparison(results []DepletionResult, config *Con
 		if len(r.SimulationResult.Years) > 0 {
 			lastYear := r.SimulationResult.Years[len(r.SimulationResult.Years)-1]
 			for _, bal := range lastYear.EndBalances {
-				finalISA += bal.TaxFreeSavings
+				finalISA += bal.Savings()
 			}
 		}
 
@@ -834,7 +926,7 @@ func PrintPensionToISADepletionComparison(results []DepletionResult, config *Con
 	}
 
 	fmt.Println()
-	fmt.Println("* = Best strategy (highest sustainable income with tax-efficient ISA transfers)")
+	fmt.Println(bestDepletionLegend(config, "* = Best strategy (highest sustainable income with tax-efficient ISA transfers)"))
 	fmt.Println()
 
 	// Print recommendation
@@ -846,7 +938,7 @@ func PrintPensionToISADepletionComparison(results []DepletionResult, config *Con
 		if len(best.SimulationResult.Years) > 0 {
 			lastYear := best.SimulationResult.Years[len(best.SimulationResult.Years)-1]
 			for _, bal := range lastYear.EndBalances {
-				finalISA += bal.TaxFreeSavings
+				finalISA += bal.Savings()
 			}
 		}
 
@@ -871,3 +963,352 @@ func PrintPensionToISADepletionComparison(results []DepletionResult, config *Con
 		fmt.Println()
 	}
 }
+
+// PrintCareScenarioComparison prints whether each strategy survives a care episode
+func PrintCareScenarioComparison(results []CareScenarioResult, config *Config) {
+	fmt.Println()
+	fmt.Println("╔════════════════════════════════════════════════════════════════════════════════════════════════════╗")
+	fmt.Println("║                                 LATER-LIFE CARE SCENARIO                                           ║")
+	fmt.Println("╚════════════════════════════════════════════════════════════════════════════════════════════════════╝")
+	fmt.Println()
+
+	careConfig := ApplyCareEpisode(config)
+	for _, p := range careConfig.People {
+		if p.CareStartAge > 0 {
+			cost := p.CareAnnualCost
+			if cost <= 0 {
+				cost = config.Care.GetAnnualCost()
+			}
+			fmt.Printf("  %s: care from age %d for %d years at %s/year (today's money)\n",
+				p.Name, p.CareStartAge, config.Care.GetDurationYears(), FormatMoney(cost))
+		}
+	}
+	if config.Care.HomeValue > 0 {
+		fmt.Printf("  Home: %s, sale rule: %s\n", FormatMoney(config.Care.HomeValue), config.Care.GetHomeSale())
+	}
+	fmt.Println()
+
+	// Header
+	fmt.Printf("%-25s │ %-12s │ %-12s │ %12s │ %12s │ %12s\n",
+		"Strategy", "Without Care", "With Care", "Care Paid", "Council Paid", "Final Bal")
+	fmt.Println(strings.Repeat("─", 100))
+
+	survivors := 0
+	for _, r := range results {
+		baseStatus := "Survives"
+		if !r.SurvivesBaseline {
+			baseStatus = fmt.Sprintf("Out %d", r.Baseline.RanOutYear)
+		}
+		careStatus := "Survives"
+		if r.SurvivesCare {
+			survivors++
+		} else {
+			careStatus = fmt.Sprintf("Out %d", r.WithCare.RanOutYear)
+		}
+		fmt.Printf("%-25s │ %-12s │ %-12s │ %12s │ %12s │ %12s\n",
+			r.Params.ShortName(),
+			baseStatus,
+			careStatus,
+			FormatMoney(r.CareCost),
+			FormatMoney(r.CouncilFunded),
+			FormatMoney(getTotalFinalBalance(r.WithCare)))
+	}
+
+	fmt.Println()
+	fmt.Printf("  %d of %d strategies survive the care episode\n", survivors, len(results))
+	fmt.Println()
+}
+
+// anyDiscretionaryCuts returns true if any result had years with discretionary spending cut
+func anyDiscretionaryCuts(results []SimulationResult) bool {
+	for _, r := range results {
+		if r.DiscretionaryCutYears > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintRetirementSearchComparison prints the earliest viable retirement date for each strategy
+func PrintRetirementSearchComparison(results []RetirementSearchResult, config *Config) {
+	fmt.Println()
+	fmt.Println("╔════════════════════════════════════════════════════════════════════════════════════════════════════╗")
+	fmt.Println("║                                 EARLIEST RETIREMENT DATE                                           ║")
+	fmt.Println("╚════════════════════════════════════════════════════════════════════════════════════════════════════╝")
+	fmt.Println()
+
+	if len(results) == 0 {
+		return
+	}
+	refPerson := config.GetSimulationReferencePerson()
+	fmt.Printf("  Plan must fund spending until %s is %d\n", refPerson.Name, results[0].TargetAge)
+	for _, p := range config.People {
+		_, age := p.GetRetirementInfo()
+		date := p.RetirementDate
+		if date == "" {
+			date = fmt.Sprintf("age %d", age)
+		}
+		fmt.Printf("  %s: planned retirement %s\n", p.Name, date)
+	}
+	fmt.Println()
+
+	// Header
+	fmt.Printf("%-25s │ %-36s │ %-14s │ %12s\n", "Strategy", "Earliest Retirement", "vs Plan", "Final Bal")
+	fmt.Println(strings.Repeat("─", 100))
+
+	bestIdx := FindBestRetirementSearch(results)
+	for i, r := range results {
+		name := r.Params.ShortName()
+		if i == bestIdx {
+			name += " *"
+		}
+		if !r.Found {
+			fmt.Printf("%-25s │ %-36s │ %-14s │ %12s\n", name, fmt.Sprintf("Not viable by age %d", maxSearchRetirementAge), "-", "-")
+			continue
+		}
+		fmt.Printf("%-25s │ %-36s │ %-14s │ %12s\n",
+			name,
+			formatRetirementDates(r, config),
+			formatYearsEarlier(r.YearsEarlier),
+			FormatMoney(getTotalFinalBalance(r.SimulationResult)))
+	}
+
+	fmt.Println()
+	if bestIdx >= 0 {
+		fmt.Printf("  * Earliest: %s - %s\n", results[bestIdx].Params.ShortName(), formatRetirementDates(results[bestIdx], config))
+	} else {
+		fmt.Printf("  No strategy supports the required spending with retirement by age %d\n", maxSearchRetirementAge)
+	}
+	fmt.Println()
+}
+
+// formatRetirementDates formats each searched person's earliest retirement date and age
+func formatRetirementDates(r RetirementSearchResult, config *Config) string {
+	var parts []string
+	for _, p := range config.People {
+		if date, ok := r.RetirementDates[p.Name]; ok {
+			parts = append(parts, fmt.Sprintf("%s %s (%d)", p.Name, date, r.RetirementAges[p.Name]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatYearsEarlier describes a retirement offset relative to the configured plan
+func formatYearsEarlier(years int) string {
+	switch {
+	case years > 0:
+		return fmt.Sprintf("%d yrs earlier", years)
+	case years < 0:
+		return fmt.Sprintf("%d yrs later", -years)
+	default:
+		return "As planned"
+	}
+}
+
+// PrintSavingsSearchComparison prints the minimum extra saving needed for each strategy
+func PrintSavingsSearchComparison(results []SavingsSearchResult, config *Config) {
+	fmt.Println()
+	fmt.Println("╔════════════════════════════════════════════════════════════════════════════════════════════════════╗")
+	fmt.Println("║                                 REQUIRED EXTRA SAVING                                              ║")
+	fmt.Println("╚════════════════════════════════════════════════════════════════════════════════════════════════════╝")
+	fmt.Println()
+
+	if len(results) == 0 {
+		return
+	}
+	first := results[0]
+	refPerson := config.GetSimulationReferencePerson()
+	fmt.Printf("  Plan must last until %s is %d\n", refPerson.Name, config.Simulation.EndAge)
+	fmt.Printf("  Extra saving: %s\n", describeSavingSearch(first))
+	fmt.Println()
+
+	// Header
+	fmt.Printf("%-25s │ %-20s │ %12s │ %-14s │ %12s\n", "Strategy", "Extra Saving", "Total Saved", "Without", "Final Bal")
+	fmt.Println(strings.Repeat("─", 100))
+
+	bestIdx := FindBestSavingsSearch(results)
+	for i, r := range results {
+		name := r.Params.ShortName()
+		if i == bestIdx {
+			name += " *"
+		}
+		fmt.Printf("%-25s │ %-20s │ %12s │ %-14s │ %12s\n",
+			name,
+			formatSavingAmount(r),
+			FormatMoney(totalExtraSaving(r)),
+			formatSavingBaseline(r),
+			FormatMoney(getTotalFinalBalance(r.SimulationResult)))
+	}
+
+	fmt.Println()
+	switch {
+	case bestIdx >= 0:
+		fmt.Printf("  * Least saving: %s - %s\n", results[bestIdx].Params.ShortName(), formatSavingAmount(results[bestIdx]))
+	case first.SavingType == SavingTypeMonthly && first.SavingYears == 0:
+		fmt.Printf("  %s has already retired, so monthly saving cannot help - try -saving-type lump_sum\n", first.Person)
+	default:
+		fmt.Println("  No strategy lasts to the end age within the amounts searched")
+	}
+	fmt.Println()
+}
+
+// describeSavingSearch describes who saves, how and into which wrapper
+func describeSavingSearch(r SavingsSearchResult) string {
+	wrapper := "ISA"
+	if r.Wrapper == SavingWrapperPension {
+		wrapper = "pension (net, plus 20% basic-rate relief)"
+	}
+	if r.SavingType == SavingTypeLumpSum {
+		return fmt.Sprintf("one-off lump sum by %s into %s", r.Person, wrapper)
+	}
+	return fmt.Sprintf("monthly by %s into %s for %d years until retirement", r.Person, wrapper, r.SavingYears)
+}
+
+// formatSavingAmount formats the extra saving found for a strategy
+func formatSavingAmount(r SavingsSearchResult) string {
+	switch {
+	case !r.Found:
+		return "Not achievable"
+	case r.AlreadyViable:
+		return "None needed"
+	case r.SavingType == SavingTypeLumpSum:
+		return FormatMoney(r.Amount)
+	default:
+		return FormatMoney(r.Amount) + "/month"
+	}
+}
+
+// formatSavingBaseline describes when the plan runs out without extra saving
+func formatSavingBaseline(r SavingsSearchResult) string {
+	if r.AlreadyViable {
+		return "Survives"
+	}
+	return fmt.Sprintf("Out %d", r.BaselineOutYear)
+}
+
+// totalExtraSaving returns the total net extra saving made over the plan
+func totalExtraSaving(r SavingsSearchResult) float64 {
+	if !r.Found {
+		return 0
+	}
+	if r.SavingType == SavingTypeLumpSum {
+		return r.Amount
+	}
+	return r.Amount * 12 * float64(r.SavingYears)
+}
+
+// PrintOptimalComparison prints how far each heuristic strategy is from the Optimal plan
+func PrintOptimalComparison(results []SimulationResult, config *Config) {
+	fmt.Println()
+	fmt.Println("╔════════════════════════════════════════════════════════════════════════════════════════════════════╗")
+	fmt.Println("║                                HEURISTICS VS OPTIMAL PLAN                                          ║")
+	fmt.Println("╚════════════════════════════════════════════════════════════════════════════════════════════════════╝")
+	fmt.Println()
+
+	objective := config.Strategy.GetOptimalObjective()
+	if objective == OptimalObjectiveTax {
+		fmt.Println("  Optimal plan objective: minimise lifetime tax")
+	} else {
+		fmt.Println("  Optimal plan objective: maximise final estate")
+	}
+	fmt.Println()
+
+	// Header
+	fmt.Printf("%-25s │ %12s │ %12s │ %-12s │ %12s │ %12s\n", "Strategy", "Total Tax", "Final Bal", "Lasts", "Tax Gap", "Estate Gap")
+	fmt.Println(strings.Repeat("─", 100))
+
+	for _, r := range results {
+		lasts := "To end"
+		if r.RanOutOfMoney {
+			lasts = fmt.Sprintf("Out %d", r.RanOutYear)
+		}
+
+		taxGap, estateGap := "-", "-"
+		if optIdx := FindOptimalResult(results, r.Params.MortgageOpt); optIdx >= 0 && r.Params.DrawdownOrder != Optimal {
+			opt := results[optIdx]
+			taxGap = formatOptimalGap(r.TotalTaxPaid - opt.TotalTaxPaid)
+			estateGap = formatOptimalGap(getTotalFinalBalance(opt) - getTotalFinalBalance(r))
+		}
+
+		fmt.Printf("%-25s │ %12s │ %12s │ %-12s │ %12s │ %12s\n",
+			r.Params.ShortName(),
+			FormatMoney(r.TotalTaxPaid),
+			FormatMoney(getTotalFinalBalance(r)),
+			lasts,
+			taxGap,
+			estateGap)
+	}
+
+	fmt.Println()
+	fmt.Println("  Tax Gap: extra tax paid compared with the Optimal plan for the same mortgage option")
+	fmt.Println("  Estate Gap: final balance given up compared with the Optimal plan")
+	fmt.Println()
+}
+
+// formatOptimalGap formats a gap to the Optimal plan with its sign (negative = heuristic did better)
+func formatOptimalGap(gap float64) string {
+	if gap < -0.5 {
+		return "-" + FormatMoney(-gap)
+	}
+	return "+" + FormatMoney(math.Max(gap, 0))
+}
+
+// PrintOptimalPlan prints the year-by-year withdrawals chosen by the Optimal plan
+func PrintOptimalPlan(result SimulationResult) {
+	plan := result.Params.OptimalPlan
+	if plan == nil {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("OPTIMAL PLAN - %s\n", result.Params.ShortName())
+	fmt.Println(strings.Repeat("─", 100))
+
+	var names []string
+	if len(plan.Years) > 0 {
+		for name := range plan.Years[0].TaxableWithdrawal {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	fmt.Printf("%-8s", "Year")
+	for _, name := range names {
+		fmt.Printf(" │ %14s", name+" taxable")
+	}
+	fmt.Printf(" │ %12s │ %12s │ %12s │ %12s\n", "From Savings", "To ISA", "To GIA", "Tax")
+	for _, py := range plan.Years {
+		fmt.Printf("%-8s", TaxYearLabel(py.Year))
+		for _, name := range names {
+			fmt.Printf(" │ %14s", FormatMoney(py.TaxableWithdrawal[name]))
+		}
+		fmt.Printf(" │ %12s │ %12s │ %12s │ %12s\n", FormatMoney(py.FromISA), FormatMoney(py.ToISA), FormatMoney(py.ToGIA), FormatMoney(py.Tax))
+	}
+	if plan.Shortfall > 0 {
+		fmt.Printf("\n  ⚠️  The plan could not fund %s of spending\n", FormatMoney(plan.Shortfall))
+	}
+	fmt.Println()
+}
+
+// PrintParetoFrontier prints the strategies no other strategy beats on tax, income, final balance and shortfall
+func PrintParetoFrontier(results []SimulationResult, config *Config) {
+	frontier := ResultsFrontier(results, config.Financial.IncomeInflationRate)
+	if len(frontier) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("TRADE-OFFS (PARETO FRONTIER)")
+	fmt.Println(strings.Repeat("─", 90))
+	fmt.Printf("%-25s │ %12s │ %14s │ %12s │ %-10s\n", "Strategy", "Total Tax", "Income (real)", "Final Bal", "Runs Out")
+	fmt.Println(strings.Repeat("─", 90))
+	for _, p := range frontier {
+		runsOut := "Never"
+		if p.ShortfallYear > 0 {
+			runsOut = fmt.Sprintf("%d", p.ShortfallYear)
+		}
+		fmt.Printf("%-25s │ %12s │ %14s │ %12s │ %-10s\n",
+			p.Name, FormatMoney(p.TotalTax), FormatMoney(p.TotalIncome), FormatMoney(p.FinalBalance), runsOut)
+	}
+	fmt.Printf("\n  %d of %d strategies are not beaten on every objective by another strategy\n", len(frontier), len(results))
+}
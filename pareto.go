@@ -0,0 +1,123 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// frontierTolerance treats objective values this close as equal (avoids float noise in £)
+const frontierTolerance = 0.5
+
+// FrontierPoint holds the objectives one strategy is judged on for the Pareto frontier
+type FrontierPoint struct {
+	Index         int     // Position of the strategy in the results it was taken from
+	Name          string  // Strategy short name
+	TotalTax      float64 // Lifetime tax paid (lower is better)
+	TotalIncome   float64 // Spendable income received in today's money (higher is better)
+	FinalBalance  float64 // ISA and pension balances left at the end (higher is better)
+	ShortfallYear int     // First year spending could not be met (0 = never, later is better)
+}
+
+// frontierObjective is one axis of the Pareto frontier; value returns higher-is-better scores
+type frontierObjective struct {
+	Name  string
+	value func(p FrontierPoint) float64
+}
+
+// frontierObjectives are the objectives compared when finding the Pareto frontier
+var frontierObjectives = []frontierObjective{
+	{Name: "tax", value: func(p FrontierPoint) float64 { return -p.TotalTax }},
+	{Name: "income", value: func(p FrontierPoint) float64 { return p.TotalIncome }},
+	{Name: "estate", value: func(p FrontierPoint) float64 { return p.FinalBalance }},
+	{Name: "shortfall", value: func(p FrontierPoint) float64 {
+		if p.ShortfallYear == 0 {
+			return math.MaxInt32 // Never running short beats any shortfall year
+		}
+		return float64(p.ShortfallYear)
+	}},
+}
+
+// NewFrontierPoint extracts the frontier objectives from a simulation result
+func NewFrontierPoint(index int, result SimulationResult, incomeInflationRate float64) FrontierPoint {
+	p := FrontierPoint{
+		Index:        index,
+		Name:         result.Params.ShortName(),
+		TotalTax:     result.TotalTaxPaid,
+		TotalIncome:  realTotalIncome(result, incomeInflationRate),
+		FinalBalance: getTotalFinalBalance(result),
+	}
+	if result.RanOutOfMoney {
+		p.ShortfallYear = result.RanOutYear
+	}
+	return p
+}
+
+// realTotalIncome sums spendable income, deflated to today's purchasing power
+func realTotalIncome(result SimulationResult, incomeInflationRate float64) float64 {
+	if len(result.Years) == 0 {
+		return 0
+	}
+	startYear := result.Years[0].Year
+	total := 0.0
+	for _, year := range result.Years {
+		deflator := math.Pow(1+incomeInflationRate, float64(year.Year-startYear))
+		total += spendableIncome(year) / deflator
+	}
+	return total
+}
+
+// spendableIncome returns net income received less withdrawals recycled into ISAs and GIAs
+func spendableIncome(year YearState) float64 {
+	return year.NetIncomeReceived - year.Withdrawals.TotalISADeposits - year.Withdrawals.TotalGIADeposits
+}
+
+// Dominates returns true if p is at least as good as other on every objective
+// and strictly better on at least one
+func (p FrontierPoint) Dominates(other FrontierPoint) bool {
+	better := false
+	for _, obj := range frontierObjectives {
+		a, b := obj.value(p), obj.value(other)
+		if a < b-frontierTolerance {
+			return false
+		}
+		if a > b+frontierTolerance {
+			better = true
+		}
+	}
+	return better
+}
+
+// ParetoFrontier returns the points no other point dominates, ordered by lowest tax
+// Each point on the frontier is a trade-off: improving one objective means giving up another
+func ParetoFrontier(points []FrontierPoint) []FrontierPoint {
+	var frontier []FrontierPoint
+	for i, p := range points {
+		dominated := false
+		for j, q := range points {
+			if i != j && q.Dominates(p) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			frontier = append(frontier, p)
+		}
+	}
+
+	sort.SliceStable(frontier, func(a, b int) bool {
+		if frontier[a].TotalTax != frontier[b].TotalTax {
+			return frontier[a].TotalTax < frontier[b].TotalTax
+		}
+		return frontier[a].FinalBalance > frontier[b].FinalBalance
+	})
+	return frontier
+}
+
+// ResultsFrontier returns the Pareto frontier of a set of simulation results
+func ResultsFrontier(results []SimulationResult, incomeInflationRate float64) []FrontierPoint {
+	points := make([]FrontierPoint, len(results))
+	for i, r := range results {
+		points[i] = NewFrontierPoint(i, r, incomeInflationRate)
+	}
+	return ParetoFrontier(points)
+}
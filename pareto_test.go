@@ -0,0 +1,162 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestFrontierPoint_Dominates verifies dominance needs no worse objective and one strictly better
+func TestFrontierPoint_Dominates(t *testing.T) {
+	base := FrontierPoint{TotalTax: 50000, TotalIncome: 900000, FinalBalance: 200000}
+
+	lessTax := base
+	lessTax.TotalTax = 40000
+	if !lessTax.Dominates(base) || base.Dominates(lessTax) {
+		t.Error("Expected lower tax with everything else equal to dominate")
+	}
+
+	tradeOff := lessTax
+	tradeOff.FinalBalance = 150000
+	if tradeOff.Dominates(base) || base.Dominates(tradeOff) {
+		t.Error("Expected lower tax but a smaller estate to be a trade-off")
+	}
+
+	// Never running short beats any shortfall year
+	shortfall := lessTax
+	shortfall.ShortfallYear = 2060
+	if shortfall.Dominates(base) || !lessTax.Dominates(shortfall) {
+		t.Error("Expected a shortfall to lose to a plan that never runs short")
+	}
+	later := shortfall
+	later.ShortfallYear = 2065
+	if !later.Dominates(shortfall) {
+		t.Error("Expected a later shortfall to dominate an earlier one")
+	}
+
+	// Differences within the tolerance and identical points do not dominate
+	noise := base
+	noise.TotalTax -= frontierTolerance / 2
+	if noise.Dominates(base) || base.Dominates(base) {
+		t.Error("Expected equal points not to dominate each other")
+	}
+}
+
+// TestParetoFrontier verifies dominated points are removed and the rest ordered by tax
+func TestParetoFrontier(t *testing.T) {
+	points := []FrontierPoint{
+		{Index: 0, TotalTax: 60000, TotalIncome: 900000, FinalBalance: 300000},
+		{Index: 1, TotalTax: 40000, TotalIncome: 900000, FinalBalance: 200000},
+		{Index: 2, TotalTax: 70000, TotalIncome: 900000, FinalBalance: 250000}, // Dominated by 0
+		{Index: 3, TotalTax: 40000, TotalIncome: 900000, FinalBalance: 200000}, // Same as 1
+		{Index: 4, TotalTax: 30000, TotalIncome: 800000, FinalBalance: 400000, ShortfallYear: 2055},
+	}
+
+	frontier := ParetoFrontier(points)
+	var got []int
+	for _, p := range frontier {
+		got = append(got, p.Index)
+	}
+	want := []int{4, 1, 3, 0}
+	if len(got) != len(want) {
+		t.Fatalf("Expected frontier %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected frontier %v, got %v", want, got)
+		}
+	}
+
+	if len(ParetoFrontier(nil)) != 0 {
+		t.Error("Expected an empty frontier for no points")
+	}
+}
+
+// TestResultsFrontier verifies every strategy off the frontier is dominated by one on it
+func TestResultsFrontier(t *testing.T) {
+	config := createTestConfig()
+	var results []SimulationResult
+	for _, params := range GetStrategiesForConfig(config) {
+		results = append(results, RunSimulation(params, config))
+	}
+
+	frontier := ResultsFrontier(results, config.Financial.IncomeInflationRate)
+	if len(frontier) == 0 || len(frontier) > len(results) {
+		t.Fatalf("Expected a frontier of 1-%d strategies, got %d", len(results), len(frontier))
+	}
+
+	onFrontier := make(map[int]bool)
+	for _, p := range frontier {
+		onFrontier[p.Index] = true
+		if p.Name != results[p.Index].Params.ShortName() {
+			t.Errorf("Frontier point %d: expected %s, got %s", p.Index, results[p.Index].Params.ShortName(), p.Name)
+		}
+	}
+	for i, r := range results {
+		if onFrontier[i] {
+			continue
+		}
+		point := NewFrontierPoint(i, r, config.Financial.IncomeInflationRate)
+		dominated := false
+		for _, p := range frontier {
+			if p.Dominates(point) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			t.Errorf("%s: off the frontier but not dominated by a frontier strategy", point.Name)
+		}
+	}
+}
+
+// TestRealTotalIncome verifies later income is deflated to today's money
+func TestRealTotalIncome(t *testing.T) {
+	result := SimulationResult{Years: []YearState{
+		{Year: 2030, NetIncomeReceived: 10000},
+		{Year: 2031, NetIncomeReceived: 11000},
+	}}
+	if got := realTotalIncome(result, 0.1); got < 19999.99 || got > 20000.01 {
+		t.Errorf("Expected £20,000 in today's money, got £%.2f", got)
+	}
+	if realTotalIncome(SimulationResult{}, 0.1) != 0 {
+		t.Error("Expected no income without any years")
+	}
+}
+
+// TestAPIFrontier verifies the API frontier keeps strategy indexes and shortfall years
+func TestAPIFrontier(t *testing.T) {
+	summaries := []APIResultSummary{
+		{StrategyIdx: 7, ShortName: "A", TotalTaxPaid: 40000, TotalIncome: 900000, FinalBalance: 200000},
+		{StrategyIdx: 3, ShortName: "B", TotalTaxPaid: 50000, TotalIncome: 900000, FinalBalance: 150000},
+		{StrategyIdx: 5, ShortName: "C", TotalTaxPaid: 20000, TotalIncome: 950000, FinalBalance: 100000, RanOutOfMoney: true, RanOutYear: 2050},
+	}
+	var points []APIFrontierPoint
+	for _, s := range summaries {
+		points = append(points, newAPIFrontierPoint(s))
+	}
+
+	frontier := apiFrontier(points)
+	if len(frontier) != 2 {
+		t.Fatalf("Expected 2 frontier strategies, got %d", len(frontier))
+	}
+	if frontier[0].StrategyIdx != 5 || frontier[0].ShortfallYear != 2050 {
+		t.Errorf("Expected strategy 5 running short in 2050 first, got %d (%d)", frontier[0].StrategyIdx, frontier[0].ShortfallYear)
+	}
+	if frontier[1].StrategyIdx != 7 || frontier[1].ShortfallYear != 0 {
+		t.Errorf("Expected strategy 7 never running short second, got %d (%d)", frontier[1].StrategyIdx, frontier[1].ShortfallYear)
+	}
+}
+
+// TestTopNTracker_Frontier verifies the frontier covers results the tracker discards
+func TestTopNTracker_Frontier(t *testing.T) {
+	tracker := newTopNTracker(1, OptimizeBalance.Weights(), 0)
+	tracker.addScored(APIResultSummary{StrategyIdx: 0, TotalTaxPaid: 40000, FinalBalance: 200000}, 2)
+	tracker.addScored(APIResultSummary{StrategyIdx: 1, TotalTaxPaid: 20000, FinalBalance: 100000}, 1)
+
+	response := tracker.response(createTestConfig())
+	if len(response.Results) != 1 || response.Best == nil || response.Best.StrategyIdx != 0 {
+		t.Fatalf("Expected only strategy 0 kept, got %v", response.Results)
+	}
+	if len(response.Frontier) != 2 {
+		t.Errorf("Expected both strategies on the frontier, got %d", len(response.Frontier))
+	}
+}
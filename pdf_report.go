@@ -203,7 +203,8 @@ func getMortgagePayoffYear(config *Config, params SimulationParams) int {
 	case PCLSMortgagePayoff:
 		// PCLS payoff happens when reference person reaches retirement age
 		refPerson := config.GetReferencePerson()
-		return GetBirthYear(refPerson.BirthDate) + refPerson.RetirementAge
+		_, retirementAge := refPerson.GetRetirementInfo()
+		return GetBirthYear(refPerson.BirthDate) + retirementAge
 	default:
 		return config.Mortgage.EndYear
 	}
@@ -273,24 +274,32 @@ const (
 
 // GenerateStrategyPDFReport creates a detailed PDF action plan for a single strategy
 func GenerateStrategyPDFReport(config *Config, result SimulationResult) ([]byte, error) {
+	return newPDFActionPlanReport(config, result).render()
+}
+
+// newPDFActionPlanReport sets up an empty A4 report for result
+func newPDFActionPlanReport(config *Config, result SimulationResult) *PDFActionPlanReport {
 	report := &PDFActionPlanReport{
 		pdf:    fpdf.New("P", "mm", "A4", ""),
 		config: config,
 		result: result,
 	}
-
 	report.pdf.SetMargins(marginLeft, marginTop, marginRight)
 	report.pdf.SetAutoPageBreak(true, marginBottom)
+	return report
+}
 
+// render adds every page and returns the PDF
+func (r *PDFActionPlanReport) render() ([]byte, error) {
 	// Add pages
-	report.addTitlePage()
-	report.addStrategyOverview()
-	report.addYearByYearSummary()
-	report.addSummaryPage()
+	r.addTitlePage()
+	r.addStrategyOverview()
+	r.addYearByYearSummary()
+	r.addSummaryPage()
 
 	// Output to buffer
 	var buf bytes.Buffer
-	err := report.pdf.Output(&buf)
+	err := r.pdf.Output(&buf)
 	if err != nil {
 		return nil, err
 	}
@@ -333,14 +342,12 @@ func (r *PDFActionPlanReport) addTitlePage() {
 	r.pdf.SetTextColor(50, 50, 50)
 	for _, person := range r.config.People {
 		birthYear := GetBirthYear(person.BirthDate)
-		pensionAccessAge := person.PensionAccessAge
-		if pensionAccessAge <= 0 {
-			pensionAccessAge = person.RetirementAge
-		}
+		pensionAccessAge := person.GetPensionAccessAge()
+		_, retirementAge := person.GetRetirementInfo()
 		var text string
-		if pensionAccessAge != person.RetirementAge {
+		if pensionAccessAge != retirementAge {
 			text = fmt.Sprintf("%s - Born %d, Stop Work %d, Pension Access %d, State Pension %d",
-				person.Name, birthYear, person.RetirementAge, pensionAccessAge, person.StatePensionAge)
+				person.Name, birthYear, retirementAge, pensionAccessAge, person.StatePensionAge)
 		} else {
 			text = fmt.Sprintf("%s - Born %d, Pension Access Age %d, State Pension Age %d",
 				person.Name, birthYear, pensionAccessAge, person.StatePensionAge)
@@ -530,6 +537,9 @@ func (r *PDFActionPlanReport) addStrategyOverview() {
 	if r.result.Params.CrystallisationStrategy == UFPLSStrategy {
 		crystalName = "UFPLS (Uncrystallised Funds Pension Lump Sum)"
 		crystalExplain = "Withdraw directly from uncrystallised pot - 25% tax-free, 75% taxable per withdrawal"
+	} else if r.result.Params.CrystallisationStrategy == TrancheCrystallisation {
+		crystalName = "Tranche Crystallisation (" + r.result.Params.TrancheTiming.Description() + ")"
+		crystalExplain = "Crystallise part of the pension in tranches - 25% tax-free cash into the ISA, any excess held outside it"
 	}
 	addOption("Crystallisation:", crystalName, crystalExplain)
 
@@ -538,9 +548,19 @@ func (r *PDFActionPlanReport) addStrategyOverview() {
 	addOption("Drawdown Order:", drawdownName, drawdownExplain)
 
 	// Guardrails
-	if r.result.Params.GuardrailsEnabled {
+	switch r.result.Params.GetWithdrawalRule() {
+	case WithdrawalGuytonKlinger:
 		addOption("Withdrawal Method:", "Guyton-Klinger Guardrails",
 			"Dynamic adjustments based on portfolio performance - cut spending in bad years, increase in good")
+	case WithdrawalVanguard:
+		addOption("Withdrawal Method:", "Vanguard Dynamic Spending",
+			"Spending follows the portfolio, with yearly real changes limited by a ceiling and floor")
+	case WithdrawalVPW:
+		addOption("Withdrawal Method:", "Variable Percentage Withdrawal",
+			"Withdraw a percentage of the portfolio that rises with age to spend it over your lifetime")
+	case WithdrawalConstantPercent:
+		addOption("Withdrawal Method:", "Constant Percentage",
+			"Withdraw a fixed percentage of the current portfolio each year")
 	}
 
 	// State Pension Deferral
@@ -552,6 +572,12 @@ func (r *PDFActionPlanReport) addStrategyOverview() {
 			"Delay claiming state pension to receive higher payments (5.8% per year deferred)")
 	}
 
+	// Planned tax-free cash
+	if r.result.Params.KeepTaxFreeCash {
+		addOption("Tax-Free Cash:", "Kept in Pension",
+			"Planned tax-free cash is not taken, so later withdrawals keep their 25% tax-free part")
+	}
+
 	// ISA to SIPP
 	if r.result.Params.ISAToSIPPEnabled {
 		addOption("ISA to Pension:", "Enabled",
@@ -639,7 +665,7 @@ func (r *PDFActionPlanReport) addStrategyOverview() {
 
 	totalFinal := 0.0
 	for _, bal := range r.result.FinalBalances {
-		totalFinal += bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
+		totalFinal += bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
 	}
 
 	// Get depletion years (reuse refPerson and refBirthYear from above)
@@ -1118,7 +1144,7 @@ func (r *PDFActionPlanReport) drawMonthlySchedule(plan YearActionPlan, yearState
 	// This determines when pension withdrawals can actually start
 	firstPensionMonth := -1 // -1 means no pension access this year
 	for _, person := range r.config.People {
-		accessMonth := getPensionAccessMonth(person.BirthDate, person.PensionAccessAge, yearState.Year)
+		accessMonth := getPensionAccessMonth(person.BirthDate, person.GetPensionAccessAge(), yearState.Year)
 		if accessMonth >= 0 {
 			if firstPensionMonth < 0 || accessMonth < firstPensionMonth {
 				firstPensionMonth = accessMonth
@@ -1154,7 +1180,8 @@ func (r *PDFActionPlanReport) drawMonthlySchedule(plan YearActionPlan, yearState
 	totalAnnualWorkIncome := plan.Summary.WorkIncome
 
 	for _, person := range r.config.People {
-		if person.WorkIncome <= 0 {
+		annualWorkIncome := person.GetAnnualWorkIncome()
+		if annualWorkIncome <= 0 {
 			continue
 		}
 		// Get retirement tax year from retirement date
@@ -1167,7 +1194,7 @@ func (r *PDFActionPlanReport) drawMonthlySchedule(plan YearActionPlan, yearState
 			// Working all year - retirement is next year or later
 			workInfoByPerson[person.Name] = workMonthInfo{
 				lastWorkMonth: 11, // Works through March
-				monthlyAmount: person.WorkIncome / 12,
+				monthlyAmount: annualWorkIncome / 12,
 			}
 		} else if retireTaxYear == plan.Year {
 			// Retiring this tax year - find the month
@@ -1175,7 +1202,7 @@ func (r *PDFActionPlanReport) drawMonthlySchedule(plan YearActionPlan, yearState
 			if lastMonth >= 0 {
 				workInfoByPerson[person.Name] = workMonthInfo{
 					lastWorkMonth: lastMonth,
-					monthlyAmount: person.WorkIncome / 12,
+					monthlyAmount: annualWorkIncome / 12,
 				}
 			}
 		}
@@ -1350,7 +1377,7 @@ func (r *PDFActionPlanReport) drawMonthlySchedule(plan YearActionPlan, yearState
 	r.pdf.CellFormat(colWidths[7], 4, "", "1", 1, "L", true, 0, "")
 
 	// Add ISA contribution instructions if there are deposits
-	if totalISADeposit > 0 {
+	if totalISADeposit > 0 || yearState.Withdrawals.TotalGIADeposits > 0 {
 		r.pdf.Ln(2)
 		r.pdf.SetFont("Arial", "B", 8)
 		r.pdf.SetTextColor(128, 0, 128) // Purple for ISA instructions
@@ -1368,6 +1395,13 @@ func (r *PDFActionPlanReport) drawMonthlySchedule(plan YearActionPlan, yearState
 						name, FormatMoneyPDF(monthlyAmount), FormatMoneyPDF(amount)), "", 1, "L", false, 0, "")
 			}
 		}
+		for name, amount := range yearState.Withdrawals.GIADeposits {
+			if amount > 0 {
+				r.pdf.CellFormat(contentWidth, 3.5,
+					fmt.Sprintf("- %s: Save %s over the ISA allowance in a general investment account",
+						name, FormatMoneyPDF(amount)), "", 1, "L", false, 0, "")
+			}
+		}
 
 		r.pdf.SetFont("Arial", "I", 7)
 		r.pdf.SetTextColor(100, 100, 100)
@@ -1532,17 +1566,19 @@ func (r *PDFActionPlanReport) buildYearActionPlan(yearState YearState) YearActio
 	refPerson := r.config.GetReferencePerson()
 	refBirthYear := GetBirthYear(refPerson.BirthDate)
 	refAge := yearState.Year - refBirthYear
-	isRetired := refAge >= refPerson.RetirementAge
+	_, refRetirementAge := refPerson.GetRetirementInfo()
+	isRetired := refAge >= refRetirementAge
 
 	// Add milestone events
 	for _, person := range r.config.People {
 		birthYear := GetBirthYear(person.BirthDate)
 		age := yearState.Year - birthYear
+		_, retirementAge := person.GetRetirementInfo()
 
-		if age == person.RetirementAge {
+		if age == retirementAge {
 			plan.Actions = append(plan.Actions, ActionItem{
 				Category:    "Milestone",
-				Description: fmt.Sprintf("%s reaches pension access age %d", person.Name, person.RetirementAge),
+				Description: fmt.Sprintf("%s reaches pension access age %d", person.Name, retirementAge),
 				Person:      person.Name,
 				Notes:       "25% PCLS tax-free lump sum now available",
 			})
@@ -1654,6 +1690,17 @@ func (r *PDFActionPlanReport) buildYearActionPlan(yearState YearState) YearActio
 			})
 		}
 	}
+	for name, amount := range yearState.Withdrawals.GIADeposits {
+		if amount > 0 {
+			plan.Actions = append(plan.Actions, ActionItem{
+				Category:    "Transfer",
+				Description: fmt.Sprintf("Transfer to %s GIA", name),
+				Amount:      amount,
+				Person:      name,
+				Notes:       "Excess over the ISA allowance",
+			})
+		}
+	}
 
 	// Mortgage
 	if yearState.MortgageCost > 0 {
@@ -1726,13 +1773,13 @@ func (r *PDFActionPlanReport) addSummaryPage() {
 	totalPension := 0.0
 	for name, bal := range r.result.FinalBalances {
 		pension := bal.CrystallisedPot + bal.UncrystallisedPot
-		totalISA += bal.TaxFreeSavings
+		totalISA += bal.Savings()
 		totalPension += pension
 		r.drawTableRow([]string{
 			name,
-			FormatMoneyPDF(bal.TaxFreeSavings),
+			FormatMoneyPDF(bal.Savings()),
 			FormatMoneyPDF(pension),
-			FormatMoneyPDF(bal.TaxFreeSavings + pension),
+			FormatMoneyPDF(bal.Savings() + pension),
 		}, []float64{50, 40, 40, 50}, false)
 	}
 	r.drawTableRow([]string{
@@ -1753,13 +1800,14 @@ func (r *PDFActionPlanReport) addSummaryPage() {
 
 	for _, person := range r.config.People {
 		birthYear := GetBirthYear(person.BirthDate)
+		_, retirementAge := person.GetRetirementInfo()
 		dbStr := "-"
 		if person.DBPensionAmount > 0 {
 			dbStr = fmt.Sprintf("%d (age %d)", birthYear+person.DBPensionStartAge, person.DBPensionStartAge)
 		}
 		r.drawTableRow([]string{
 			person.Name,
-			fmt.Sprintf("%d (age %d)", birthYear+person.RetirementAge, person.RetirementAge),
+			fmt.Sprintf("%d (age %d)", birthYear+retirementAge, retirementAge),
 			fmt.Sprintf("%d (age %d)", birthYear+person.StatePensionAge, person.StatePensionAge),
 			dbStr,
 		}, []float64{45, 45, 45, 45}, false)
@@ -1767,6 +1815,25 @@ func (r *PDFActionPlanReport) addSummaryPage() {
 
 	r.pdf.Ln(10)
 
+	// Compliance warnings
+	if warnings := CheckCompliance(r.result, r.config); len(warnings) > 0 {
+		r.pdf.SetFont("Arial", "B", 11)
+		r.pdf.SetTextColor(0, 51, 102)
+		r.pdf.CellFormat(contentWidth, 7, "Compliance Warnings", "", 1, "L", false, 0, "")
+
+		r.pdf.SetFont("Arial", "", 9)
+		for _, w := range warnings {
+			if w.Severity == SeverityBreach {
+				r.pdf.SetTextColor(180, 0, 0)
+			} else {
+				r.pdf.SetTextColor(200, 100, 0) // Orange for risks
+			}
+			r.pdf.MultiCell(contentWidth, 5, pdfText(fmt.Sprintf("%s: %s", strings.ToUpper(w.Severity), w.String())), "", "L", false)
+		}
+		r.pdf.SetTextColor(50, 50, 50)
+		r.pdf.Ln(6)
+	}
+
 	// Important Reminders
 	r.pdf.SetFont("Arial", "B", 11)
 	r.pdf.SetTextColor(0, 51, 102)
@@ -1887,6 +1954,13 @@ func (r *PDFActionPlanReport) getStrategyDescription() string {
 		desc = "Fill Basic Rate: Draw pension up to the basic rate tax threshold each year."
 	case StatePensionBridge:
 		desc = "State Pension Bridge: Use pension to bridge income gap until state pension begins."
+	case Optimal:
+		desc = "Optimal Withdrawal Plan: Follow a year-by-year plan of pension, ISA and recycling amounts found by dynamic programming."
+	}
+
+	// Add people who draw differently from the household
+	for _, d := range r.result.Params.personStrategyDescriptions() {
+		desc += " Instead, " + d + "."
 	}
 
 	// Only add mortgage description if there is a mortgage
@@ -1905,8 +1979,8 @@ func (r *PDFActionPlanReport) getStrategyDescription() string {
 	}
 
 	// Add withdrawal adjustment strategies
-	if r.result.Params.GuardrailsEnabled {
-		desc += " Using Guyton-Klinger Guardrails for dynamic withdrawal adjustments."
+	if rule := r.result.Params.GetWithdrawalRule(); rule != WithdrawalFixed {
+		desc += fmt.Sprintf(" Using %s for dynamic withdrawal adjustments.", rule)
 	}
 
 	// Add state pension deferral if applicable
@@ -1963,6 +2037,9 @@ func (r *PDFActionPlanReport) getDrawdownOrderNameWithExplanation() (string, str
 	case StatePensionBridge:
 		return "State Pension Bridge",
 			"Higher withdrawals before state pension starts, then reduce when state pension begins"
+	case Optimal:
+		return "Optimal Withdrawal Plan",
+			"Draw each person's pension to the tax band found best for that year by dynamic programming, recycling any excess to ISA"
 	default:
 		return "Standard", "Default withdrawal strategy"
 	}
@@ -1978,7 +2055,7 @@ func (r *PDFActionPlanReport) getDepletionYears() (isaYear int, pensionYear int)
 		isaTotal := 0.0
 		pensionTotal := 0.0
 		for _, bal := range year.EndBalances {
-			isaTotal += bal.TaxFreeSavings
+			isaTotal += bal.Savings()
 			pensionTotal += bal.CrystallisedPot + bal.UncrystallisedPot
 		}
 
@@ -2003,7 +2080,7 @@ func (r *PDFActionPlanReport) getDepletionYears() (isaYear int, pensionYear int)
 		lastISA := 0.0
 		lastPension := 0.0
 		for _, bal := range lastYear.EndBalances {
-			lastISA += bal.TaxFreeSavings
+			lastISA += bal.Savings()
 			lastPension += bal.CrystallisedPot + bal.UncrystallisedPot
 		}
 		// If balance is very low at end (near zero), mark as depleted that year
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Normal minimum pension age timeline
+const (
+	NormalMinimumPensionAge   = 55   // Until 5 April 2028
+	RaisedMinimumPensionAge   = 57   // From 6 April 2028
+	MinimumPensionAgeRiseYear = 2028 // Tax year the minimum rises in
+	LowestProtectedPensionAge = 50   // Lowest protected pension age kept from before 2010
+)
+
+// minimumPensionAge returns the normal minimum pension age in tax year year
+func minimumPensionAge(year int) int {
+	if year >= MinimumPensionAgeRiseYear {
+		return RaisedMinimumPensionAge
+	}
+	return NormalMinimumPensionAge
+}
+
+// minimumPensionAgeFor returns the minimum pension age in tax year year for someone
+// with protectedAge (0 = no protection), which keeps access below the normal minimum
+func minimumPensionAgeFor(protectedAge, year int) int {
+	minimum := minimumPensionAge(year)
+	if protectedAge > 0 && protectedAge < minimum {
+		return protectedAge
+	}
+	return minimum
+}
+
+// MinimumPensionAge returns the earliest age the person can access their pension in tax year year
+func (p *Person) MinimumPensionAge(year int) int {
+	return minimumPensionAgeFor(p.ProtectedPensionAge, year)
+}
+
+// firstPensionAccessAge returns the first age from age that the person can access their pension at,
+// in the tax year they reach it
+func (p *PersonConfig) firstPensionAccessAge(age int) int {
+	for age < minimumPensionAgeFor(p.ProtectedPensionAge, GetTaxYearForAge(p.BirthDate, age)) {
+		age++
+	}
+	return age
+}
+
+// GetPensionAccessAge returns the age the person starts drawing their pension: pension_access_age
+// (or the retirement age if not set), raised to the minimum pension age when they reach it
+func (p *PersonConfig) GetPensionAccessAge() int {
+	age := p.PensionAccessAge
+	if age <= 0 {
+		_, age = p.GetRetirementInfo()
+	}
+	return p.firstPensionAccessAge(age)
+}
+
+// EarliestPensionAccess returns the earliest age and date the person can access their pension,
+// from their birth date, the minimum pension age timeline and any protected pension age
+func (p *PersonConfig) EarliestPensionAccess() (int, time.Time) {
+	age := p.firstPensionAccessAge(0)
+	birth, err := time.Parse("2006-01-02", p.BirthDate)
+	if err != nil {
+		return age, time.Time{}
+	}
+	return age, birth.AddDate(age, 0, 0)
+}
+
+// ValidatePensionAccess checks each person's pension access and planned tax-free cash
+// are not before the minimum pension age
+func ValidatePensionAccess(config *Config) ConfigErrors {
+	var errs ConfigErrors
+	for i, pc := range config.People {
+		path := fmt.Sprintf("people[%d]", i)
+		if pc.ProtectedPensionAge != 0 && (pc.ProtectedPensionAge < LowestProtectedPensionAge || pc.ProtectedPensionAge >= RaisedMinimumPensionAge) {
+			errs = append(errs, ConfigError{Path: path + ".protected_pension_age", Message: fmt.Sprintf(
+				"expected 0 (none) or %d to %d, got %d", LowestProtectedPensionAge, RaisedMinimumPensionAge-1, pc.ProtectedPensionAge)})
+			continue
+		}
+		if pc.PensionAccessAge <= 0 || pc.BirthDate == "" {
+			continue
+		}
+		year := GetTaxYearForAge(pc.BirthDate, pc.PensionAccessAge)
+		if minimum := minimumPensionAgeFor(pc.ProtectedPensionAge, year); pc.PensionAccessAge < minimum {
+			errs = append(errs, ConfigError{Path: path + ".pension_access_age", Message: fmt.Sprintf(
+				"%d is before the minimum pension age of %d in %s, %s",
+				pc.PensionAccessAge, minimum, TaxYearLabel(year), earliestAccessText(&pc))})
+		}
+	}
+	for i, use := range config.TaxFreeCash {
+		pc := config.FindPerson(use.Person)
+		if pc == nil || pc.BirthDate == "" || use.Year <= 0 {
+			continue
+		}
+		age := GetAgeInTaxYear(pc.BirthDate, use.Year)
+		if minimum := minimumPensionAgeFor(pc.ProtectedPensionAge, use.Year); age < minimum {
+			errs = append(errs, ConfigError{Path: fmt.Sprintf("tax_free_cash[%d].year", i), Message: fmt.Sprintf(
+				"%s is %d in %s, before the minimum pension age of %d, %s",
+				pc.Name, age, TaxYearLabel(use.Year), minimum, earliestAccessText(pc))})
+		}
+	}
+	return errs
+}
+
+// earliestAccessText describes the person's earliest pension access for validation errors
+func earliestAccessText(pc *PersonConfig) string {
+	age, date := pc.EarliestPensionAccess()
+	if date.IsZero() {
+		return fmt.Sprintf("the earliest is %d", age)
+	}
+	return fmt.Sprintf("the earliest is %d on %s", age, date.Format("2 January 2006"))
+}
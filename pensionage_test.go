@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestCanAccessPension_MinimumPensionAge verifies access follows the rise to 57 from the 2028/29 tax year
+func TestCanAccessPension_MinimumPensionAge(t *testing.T) {
+	tests := []struct {
+		name      string
+		birthDate string
+		protected int
+		year      int
+		expected  bool
+	}{
+		{"55 before the rise", "1972-06-01", 0, 2027, true},
+		{"56 after the rise", "1972-06-01", 0, 2028, false},
+		{"57 after the rise", "1972-06-01", 0, 2029, true},
+		{"55 after the rise", "1975-01-13", 0, 2029, false},
+		{"57 in the tax year before the birthday", "1975-01-13", 0, 2030, false},
+		{"57 in the tax year of the birthday", "1975-01-13", 0, 2031, true},
+		{"protected at 55", "1972-06-01", 55, 2028, true},
+		{"protected at 50", "1980-01-01", 50, 2029, true},
+		{"protected below 50", "1980-01-01", 50, 2028, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			person := &Person{BirthYear: GetBirthYear(tc.birthDate), BirthDate: tc.birthDate, PensionAccessAge: 50, ProtectedPensionAge: tc.protected}
+			if got := person.CanAccessPension(tc.year); got != tc.expected {
+				t.Errorf("%s in %d: expected CanAccessPension=%v, got %v", tc.birthDate, tc.year, tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestPensionAccessAge verifies access ages are derived from the birth date and the minimum pension age
+func TestPensionAccessAge(t *testing.T) {
+	pc := PersonConfig{BirthDate: "1975-01-13", RetirementAge: 55}
+	if age := pc.GetPensionAccessAge(); age != 57 {
+		t.Errorf("Expected the retirement age raised to 57, got %d", age)
+	}
+	age, date := pc.EarliestPensionAccess()
+	if age != 57 || date.Format("2006-01-02") != "2032-01-13" {
+		t.Errorf("Expected the earliest access at 57 on 2032-01-13, got %d on %s", age, date.Format("2006-01-02"))
+	}
+
+	pc = PersonConfig{BirthDate: "1971-07-15", PensionAccessAge: 55}
+	if age, date := pc.EarliestPensionAccess(); age != 55 || pc.GetPensionAccessAge() != 55 || date.Year() != 2026 {
+		t.Errorf("Expected access at 55 in 2026, got %d in %d", age, date.Year())
+	}
+
+	pc = PersonConfig{BirthDate: "1975-01-13", PensionAccessAge: 60, ProtectedPensionAge: 55}
+	if age, _ := pc.EarliestPensionAccess(); age != 55 || pc.GetPensionAccessAge() != 60 {
+		t.Errorf("Expected the protected age to allow 55 and the configured age kept, got %d and %d", age, pc.GetPensionAccessAge())
+	}
+}
+
+// TestValidatePensionAccess verifies access and planned tax-free cash before the minimum pension age are reported
+func TestValidatePensionAccess(t *testing.T) {
+	config := createTestConfig()
+	config.People[1].BirthDate = "1975-01-13"
+	config.People[1].PensionAccessAge = 55
+	config.TaxFreeCash = []TaxFreeCashConfig{{Person: "Delphine", Year: 2030, Purpose: TaxFreeCashISA}}
+	err := ValidatePensionAccess(config)
+	for _, want := range []string{
+		"people[1].pension_access_age: 55 is before the minimum pension age of 57 in 2029/30, the earliest is 57 on 13 January 2032",
+		"tax_free_cash[0].year: Delphine is 56 in 2030/31, before the minimum pension age of 57",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q, got %v", want, err)
+		}
+	}
+
+	config.People[1].ProtectedPensionAge = 55
+	if err := ValidatePensionAccess(config); err != nil {
+		t.Errorf("Expected a protected pension age to allow access at 55, got %v", err)
+	}
+	config.People[1].ProtectedPensionAge = 45
+	if err := ValidatePensionAccess(config); err == nil || !strings.Contains(err.Error(), "protected_pension_age: expected 0 (none) or 50 to 56") {
+		t.Errorf("Expected the protected pension age to be checked, got %v", err)
+	}
+}
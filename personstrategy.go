@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PersonStrategy is one person's crystallisation and drawdown choice
+type PersonStrategy struct {
+	Crystallisation Strategy
+	Drawdown        DrawdownOrder
+}
+
+// PersonCrystallisation is the value of a crystallisation@<person> factor
+type PersonCrystallisation struct {
+	Person   string
+	Strategy Strategy
+}
+
+// PersonDrawdown is the value of a drawdown@<person> factor
+type PersonDrawdown struct {
+	Person string
+	Order  DrawdownOrder
+}
+
+// StrategyFor returns the crystallisation and drawdown choice followed by the named person
+func (sp SimulationParams) StrategyFor(name string) PersonStrategy {
+	if ps, ok := sp.PersonStrategies[name]; ok {
+		return ps
+	}
+	return PersonStrategy{Crystallisation: sp.CrystallisationStrategy, Drawdown: sp.DrawdownOrder}
+}
+
+// personStrategyNames returns, sorted, the people whose choice differs from the household's
+func (sp SimulationParams) personStrategyNames() []string {
+	household := PersonStrategy{Crystallisation: sp.CrystallisationStrategy, Drawdown: sp.DrawdownOrder}
+	var names []string
+	for name, ps := range sp.PersonStrategies {
+		if ps != household {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// personStrategyDescriptions describes each choice that differs from the household's,
+// e.g. "Delphine: UFPLS Pension First, Then ISA"
+func (sp SimulationParams) personStrategyDescriptions() []string {
+	var descriptions []string
+	for _, name := range sp.personStrategyNames() {
+		ps := sp.PersonStrategies[name]
+		descriptions = append(descriptions, name+": "+ps.crystallisationPrefix("UFPLS ", "Tranche ")+drawdownDescription(ps.Drawdown))
+	}
+	return descriptions
+}
+
+// crystallisationPrefix returns ufpls or tranche for those strategies and "" for gradual crystallisation
+func (ps PersonStrategy) crystallisationPrefix(ufpls, tranche string) string {
+	switch ps.Crystallisation {
+	case UFPLSStrategy:
+		return ufpls
+	case TrancheCrystallisation:
+		return tranche
+	default:
+		return ""
+	}
+}
+
+// personFactorID returns the ID of a per-person factor, e.g. drawdown@Delphine
+func personFactorID(base FactorID, name string) FactorID {
+	return base + "@" + FactorID(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" =!|", r) {
+			return '_'
+		}
+		return r
+	}, name))
+}
+
+// RegisterPersonFactors adds crystallisation and drawdown factors for everyone but the first person
+// Their "same" value follows the household factor, which the first person always follows
+func (r *FactorRegistry) RegisterPersonFactors(config *Config) {
+	if len(config.People) < 2 {
+		return
+	}
+	household := NewFactorRegistry()
+	for _, person := range config.People[1:] {
+		for _, base := range []FactorID{FactorCrystallisation, FactorDrawdown} {
+			f := household.Get(base)
+			personal := &Factor{
+				ID:             personFactorID(base, person.Name),
+				Name:           fmt.Sprintf("%s (%s)", f.Name, person.Name),
+				Description:    f.Description + " for " + person.Name,
+				Values:         []FactorValue{{ID: "same", Name: "Same as household", ShortName: "Same"}},
+				DefaultValueID: "same",
+				Person:         person.Name,
+			}
+			for _, v := range f.Values {
+				if v.Value == TrancheCrystallisation {
+					// Tranche timing is a household choice, so tranches are too
+					continue
+				}
+				switch value := v.Value.(type) {
+				case Strategy:
+					v.Value = PersonCrystallisation{Person: person.Name, Strategy: value}
+				case DrawdownOrder:
+					v.Value = PersonDrawdown{Person: person.Name, Order: value}
+				}
+				personal.Values = append(personal.Values, v)
+			}
+			r.Register(personal)
+		}
+	}
+}
+
+// personConstraintRules returns the rules for the per-person factors of config, in ParseConstraint form
+// A per-person value equal to the household's is the same as "same", so only one of them is kept
+func personConstraintRules(config *Config, registry *FactorRegistry) []string {
+	if len(config.People) < 2 {
+		return nil
+	}
+	var rules []string
+	for _, person := range config.People[1:] {
+		crystallisation := personFactorID(FactorCrystallisation, person.Name)
+		drawdown := personFactorID(FactorDrawdown, person.Name)
+		for _, base := range []FactorID{FactorCrystallisation, FactorDrawdown} {
+			for _, v := range registry.Get(personFactorID(base, person.Name)).Values[1:] {
+				rules = append(rules, fmt.Sprintf("if %s=%s then %s!=%s", base, v.ID, personFactorID(base, person.Name), v.ID))
+			}
+		}
+		rules = append(rules,
+			fmt.Sprintf("if %s=ufpls then mortgage!=pcls", crystallisation),
+			fmt.Sprintf("if isa_to_sipp=on then %s!=pension_to_isa|pension_to_isa_proactive", drawdown),
+			// Filling both ISAs from one pension needs the couple to draw together
+			fmt.Sprintf("if maximize_couple_isa=on then %s=same and %s=same", crystallisation, drawdown),
+		)
+	}
+	return rules
+}
+
+// PersonConstraints returns the constraints on the per-person factors of config
+func PersonConstraints(config *Config, registry *FactorRegistry) []Constraint {
+	var constraints []Constraint
+	for _, rule := range personConstraintRules(config, registry) {
+		c, err := ParseConstraint(rule, registry)
+		if err != nil {
+			panic(err) // The rules are built from registered factors, so this is a programming error
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints
+}
+
+// personStrategies returns the per-person choices in combo, starting from the household's
+// It returns nil if combo has no per-person values
+func personStrategies(combo StrategyCombo, household SimulationParams) map[string]PersonStrategy {
+	var strategies map[string]PersonStrategy
+	choice := func(name string) PersonStrategy {
+		if strategies == nil {
+			strategies = make(map[string]PersonStrategy)
+		}
+		if ps, ok := strategies[name]; ok {
+			return ps
+		}
+		return household.StrategyFor(name)
+	}
+	for _, v := range combo.Values {
+		switch value := v.Value.(type) {
+		case PersonCrystallisation:
+			ps := choice(value.Person)
+			ps.Crystallisation = value.Strategy
+			strategies[value.Person] = ps
+		case PersonDrawdown:
+			ps := choice(value.Person)
+			ps.Drawdown = value.Order
+			strategies[value.Person] = ps
+		}
+	}
+	return strategies
+}
+
+// drawdownGroup is the people following one crystallisation and drawdown choice
+type drawdownGroup struct {
+	people []*Person
+	params SimulationParams // Household params with the group's choice
+}
+
+// drawdownGroups splits people by the choice they follow, in the order people are listed
+func (sp SimulationParams) drawdownGroups(people []*Person) []drawdownGroup {
+	var groups []drawdownGroup
+	for _, p := range people {
+		ps := sp.StrategyFor(p.Name)
+		found := false
+		for i := range groups {
+			if groups[i].params.CrystallisationStrategy == ps.Crystallisation && groups[i].params.DrawdownOrder == ps.Drawdown {
+				groups[i].people = append(groups[i].people, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			params := sp
+			params.CrystallisationStrategy, params.DrawdownOrder = ps.Crystallisation, ps.Drawdown
+			params.PersonStrategies = nil
+			groups = append(groups, drawdownGroup{people: []*Person{p}, params: params})
+		}
+	}
+	return groups
+}
+
+// executePerPersonDrawdown meets netNeeded with each group of people following its own choice
+// The need is shared by the wealth each group can draw on this year; whatever a group cannot
+// provide is then asked of each group in turn
+func executePerPersonDrawdown(people []*Person, netNeeded float64, params SimulationParams, year int, taxableIncomeByPerson map[string]float64, taxBands []TaxBand) WithdrawalBreakdown {
+	groups := params.drawdownGroups(people)
+	if len(groups) == 1 {
+		return executeHouseholdDrawdown(people, netNeeded, groups[0].params, year, taxableIncomeByPerson, taxBands)
+	}
+
+	breakdown := NewWithdrawalBreakdown()
+
+	// Taxable income so far, so later withdrawals are grossed up at the right rate
+	income := make(map[string]float64, len(taxableIncomeByPerson))
+	for name, amount := range taxableIncomeByPerson {
+		income[name] = amount
+	}
+
+	// draw runs a group's strategy and returns the net income it provided
+	draw := func(g drawdownGroup, need float64) float64 {
+		b := executeHouseholdDrawdown(g.people, need, g.params, year, income, taxBands)
+		net := 0.0
+		for _, p := range g.people {
+			taxable := b.TaxableFromPension[p.Name]
+			tax := CalculatePersonTax(income[p.Name], taxable, taxBands) - CalculatePersonTax(income[p.Name], 0, taxBands)
+			net += b.TaxFreeFromISA[p.Name] + b.TaxFreeFromPension[p.Name] + taxable - tax - b.ISADeposits[p.Name] - b.GIADeposits[p.Name]
+			income[p.Name] += taxable
+		}
+		breakdown.Add(b)
+		return net
+	}
+
+	wealth := make([]float64, len(groups))
+	totalWealth := 0.0
+	for i, g := range groups {
+		for _, p := range g.people {
+			wealth[i] += p.AvailableISA()
+			if p.CanAccessPension(year) {
+				wealth[i] += p.TotalPension()
+			}
+		}
+		totalWealth += wealth[i]
+	}
+
+	shortfall := 0.0
+	for i, g := range groups {
+		share := netNeeded / float64(len(groups))
+		if totalWealth > 0 {
+			share = netNeeded * wealth[i] / totalWealth
+		}
+		if share > 0 {
+			shortfall += share - draw(g, share)
+		}
+	}
+	for _, g := range groups {
+		if shortfall <= 0.01 {
+			break
+		}
+		shortfall -= draw(g, shortfall)
+	}
+
+	return breakdown
+}
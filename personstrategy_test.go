@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// netDrawn returns the spendable income in breakdown, after the tax on its taxable withdrawals
+func netDrawn(b WithdrawalBreakdown, income map[string]float64) float64 {
+	net := 0.0
+	for name := range income {
+		taxable := b.TaxableFromPension[name]
+		tax := CalculatePersonTax(income[name], taxable, ukTaxBands2024) - CalculatePersonTax(income[name], 0, ukTaxBands2024)
+		net += b.TaxFreeFromISA[name] + b.TaxFreeFromPension[name] + taxable - tax - b.ISADeposits[name] - b.GIADeposits[name]
+	}
+	return net
+}
+
+// TestExecuteDrawdown_PerPerson verifies each person draws with their own choice and the need is still met
+func TestExecuteDrawdown_PerPerson(t *testing.T) {
+	couple := func(delphinePension float64) []*Person {
+		return []*Person{
+			{Name: "James", BirthYear: 1960, RetirementAge: 55, UncrystallisedPot: 200000, TaxFreeSavings: 100000, ISAAnnualLimit: 20000},
+			{Name: "Delphine", BirthYear: 1962, RetirementAge: 55, UncrystallisedPot: delphinePension, TaxFreeSavings: 100000, ISAAnnualLimit: 20000},
+		}
+	}
+	income := map[string]float64{"James": 0, "Delphine": 0}
+	household := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: SavingsFirst}
+
+	params := household
+	params.PersonStrategies = map[string]PersonStrategy{"Delphine": {Crystallisation: UFPLSStrategy, Drawdown: PensionFirst}}
+	b := ExecuteDrawdown(couple(200000), 30000, params, 2025, income, ukTaxBands2024)
+	if b.TaxFreeFromISA["James"] == 0 || b.TaxableFromPension["James"] != 0 {
+		t.Errorf("Expected James to draw from his ISA first, got ISA £%.0f and pension £%.0f", b.TaxFreeFromISA["James"], b.TaxableFromPension["James"])
+	}
+	if b.TaxFreeFromISA["Delphine"] != 0 || b.TaxableFromPension["Delphine"] == 0 {
+		t.Errorf("Expected Delphine to draw from her pension first, got ISA £%.0f and pension £%.0f", b.TaxFreeFromISA["Delphine"], b.TaxableFromPension["Delphine"])
+	}
+	if net := netDrawn(b, income); math.Abs(net-30000) > 1 {
+		t.Errorf("Expected £30000 net, got £%.2f", net)
+	}
+
+	// A choice the same as the household's draws exactly as the household does
+	same := household
+	same.PersonStrategies = map[string]PersonStrategy{"Delphine": {Crystallisation: GradualCrystallisation, Drawdown: SavingsFirst}}
+	a := ExecuteDrawdown(couple(200000), 30000, same, 2025, income, ukTaxBands2024)
+	h := ExecuteDrawdown(couple(200000), 30000, household, 2025, income, ukTaxBands2024)
+	if a.TotalTaxFree != h.TotalTaxFree || a.TotalTaxable != h.TotalTaxable || a.TaxFreeFromISA["Delphine"] != h.TaxFreeFromISA["Delphine"] {
+		t.Errorf("Expected the household withdrawals, got £%.0f/£%.0f against £%.0f/£%.0f", a.TotalTaxFree, a.TotalTaxable, h.TotalTaxFree, h.TotalTaxable)
+	}
+
+	// What one person cannot provide is drawn from the other
+	params.PersonStrategies = map[string]PersonStrategy{"Delphine": {Crystallisation: GradualCrystallisation, Drawdown: PensionOnly}}
+	b = ExecuteDrawdown(couple(0), 30000, params, 2025, income, ukTaxBands2024)
+	if b.TaxFreeFromISA["Delphine"] != 0 {
+		t.Errorf("Expected Delphine's ISA to be untouched, got £%.0f", b.TaxFreeFromISA["Delphine"])
+	}
+	if net := netDrawn(b, income); math.Abs(net-30000) > 1 {
+		t.Errorf("Expected James to make up the £30000 net, got £%.2f", net)
+	}
+}
+
+// TestPersonFactors verifies per-person choices are searched only in adaptive mode, without duplicates
+func TestPersonFactors(t *testing.T) {
+	config := createTestConfig()
+	for _, params := range GetStrategiesForConfigV2(config, ModeComprehensive) {
+		if params.PersonStrategies != nil {
+			t.Fatalf("Expected no per-person choices in comprehensive mode, got %s", params.ShortName())
+		}
+	}
+
+	var found bool
+	for _, params := range GetStrategiesForConfigV2(config, ModeAdaptive) {
+		if params.PersonStrategies == nil {
+			continue
+		}
+		if len(params.personStrategyNames()) == 0 {
+			t.Fatalf("Expected per-person choices to differ from the household, got %s", params.ShortName())
+		}
+		if params.MaximizeCoupleISA || (params.MortgageOpt == PCLSMortgagePayoff && params.StrategyFor("Delphine").Crystallisation == UFPLSStrategy) {
+			t.Fatalf("Expected the constraints to apply to per-person choices, got %s", params.ShortName())
+		}
+		if _, ok := params.PersonStrategies["James"]; ok {
+			t.Fatalf("Expected the first person to follow the household, got %s", params.ShortName())
+		}
+		if params.DrawdownOrder == TaxOptimized && params.StrategyFor("Delphine") == (PersonStrategy{UFPLSStrategy, SavingsFirst}) {
+			found = true
+			if name := params.ShortName(); !strings.Contains(name, "TaxOpt/Delphine=U+ISAFirst/") {
+				t.Errorf("Expected Delphine's choice in the short name, got %s", name)
+			}
+			if name := params.DescriptiveName(0); !strings.Contains(name, "Delphine: UFPLS ISA First, Then Pension") {
+				t.Errorf("Expected Delphine's choice in the descriptive name, got %s", name)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Expected Delphine to be able to take UFPLS from savings first")
+	}
+
+	config.Strategy.Constraints = []string{"if drawdown@Delphine=pension_only then mortgage=normal"}
+	if err := ValidateUserStrategy(config); err != nil {
+		t.Errorf("Expected rules on per-person factors to be accepted, got %v", err)
+	}
+}
+
+// TestPersonStrategy_Cached verifies a per-person strategy is simulated and saved to disk
+func TestPersonStrategy_Cached(t *testing.T) {
+	dir := t.TempDir()
+	config := createTestConfig()
+	config.Cache = NewResultCache(dir)
+	var params SimulationParams
+	for _, p := range GetStrategiesForConfigV2(config, ModeAdaptive) {
+		if p.PersonStrategies != nil {
+			params = p
+			break
+		}
+	}
+	saved := runCachedSimulationV2(params, config)
+
+	entry, ok := NewResultCache(dir).Lookup(saved.ResultKey)
+	if !ok || entry.Simulation == nil || entry.Simulation.Params.ShortName() != params.ShortName() {
+		t.Fatalf("Expected strategy %s to be cached", params.ShortName())
+	}
+}
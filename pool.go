@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// poolBatchPerWorker is how many results per worker are computed before they are handed on
+// in order, bounding the results held in memory while the slowest job finishes
+const poolBatchPerWorker = 4
+
+// RunControl follows the progress of a long run and lets it be stopped early
+// It travels on Config.Control so every pool a run starts reports to it; nil means no control
+type RunControl struct {
+	ctx     context.Context
+	started time.Time
+	planned atomic.Int64 // Work expected in total, if known up front
+	total   atomic.Int64 // Work started by the pools so far
+	done    atomic.Int64 // Work finished so far
+
+	mu      sync.Mutex
+	observe func(i int, result any) // Called with each finished result and its index, e.g. to track the best so far
+}
+
+// RunProgress is a snapshot of a run's progress
+type RunProgress struct {
+	Done    int           // Strategies (or searches) finished
+	Total   int           // Strategies (or searches) expected
+	Elapsed time.Duration // Time since the run started
+	ETA     time.Duration // Estimated time remaining (0 until something has finished)
+}
+
+// NewRunControl creates a control for a run that stops when ctx is cancelled
+func NewRunControl(ctx context.Context) *RunControl {
+	return &RunControl{ctx: ctx, started: time.Now()}
+}
+
+// Plan sets how much work the run is expected to do, for runs made of several pools
+func (c *RunControl) Plan(total int) {
+	if c != nil {
+		c.planned.Store(int64(total))
+	}
+}
+
+// Observe sets a function called with each finished result and its index in its pool
+func (c *RunControl) Observe(fn func(i int, result any)) {
+	if c != nil {
+		c.mu.Lock()
+		c.observe = fn
+		c.mu.Unlock()
+	}
+}
+
+// Cancelled returns true once the run's context has been cancelled
+func (c *RunControl) Cancelled() bool {
+	return c.Err() != nil
+}
+
+// Err returns the run's context error once it has been cancelled, and nil before then
+func (c *RunControl) Err() error {
+	if c == nil {
+		return nil
+	}
+	return c.ctx.Err()
+}
+
+// Progress returns a snapshot of the run's progress
+func (c *RunControl) Progress() RunProgress {
+	done := int(c.done.Load())
+	total := int(max(c.planned.Load(), c.total.Load()))
+	p := RunProgress{Done: done, Total: total, Elapsed: time.Since(c.started)}
+	if done > 0 && total > done {
+		p.ETA = p.Elapsed / time.Duration(done) * time.Duration(total-done)
+	}
+	return p
+}
+
+// start records n pieces of work beginning
+func (c *RunControl) start(n int) {
+	if c != nil {
+		c.total.Add(int64(n))
+	}
+}
+
+// finish records the work at index i finishing with result
+func (c *RunControl) finish(i int, result any) {
+	if c == nil {
+		return
+	}
+	c.done.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.observe != nil {
+		c.observe(i, result)
+	}
+}
+
+// parallelMap runs work for every index in [0, n) on up to workers goroutines
+// Results are returned in index order, so output is the same for any number of workers
+// If ctl is cancelled, work not yet started is skipped and its results are left as zero values,
+// so callers of a controlled run check ctl.Err() before using them
+func parallelMap[T any](ctl *RunControl, n, workers int, work func(i int) T) []T {
+	results := make([]T, n)
+	parallelOrdered(ctl, n, workers, work, func(i int, result T) {
+		results[i] = result
+	})
+	return results
+}
+
+// parallelOrdered runs work for every index in [0, n) on up to workers goroutines and passes
+// each result to consume in index order on the calling goroutine
+// work must only read shared state; consume may update state without locking
+// If ctl is cancelled, no more work is started or consumed and the context's error is returned
+func parallelOrdered[T any](ctl *RunControl, n, workers int, work func(i int) T, consume func(i int, result T)) error {
+	ctl.start(n)
+	if workers <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			if err := ctl.Err(); err != nil {
+				return err
+			}
+			result := work(i)
+			ctl.finish(i, result)
+			consume(i, result)
+		}
+		return nil
+	}
+	workers = min(workers, n)
+
+	batch := make([]T, min(workers*poolBatchPerWorker, n))
+	for start := 0; start < n; start += len(batch) {
+		end := min(start+len(batch), n)
+
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					batch[i-start] = work(i)
+					ctl.finish(i, batch[i-start])
+				}
+			}()
+		}
+		for i := start; i < end && !ctl.Cancelled(); i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		if err := ctl.Err(); err != nil {
+			return err
+		}
+
+		for i := start; i < end; i++ {
+			consume(i, batch[i-start])
+		}
+	}
+	return nil
+}
+
+// RunSimulations runs every strategy against config using the configured concurrency
+// Results are in the same order as strategies
+func RunSimulations(strategies []SimulationParams, config *Config) []SimulationResult {
+	return parallelMap(config.Control, len(strategies), config.Simulation.GetConcurrency(), func(i int) SimulationResult {
+		return runCachedSimulation(strategies[i], config)
+	})
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestParallelMap_Order verifies results come back in index order for any number of workers
+func TestParallelMap_Order(t *testing.T) {
+	for _, workers := range []int{0, 1, 3, 8, 100} {
+		got := parallelMap(nil, 50, workers, func(i int) int { return i * i })
+		if len(got) != 50 {
+			t.Fatalf("workers=%d: expected 50 results, got %d", workers, len(got))
+		}
+		for i, v := range got {
+			if v != i*i {
+				t.Fatalf("workers=%d: result %d is %d, expected %d", workers, i, v, i*i)
+			}
+		}
+	}
+	if len(parallelMap(nil, 0, 4, func(i int) int { return i })) != 0 {
+		t.Error("Expected no results for no work")
+	}
+}
+
+// TestParallelOrdered_ConsumeInOrder verifies consume sees every index once, in order
+func TestParallelOrdered_ConsumeInOrder(t *testing.T) {
+	var seen []int
+	parallelOrdered(nil, 37, 4, func(i int) int { return i }, func(i int, result int) {
+		if i != result {
+			t.Errorf("Index %d consumed result %d", i, result)
+		}
+		seen = append(seen, i)
+	})
+	if len(seen) != 37 {
+		t.Fatalf("Expected 37 results, got %d", len(seen))
+	}
+	for i, v := range seen {
+		if v != i {
+			t.Fatalf("Expected index %d at position %d", v, i)
+		}
+	}
+}
+
+// TestRunSimulations_MatchesSequential verifies parallel results are identical to one at a time
+func TestRunSimulations_MatchesSequential(t *testing.T) {
+	config := createTestConfig()
+	strategies := GetStrategiesForConfig(config)
+
+	config.Simulation.Concurrency = 1
+	sequential := RunSimulations(strategies, config)
+	config.Simulation.Concurrency = 8
+	parallel := RunSimulations(strategies, config)
+
+	if len(parallel) != len(sequential) {
+		t.Fatalf("Expected %d results, got %d", len(sequential), len(parallel))
+	}
+	for i := range sequential {
+		s, p := sequential[i], parallel[i]
+		if s.Params.ShortName() != p.Params.ShortName() {
+			t.Errorf("Result %d: expected %s, got %s", i, s.Params.ShortName(), p.Params.ShortName())
+		}
+		if s.TotalTaxPaid != p.TotalTaxPaid || getTotalFinalBalance(s) != getTotalFinalBalance(p) ||
+			s.RanOutOfMoney != p.RanOutOfMoney || len(s.Years) != len(p.Years) {
+			t.Errorf("%s: parallel result differs from sequential", s.Params.ShortName())
+		}
+	}
+	if FindBestResult(sequential, config) != FindBestResult(parallel, config) {
+		t.Error("Expected the same best strategy for any number of workers")
+	}
+}
+
+// TestDepletion_ParallelMatchesSequential verifies depletion searches give the same incomes in parallel
+func TestDepletion_ParallelMatchesSequential(t *testing.T) {
+	config := createDepletionTestConfig()
+
+	config.Simulation.Concurrency = 1
+	sequential := runDepletionCalculations(config, false)
+	config.Simulation.Concurrency = 8
+	parallel := runDepletionCalculations(config, false)
+
+	if len(parallel) != len(sequential) {
+		t.Fatalf("Expected %d results, got %d", len(sequential), len(parallel))
+	}
+	for i := range sequential {
+		if sequential[i].MonthlyBeforeAge != parallel[i].MonthlyBeforeAge {
+			t.Errorf("%s: expected £%.2f/month, got £%.2f/month", sequential[i].Params.ShortName(),
+				sequential[i].MonthlyBeforeAge, parallel[i].MonthlyBeforeAge)
+		}
+	}
+}
+
+// TestSharedConfig_ConcurrentUse exercises simulations and config clones sharing one config
+// Run with -race to check strategies never write to the shared config or people
+func TestSharedConfig_ConcurrentUse(t *testing.T) {
+	config := createTierDepletionTestConfig()
+	strategies := GetStrategiesForConfig(config)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cloned := cloneConfigWithMultiplier(config, float64(1000+i*100))
+			RunSimulationV2(strategies[i%len(strategies)], cloned)
+			RunSimulation(strategies[i%len(strategies)], config)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestParallelOrdered_Cancel verifies a cancelled run stops starting work and returns the context error
+func TestParallelOrdered_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctl := NewRunControl(ctx)
+
+	var started atomic.Int32
+	consumed := 0
+	err := parallelOrdered(ctl, 1000, 4, func(i int) int {
+		if started.Add(1) == 10 {
+			cancel()
+		}
+		return i
+	}, func(i int, result int) {
+		consumed++
+	})
+	if err != context.Canceled || ctl.Err() != context.Canceled {
+		t.Fatalf("Expected the run to be cancelled, got %v", err)
+	}
+	if started.Load() >= 1000 || consumed >= 1000 {
+		t.Errorf("Expected work to stop early, %d started and %d consumed", started.Load(), consumed)
+	}
+	if p := ctl.Progress(); p.Total != 1000 || p.Done > int(started.Load()) {
+		t.Errorf("Expected 1000 total and at most %d done, got %d of %d", started.Load(), p.Done, p.Total)
+	}
+}
+
+// TestRunControl_Progress verifies the planned total is used until the pools start more work
+func TestRunControl_Progress(t *testing.T) {
+	ctl := NewRunControl(context.Background())
+	ctl.Plan(20)
+	var seen []int
+	ctl.Observe(func(i int, result any) { seen = append(seen, result.(int)) })
+
+	parallelMap(ctl, 5, 1, func(i int) int { return i * 10 })
+	if p := ctl.Progress(); p.Done != 5 || p.Total != 20 || p.ETA <= 0 {
+		t.Errorf("Expected 5 of 20 done with time remaining, got %d of %d (%v)", p.Done, p.Total, p.ETA)
+	}
+	if len(seen) != 5 || seen[4] != 40 {
+		t.Errorf("Expected every result observed, got %v", seen)
+	}
+
+	parallelMap(ctl, 30, 4, func(i int) int { return i })
+	if p := ctl.Progress(); p.Done != 35 || p.Total != 35 || p.ETA != 0 {
+		t.Errorf("Expected 35 of 35 done, got %d of %d (%v)", p.Done, p.Total, p.ETA)
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"time"
+)
+
+// maxSearchRetirementAge is the latest retirement age the retirement date solver will try
+const maxSearchRetirementAge = 75
+
+// RetirementSearchResult holds the earliest viable retirement date for one strategy
+type RetirementSearchResult struct {
+	Params           SimulationParams
+	Found            bool              // False if the plan fails even at the latest date searched
+	RetirementDates  map[string]string // Earliest viable retirement date for each person searched
+	RetirementAges   map[string]int    // Age at the earliest viable retirement date
+	YearsEarlier     int               // Years earlier than the configured plan (negative = later)
+	TargetAge        int               // Age (of the simulation reference person) the plan must last to
+	SimulationResult SimulationResult  // Full simulation at the earliest viable date
+}
+
+// GetRetirementSearchTargetAge returns the age the plan must support spending to
+// Uses target_depletion_age if set, otherwise the simulation end age
+func GetRetirementSearchTargetAge(config *Config) int {
+	if config.IncomeRequirements.TargetDepletionAge > 0 {
+		return config.IncomeRequirements.TargetDepletionAge
+	}
+	return config.Simulation.EndAge
+}
+
+// CalculateEarliestRetirement uses binary search to find the earliest retirement date
+// that still supports the configured spending to targetAge
+// people lists who to move; if empty, everyone moves together by the same number of years
+func CalculateEarliestRetirement(params SimulationParams, config *Config, people []string, targetAge int) RetirementSearchResult {
+	people = retirementSearchPeople(config, people)
+	refPerson := config.GetSimulationReferencePerson()
+	targetYear := GetBirthYear(refPerson.BirthDate) + targetAge
+
+	// Search offsets (in years) relative to each person's configured retirement
+	// Earliest: nobody retires before the simulation starts; latest: nobody works past maxSearchRetirementAge
+	lowOffset, highOffset := -100, 100
+	for _, name := range people {
+		pc := config.FindPerson(name)
+		if pc == nil {
+			continue
+		}
+		taxYear, age := pc.GetRetirementInfo()
+		if offset := config.Simulation.StartYear - taxYear; offset > lowOffset {
+			lowOffset = offset
+		}
+		if offset := maxSearchRetirementAge - age; offset < highOffset {
+			highOffset = offset
+		}
+	}
+
+	result := RetirementSearchResult{Params: params, TargetAge: targetAge}
+	if lowOffset > highOffset {
+		return result
+	}
+
+	viable := func(offset int) (SimulationResult, bool) {
+		sim := RunSimulationV2(params, cloneConfigWithRetirementOffset(config, people, offset))
+		return sim, !sim.RanOutOfMoney || sim.RanOutYear > targetYear
+	}
+
+	// If retiring as late as possible still fails, there is no viable date
+	bestSim, ok := viable(highOffset)
+	if !ok {
+		result.SimulationResult = bestSim
+		return result
+	}
+	bestOffset := highOffset
+
+	// Find the smallest viable offset (retiring later never makes the plan worse)
+	for lowOffset < highOffset {
+		midOffset := lowOffset + (highOffset-lowOffset)/2
+		if sim, ok := viable(midOffset); ok {
+			highOffset = midOffset
+			bestOffset = midOffset
+			bestSim = sim
+		} else {
+			lowOffset = midOffset + 1
+		}
+	}
+	if bestOffset != lowOffset {
+		bestOffset = lowOffset
+		bestSim, _ = viable(lowOffset)
+	}
+
+	result.Found = true
+	result.YearsEarlier = -bestOffset
+	result.SimulationResult = bestSim
+	result.RetirementDates = make(map[string]string)
+	result.RetirementAges = make(map[string]int)
+	searchConfig := cloneConfigWithRetirementOffset(config, people, bestOffset)
+	for _, name := range people {
+		if pc := searchConfig.FindPerson(name); pc != nil {
+			_, age := pc.GetRetirementInfo()
+			result.RetirementDates[name] = pc.RetirementDate
+			result.RetirementAges[name] = age
+		}
+	}
+	return result
+}
+
+// retirementSearchPeople returns the people whose retirement dates are searched
+// Unknown names are ignored; an empty list means everyone
+func retirementSearchPeople(config *Config, people []string) []string {
+	var names []string
+	for _, name := range people {
+		if config.FindPerson(name) != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		return names
+	}
+	for _, p := range config.People {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// cloneConfigWithRetirementOffset creates a copy of config with retirement moved by offset years
+// for the named people. Each person keeps their retirement day and month (or their birthday if
+// only retirement_age is set). Pension access age is pinned to the original plan so that
+// moving retirement does not also move when the pension can be drawn.
+func cloneConfigWithRetirementOffset(config *Config, people []string, offset int) *Config {
+	newConfig := *config
+	newConfig.People = make([]PersonConfig, len(config.People))
+	copy(newConfig.People, config.People)
+
+	for i := range newConfig.People {
+		pc := &newConfig.People[i]
+		if !containsString(people, pc.Name) {
+			continue
+		}
+		_, age := pc.GetRetirementInfo()
+		if pc.PensionAccessAge <= 0 {
+			pc.PensionAccessAge = age
+		}
+		if date, err := time.Parse("2006-01-02", pc.RetirementDate); err == nil {
+			pc.RetirementDate = date.AddDate(offset, 0, 0).Format("2006-01-02")
+		} else if birth, err := time.Parse("2006-01-02", pc.BirthDate); err == nil {
+			pc.RetirementDate = birth.AddDate(age+offset, 0, 0).Format("2006-01-02")
+		}
+		pc.RetirementAge = age + offset
+	}
+	return &newConfig
+}
+
+// containsString returns true if s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// RunAllRetirementSearches finds the earliest viable retirement date for every strategy
+func RunAllRetirementSearches(config *Config, people []string, targetAge int) []RetirementSearchResult {
+	strategies := GetStrategiesForConfig(config)
+
+	// Apply config settings to strategies
+	maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
+	for i := range strategies {
+		strategies[i].MaximizeCoupleISA = maximizeCoupleISA
+	}
+
+	return parallelMap(config.Control, len(strategies), config.Simulation.GetConcurrency(), func(i int) RetirementSearchResult {
+		return CalculateEarliestRetirement(strategies[i], config, people, targetAge)
+	})
+}
+
+// FindBestRetirementSearch returns the index of the strategy allowing the earliest retirement
+// Ties are broken by the highest final balance; returns -1 if no strategy has a viable date
+func FindBestRetirementSearch(results []RetirementSearchResult) int {
+	bestIdx := -1
+	for i, r := range results {
+		if !r.Found {
+			continue
+		}
+		if bestIdx < 0 || r.YearsEarlier > results[bestIdx].YearsEarlier ||
+			(r.YearsEarlier == results[bestIdx].YearsEarlier &&
+				getTotalFinalBalance(r.SimulationResult) > getTotalFinalBalance(results[bestIdx].SimulationResult)) {
+			bestIdx = i
+		}
+	}
+	return bestIdx
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestCloneConfigWithRetirementOffset verifies dates move for the named people only
+// and pension access stays at the original retirement age
+func TestCloneConfigWithRetirementOffset(t *testing.T) {
+	config := createTestConfig()
+	config.People[1].RetirementDate = "2030-07-15"
+
+	clone := cloneConfigWithRetirementOffset(config, []string{"James", "Delphine"}, 2)
+
+	james := clone.FindPerson("James")
+	if james.RetirementDate != "2028-01-01" || james.RetirementAge != 57 || james.PensionAccessAge != 55 {
+		t.Errorf("James: expected 2028-01-01 at 57 with access at 55, got %s at %d with access at %d",
+			james.RetirementDate, james.RetirementAge, james.PensionAccessAge)
+	}
+	delphine := clone.FindPerson("Delphine")
+	if delphine.RetirementDate != "2032-07-15" {
+		t.Errorf("Delphine: expected 2032-07-15, got %s", delphine.RetirementDate)
+	}
+
+	// Only the named person moves and the original config is untouched
+	clone = cloneConfigWithRetirementOffset(config, []string{"Delphine"}, -1)
+	if clone.FindPerson("James").RetirementDate != "" || clone.FindPerson("Delphine").RetirementDate != "2029-07-15" {
+		t.Errorf("Expected only Delphine to move, got James %q Delphine %q",
+			clone.FindPerson("James").RetirementDate, clone.FindPerson("Delphine").RetirementDate)
+	}
+	if config.People[0].RetirementDate != "" || config.People[1].RetirementDate != "2030-07-15" {
+		t.Error("Expected original config to be unchanged")
+	}
+}
+
+// TestCalculateEarliestRetirement verifies the solver returns the earliest viable date
+func TestCalculateEarliestRetirement(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	config := createTestConfig()
+	config.Mortgage = MortgageConfig{}
+	config.IncomeRequirements.MonthlyBeforeAge = 7000
+	config.IncomeRequirements.MonthlyAfterAge = 6000
+	if !RunSimulation(params, config).RanOutOfMoney {
+		t.Fatal("Expected the configured plan to run out of money")
+	}
+
+	targetAge := GetRetirementSearchTargetAge(config)
+	result := CalculateEarliestRetirement(params, config, nil, targetAge)
+	if !result.Found {
+		t.Fatal("Expected a viable retirement date")
+	}
+	if result.YearsEarlier >= 0 {
+		t.Errorf("Expected retirement later than planned, got %d years earlier", result.YearsEarlier)
+	}
+	if len(result.RetirementDates) != 2 {
+		t.Errorf("Expected dates for both people, got %v", result.RetirementDates)
+	}
+	if result.SimulationResult.RanOutOfMoney {
+		t.Errorf("Expected the plan to last to age %d, ran out in %d", targetAge, result.SimulationResult.RanOutYear)
+	}
+
+	// One year earlier is not viable
+	earlier := RunSimulation(params, cloneConfigWithRetirementOffset(config, []string{"James", "Delphine"}, -result.YearsEarlier-1))
+	if !earlier.RanOutOfMoney {
+		t.Error("Expected retiring a year earlier than the result to run out of money")
+	}
+
+	// Searching one person only moves that person
+	single := CalculateEarliestRetirement(params, config, []string{"James"}, targetAge)
+	if _, ok := single.RetirementDates["Delphine"]; ok || single.RetirementDates["James"] == "" {
+		t.Errorf("Expected only James to be searched, got %v", single.RetirementDates)
+	}
+}
+
+// TestCalculateEarliestRetirement_EarlierThanPlan verifies a well-funded plan can retire sooner
+func TestCalculateEarliestRetirement_EarlierThanPlan(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	config := createTestConfig()
+	config.Mortgage = MortgageConfig{}
+	config.IncomeRequirements.MonthlyBeforeAge = 2000
+	config.IncomeRequirements.MonthlyAfterAge = 2000
+	config.People[0].RetirementAge = 60
+	config.People[1].RetirementAge = 62
+
+	result := CalculateEarliestRetirement(params, config, nil, 90)
+	if !result.Found || result.YearsEarlier <= 0 {
+		t.Fatalf("Expected retirement earlier than planned, got found=%v years earlier=%d", result.Found, result.YearsEarlier)
+	}
+	// Nobody can retire before the simulation starts
+	if result.RetirementAges["James"] < 55 {
+		t.Errorf("Expected James to retire no earlier than the start year, got age %d", result.RetirementAges["James"])
+	}
+}
+
+// TestCalculateEarliestRetirement_NotViable verifies an unaffordable plan reports no date
+func TestCalculateEarliestRetirement_NotViable(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	config := createTestConfig()
+	config.IncomeRequirements.MonthlyBeforeAge = 50000
+	config.IncomeRequirements.MonthlyAfterAge = 50000
+
+	results := []RetirementSearchResult{CalculateEarliestRetirement(params, config, nil, 90)}
+	if results[0].Found {
+		t.Errorf("Expected no viable date, got %v", results[0].RetirementDates)
+	}
+	if FindBestRetirementSearch(results) != -1 {
+		t.Error("Expected no best strategy when no date is viable")
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+// Kinds of extra saving the required saving solver can search over
+const (
+	SavingTypeMonthly = "monthly"  // Regular saving each month until retirement
+	SavingTypeLumpSum = "lump_sum" // One-off addition at the start of the simulation
+)
+
+// Search ceilings for the required saving solver
+const (
+	maxSearchMonthlySaving = 20000.0
+	maxSearchLumpSum       = 5000000.0
+)
+
+// SavingsSearchResult holds the minimum extra saving needed for one strategy
+type SavingsSearchResult struct {
+	Params           SimulationParams
+	Person           string           // Who makes the extra saving
+	SavingType       string           // "monthly" or "lump_sum"
+	Wrapper          string           // "isa" or "pension"
+	SavingYears      int              // Tax years of saving before retirement (monthly saving only)
+	Found            bool             // False if even the maximum searched amount is not enough
+	AlreadyViable    bool             // True if the plan lasts without any extra saving
+	BaselineOutYear  int              // Year the plan runs out without extra saving (0 = never)
+	Amount           float64          // Minimum monthly saving or lump sum (net of pension relief)
+	SimulationResult SimulationResult // Full simulation with the extra saving
+}
+
+// GetSavingType returns a valid saving type, defaulting to monthly
+func GetSavingType(savingType string) string {
+	if savingType == SavingTypeLumpSum {
+		return SavingTypeLumpSum
+	}
+	return SavingTypeMonthly
+}
+
+// GetSavingWrapper returns a valid saving wrapper, defaulting to ISA
+func GetSavingWrapper(wrapper string) string {
+	if wrapper == SavingWrapperPension {
+		return SavingWrapperPension
+	}
+	return SavingWrapperISA
+}
+
+// savingsSearchPerson returns the person who makes the extra saving
+// Defaults to the simulation reference person, then the first person
+func savingsSearchPerson(config *Config, person string) string {
+	if config.FindPerson(person) != nil {
+		return person
+	}
+	if ref := config.GetSimulationReferencePerson(); ref != nil {
+		return ref.Name
+	}
+	if len(config.People) > 0 {
+		return config.People[0].Name
+	}
+	return ""
+}
+
+// CalculateRequiredSaving uses binary search to find the minimum extra saving that lets
+// the plan last to the simulation end age without running out of money
+func CalculateRequiredSaving(params SimulationParams, config *Config, person, savingType, wrapper string) SavingsSearchResult {
+	person = savingsSearchPerson(config, person)
+	savingType = GetSavingType(savingType)
+	wrapper = GetSavingWrapper(wrapper)
+
+	result := SavingsSearchResult{Params: params, Person: person, SavingType: savingType, Wrapper: wrapper}
+	if pc := config.FindPerson(person); pc != nil {
+		taxYear, _ := pc.GetRetirementInfo()
+		if taxYear > config.Simulation.StartYear {
+			result.SavingYears = taxYear - config.Simulation.StartYear
+		}
+	}
+
+	run := func(amount float64) SimulationResult {
+		return RunSimulationV2(params, cloneConfigWithExtraSaving(config, person, savingType, wrapper, amount))
+	}
+
+	// Check whether the plan already works without extra saving
+	sim := run(0)
+	result.BaselineOutYear = sim.RanOutYear
+	if !sim.RanOutOfMoney {
+		result.Found = true
+		result.AlreadyViable = true
+		result.SimulationResult = sim
+		return result
+	}
+
+	// Monthly saving cannot help if retirement has already started
+	if savingType == SavingTypeMonthly && result.SavingYears == 0 {
+		result.SimulationResult = sim
+		return result
+	}
+
+	lowAmount := 0.0
+	highAmount := maxSearchMonthlySaving
+	tolerance := 1.0 // Within £1/month
+	if savingType == SavingTypeLumpSum {
+		highAmount = maxSearchLumpSum
+		tolerance = 100.0 // Within £100
+	}
+
+	bestSim := run(highAmount)
+	if bestSim.RanOutOfMoney {
+		result.SimulationResult = bestSim
+		return result
+	}
+
+	maxIterations := 100
+	for i := 0; i < maxIterations && highAmount-lowAmount > tolerance; i++ {
+		midAmount := (lowAmount + highAmount) / 2
+		if sim := run(midAmount); sim.RanOutOfMoney {
+			// Still runs out - need to save more
+			lowAmount = midAmount
+		} else {
+			highAmount = midAmount
+			bestSim = sim
+		}
+	}
+
+	result.Found = true
+	result.Amount = highAmount
+	result.SimulationResult = bestSim
+	return result
+}
+
+// cloneConfigWithExtraSaving creates a copy of config with extra saving added for one person
+// The amount is added to any saving already configured
+func cloneConfigWithExtraSaving(config *Config, person, savingType, wrapper string, amount float64) *Config {
+	newConfig := *config
+	newConfig.People = make([]PersonConfig, len(config.People))
+	copy(newConfig.People, config.People)
+
+	if pc := newConfig.FindPerson(person); pc != nil {
+		pc.ExtraSavingWrapper = wrapper
+		if savingType == SavingTypeLumpSum {
+			pc.ExtraLumpSum += amount
+		} else {
+			pc.ExtraMonthlySaving += amount
+		}
+	}
+	return &newConfig
+}
+
+// RunAllSavingsSearches finds the minimum extra saving for every strategy
+func RunAllSavingsSearches(config *Config, person, savingType, wrapper string) []SavingsSearchResult {
+	strategies := GetStrategiesForConfig(config)
+
+	// Apply config settings to strategies
+	maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
+	for i := range strategies {
+		strategies[i].MaximizeCoupleISA = maximizeCoupleISA
+	}
+
+	return parallelMap(config.Control, len(strategies), config.Simulation.GetConcurrency(), func(i int) SavingsSearchResult {
+		return CalculateRequiredSaving(strategies[i], config, person, savingType, wrapper)
+	})
+}
+
+// FindBestSavingsSearch returns the index of the strategy needing the least extra saving
+// Ties are broken by the highest final balance; returns -1 if no strategy can be rescued
+func FindBestSavingsSearch(results []SavingsSearchResult) int {
+	bestIdx := -1
+	for i, r := range results {
+		if !r.Found {
+			continue
+		}
+		if bestIdx < 0 || r.Amount < results[bestIdx].Amount ||
+			(r.Amount == results[bestIdx].Amount &&
+				getTotalFinalBalance(r.SimulationResult) > getTotalFinalBalance(results[bestIdx].SimulationResult)) {
+			bestIdx = i
+		}
+	}
+	return bestIdx
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// createSavingsTestConfig creates an underfunded plan where James retires at 60 (tax year 2030/31)
+func createSavingsTestConfig() *Config {
+	config := createTestConfig()
+	config.Mortgage = MortgageConfig{}
+	config.People[0].RetirementAge = 60
+	config.People[1].RetirementAge = 62
+	config.IncomeRequirements.MonthlyBeforeAge = 7000
+	config.IncomeRequirements.MonthlyAfterAge = 6000
+	return config
+}
+
+// TestAddSaving verifies ISA saving is added as is and pension saving gets basic-rate relief
+func TestAddSaving(t *testing.T) {
+	p := &Person{PensionAnnualAllowance: 60000}
+	if toPension, toISA := p.AddSaving(8000); toPension != 0 || toISA != 8000 || p.Savings() != 8000 {
+		t.Errorf("Expected £8,000 added to savings, got £%.0f (savings £%.0f)", toISA, p.Savings())
+	}
+
+	p.ExtraSavingToPension = true
+	if toPension, toISA := p.AddSaving(8000); toPension != 10000 || toISA != 0 || p.UncrystallisedPot != 10000 {
+		t.Errorf("Expected £10,000 gross added to pension, got £%.0f", toPension)
+	}
+}
+
+// TestAddSaving_OverAllowance verifies net saving over the annual allowance goes to the ISA rather than being lost
+func TestAddSaving_OverAllowance(t *testing.T) {
+	p := &Person{PensionAnnualAllowance: 60000, EmployerContribution: 10000, ExtraSavingToPension: true}
+
+	toPension, toISA := p.AddSaving(80000)
+	if toPension != 50000 {
+		t.Errorf("Expected contribution capped at £50,000, got £%.0f", toPension)
+	}
+	// £50,000 gross costs £40,000 net, so the other £40,000 is saved tax-free
+	if toISA != 40000 || p.TaxFreeSavings+p.GIA != 40000 {
+		t.Errorf("Expected £40,000 saved outside the pension, got £%.0f (savings £%.0f)", toISA, p.TaxFreeSavings+p.GIA)
+	}
+	// Every pound saved is accounted for: the relief is the only money added
+	if net := toPension*(1-pensionBasicRateRelief) + toISA; net != 80000 {
+		t.Errorf("Expected £80,000 net saved, got £%.0f", net)
+	}
+}
+
+// TestExtraSaving_OnlyBeforeRetirement verifies monthly saving stops at retirement
+func TestExtraSaving_OnlyBeforeRetirement(t *testing.T) {
+	config := createSavingsTestConfig()
+	config.People[0].ExtraMonthlySaving = 500
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	result := RunSimulation(params, config)
+	for _, year := range result.Years {
+		saved := year.ExtraSavingByPerson["James"]
+		if year.Year < 2030 && saved != 6000 {
+			t.Errorf("Year %d: expected £6,000 saved, got £%.0f", year.Year, saved)
+		}
+		if year.Year >= 2030 && saved != 0 {
+			t.Errorf("Year %d: expected no saving after retirement, got £%.0f", year.Year, saved)
+		}
+	}
+}
+
+// TestCalculateRequiredSaving verifies the solver finds the minimum monthly saving
+func TestCalculateRequiredSaving(t *testing.T) {
+	config := createSavingsTestConfig()
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	result := CalculateRequiredSaving(params, config, "James", SavingTypeMonthly, SavingWrapperISA)
+	if !result.Found || result.AlreadyViable {
+		t.Fatalf("Expected extra saving to be needed and found, got found=%v already=%v", result.Found, result.AlreadyViable)
+	}
+	if result.SavingYears != 4 || result.BaselineOutYear == 0 {
+		t.Errorf("Expected 4 saving years and a baseline shortfall, got %d years, out %d", result.SavingYears, result.BaselineOutYear)
+	}
+	if result.SimulationResult.RanOutOfMoney {
+		t.Error("Expected the plan to last with the extra saving")
+	}
+
+	// A little less saving is not enough
+	less := RunSimulation(params, cloneConfigWithExtraSaving(config, "James", SavingTypeMonthly, SavingWrapperISA, result.Amount-5))
+	if !less.RanOutOfMoney {
+		t.Errorf("Expected £%.0f/month less £5 to run out of money", result.Amount)
+	}
+
+	// The original config is untouched
+	if config.People[0].ExtraMonthlySaving != 0 {
+		t.Error("Expected original config to be unchanged")
+	}
+}
+
+// TestCalculateRequiredSaving_LumpSum verifies lump sums work once retirement has started
+func TestCalculateRequiredSaving_LumpSum(t *testing.T) {
+	config := createSavingsTestConfig()
+	config.People[0].RetirementAge = 55 // Retires in the first simulation year
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	monthly := CalculateRequiredSaving(params, config, "James", SavingTypeMonthly, SavingWrapperISA)
+	if monthly.Found || monthly.SavingYears != 0 {
+		t.Errorf("Expected no monthly saving to be possible after retirement, got found=%v years=%d", monthly.Found, monthly.SavingYears)
+	}
+
+	lump := CalculateRequiredSaving(params, config, "James", SavingTypeLumpSum, SavingWrapperISA)
+	if !lump.Found || lump.Amount <= 0 {
+		t.Fatalf("Expected a lump sum to be found, got found=%v amount £%.0f", lump.Found, lump.Amount)
+	}
+	if lump.SimulationResult.RanOutOfMoney {
+		t.Error("Expected the plan to last with the lump sum")
+	}
+	if math.Abs(totalExtraSaving(lump)-lump.Amount) > 0.01 {
+		t.Errorf("Expected total saved to equal the lump sum, got £%.0f", totalExtraSaving(lump))
+	}
+
+	// A pension lump sum is limited by the annual allowance, and the rest is saved tax-free
+	pension := CalculateRequiredSaving(params, config, "James", SavingTypeLumpSum, SavingWrapperPension)
+	if !pension.Found || pension.SimulationResult.RanOutOfMoney {
+		t.Errorf("Expected a pension lump sum over the annual allowance to be found, got found=%v amount £%.0f", pension.Found, pension.Amount)
+	}
+}
+
+// TestCalculateRequiredSaving_AlreadyViable verifies a funded plan needs no extra saving
+func TestCalculateRequiredSaving_AlreadyViable(t *testing.T) {
+	config := createSavingsTestConfig()
+	config.IncomeRequirements.MonthlyBeforeAge = 2000
+	config.IncomeRequirements.MonthlyAfterAge = 2000
+
+	results := RunAllSavingsSearches(config, "", "", "")
+	if len(results) == 0 {
+		t.Fatal("Expected results for every strategy")
+	}
+	for _, r := range results {
+		if !r.AlreadyViable || r.Amount != 0 {
+			t.Errorf("%s: expected no extra saving needed, got £%.0f", r.Params.ShortName(), r.Amount)
+		}
+		if r.Person != "James" || r.SavingType != SavingTypeMonthly || r.Wrapper != SavingWrapperISA {
+			t.Errorf("Expected defaults James/monthly/isa, got %s/%s/%s", r.Person, r.SavingType, r.Wrapper)
+		}
+	}
+	if FindBestSavingsSearch(results) < 0 {
+		t.Error("Expected a best strategy")
+	}
+}
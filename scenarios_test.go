@@ -381,7 +381,7 @@ func TestScenario_LongTermGrowth(t *testing.T) {
 
 	// Apply 10 years of growth (before accessing pension)
 	for i := 0; i < 10; i++ {
-		ApplyGrowth(person, 0.04, 0.05)
+		ApplyGrowth(person, 0.04, 0.05, 0.20)
 	}
 
 	// ISA should have grown: 100000 × (1.04)^10 = 148,024
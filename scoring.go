@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math"
+)
+
+// defaultShortfallYearWeight makes meeting spending outweigh every other objective
+const defaultShortfallYearWeight = 1e9
+
+// Weights returns the preset scoring weights for a goal
+// Each preset keeps a small secondary weight as a tiebreaker
+func (o OptimizationGoal) Weights() ScoringWeights {
+	switch o {
+	case OptimizeTax:
+		return ScoringWeights{Tax: 1, Income: 0.0001, ShortfallYears: defaultShortfallYearWeight}
+	case OptimizeIncome:
+		return ScoringWeights{Income: 1, Tax: 0.001, ShortfallYears: defaultShortfallYearWeight}
+	default: // OptimizeBalance, or OptimizeCustom with no weights configured
+		return ScoringWeights{FinalBalance: 1, Income: 0.001, Tax: 0.0001, ShortfallYears: defaultShortfallYearWeight}
+	}
+}
+
+// ScoreMetrics are the measures of a strategy's outcome that scoring weights combine
+type ScoreMetrics struct {
+	TotalTax       float64 // Lifetime tax paid
+	TotalIncome    float64 // Spendable income received in today's money
+	FinalBalance   float64 // Final ISA + pension balance in today's money
+	ISAShare       float64 // Fraction of the final balance held in ISAs (0-1)
+	MinYearIncome  float64 // Lowest spendable income in a year with spending, in today's money
+	ShortfallYears int     // Years spending could not be met
+}
+
+// NewScoreMetrics measures a simulation result for scoring
+func NewScoreMetrics(result SimulationResult, incomeInflationRate float64) ScoreMetrics {
+	m := ScoreMetrics{
+		TotalTax:    result.TotalTaxPaid,
+		TotalIncome: realTotalIncome(result, incomeInflationRate),
+	}
+	if len(result.Years) == 0 {
+		return m
+	}
+
+	startYear := result.Years[0].Year
+	deflator := 1.0
+	minIncome := -1.0
+	for _, year := range result.Years {
+		deflator = math.Pow(1+incomeInflationRate, float64(year.Year-startYear))
+		if year.RequiredIncome > 0 {
+			income := spendableIncome(year) / deflator
+			if minIncome < 0 || income < minIncome {
+				minIncome = income
+			}
+		}
+		if isShortfallYear(year) {
+			m.ShortfallYears++
+		}
+	}
+	m.MinYearIncome = math.Max(minIncome, 0)
+
+	// Final balances are at the end of the last year
+	finalBalance := getTotalFinalBalance(result)
+	m.FinalBalance = finalBalance / deflator
+	if finalBalance > 0 {
+		isa := 0.0
+		for _, bal := range result.FinalBalances {
+			isa += bal.Savings()
+		}
+		m.ISAShare = isa / finalBalance
+	}
+	return m
+}
+
+// isShortfallYear returns true if withdrawals fell short of what was needed, beyond
+// any shortfall absorbed by cutting discretionary spending above an essential floor
+func isShortfallYear(year YearState) bool {
+	shortfall := year.NetRequired - (year.Withdrawals.TotalTaxFree + year.Withdrawals.TotalTaxable)
+	if year.NetRequired <= 0 || shortfall <= 1 {
+		return false
+	}
+	if year.EssentialIncome > 0 {
+		return shortfall > year.RequiredIncome-year.EssentialIncome+1
+	}
+	return true
+}
+
+// Score combines the metrics into one score (higher is better)
+func (w ScoringWeights) Score(m ScoreMetrics) float64 {
+	return -w.Tax*m.TotalTax +
+		w.Income*m.TotalIncome +
+		w.FinalBalance*m.FinalBalance +
+		w.ISAShare*m.ISAShare +
+		w.MinYearIncome*m.MinYearIncome -
+		w.ShortfallYears*float64(m.ShortfallYears)
+}
+
+// ScoreResult scores a simulation result with the weights
+func (w ScoringWeights) ScoreResult(result SimulationResult, incomeInflationRate float64) float64 {
+	return w.Score(NewScoreMetrics(result, incomeInflationRate))
+}
+
+// ScoreDepletionResult scores a depletion result over the years before its target age
+// Depletion plans run the money out in the target year, so it and later years are not scored
+func (w ScoringWeights) ScoreDepletionResult(r DepletionResult, config *Config) float64 {
+	refPerson := config.GetSimulationReferencePerson()
+	targetYear := GetBirthYear(refPerson.BirthDate) + config.IncomeRequirements.TargetDepletionAge
+	return w.ScoreResult(resultToYear(r.SimulationResult, targetYear-1), config.Financial.IncomeInflationRate)
+}
+
+// resultToYear returns a copy of result ending at endYear, with final balances from that year
+func resultToYear(result SimulationResult, endYear int) SimulationResult {
+	n := 0
+	for n < len(result.Years) && result.Years[n].Year <= endYear {
+		n++
+	}
+	if n == len(result.Years) || n == 0 {
+		return result
+	}
+
+	cut := result
+	cut.Years = result.Years[:n]
+	cut.FinalBalances = cut.Years[n-1].EndBalances
+	cut.TotalTaxPaid, cut.TotalWithdrawn = 0, 0
+	for _, year := range cut.Years {
+		cut.TotalTaxPaid += year.TotalTaxPaid + year.GIATax
+		cut.TotalWithdrawn += year.Withdrawals.TotalTaxFree + year.Withdrawals.TotalTaxable
+	}
+	if cut.RanOutOfMoney && cut.RanOutYear > endYear {
+		cut.RanOutOfMoney, cut.RanOutYear = false, 0
+	}
+	return cut
+}
+
+// FindBestResult returns the index of the highest scoring result (-1 if there are none)
+// Uses the configured scoring weights, or the final balance goal if none are set
+func FindBestResult(results []SimulationResult, config *Config) int {
+	weights := config.Strategy.GetScoringWeights(OptimizeBalance)
+	bestIdx := -1
+	bestScore := 0.0
+	for i, r := range results {
+		score := weights.ScoreResult(r, config.Financial.IncomeInflationRate)
+		if bestIdx < 0 || score > bestScore {
+			bestIdx = i
+			bestScore = score
+		}
+	}
+	return bestIdx
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestNewScoreMetrics verifies each measure is taken in today's money
+func TestNewScoreMetrics(t *testing.T) {
+	result := SimulationResult{
+		TotalTaxPaid: 5000,
+		Years: []YearState{
+			{Year: 2030, RequiredIncome: 20000, NetRequired: 10000, NetIncomeReceived: 30000,
+				Withdrawals: WithdrawalBreakdown{TotalTaxable: 20000, TotalISADeposits: 10000}},
+			{Year: 2031, RequiredIncome: 22000, NetRequired: 12000, NetIncomeReceived: 11000,
+				Withdrawals: WithdrawalBreakdown{TotalTaxable: 1000}},
+		},
+		FinalBalances: map[string]PersonBalances{
+			"James": {TaxFreeSavings: 33000, UncrystallisedPot: 77000},
+		},
+	}
+
+	m := NewScoreMetrics(result, 0.1)
+	if m.TotalTax != 5000 {
+		t.Errorf("Expected £5,000 tax, got £%.0f", m.TotalTax)
+	}
+	// £20,000 spent in 2030 (£10,000 recycled to ISA) and £11,000 in 2031 is £10,000 today
+	if math.Abs(m.TotalIncome-30000) > 0.01 || math.Abs(m.MinYearIncome-10000) > 0.01 {
+		t.Errorf("Expected £30,000 income with a £10,000 lowest year, got £%.2f and £%.2f", m.TotalIncome, m.MinYearIncome)
+	}
+	if math.Abs(m.FinalBalance-100000) > 0.01 || math.Abs(m.ISAShare-0.3) > 0.0001 {
+		t.Errorf("Expected £100,000 final balance with 30%% in ISAs, got £%.2f and %.4f", m.FinalBalance, m.ISAShare)
+	}
+	if m.ShortfallYears != 1 {
+		t.Errorf("Expected 1 shortfall year, got %d", m.ShortfallYears)
+	}
+}
+
+// TestIsShortfallYear_EssentialFloor verifies a shortfall met by cutting discretionary spending is not counted
+func TestIsShortfallYear_EssentialFloor(t *testing.T) {
+	year := YearState{RequiredIncome: 30000, EssentialIncome: 20000, NetRequired: 30000,
+		Withdrawals: WithdrawalBreakdown{TotalTaxable: 25000}}
+	if isShortfallYear(year) {
+		t.Error("Expected a £5,000 cut within £10,000 discretionary spending not to be a shortfall")
+	}
+	year.Withdrawals.TotalTaxable = 15000
+	if !isShortfallYear(year) {
+		t.Error("Expected a £15,000 gap below the essential floor to be a shortfall")
+	}
+}
+
+// TestScoringWeights_Score verifies tax and shortfall years count against the score
+func TestScoringWeights_Score(t *testing.T) {
+	m := ScoreMetrics{TotalTax: 100, TotalIncome: 1000, FinalBalance: 2000, ISAShare: 0.5, MinYearIncome: 50, ShortfallYears: 2}
+	w := ScoringWeights{Tax: 2, Income: 1, FinalBalance: 0.5, ISAShare: 100, MinYearIncome: 1, ShortfallYears: 10}
+	if got := w.Score(m); got != -200+1000+1000+50+50-20 {
+		t.Errorf("Expected score 1880, got %.2f", got)
+	}
+}
+
+// TestGetScoringWeights verifies configured weights replace the goal presets
+func TestGetScoringWeights(t *testing.T) {
+	var s StrategyConfig
+	if s.GetScoringWeights(OptimizeTax) != OptimizeTax.Weights() {
+		t.Error("Expected the tax preset without configured weights")
+	}
+	if s.GetScoringWeights(OptimizeCustom) != OptimizeBalance.Weights() {
+		t.Error("Expected the custom goal to fall back to the balance preset")
+	}
+
+	s.Scoring = &ScoringWeights{Tax: 1}
+	w := s.GetScoringWeights(OptimizeIncome)
+	if w.Tax != 1 || w.Income != 0 || w.ShortfallYears != defaultShortfallYearWeight {
+		t.Errorf("Expected configured weights with the default shortfall weight, got %+v", w)
+	}
+}
+
+// TestFindBestResult_Presets verifies the default score keeps the highest balance, then the longest lasting
+func TestFindBestResult_Presets(t *testing.T) {
+	config := createTestConfig()
+	var results []SimulationResult
+	for _, params := range GetStrategiesForConfig(config) {
+		results = append(results, RunSimulation(params, config))
+	}
+
+	bestIdx := FindBestResult(results, config)
+	best := results[bestIdx]
+	for _, r := range results {
+		if !r.RanOutOfMoney && best.RanOutOfMoney {
+			t.Errorf("%s lasts but the best strategy runs out", r.Params.ShortName())
+		}
+		if !r.RanOutOfMoney && getTotalFinalBalance(r) > getTotalFinalBalance(best)+1 {
+			t.Errorf("%s: final balance £%.0f beats the best £%.0f", r.Params.ShortName(), getTotalFinalBalance(r), getTotalFinalBalance(best))
+		}
+	}
+
+	// Weighting tax alone picks the lowest tax strategy
+	config.Strategy.Scoring = &ScoringWeights{Tax: 1}
+	taxIdx := FindBestResult(results, config)
+	for _, r := range results {
+		if !r.RanOutOfMoney && r.TotalTaxPaid < results[taxIdx].TotalTaxPaid-1 {
+			t.Errorf("%s: tax £%.0f is lower than the best £%.0f", r.Params.ShortName(), r.TotalTaxPaid, results[taxIdx].TotalTaxPaid)
+		}
+	}
+}
+
+// TestFindBestDepletionStrategy_Weights verifies the default picks the highest income and weights can change it
+func TestFindBestDepletionStrategy_Weights(t *testing.T) {
+	config := createDepletionTestConfig()
+	results := runDepletionCalculations(config, false)
+
+	bestIdx := FindBestDepletionStrategy(results, config)
+	for _, r := range results {
+		if r.MonthlyBeforeAge > results[bestIdx].MonthlyBeforeAge+1 {
+			t.Errorf("%s: £%.0f/month beats the best £%.0f/month", r.Params.ShortName(), r.MonthlyBeforeAge, results[bestIdx].MonthlyBeforeAge)
+		}
+	}
+
+	config.Strategy.Scoring = &ScoringWeights{Tax: 1}
+	taxIdx := FindBestDepletionStrategy(results, config)
+	targetYear := GetBirthYear(config.People[0].BirthDate) + config.IncomeRequirements.TargetDepletionAge
+	taxBefore := func(r DepletionResult) float64 {
+		return resultToYear(r.SimulationResult, targetYear-1).TotalTaxPaid
+	}
+	for _, r := range results {
+		if taxBefore(r) < taxBefore(results[taxIdx])-1 {
+			t.Errorf("%s: tax £%.0f is lower than the best £%.0f", r.Params.ShortName(), taxBefore(r), taxBefore(results[taxIdx]))
+		}
+	}
+}
+
+// TestResultToYear verifies a result is cut at the end year with final balances from that year
+func TestResultToYear(t *testing.T) {
+	result := SimulationResult{
+		RanOutOfMoney: true, RanOutYear: 2032, TotalTaxPaid: 600,
+		Years: []YearState{
+			{Year: 2030, TotalTaxPaid: 100, EndBalances: map[string]PersonBalances{"James": {TaxFreeSavings: 2000}}},
+			{Year: 2031, TotalTaxPaid: 200, EndBalances: map[string]PersonBalances{"James": {TaxFreeSavings: 1000}}},
+			{Year: 2032, TotalTaxPaid: 300, EndBalances: map[string]PersonBalances{}},
+		},
+	}
+
+	cut := resultToYear(result, 2031)
+	if len(cut.Years) != 2 || cut.TotalTaxPaid != 300 || cut.RanOutOfMoney {
+		t.Errorf("Expected 2 years, £300 tax and no shortfall, got %d years, £%.0f, ran out %v", len(cut.Years), cut.TotalTaxPaid, cut.RanOutOfMoney)
+	}
+	if getTotalFinalBalance(cut) != 1000 {
+		t.Errorf("Expected £1,000 final balance, got £%.0f", getTotalFinalBalance(cut))
+	}
+	if len(resultToYear(result, 2040).Years) != 3 || len(result.Years) != 3 {
+		t.Error("Expected the original result to be unchanged")
+	}
+}
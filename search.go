@@ -0,0 +1,273 @@
+package main
+
+import (
+	"sort"
+)
+
+// adaptiveSearchStarts is how many of the best starting points are improved by coordinate descent
+const adaptiveSearchStarts = 3
+
+// AdaptiveSearchResult is the outcome of an adaptive search of the strategy space
+type AdaptiveSearchResult struct {
+	Indices   []int              // Strategies simulated, as indices into the adaptive space (see AdaptiveStrategy), ascending
+	Results   []SimulationResult // Result for each of Indices
+	Best      int                // Position in Results of the best strategy (-1 if none)
+	Evaluated int                // Factor combinations simulated
+	SpaceSize int                // Factor combinations in the full space, before constraints
+}
+
+// adaptiveSpace numbers the strategies of adaptive mode without generating them
+// Index i < size is a factor combination, read as a mixed-radix number with the first factor most
+// significant (the order GenerateCombinations uses); the strategies outside the factor space follow
+type adaptiveSpace struct {
+	config    *Config
+	generator *CombinationGenerator
+	factors   []*Factor
+	size      int                // Product of the factors' value counts
+	extras    []SimulationParams // Strategies outside the factor space (e.g. Optimal)
+}
+
+func newAdaptiveSpace(config *Config) *adaptiveSpace {
+	generator := NewCombinationGenerator(config)
+	s := &adaptiveSpace{
+		config:    config,
+		generator: generator,
+		factors:   generator.registry.GetFactorsByMode(config, ModeAdaptive),
+		size:      1,
+	}
+
+	// Optimal is added once per mortgage option, as withOptimalStrategies does for a full list
+	mortgages := []SimulationParams{{MortgageOpt: MortgageNormal}}
+	for _, factor := range s.factors {
+		s.size *= len(factor.Values)
+		if factor.ID == FactorMortgage {
+			mortgages = nil
+			for _, v := range factor.Values {
+				opt, _ := v.Value.(MortgageOption)
+				mortgages = append(mortgages, SimulationParams{MortgageOpt: opt})
+			}
+		}
+	}
+	s.extras = withOptimalStrategies(config, mortgages)[len(mortgages):]
+	return s
+}
+
+// positions returns the value position of each factor in combination i
+func (s *adaptiveSpace) positions(i int) []int {
+	positions := make([]int, len(s.factors))
+	for f := len(s.factors) - 1; f >= 0; f-- {
+		n := len(s.factors[f].Values)
+		positions[f] = i % n
+		i /= n
+	}
+	return positions
+}
+
+// index returns the combination with the given value positions
+func (s *adaptiveSpace) index(positions []int) int {
+	i := 0
+	for f, p := range positions {
+		i = i*len(s.factors[f].Values) + p
+	}
+	return i
+}
+
+func (s *adaptiveSpace) combo(i int) StrategyCombo {
+	combo := StrategyCombo{Values: make(map[FactorID]FactorValue)}
+	for f, p := range s.positions(i) {
+		combo.Values[s.factors[f].ID] = s.factors[f].Values[p]
+	}
+	return combo
+}
+
+// strategy returns strategy i set up as it will be run, or false if it breaks a constraint
+func (s *adaptiveSpace) strategy(i int) (SimulationParams, bool) {
+	var params SimulationParams
+	switch {
+	case i < 0 || i >= s.size+len(s.extras):
+		return params, false
+	case i >= s.size:
+		params = s.extras[i-s.size]
+	default:
+		combo := s.combo(i)
+		if !s.generator.isValid(combo) {
+			return params, false
+		}
+		params = combo.ToSimulationParams()
+	}
+	params.MaximizeCoupleISA = s.config.Strategy.ShouldMaximizeCoupleISA()
+	return params, true
+}
+
+// AdaptiveStrategy returns strategy i of the space AdaptiveSearch searches, or false if there is none
+func AdaptiveStrategy(config *Config, i int) (SimulationParams, bool) {
+	return newAdaptiveSpace(config).strategy(i)
+}
+
+// adaptiveSearch holds the state of one adaptive search
+type adaptiveSearch struct {
+	*adaptiveSpace
+	weights ScoringWeights
+	valid   map[int]bool // Whether each combination looked at passes the constraints
+	results map[int]SimulationResult
+	scores  map[int]float64
+}
+
+// AdaptiveSearch finds near-best strategies without simulating every factor combination
+// Combinations are decoded from their index only as the search reaches them, so the space is never listed
+// The default combination is tried with every drawdown order, then the best few are improved by
+// coordinate descent: each factor in turn is set to its best value with the others held, until no
+// single change improves the score. Strategies outside the factor space (e.g. Optimal) are always run
+func AdaptiveSearch(config *Config, weights ScoringWeights) AdaptiveSearchResult {
+	s := &adaptiveSearch{
+		adaptiveSpace: newAdaptiveSpace(config),
+		weights:       weights,
+		valid:         make(map[int]bool),
+		results:       make(map[int]SimulationResult),
+		scores:        make(map[int]float64),
+	}
+
+	extra := make([]int, len(s.extras))
+	for k := range s.extras {
+		extra[k] = s.size + k
+	}
+	s.evaluate(extra)
+
+	// Starting points: the default combination with each drawdown order
+	defaults := make([]int, len(s.factors))
+	drawdown := -1
+	for f, factor := range s.factors {
+		for p, v := range factor.Values {
+			if v.ID == factor.DefaultValueID {
+				defaults[f] = p
+			}
+		}
+		if factor.ID == FactorDrawdown {
+			drawdown = f
+		}
+	}
+	starts := s.neighbours(defaults, drawdown)
+	if len(starts) == 0 {
+		// Defaults break a constraint: start from the first valid combination
+		for i := 0; i < s.size && !config.Control.Cancelled(); i++ {
+			if s.isValid(i) {
+				starts = []int{i}
+				break
+			}
+		}
+	}
+	s.evaluate(starts)
+
+	sort.Slice(starts, func(a, b int) bool { return s.better(starts[a], starts[b]) })
+	for _, start := range starts[:min(adaptiveSearchStarts, len(starts))] {
+		s.descend(start)
+	}
+
+	return s.result()
+}
+
+// isValid returns true if combination i passes the constraints
+func (s *adaptiveSearch) isValid(i int) bool {
+	valid, ok := s.valid[i]
+	if !ok {
+		valid = s.generator.isValid(s.combo(i))
+		s.valid[i] = valid
+	}
+	return valid
+}
+
+// neighbours returns the valid combinations that differ from positions only in factor f
+// Without such a factor, it returns positions itself if it is valid
+func (s *adaptiveSearch) neighbours(positions []int, f int) []int {
+	if f < 0 {
+		if i := s.index(positions); s.isValid(i) {
+			return []int{i}
+		}
+		return nil
+	}
+	var found []int
+	changed := append([]int(nil), positions...)
+	for p := range s.factors[f].Values {
+		changed[f] = p
+		if i := s.index(changed); s.isValid(i) {
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+// descend improves strategy i one factor at a time until no single change is better
+func (s *adaptiveSearch) descend(i int) {
+	for improved := true; improved && !s.config.Control.Cancelled(); {
+		improved = false
+		for f := range s.factors {
+			candidates := s.neighbours(s.positions(i), f)
+			s.evaluate(candidates)
+			for _, c := range candidates {
+				if s.better(c, i) {
+					i, improved = c, true
+				}
+			}
+		}
+	}
+}
+
+// evaluate simulates the strategies not already simulated
+// Each strategy is run once, so ties and results are the same for any number of workers
+func (s *adaptiveSearch) evaluate(indices []int) {
+	var todo []int
+	for _, i := range indices {
+		if _, done := s.results[i]; !done && !containsInt(todo, i) {
+			todo = append(todo, i)
+		}
+	}
+	sort.Ints(todo)
+
+	strategies := make([]SimulationParams, len(todo))
+	for j, i := range todo {
+		strategies[j], _ = s.strategy(i)
+	}
+	results := parallelMap(s.config.Control, len(todo), s.config.Simulation.GetConcurrency(), func(j int) SimulationResult {
+		return runCachedSimulationV2(strategies[j], s.config)
+	})
+	if s.config.Control.Cancelled() {
+		return // Results of a cancelled run are incomplete
+	}
+	for j, i := range todo {
+		s.results[i] = results[j]
+		s.scores[i] = s.weights.ScoreResult(results[j], s.config.Financial.IncomeInflationRate)
+	}
+}
+
+// better returns true if strategy a scores higher than b; ties go to the earlier strategy
+func (s *adaptiveSearch) better(a, b int) bool {
+	return s.scores[a] > s.scores[b] || (s.scores[a] == s.scores[b] && a < b)
+}
+
+func (s *adaptiveSearch) result() AdaptiveSearchResult {
+	r := AdaptiveSearchResult{Best: -1, SpaceSize: s.size}
+	for i := range s.results {
+		r.Indices = append(r.Indices, i)
+	}
+	sort.Ints(r.Indices)
+	for j, i := range r.Indices {
+		r.Results = append(r.Results, s.results[i])
+		if i < s.size {
+			r.Evaluated++
+		}
+		if r.Best < 0 || s.better(i, r.Indices[r.Best]) {
+			r.Best = j
+		}
+	}
+	return r
+}
+
+// containsInt returns true if values contains v
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
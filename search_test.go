@@ -0,0 +1,122 @@
+package main
+
+import (
+	"math"
+	"runtime"
+	"testing"
+)
+
+// TestAdaptiveSearch_NearBest verifies the search finds a strategy close to the best of every
+// combination while running far fewer of them
+func TestAdaptiveSearch_NearBest(t *testing.T) {
+	for name, config := range map[string]*Config{
+		"fixed":     createTestConfig(),
+		"depletion": createDepletionTestConfig(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			weights := config.Strategy.GetScoringWeights(OptimizeBalance)
+			strategies := GetStrategiesForConfigV2(config, ModeAdaptive)
+
+			bestScore := math.Inf(-1)
+			scores := parallelMap(nil, len(strategies), runtime.NumCPU(), func(i int) float64 {
+				return weights.ScoreResult(RunSimulationV2(strategies[i], config), config.Financial.IncomeInflationRate)
+			})
+			for _, score := range scores {
+				bestScore = max(bestScore, score)
+			}
+
+			found := AdaptiveSearch(config, weights)
+			if found.Best < 0 {
+				t.Fatal("Expected a best strategy")
+			}
+			score := weights.ScoreResult(found.Results[found.Best], config.Financial.IncomeInflationRate)
+			if score < bestScore-math.Abs(bestScore)*0.01 {
+				t.Errorf("Expected a score within 1%% of the best %.4f, got %.4f", bestScore, score)
+			}
+
+			size := 1
+			for _, factor := range NewCombinationGenerator(config).registry.GetFactorsByMode(config, ModeAdaptive) {
+				size *= len(factor.Values)
+			}
+			if found.SpaceSize != size {
+				t.Errorf("Expected the space size to be the product of the factors' %d values, got %d", size, found.SpaceSize)
+			}
+			if found.Evaluated == 0 || found.Evaluated*4 > found.SpaceSize {
+				t.Errorf("Expected under a quarter of %d combinations to be run, got %d", found.SpaceSize, found.Evaluated)
+			}
+			t.Logf("Ran %d of %d combinations, score %.4f against %.4f", found.Evaluated, found.SpaceSize, score, bestScore)
+		})
+	}
+}
+
+// TestAdaptiveSearch_Deterministic verifies the search runs the same strategies for any number of workers
+func TestAdaptiveSearch_Deterministic(t *testing.T) {
+	sequential := createTestConfig()
+	sequential.Simulation.Concurrency = 1
+	parallel := createTestConfig()
+	parallel.Simulation.Concurrency = 8
+
+	a := AdaptiveSearch(sequential, sequential.Strategy.GetScoringWeights(OptimizeBalance))
+	b := AdaptiveSearch(parallel, parallel.Strategy.GetScoringWeights(OptimizeBalance))
+	if len(a.Indices) != len(b.Indices) || a.Indices[a.Best] != b.Indices[b.Best] {
+		t.Fatalf("Expected the same search, got %d and %d strategies", len(a.Indices), len(b.Indices))
+	}
+	for j := range a.Indices {
+		if a.Indices[j] != b.Indices[j] {
+			t.Fatalf("Expected the same strategies to be run, got %d and %d at %d", a.Indices[j], b.Indices[j], j)
+		}
+	}
+}
+
+// TestAdaptiveStrategy_MatchesGenerated verifies decoding every index of the adaptive space gives
+// the strategies adaptive mode generates, in the same order
+func TestAdaptiveStrategy_MatchesGenerated(t *testing.T) {
+	for name, config := range map[string]*Config{
+		"fixed":     createTestConfig(),
+		"depletion": createDepletionTestConfig(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			config.Strategy.IncludeOptimal = true
+			want := GetStrategiesForConfigV2(config, ModeAdaptive)
+
+			space := newAdaptiveSpace(config)
+			var got []SimulationParams
+			for i := 0; i < space.size+len(space.extras); i++ {
+				if params, ok := space.strategy(i); ok {
+					got = append(got, params)
+				}
+			}
+			if len(got) != len(want) {
+				t.Fatalf("Expected %d strategies, got %d", len(want), len(got))
+			}
+			for i := range want {
+				if got[i].ShortName() != want[i].ShortName() {
+					t.Errorf("Strategy %d: expected %s, got %s", i, want[i].ShortName(), got[i].ShortName())
+				}
+			}
+		})
+	}
+}
+
+// TestRunFixedSimulation_Adaptive verifies the web runner reports the search and exports its strategies
+func TestRunFixedSimulation_Adaptive(t *testing.T) {
+	ws := NewWebServer(createDepletionTestConfig(), "")
+	req := APIPDFExportRequest{APISimulationRequest: APISimulationRequest{Mode: "fixed", PermutationMode: "adaptive"}}
+	response := ws.runFixedSimulation(ws.buildConfig(&req.APISimulationRequest), OptimizeBalance, req.PermutationMode)
+	if response.Search == nil || response.Search.Evaluated == 0 || response.Search.Evaluated >= response.Search.SpaceSize {
+		t.Fatalf("Expected the search to run part of the space, got %+v", response.Search)
+	}
+	if response.Best == nil {
+		t.Fatal("Expected a best strategy")
+	}
+
+	// Older clients export by strategy index, which must find the same strategy
+	req.StrategyIdx = response.Best.StrategyIdx
+	_, result, err := ws.exportResult(&req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Params.ShortName() != response.Best.ShortName {
+		t.Errorf("Expected strategy %s to be exported, got %s", response.Best.ShortName, result.Params.ShortName())
+	}
+}
@@ -45,7 +45,8 @@ func buildGrowthRates(min, max, step float64) []float64 {
 }
 
 // RunSensitivityAnalysis runs simulations across a range of growth rates
-// Uses the provided optimization goal to determine the "best" strategy for each cell
+// Uses the configured scoring weights, or the provided optimization goal, to determine
+// the "best" strategy for each cell
 func RunSensitivityAnalysis(config *Config, goal OptimizationGoal) *SensitivityAnalysis {
 	// Use config for growth rate ranges, with defaults if not set
 	pensionMin := config.Sensitivity.PensionGrowthMin
@@ -97,154 +98,24 @@ func RunSensitivityAnalysis(config *Config, goal OptimizationGoal) *SensitivityA
 			testConfig.Financial.PensionGrowthRate = pensionRate
 			testConfig.Financial.SavingsGrowthRate = savingsRate
 
-			// Helper to calculate primary and secondary scores based on goal
-			// Returns: primaryScore, secondaryScore, higherIsBetterPrimary, higherIsBetterSecondary
-			calcScores := func(totalTax, totalWithdrawn, totalIncome, finalBalance float64) (primary, secondary float64, higherPrimary, higherSecondary bool) {
-				taxEfficiency := 1.0
-				if totalWithdrawn > 0 {
-					taxEfficiency = totalTax / totalWithdrawn
+			// Run all strategies and keep the highest scoring one
+			weights := config.Strategy.GetScoringWeights(goal)
+			bestIdx := -1
+			bestScore := 0.0
+			simResults := RunSimulations(strategies, &testConfig)
+			for i, result := range simResults {
+				score := weights.ScoreResult(result, testConfig.Financial.IncomeInflationRate)
+				if bestIdx < 0 || score > bestScore {
+					bestScore = score
+					bestIdx = i
 				}
-				switch goal {
-				case OptimizeIncome:
-					// Primary: income (higher better), Secondary: tax efficiency (lower better)
-					return totalIncome, taxEfficiency, true, false
-				case OptimizeBalance:
-					// Primary: balance (higher better), Secondary: income (higher better)
-					return finalBalance, totalIncome, true, true
-				default: // OptimizeTax
-					// Primary: total tax (lower better), Secondary: income (higher better)
-					return totalTax, totalIncome, false, true
-				}
-			}
-
-			// Check if two scores are "similar" (within 1%)
-			isSimilar := func(a, b float64) bool {
-				if a == 0 && b == 0 {
-					return true
-				}
-				if a == 0 || b == 0 {
-					return false
-				}
-				diff := (a - b) / a
-				if diff < 0 {
-					diff = -diff
-				}
-				return diff < 0.01 // Within 1%
-			}
-
-			// Run all strategies
-			var bestIdx int = -1
-			var bestScore float64 = -1
-			var bestSecondaryScore float64 = -1
-			var longestYear int = 0
-			var longestIdx int = 0
-
-			var simResults []SimulationResult
-			for i, params := range strategies {
-				result := RunSimulation(params, &testConfig)
-				simResults = append(simResults, result)
-
-				// Track longest lasting
-				if result.RanOutYear > longestYear {
-					longestYear = result.RanOutYear
-					longestIdx = i
-				}
-
-				// Calculate final balance and total income
-				finalBal := 0.0
-				for _, bal := range result.FinalBalances {
-					finalBal += bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
-				}
-				// Calculate total income from yearly data
-				totalIncome := 0.0
-				for _, year := range result.Years {
-					totalIncome += year.NetIncomeReceived
-				}
-
-				// First pass: Find best among strategies that don't run out
-				if !result.RanOutOfMoney {
-					score, secondary, higherIsBetter, higherSecondary := calcScores(result.TotalTaxPaid, result.TotalWithdrawn, totalIncome, finalBal)
-
-					// Determine if this is better
-					isBetter := false
-					if bestScore < 0 {
-						isBetter = true
-					} else if higherIsBetter && score > bestScore {
-						isBetter = true
-					} else if !higherIsBetter && score < bestScore {
-						isBetter = true
-					} else if isSimilar(score, bestScore) {
-						// Primary scores are similar, use secondary as tiebreaker
-						if higherSecondary && secondary > bestSecondaryScore {
-							isBetter = true
-						} else if !higherSecondary && secondary < bestSecondaryScore {
-							isBetter = true
-						}
-					}
-
-					if isBetter {
-						bestScore = score
-						bestSecondaryScore = secondary
-						bestIdx = i
-					}
-				}
-			}
-
-			// Second pass: If all ran out, prefer strategies with positive final balance
-			if bestIdx < 0 {
-				bestScore = -1
-				bestSecondaryScore = -1
-				for i, result := range simResults {
-					finalBal := 0.0
-					for _, bal := range result.FinalBalances {
-						finalBal += bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
-					}
-					if finalBal > 1000 {
-						// Calculate total income from yearly data
-						totalIncome := 0.0
-						for _, year := range result.Years {
-							totalIncome += year.NetIncomeReceived
-						}
-						score, secondary, higherIsBetter, higherSecondary := calcScores(result.TotalTaxPaid, result.TotalWithdrawn, totalIncome, finalBal)
-
-						// Determine if this is better
-						isBetter := false
-						if bestScore < 0 {
-							isBetter = true
-						} else if higherIsBetter && score > bestScore {
-							isBetter = true
-						} else if !higherIsBetter && score < bestScore {
-							isBetter = true
-						} else if isSimilar(score, bestScore) {
-							// Primary scores are similar, use secondary as tiebreaker
-							if higherSecondary && secondary > bestSecondaryScore {
-								isBetter = true
-							} else if !higherSecondary && secondary < bestSecondaryScore {
-								isBetter = true
-							}
-						}
-
-						if isBetter {
-							bestScore = score
-							bestSecondaryScore = secondary
-							bestIdx = i
-						}
-					}
-				}
-			}
-
-			// Third pass: If still no best (all have zero balance), pick longest-lasting
-			allRunOut := bestIdx < 0
-			if allRunOut {
-				bestIdx = longestIdx
 			}
 
 			best := simResults[bestIdx]
-			// Calculate total final balance
-			finalBalance := 0.0
-			for _, bal := range best.FinalBalances {
-				finalBalance += bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
-			}
+			finalBalance := getTotalFinalBalance(best)
+
+			// All run out if even the best strategy has no balance left
+			allRunOut := best.RanOutOfMoney && finalBalance <= 1000
 
 			// Determine if this is a "shortfall" scenario (has income gap but still has money)
 			hasShortfall := best.RanOutOfMoney && finalBalance > 1000
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"log"
 	"math"
 )
 
@@ -22,11 +23,8 @@ func InitializePeople(config *Config) []*Person {
 		// Calculate retirement tax year and age from retirement date or age
 		retirementTaxYear, retirementAge := pc.GetRetirementInfo()
 
-		// Use PensionAccessAge if set, otherwise default to RetirementAge
-		pensionAccessAge := pc.PensionAccessAge
-		if pensionAccessAge <= 0 {
-			pensionAccessAge = retirementAge
-		}
+		// Use PensionAccessAge if set, otherwise default to RetirementAge, never before the minimum pension age
+		pensionAccessAge := pc.GetPensionAccessAge()
 
 		// ISA to SIPP defaults
 		pensionAnnualAllowance := pc.PensionAnnualAllowance
@@ -42,19 +40,30 @@ func InitializePeople(config *Config) []*Person {
 			isaToSIPPPreserveMonths = 12 // 12 months by default
 		}
 
+		// Use per-person care cost if set, otherwise the household default
+		careAnnualCost := pc.CareAnnualCost
+		if careAnnualCost <= 0 {
+			careAnnualCost = config.Care.GetAnnualCost()
+		}
+
 		people[i] = &Person{
-			Name:              pc.Name,
-			BirthYear:         GetBirthYear(pc.BirthDate),
-			BirthDate:         pc.BirthDate, // Store full date for tax year age calculations
-			RetirementDate:    pc.RetirementDate,
-			RetirementAge:     retirementAge,
-			RetirementTaxYear: retirementTaxYear,
-			PensionAccessAge:  pensionAccessAge,
-			StatePensionAge:   pc.StatePensionAge,
-			TaxFreeSavings:    pc.TaxFreeSavings,
-			UncrystallisedPot: pc.Pension,
-			CrystallisedPot:   0,
-			ISAAnnualLimit:    isaLimit,
+			Name:                 pc.Name,
+			BirthYear:            GetBirthYear(pc.BirthDate),
+			BirthDate:            pc.BirthDate, // Store full date for tax year age calculations
+			RetirementDate:       pc.RetirementDate,
+			RetirementAge:        retirementAge,
+			RetirementTaxYear:    retirementTaxYear,
+			PensionAccessAge:     pensionAccessAge,
+			ProtectedPensionAge:  pc.ProtectedPensionAge,
+			StatePensionAge:      pc.StatePensionAge,
+			TaxFreeSavings:       pc.TaxFreeSavings,
+			UncrystallisedPot:    pc.Pension,
+			CrystallisedPot:      0,
+			ISAAnnualLimit:       isaLimit,
+			LISA:                 pc.LifetimeISA,
+			LISAContribution:     pc.LISAContribution,
+			LumpSumAllowance:     config.Tax.GetLumpSumAllowance(),
+			LumpSumAllowanceUsed: pc.LumpSumAllowanceUsed,
 			// DB Pension
 			DBPensionAmount:        pc.DBPensionAmount,
 			DBPensionStartAge:      pc.DBPensionStartAge,
@@ -80,6 +89,15 @@ func InitializePeople(config *Config) []*Person {
 			EmployerContribution:    pc.EmployerContribution,
 			ISAToSIPPMaxPercent:     isaToSIPPMaxPercent,
 			ISAToSIPPPreserveMonths: isaToSIPPPreserveMonths,
+			// Extra saving
+			ExtraMonthlySaving:   pc.ExtraMonthlySaving,
+			ExtraSavingToPension: pc.GetExtraSavingWrapper() == SavingWrapperPension,
+			// Later-life care
+			CareStartAge:   pc.CareStartAge,
+			CareAnnualCost: careAnnualCost,
+		}
+		if pc.ExtraLumpSum > 0 {
+			people[i].AddSaving(pc.ExtraLumpSum)
 		}
 	}
 	return people
@@ -123,6 +141,10 @@ func ApplyParamsToConfig(params SimulationParams, config *Config) *Config {
 	if params.GuardrailsEnabled {
 		newConfig.IncomeRequirements.GuardrailsEnabled = true
 	}
+	// Apply the dynamic withdrawal rule from params (overrides config if set)
+	if params.WithdrawalRule != WithdrawalFixed {
+		newConfig.IncomeRequirements.WithdrawalRule = params.WithdrawalRule.ID()
+	}
 
 	// Apply state pension deferral to all people if set in params
 	if params.StatePensionDeferYears > 0 {
@@ -131,6 +153,13 @@ func ApplyParamsToConfig(params SimulationParams, config *Config) *Config {
 		}
 	}
 
+	// Apply user-defined factors (checked when they were registered)
+	for _, o := range params.ConfigOverrides {
+		if err := setConfigPath(&newConfig, o.Path, o.Value); err != nil {
+			log.Printf("Warning: factor %s not applied: %v", o.Factor, err)
+		}
+	}
+
 	return &newConfig
 }
 
@@ -143,6 +172,11 @@ func RunSimulationV2(params SimulationParams, config *Config) SimulationResult {
 
 // RunSimulation runs the complete retirement simulation for given parameters
 func RunSimulation(params SimulationParams, config *Config) SimulationResult {
+	// The Optimal drawdown order needs its year-by-year plan before the simulation starts
+	if params.DrawdownOrder == Optimal && params.OptimalPlan == nil {
+		params.OptimalPlan = BuildOptimalPlan(params, config)
+	}
+
 	// Initialize people
 	people := InitializePeople(config)
 
@@ -171,9 +205,9 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 	baseIncomeAfterAge := config.IncomeRequirements.MonthlyAfterAge * 12
 	ageThreshold := config.IncomeRequirements.AgeThreshold
 
-	// Initialize guardrails state if enabled
+	// Initialize guardrails state if a dynamic withdrawal rule is enabled
 	var guardrails *GuardrailsState
-	if config.IncomeRequirements.GuardrailsEnabled {
+	if config.IncomeRequirements.GetWithdrawalRule() != WithdrawalFixed {
 		guardrails = NewGuardrailsState(config)
 	}
 
@@ -187,6 +221,20 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 		}
 	}
 
+	// Shortfalls within discretionary spending only count as cuts when an essential floor is set
+	hasEssentialFloor := config.IncomeRequirements.HasEssentialFloor()
+
+	// Track the home so it can be sold to fund care
+	home := &HomeState{}
+
+	// Planned crystallisation tranches, and the first tax year each person's are timed from
+	tranche := config.Strategy.GetTranche()
+	trancheFirstYear := make(map[string]int)
+
+	// Tax-free cash taken for planned uses, and the first year it pays off the mortgage
+	cashPlan := params.TaxFreeCashPlan(config)
+	cashMortgageYear := mortgageCashYear(cashPlan)
+
 	// Run simulation year by year
 	for year := config.Simulation.StartYear; year <= endYear; year++ {
 		state := NewYearState(year)
@@ -215,9 +263,54 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 		state.SavingsGrowthRateUsed = savingsRate
 
 		// Apply growth at start of year (except first year)
+		preGrowthPortfolio := 0.0
 		if year > config.Simulation.StartYear {
 			for _, p := range people {
-				ApplyGrowth(p, savingsRate, pensionRate)
+				preGrowthPortfolio += p.TotalWealth()
+				state.GIATax += ApplyGrowth(p, savingsRate, pensionRate, config.Tax.GetGIATaxRate())
+			}
+		}
+
+		// New ISA allowance; savings held in the GIA move into the ISA as far as it allows
+		// The first year's allowance is already counting saving made at the start
+		if year > config.Simulation.StartYear {
+			for _, p := range people {
+				p.StartTaxYear()
+			}
+		}
+		// Lifetime ISAs join tax-free savings once they can be drawn without the penalty
+		for _, p := range people {
+			p.releaseLISA(year)
+		}
+
+		// Tax-free cash taken so far, so this year's can be recorded
+		lumpSumUsed := make(map[string]float64, len(people))
+		for _, p := range people {
+			lumpSumUsed[p.Name] = p.LumpSumAllowanceUsed
+		}
+
+		// Planned crystallisation tranches, timed from the first year each pension can be accessed
+		for _, p := range people {
+			if params.StrategyFor(p.Name).Crystallisation != TrancheCrystallisation || !p.CanAccessPension(year) {
+				continue
+			}
+			if _, ok := trancheFirstYear[p.Name]; !ok {
+				trancheFirstYear[p.Name] = year
+			}
+			if params.TrancheTiming.Due(year, trancheFirstYear[p.Name], tranche) {
+				crystallised, toGIA := CrystalliseTranche(p, tranche.Percent)
+				state.TrancheCrystallised[p.Name] = crystallised.AmountCrystallised
+				state.TrancheToGIA[p.Name] = toGIA
+			}
+		}
+
+		// Add extra saving for anyone not yet retired
+		for _, p := range people {
+			if p.IsSaving(year) {
+				toPension, toISA := p.AddSaving(p.ExtraMonthlySaving * 12)
+				state.ExtraSavingByPerson[p.Name] = toPension + toISA
+				state.TotalExtraSaving += toPension + toISA
+				state.PensionContributions[p.Name] += toPension
 			}
 		}
 
@@ -227,6 +320,10 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 			currentPortfolio += p.TotalWealth()
 		}
 		state.StartBalance = currentPortfolio
+		portfolioReturn := 0.0
+		if preGrowthPortfolio > 0 {
+			portfolioReturn = currentPortfolio/preGrowthPortfolio - 1
+		}
 
 		// Calculate ages (using tax year age calculation)
 		for _, p := range people {
@@ -254,9 +351,9 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 		if yearsFromRetirement < 0 {
 			yearsFromRetirement = 0
 		}
-		inflationMultiplier := math.Pow(1+config.Financial.IncomeInflationRate, float64(yearsFromRetirement))
 
-		var baseIncome float64
+		// baseIncome is total planned spending; essentialIncome is the floor within it that is never cut
+		var baseIncome, essentialIncome float64
 		// Only require income once retired
 		if refAge >= refPerson.RetirementAge {
 			if config.IncomeRequirements.HasTiers() {
@@ -271,7 +368,7 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 					totalISA := 0.0
 					for _, p := range people {
 						totalPension += p.CrystallisedPot + p.UncrystallisedPot
-						totalISA += p.TaxFreeSavings
+						totalISA += p.Savings()
 					}
 
 					// Calculate nominal gains
@@ -290,34 +387,51 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 				} else {
 					// Both fixed and percentage tiers: apply inflation to the amount
 					// For percentage: 3.5% of initial = £35k, then £35k inflates each year
-					baseIncome = annualIncome * inflationMultiplier
+					// The spending profile shapes the real amount (e.g., spending smile) and
+					// essential/discretionary buckets inflate at their own rates
+					essentialIncome, baseIncome = config.IncomeRequirements.SplitSpending(
+						refAge, refPerson.RetirementAge, annualIncome, yearsFromRetirement, config.Financial.IncomeInflationRate)
 				}
 			} else {
 				// Legacy before/after threshold system
+				legacyIncome := baseIncomeAfterAge
 				if refAge < ageThreshold {
-					baseIncome = baseIncomeBeforeAge * inflationMultiplier
-				} else {
-					baseIncome = baseIncomeAfterAge * inflationMultiplier
+					legacyIncome = baseIncomeBeforeAge
 				}
+				essentialIncome, baseIncome = config.IncomeRequirements.SplitSpending(
+					refAge, refPerson.RetirementAge, legacyIncome, yearsFromRetirement, config.Financial.IncomeInflationRate)
 			}
 		}
 		state.RequiredIncome = baseIncome
+		state.EssentialIncome = essentialIncome
 
 		// Apply guardrails adjustment if enabled (only once retired)
+		// Guardrails only flex discretionary spending - the essential floor is never cut
 		if guardrails != nil && refAge >= refPerson.RetirementAge {
+			discretionaryIncome := baseIncome - essentialIncome
 			if yearsFromRetirement == 0 {
 				// Initialize guardrails in first year of retirement
-				guardrails.Initialize(currentPortfolio, baseIncome)
+				guardrails.Initialize(currentPortfolio, essentialIncome, discretionaryIncome)
 			} else {
-				// Check if guardrails are triggered
-				state.GuardrailsTriggered = guardrails.IsTriggered(currentPortfolio)
-				// Apply inflation to current withdrawal, then apply guardrails
-				guardrails.CurrentWithdrawal *= (1 + config.Financial.IncomeInflationRate)
-				adjustedIncome := guardrails.CalculateAdjustedWithdrawal(currentPortfolio, baseIncome)
-				state.GuardrailsAdjusted = adjustedIncome
-				state.RequiredIncome = adjustedIncome
+				// Apply the withdrawal rule (inflation is applied to the current discretionary withdrawal)
+				adjustedDiscretionary, triggered := guardrails.Apply(GuardrailsYear{
+					PortfolioValue:    currentPortfolio,
+					Essential:         essentialIncome,
+					BaseDiscretionary: discretionaryIncome,
+					Inflation:         config.IncomeRequirements.SpendingProfile.GetDiscretionaryInflation(config.Financial.IncomeInflationRate),
+					PortfolioReturn:   portfolioReturn,
+					Age:               refAge,
+					YearsRemaining:    endYear - year + 1,
+				})
+				state.GuardrailsTriggered = triggered
+				state.GuardrailsAdjusted = essentialIncome + adjustedDiscretionary
+				state.RequiredIncome = essentialIncome + adjustedDiscretionary
 			}
 		}
+		// Record spending cut below plan (only ever discretionary)
+		if state.RequiredIncome < baseIncome-1 {
+			state.DiscretionaryCut = baseIncome - state.RequiredIncome
+		}
 
 		// Update emergency fund minimums for each person
 		// Based on configured months of expenses
@@ -364,40 +478,29 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 		default: // MortgageNormal
 			payoffYear = config.Mortgage.EndYear
 		}
+		// Tax-free cash planned for the mortgage pays it off in its year
+		if cashMortgageYear > 0 && cashMortgageYear < payoffYear {
+			payoffYear = cashMortgageYear
+		}
 
 		// Pay annual payments until payoff year, then pay off remaining balance
 		if year < payoffYear {
 			state.MortgageCost = annualPayment
 		}
-		// Track PCLS tax-free available for this year (used for mortgage payoff)
-		var pclsTaxFreeTotal float64
-
 		if year == payoffYear {
 			state.MortgageCost = config.GetTotalPayoffAmount(year)
-
-			// For PCLS mortgage payoff, take 25% lump sum from each person's pension
-			// The tax-free portion is used directly to pay the mortgage (not stored in ISA)
-			if params.MortgageOpt == PCLSMortgagePayoff {
-				for _, p := range people {
-					if p.CanAccessPension(year) && p.UncrystallisedPot > 0 && !p.PCLSTaken {
-						result := TakePCLSLumpSum(p)
-						if result.AmountCrystallised > 0 {
-							// Record the tax-free portion as a withdrawal (it pays the mortgage)
-							state.Withdrawals.TaxFreeFromPension[p.Name] += result.TaxFreePortion
-							state.Withdrawals.TotalTaxFree += result.TaxFreePortion
-							pclsTaxFreeTotal += result.TaxFreePortion
-
-							// Remove from ISA since we're using it directly for mortgage, not storing it
-							// (TakePCLSLumpSum added it to TaxFreeSavings, but for PCLS mortgage we use it immediately)
-							p.TaxFreeSavings -= result.TaxFreePortion
-						}
-					}
-				}
-			}
 		}
 		// No payments after payoff year
 
-		state.TotalRequired = state.RequiredIncome + state.MortgageCost
+		// Tax-free cash taken this year pays the mortgage and one-off expenses (PCLS mortgage
+		// payoff takes everyone's at the payoff year); whatever they don't need is saved
+		state.OneOffExpense = oneOffExpense(config, year)
+		taxFreeCashSpent := takePlannedCash(&state, people, cashPlan, year)
+
+		// Later-life care costs (may sell the home and apply the means test)
+		ApplyCareCosts(&state, people, config, home, year)
+
+		state.TotalRequired = state.RequiredIncome + state.MortgageCost + state.CareCost + state.OneOffExpense
 
 		// Calculate state pension income (accounting for deferral enhancement)
 		for _, p := range people {
@@ -437,7 +540,8 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 				if year == startYear && !p.DBPensionLumpSumTaken && p.DBPensionCommutation > 0 {
 					lumpSum := p.GetDBPensionLumpSum()
 					if lumpSum > 0 {
-						p.TaxFreeSavings += lumpSum // DB pension lump sum is tax-free
+						p.SaveTaxFree(lumpSum) // DB pension lump sum is tax-free
+						p.LumpSumAllowanceUsed += lumpSum
 						p.DBPensionLumpSum = lumpSum
 						p.DBPensionLumpSumTaken = true
 					}
@@ -481,25 +585,26 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 		}
 
 		// Net amount needed from withdrawals (after state pension, DB pension, part-time income, work income, and PCLS tax-free)
-		state.NetRequired = state.TotalRequired - state.TotalStatePension - state.TotalDBPension - state.PartTimeIncome - state.TotalWorkIncome - pclsTaxFreeTotal
+		state.NetRequired = state.TotalRequired - state.TotalStatePension - state.TotalDBPension - state.PartTimeIncome - state.TotalWorkIncome - taxFreeCashSpent
 		if state.NetRequired < 0 {
 			state.NetRequired = 0
 		}
 
 		// Split NetRequired into income and mortgage components
-		// Other income sources first cover income needs, then mortgage if excess
-		totalOtherIncome := state.TotalStatePension + state.TotalDBPension + state.PartTimeIncome + state.TotalWorkIncome + pclsTaxFreeTotal
-		if totalOtherIncome >= state.RequiredIncome {
+		// Other income sources first cover income needs (including care), then mortgage if excess
+		totalOtherIncome := state.TotalStatePension + state.TotalDBPension + state.PartTimeIncome + state.TotalWorkIncome + taxFreeCashSpent
+		incomeNeeds := state.RequiredIncome + state.CareCost + state.OneOffExpense
+		if totalOtherIncome >= incomeNeeds {
 			// Other income fully covers income needs, excess goes to mortgage
 			state.NetIncomeRequired = 0
-			excessForMortgage := totalOtherIncome - state.RequiredIncome
+			excessForMortgage := totalOtherIncome - incomeNeeds
 			state.NetMortgageRequired = state.MortgageCost - excessForMortgage
 			if state.NetMortgageRequired < 0 {
 				state.NetMortgageRequired = 0
 			}
 		} else {
 			// Other income doesn't fully cover income needs
-			state.NetIncomeRequired = state.RequiredIncome - totalOtherIncome
+			state.NetIncomeRequired = incomeNeeds - totalOtherIncome
 			state.NetMortgageRequired = state.MortgageCost // Full mortgage still needed
 		}
 
@@ -535,10 +640,15 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 				taxableIncomeByPerson[p.Name] = taxableIncome
 			}
 			state.Withdrawals = ExecuteDrawdown(people, state.NetRequired, params, year, taxableIncomeByPerson, taxBands)
+
+			// Lifetime ISAs before 60 are a last resort, drawn with the withdrawal penalty
+			if short := state.NetRequired - state.Withdrawals.TotalTaxFree - state.Withdrawals.TotalTaxable; short > 1 {
+				withdrawEarlyLISA(people, short, &state)
+			}
 		}
 
 		// Merge PCLS withdrawals back (they were made before ExecuteDrawdown)
-		if pclsTaxFreeTotal > 0 {
+		if taxFreeCashSpent > 0 {
 			for name, amount := range pclsWithdrawals.TaxFreeFromPension {
 				state.Withdrawals.TaxFreeFromPension[name] += amount
 			}
@@ -562,17 +672,17 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 			state.TotalTaxPaid += tax
 		}
 
-		// Calculate net income received (spendable after tax and mortgage)
-		// = State Pension + DB Pension + Part-time income + Work income + Tax-free withdrawals + Taxable withdrawals - Tax paid - Mortgage
+		// Calculate net income received (spendable after tax, mortgage and care)
+		// = State Pension + DB Pension + Part-time income + Work income + Tax-free withdrawals + Taxable withdrawals - Tax paid - Mortgage - Care
 		totalWithdrawals := state.Withdrawals.TotalTaxFree + state.Withdrawals.TotalTaxable
-		state.NetIncomeReceived = state.TotalStatePension + state.TotalDBPension + state.PartTimeIncome + state.TotalWorkIncome + totalWithdrawals - state.TotalTaxPaid - state.MortgageCost
+		state.NetIncomeReceived = state.TotalStatePension + state.TotalDBPension + state.PartTimeIncome + state.TotalWorkIncome + totalWithdrawals - state.TotalTaxPaid - state.MortgageCost - state.CareCost - state.OneOffExpense
 
 		// Handle surplus work income - deposit to ISA if work income exceeds expenses
 		// This only applies when NetRequired is 0 or negative (all expenses covered by work income)
 		if state.TotalWorkIncome > 0 && state.NetRequired == 0 {
 			// Calculate how much of work income was needed for expenses
 			// Work income is used after state pension, DB pension, part-time income, and PCLS
-			otherIncomeExcludingWork := state.TotalStatePension + state.TotalDBPension + state.PartTimeIncome + pclsTaxFreeTotal
+			otherIncomeExcludingWork := state.TotalStatePension + state.TotalDBPension + state.PartTimeIncome + taxFreeCashSpent
 			expensesCoveredByOther := math.Min(otherIncomeExcludingWork, state.TotalRequired)
 			remainingExpenses := state.TotalRequired - expensesCoveredByOther
 			workIncomeUsedForExpenses := math.Min(state.TotalWorkIncome, remainingExpenses)
@@ -596,11 +706,13 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 						marginalRate := GetMarginalTaxRate(totalTaxable, taxBands)
 						netSurplus := personShare * (1 - marginalRate)
 
-						// Deposit to ISA up to annual limit
-						isaDeposit := math.Min(netSurplus, p.ISAAnnualLimit)
-						p.TaxFreeSavings += isaDeposit
-						state.ISAContributions[p.Name] = isaDeposit
-						state.TotalISAContributions += isaDeposit
+						// Pay into the Lifetime ISA, then save to the ISA up to the allowance left and the rest to the GIA
+						lisa, bonus := p.ContributeLISA(netSurplus, year)
+						state.LISAContributions[p.Name] = lisa
+						state.LISABonus[p.Name] = bonus
+						p.SaveTaxFree(netSurplus - lisa)
+						state.ISAContributions[p.Name] = netSurplus - lisa
+						state.TotalISAContributions += netSurplus - lisa
 					}
 				}
 			}
@@ -638,7 +750,7 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 					monthlyExpenses = config.IncomeRequirements.Tiers[0].MonthlyAmount
 				}
 				preserveAmount := monthlyExpenses * incomeInflation * float64(p.ISAToSIPPPreserveMonths)
-				availableISA := math.Max(0, p.TaxFreeSavings-preserveAmount)
+				availableISA := math.Max(0, p.Savings()-preserveAmount)
 
 				if availableISA <= 0 {
 					continue
@@ -665,12 +777,13 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 					taxRelief := grossContribution - netContribution
 
 					// Transfer: reduce ISA by net amount, increase pension by gross amount
-					p.TaxFreeSavings -= netContribution
+					p.spendSavings(netContribution)
 					p.UncrystallisedPot += grossContribution
 
 					// Track the transfer
 					state.ISAToSIPPByPerson[p.Name] = netContribution
 					state.ISAToSIPPTaxRelief[p.Name] = taxRelief
+					state.PensionContributions[p.Name] += grossContribution
 					state.TotalISAToSIPP += netContribution
 					state.TotalISAToSIPPRelief += taxRelief
 				}
@@ -679,35 +792,45 @@ func RunSimulation(params SimulationParams, config *Config) SimulationResult {
 
 		// Record end of year balances
 		for _, p := range people {
-			state.EndBalances[p.Name] = PersonBalances{
-				TaxFreeSavings:    p.TaxFreeSavings,
-				UncrystallisedPot: p.UncrystallisedPot,
-				CrystallisedPot:   p.CrystallisedPot,
-			}
+			state.EndBalances[p.Name] = p.Balances()
 			state.TotalBalance += p.TotalWealth()
+			state.TaxFreeCashTaken[p.Name] = p.LumpSumAllowanceUsed - lumpSumUsed[p.Name]
 		}
 
 		// Check if ran out of money
+		// With an essential floor, a shortfall that only eats into discretionary spending
+		// is reported as a discretionary cut rather than running out of money
 		totalWithdrawn := state.Withdrawals.TotalTaxFree + state.Withdrawals.TotalTaxable
 		if state.NetRequired > 0 && totalWithdrawn < state.NetRequired-1 {
-			if !result.RanOutOfMoney {
+			shortfall := state.NetRequired - totalWithdrawn
+			discretionaryLeft := state.RequiredIncome - state.EssentialIncome
+			if hasEssentialFloor && shortfall <= discretionaryLeft+1 {
+				state.DiscretionaryCut += shortfall
+			} else if !result.RanOutOfMoney {
 				result.RanOutOfMoney = true
 				result.RanOutYear = year
 			}
 		}
+		if baseIncome > 0 {
+			result.SpendingYears++
+		}
+		if state.DiscretionaryCut > 0 {
+			result.DiscretionaryCutYears++
+		}
 
 		result.Years = append(result.Years, state)
-		result.TotalTaxPaid += state.TotalTaxPaid
+		result.TotalTaxPaid += state.TotalTaxPaid + state.GIATax
 		result.TotalWithdrawn += totalWithdrawn
+		result.TotalCareCost += state.CareCost
+	}
+
+	if home.Sold {
+		result.HomeSaleYear = home.SaleYear
 	}
 
 	// Record final balances
 	for _, p := range people {
-		result.FinalBalances[p.Name] = PersonBalances{
-			TaxFreeSavings:    p.TaxFreeSavings,
-			UncrystallisedPot: p.UncrystallisedPot,
-			CrystallisedPot:   p.CrystallisedPot,
-		}
+		result.FinalBalances[p.Name] = p.Balances()
 	}
 
 	return result
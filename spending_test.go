@@ -0,0 +1,250 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSpendingProfile_FlatIsUnchanged verifies an empty profile matches plain income inflation
+func TestSpendingProfile_FlatIsUnchanged(t *testing.T) {
+	sp := SpendingProfileConfig{}
+
+	for _, age := range []int{60, 70, 85} {
+		if m := sp.RealMultiplier(age, 60, 40000); m != 1.0 {
+			t.Errorf("Age %d: expected real multiplier 1.0, got %.4f", age, m)
+		}
+	}
+
+	for _, years := range []int{0, 5, 20} {
+		expected := math.Pow(1.03, float64(years))
+		essential, discretionary := sp.BucketInflation(years, 0.03)
+		if math.Abs(essential-expected) > 1e-9 || math.Abs(discretionary-expected) > 1e-9 {
+			t.Errorf("Year %d: expected inflation multipliers %.4f, got %.4f and %.4f", years, expected, essential, discretionary)
+		}
+	}
+}
+
+// TestSpendingProfile_Decline verifies the fixed real decline after the start age
+func TestSpendingProfile_Decline(t *testing.T) {
+	sp := SpendingProfileConfig{Type: SpendingProfileDecline, DeclineStartAge: 75, DeclineRate: 0.02}
+
+	tests := []struct {
+		age      int
+		expected float64
+	}{
+		{65, 1.0},
+		{75, 1.0},
+		{76, 0.98},
+		{85, math.Pow(0.98, 10)},
+	}
+
+	for _, tc := range tests {
+		if m := sp.RealMultiplier(tc.age, 65, 40000); math.Abs(m-tc.expected) > 1e-9 {
+			t.Errorf("Age %d: expected %.4f, got %.4f", tc.age, tc.expected, m)
+		}
+	}
+
+	// Without a start age the decline begins at retirement
+	sp.DeclineStartAge = 0
+	if m := sp.RealMultiplier(66, 65, 40000); math.Abs(m-0.98) > 1e-9 {
+		t.Errorf("Expected decline from retirement, got %.4f", m)
+	}
+}
+
+// TestSpendingProfile_Smile verifies the Blanchett smile falls through the 70s and flattens later
+func TestSpendingProfile_Smile(t *testing.T) {
+	sp := SpendingProfileConfig{Type: SpendingProfileSmile}
+
+	at70 := sp.RealMultiplier(70, 65, 40000)
+	at80 := sp.RealMultiplier(80, 65, 40000)
+	at90 := sp.RealMultiplier(90, 65, 40000)
+
+	if at70 >= 1.0 || at80 >= at70 {
+		t.Errorf("Expected real spending to fall through the 70s: 70=%.3f, 80=%.3f", at70, at80)
+	}
+
+	// The rate of decline should slow in later life (the "smile")
+	earlyDrop := at70 / sp.RealMultiplier(69, 65, 40000)
+	lateDrop := at90 / sp.RealMultiplier(89, 65, 40000)
+	if lateDrop <= earlyDrop {
+		t.Errorf("Expected decline to slow with age: drop at 70=%.4f, drop at 90=%.4f", earlyDrop, lateDrop)
+	}
+
+	// Higher spenders cut back faster
+	if sp.RealMultiplier(80, 65, 100000) >= at80 {
+		t.Error("Expected higher spending to decline faster")
+	}
+}
+
+// TestSpendingProfile_BucketInflation verifies essential and discretionary spending inflate separately
+func TestSpendingProfile_BucketInflation(t *testing.T) {
+	sp := SpendingProfileConfig{EssentialFraction: 0.6, EssentialInflation: 0.04, DiscretionaryInflation: 0.02}
+
+	essential, discretionary := sp.BucketInflation(10, 0.03)
+	if math.Abs(essential-math.Pow(1.04, 10)) > 1e-9 || math.Abs(discretionary-math.Pow(1.02, 10)) > 1e-9 {
+		t.Errorf("Expected bucket multipliers %.4f and %.4f, got %.4f and %.4f",
+			math.Pow(1.04, 10), math.Pow(1.02, 10), essential, discretionary)
+	}
+
+	// Each bucket is inflated at its own rate, so total spending blends them by the essential share
+	ic := IncomeConfig{SpendingProfile: sp}
+	essentialSpend, total := ic.SplitSpending(65, 65, 40000, 10, 0.03)
+	expected := 40000 * (0.6*math.Pow(1.04, 10) + 0.4*math.Pow(1.02, 10))
+	if math.Abs(essentialSpend-24000*math.Pow(1.04, 10)) > 1e-6 || math.Abs(total-expected) > 1e-6 {
+		t.Errorf("Expected £%.2f essential of £%.2f, got £%.2f of £%.2f", 24000*math.Pow(1.04, 10), expected, essentialSpend, total)
+	}
+
+	// Unset bucket rates fall back to income inflation
+	sp = SpendingProfileConfig{EssentialFraction: 0.5, EssentialInflation: 0.05}
+	essential, discretionary = sp.BucketInflation(10, 0.03)
+	if math.Abs(essential-math.Pow(1.05, 10)) > 1e-9 || math.Abs(discretionary-math.Pow(1.03, 10)) > 1e-9 {
+		t.Errorf("Expected fallback multipliers %.4f and %.4f, got %.4f and %.4f",
+			math.Pow(1.05, 10), math.Pow(1.03, 10), essential, discretionary)
+	}
+}
+
+// TestSpendingProfile_FixedMode verifies the profile reduces required income in the simulation
+func TestSpendingProfile_FixedMode(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	flat := createTestConfig()
+	flatResult := RunSimulation(params, flat)
+
+	declining := createTestConfig()
+	declining.IncomeRequirements.SpendingProfile = SpendingProfileConfig{
+		Type: SpendingProfileDecline, DeclineStartAge: 70, DeclineRate: 0.02,
+	}
+	declineResult := RunSimulation(params, declining)
+
+	for i, year := range declineResult.Years {
+		age := year.Ages["James"]
+		flatIncome := flatResult.Years[i].RequiredIncome
+		if age <= 70 && math.Abs(year.RequiredIncome-flatIncome) > 0.01 {
+			t.Errorf("Age %d: expected unchanged income £%.0f, got £%.0f", age, flatIncome, year.RequiredIncome)
+		}
+		if age > 70 {
+			expected := flatIncome * math.Pow(0.98, float64(age-70))
+			if math.Abs(year.RequiredIncome-expected) > 1 {
+				t.Errorf("Age %d: expected £%.0f, got £%.0f", age, expected, year.RequiredIncome)
+			}
+		}
+	}
+}
+
+// TestSpendingProfile_Depletion verifies a declining profile supports a higher starting income
+func TestSpendingProfile_Depletion(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	config := createTestConfig()
+	config.Mortgage = MortgageConfig{}
+	config.IncomeRequirements.TargetDepletionAge = 85
+	config.IncomeRequirements.IncomeRatioPhase1 = 1
+	config.IncomeRequirements.IncomeRatioPhase2 = 1
+	flat := CalculateDepletionIncome(params, config)
+
+	config.IncomeRequirements.SpendingProfile = SpendingProfileConfig{Type: SpendingProfileSmile}
+	smile := CalculateDepletionIncome(params, config)
+
+	if smile.MonthlyBeforeAge <= flat.MonthlyBeforeAge {
+		t.Errorf("Expected smile profile to allow higher starting income: flat £%.0f, smile £%.0f",
+			flat.MonthlyBeforeAge, smile.MonthlyBeforeAge)
+	}
+	if math.Abs(smile.ConvergenceError) > 50000 {
+		t.Errorf("Expected depletion search to converge with smile profile, balance at target £%.0f", smile.ConvergenceError)
+	}
+}
+
+// TestEssentialFloor_TierPrecedence verifies a tier's essential_monthly overrides the profile fraction
+func TestEssentialFloor_TierPrecedence(t *testing.T) {
+	age75 := 75
+	ic := IncomeConfig{
+		Tiers: []IncomeTier{
+			{EndAge: &age75, MonthlyAmount: 3000},
+			{StartAge: &age75, MonthlyAmount: 2500, EssentialMonthly: 2000},
+		},
+		SpendingProfile: SpendingProfileConfig{EssentialFraction: 0.5},
+	}
+
+	if !ic.HasEssentialFloor() {
+		t.Fatal("Expected essential floor to be detected")
+	}
+	if f := ic.GetEssentialFractionForAge(70, 36000); math.Abs(f-0.5) > 1e-9 {
+		t.Errorf("Age 70: expected profile fraction 0.5, got %.3f", f)
+	}
+	if f := ic.GetEssentialFractionForAge(80, 30000); math.Abs(f-0.8) > 1e-9 {
+		t.Errorf("Age 80: expected tier fraction 0.8, got %.3f", f)
+	}
+
+	// The floor stays constant in real terms while a decline profile shrinks the total
+	ic.SpendingProfile = SpendingProfileConfig{Type: SpendingProfileDecline, DeclineStartAge: 75, DeclineRate: 0.5}
+	essential, total := ic.SplitSpending(80, 60, 30000, 0, 0.03)
+	if math.Abs(essential-24000) > 0.01 || math.Abs(total-24000) > 0.01 {
+		t.Errorf("Expected total to fall no lower than the £24,000 floor, got essential £%.0f total £%.0f", essential, total)
+	}
+}
+
+// TestEssentialFloor_GuardrailsCutOnlyDiscretionary verifies guardrails never cut below the essential floor
+func TestEssentialFloor_GuardrailsCutOnlyDiscretionary(t *testing.T) {
+	g := &GuardrailsState{UpperLimit: 1.2, LowerLimit: 0.8, CutRate: 0.10, RaiseRate: 0.10, ExemptYears: 15}
+	g.Initialize(1000000, 30000, 10000)
+
+	// Portfolio halves - rate doubles, triggering repeated cuts
+	essential := 30000.0
+	for i := 0; i < 30; i++ {
+		if g.IsTriggered(500000, essential, 30) != -1 {
+			t.Fatalf("Iteration %d: expected upper guardrail to trigger", i)
+		}
+		discretionary := g.CalculateAdjustedWithdrawal(500000, essential, 10000, 30)
+		if discretionary < 0 || essential+discretionary < essential {
+			t.Fatalf("Iteration %d: spending fell below essential floor", i)
+		}
+	}
+	if g.CurrentWithdrawal >= 10000*0.9 {
+		t.Errorf("Expected discretionary spending to be cut, got £%.0f", g.CurrentWithdrawal)
+	}
+
+	// Guardrails enabled in a full simulation with a floor: income never falls below essential
+	config := createTestConfig()
+	config.IncomeRequirements.GuardrailsEnabled = true
+	config.IncomeRequirements.SpendingProfile.EssentialFraction = 0.7
+	config.Financial.PensionGrowthRate = -0.05
+	config.Financial.SavingsGrowthRate = -0.05
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+	result := RunSimulation(params, config)
+	for _, year := range result.Years {
+		if year.RequiredIncome < year.EssentialIncome-0.01 {
+			t.Errorf("Year %d: required income £%.0f below essential floor £%.0f", year.Year, year.RequiredIncome, year.EssentialIncome)
+		}
+	}
+}
+
+// TestEssentialFloor_CutsReportedSeparately verifies discretionary-only shortfalls are not RanOutOfMoney
+func TestEssentialFloor_CutsReportedSeparately(t *testing.T) {
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: TaxOptimized}
+
+	// Without a floor, an underfunded plan runs out of money
+	config := createTestConfig()
+	config.Mortgage = MortgageConfig{}
+	config.IncomeRequirements.MonthlyBeforeAge = 12000
+	config.IncomeRequirements.MonthlyAfterAge = 12000
+	noFloor := RunSimulation(params, config)
+	if !noFloor.RanOutOfMoney {
+		t.Fatal("Expected plan without essential floor to run out of money")
+	}
+	if noFloor.DiscretionaryCutYears != 0 {
+		t.Errorf("Expected no discretionary cuts without a floor, got %d", noFloor.DiscretionaryCutYears)
+	}
+
+	// With a small floor covered by state pensions, shortfalls are discretionary cuts
+	config.IncomeRequirements.SpendingProfile.EssentialFraction = 0.05
+	withFloor := RunSimulation(params, config)
+	if withFloor.DiscretionaryCutYears == 0 {
+		t.Fatal("Expected discretionary cuts with an essential floor")
+	}
+	if withFloor.RanOutOfMoney && withFloor.RanOutYear <= noFloor.RanOutYear {
+		t.Errorf("Expected discretionary cuts before any genuine shortfall, ran out in %d", withFloor.RanOutYear)
+	}
+	if pct := withFloor.CutYearsPercent(); pct <= 0 || pct > 100 {
+		t.Errorf("Expected cut years percent in (0, 100], got %.1f", pct)
+	}
+}
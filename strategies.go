@@ -1,6 +1,7 @@
 package main
 
 import (
+	"log"
 	"math"
 )
 
@@ -12,28 +13,12 @@ type CrystallisationResult struct {
 }
 
 // TakePCLSLumpSum takes the 25% PCLS lump sum from a person's entire pension pot
-// 25% becomes tax-free (added to ISA), 75% becomes crystallised (taxable)
+// 25% becomes tax-free (saved in the ISA, over the allowance in the GIA), 75% becomes crystallised (taxable)
 // This sets PCLSTaken = true, so no further 25% tax-free on future withdrawals
 func TakePCLSLumpSum(person *Person) CrystallisationResult {
-	if person.UncrystallisedPot <= 0 || person.PCLSTaken {
-		return CrystallisationResult{}
-	}
-
-	amount := person.UncrystallisedPot
-	taxFree := amount * 0.25
-	taxable := amount * 0.75
-
-	// Move to ISA and crystallised pot
-	person.TaxFreeSavings += taxFree
-	person.CrystallisedPot += taxable
-	person.UncrystallisedPot = 0
-	person.PCLSTaken = true
-
-	return CrystallisationResult{
-		AmountCrystallised: amount,
-		TaxFreePortion:     taxFree,
-		TaxablePortion:     taxable,
-	}
+	result := TakeTaxFreeCash(person, 0)
+	person.SaveTaxFree(result.TaxFreePortion)
+	return result
 }
 
 // GradualCrystallise crystallises just enough pension to meet the needed amount
@@ -52,9 +37,9 @@ func GradualCrystallise(person *Person, amountNeeded float64) CrystallisationRes
 		taxFree = 0
 		taxable = crystalliseAmount
 	} else {
-		// Normal gradual crystallisation - 25% tax-free, 75% taxable
-		taxFree = crystalliseAmount * 0.25
-		taxable = crystalliseAmount * 0.75
+		// Normal gradual crystallisation - 25% tax-free (within the lump sum allowance), 75% taxable
+		taxFree = person.useTaxFree(crystalliseAmount)
+		taxable = crystalliseAmount - taxFree
 	}
 
 	person.UncrystallisedPot -= crystalliseAmount
@@ -78,10 +63,10 @@ func UFPLSWithdraw(person *Person, amountNeeded float64) CrystallisationResult {
 	// Withdraw directly from uncrystallised pot
 	withdrawAmount := math.Min(person.UncrystallisedPot, amountNeeded)
 
-	// UFPLS: always 25% tax-free, 75% taxable on each withdrawal
+	// UFPLS: 25% tax-free (within the lump sum allowance), 75% taxable on each withdrawal
 	// Unlike PCLS, this doesn't affect future withdrawals - each withdrawal gets 25% tax-free
-	taxFree := withdrawAmount * 0.25
-	taxable := withdrawAmount * 0.75
+	taxFree := person.useTaxFree(withdrawAmount)
+	taxable := withdrawAmount - taxFree
 
 	person.UncrystallisedPot -= withdrawAmount
 
@@ -106,7 +91,7 @@ func WithdrawFromISA(person *Person, amount float64) float64 {
 	}
 
 	withdrawal := math.Min(amount, available)
-	person.TaxFreeSavings -= withdrawal
+	person.spendSavings(withdrawal)
 	return withdrawal
 }
 
@@ -122,10 +107,13 @@ func WithdrawFromCrystallised(person *Person, amount float64) float64 {
 }
 
 // ApplyGrowth applies growth rates to a person's assets
-func ApplyGrowth(person *Person, savingsRate, pensionRate float64) {
+// GIA gains are taxed at giaTaxRate as they arise; returns that tax
+func ApplyGrowth(person *Person, savingsRate, pensionRate, giaTaxRate float64) float64 {
 	person.TaxFreeSavings *= (1 + savingsRate)
 	person.CrystallisedPot *= (1 + pensionRate)
 	person.UncrystallisedPot *= (1 + pensionRate)
+	person.LISA *= (1 + savingsRate)
+	return person.applyGIAGrowth(savingsRate, giaTaxRate)
 }
 
 // GetGrowthRateForYear calculates linearly declining growth rate based on age
@@ -171,8 +159,19 @@ func ProportionalSplit(totalNeeded float64, person1Available, person2Available f
 // For PensionOnly: Only use pension, never touch ISAs (for pension-only depletion)
 // For FillBasicRate: Withdraw pension up to basic rate limit, excess to ISA
 // For StatePensionBridge: Draw heavily before state pension, reduce after
+// For Optimal: Follow the year-by-year plan from BuildOptimalPlan
 // netNeeded is the after-tax amount required - taxable withdrawals are grossed up
+// People with their own choice (params.PersonStrategies) are drawn from with it
 func ExecuteDrawdown(people []*Person, netNeeded float64, params SimulationParams, year int, statePensionByPerson map[string]float64, taxBands []TaxBand) WithdrawalBreakdown {
+	if len(params.PersonStrategies) > 0 {
+		return executePerPersonDrawdown(people, netNeeded, params, year, statePensionByPerson, taxBands)
+	}
+	return executeHouseholdDrawdown(people, netNeeded, params, year, statePensionByPerson, taxBands)
+}
+
+// executeHouseholdDrawdown draws from people with the household's crystallisation and drawdown choice
+func executeHouseholdDrawdown(people []*Person, netNeeded float64, params SimulationParams, year int, statePensionByPerson map[string]float64, taxBands []TaxBand) WithdrawalBreakdown {
+	params.CrystallisationStrategy = params.CrystallisationStrategy.withdrawalStrategy()
 	breakdown := NewWithdrawalBreakdown()
 	remaining := netNeeded
 
@@ -195,6 +194,9 @@ func ExecuteDrawdown(people []*Person, netNeeded float64, params SimulationParam
 	} else if params.DrawdownOrder == StatePensionBridge {
 		// Draw heavily before state pension, reduce after
 		return ExecuteStatePensionBridgeDrawdown(people, netNeeded, params.CrystallisationStrategy, year, statePensionByPerson, taxBands)
+	} else if params.DrawdownOrder == Optimal {
+		// Follow the dynamic programming plan for this year
+		return ExecuteOptimalDrawdown(people, netNeeded, params.OptimalPlan.ForYear(year), params.CrystallisationStrategy, year, statePensionByPerson, taxBands)
 	} else if params.DrawdownOrder == SavingsFirst {
 		// Order: ISAs first, then pension
 		remaining = withdrawFromISAs(people, remaining, &breakdown)
@@ -237,14 +239,15 @@ func ExecuteOptimizedDrawdown(people []*Person, netNeeded float64, strategy Stra
 		if taxFreeFromPension > 0 && (strategy == GradualCrystallisation || strategy == UFPLSStrategy) {
 			// The amount crystallised = taxFreeFromPension / 0.25 = taxFreeFromPension * 4
 			amountCrystallised := taxFreeFromPension * 4
-			taxablePortion := amountCrystallised * 0.75
 
 			// Actually withdraw from uncrystallised pot
 			if p.UncrystallisedPot >= amountCrystallised {
 				p.UncrystallisedPot -= amountCrystallised
-				// 25% goes directly to breakdown as tax-free withdrawal
-				breakdown.TaxFreeFromPension[p.Name] = taxFreeFromPension
-				breakdown.TotalTaxFree += taxFreeFromPension
+				// 25% goes directly to breakdown as tax-free withdrawal (within the lump sum allowance)
+				taxFree := p.useTaxFree(amountCrystallised)
+				taxablePortion := amountCrystallised - taxFree
+				breakdown.TaxFreeFromPension[p.Name] = taxFree
+				breakdown.TotalTaxFree += taxFree
 
 				// 75% is taxable
 				// The taxableAmount from plan already includes this, so just track it
@@ -361,8 +364,8 @@ func withdrawFromPensionGrossedUp(people []*Person, remaining float64, strategy
 						if toGet > p.UncrystallisedPot {
 							toGet = p.UncrystallisedPot
 						}
-						taxFree := toGet * 0.25
-						taxableGross := toGet * 0.75
+						taxFree := p.taxFreePortion(toGet)
+						taxableGross := toGet - taxFree
 						taxOnTaxable := CalculateMarginalTax(taxableGross, existingTaxable, taxBands)
 						taxableNet := taxableGross - taxOnTaxable
 						totalNet := taxFree + taxableNet
@@ -430,8 +433,8 @@ func withdrawFromPensionUFPLS(people []*Person, remaining float64, year int, bre
 					if toGet > p.UncrystallisedPot {
 						toGet = p.UncrystallisedPot
 					}
-					taxFree := toGet * 0.25
-					taxableGross := toGet * 0.75
+					taxFree := p.taxFreePortion(toGet)
+					taxableGross := toGet - taxFree
 					taxOnTaxable := CalculateMarginalTax(taxableGross, existingTaxable, taxBands)
 					taxableNet := taxableGross - taxOnTaxable
 					totalNet := taxFree + taxableNet
@@ -554,8 +557,8 @@ func ExecutePensionToISADrawdown(people []*Person, netNeeded float64, strategy S
 		// When crystallising: 25% tax-free, 75% taxable (unless PCLSTaken)
 		// To get X taxable from crystallisation, we need to crystallise X/0.75 (or just X if PCLSTaken)
 		var amountToCrystallise float64
-		if p.PCLSTaken {
-			// PCLS already taken - all crystallisation is taxable
+		if !p.hasTaxFreeCash() {
+			// PCLS taken or lump sum allowance used - all crystallisation is taxable
 			amountToCrystallise = targetTaxableWithdrawal
 		} else {
 			// Normal - 25% tax-free, 75% taxable
@@ -631,7 +634,7 @@ func ExecutePensionToISADrawdown(people []*Person, netNeeded float64, strategy S
 
 			// Calculate gross needed to generate the additional net
 			var grossMultiplier float64
-			if p.PCLSTaken {
+			if !p.hasTaxFreeCash() {
 				// No PCLS, all taxable at 40%: Net = Gross * 0.60
 				grossMultiplier = 1.0 / 0.60
 			} else {
@@ -681,7 +684,7 @@ func ExecutePensionToISADrawdown(people []*Person, netNeeded float64, strategy S
 		netFromPension = totalGrossWithdrawn - totalTaxPaid
 	}
 
-	// If we have more net than needed, excess goes to ISA (up to annual limit per person)
+	// If we have more net than needed, excess goes to ISA (up to the allowance left per person, then the GIA)
 	excess := netFromPension - netNeeded
 	if excess > 0 {
 		// Distribute excess equally to ALL people's ISAs (not just those who withdrew)
@@ -692,11 +695,9 @@ func ExecutePensionToISADrawdown(people []*Person, netNeeded float64, strategy S
 				break
 			}
 			isaDeposit := remainingExcess / float64(len(people))
-			// Cap at per-person annual ISA limit
-			if isaDeposit > p.ISAAnnualLimit {
-				isaDeposit = p.ISAAnnualLimit
-			}
-			p.TaxFreeSavings += isaDeposit
+			// Cap at the ISA allowance left this tax year
+			isaDeposit = math.Min(isaDeposit, p.ISAAllowanceLeft())
+			p.SaveTaxFree(isaDeposit)
 			breakdown.ISADeposits[p.Name] = isaDeposit
 			breakdown.TotalISADeposits += isaDeposit
 			remainingExcess -= isaDeposit
@@ -706,15 +707,17 @@ func ExecutePensionToISADrawdown(people []*Person, netNeeded float64, strategy S
 			if remainingExcess <= 0 {
 				break
 			}
-			spaceLeft := p.ISAAnnualLimit - breakdown.ISADeposits[p.Name]
+			spaceLeft := p.ISAAllowanceLeft()
 			if spaceLeft > 0 {
 				additional := math.Min(remainingExcess, spaceLeft)
-				p.TaxFreeSavings += additional
+				p.SaveTaxFree(additional)
 				breakdown.ISADeposits[p.Name] += additional
 				breakdown.TotalISADeposits += additional
 				remainingExcess -= additional
 			}
 		}
+		// Anything over everyone's allowance is saved in the GIA
+		saveExcess(people, remainingExcess, &breakdown)
 	} else if netFromPension < netNeeded {
 		// Need more - try ISA first, then more pension if needed
 		shortfall := netNeeded - netFromPension
@@ -722,15 +725,15 @@ func ExecutePensionToISADrawdown(people []*Person, netNeeded float64, strategy S
 		// First: try to cover from ISA
 		totalISA := 0.0
 		for _, p := range people {
-			totalISA += p.TaxFreeSavings
+			totalISA += p.Savings()
 		}
 
 		if totalISA > 0 {
 			isaNeeded := math.Min(shortfall, totalISA)
 			for _, p := range people {
-				if p.TaxFreeSavings > 0 {
-					share := p.TaxFreeSavings / totalISA
-					withdrawal := math.Min(isaNeeded*share, p.TaxFreeSavings)
+				if p.Savings() > 0 {
+					share := p.Savings() / totalISA
+					withdrawal := math.Min(isaNeeded*share, p.Savings())
 					actual := WithdrawFromISA(p, withdrawal)
 					breakdown.TaxFreeFromISA[p.Name] += actual
 					breakdown.TotalTaxFree += actual
@@ -793,7 +796,7 @@ func ExecutePensionToISAProactiveDrawdown(people []*Person, netNeeded float64, s
 
 		// Calculate how much we need to crystallise/withdraw to get this taxable amount
 		var amountToCrystallise float64
-		if p.PCLSTaken {
+		if !p.hasTaxFreeCash() {
 			amountToCrystallise = targetTaxableWithdrawal
 		} else {
 			// 25% tax-free, 75% taxable
@@ -849,17 +852,15 @@ func ExecutePensionToISAProactiveDrawdown(people []*Person, netNeeded float64, s
 	}
 
 	if excess > 0 {
-		// Distribute excess to ISAs (up to annual limit per person)
+		// Distribute excess to ISAs (up to the allowance left per person, then the GIA)
 		remainingExcess := excess
 		for _, p := range people {
 			if remainingExcess <= 0 {
 				break
 			}
 			isaDeposit := remainingExcess / float64(len(people))
-			if isaDeposit > p.ISAAnnualLimit {
-				isaDeposit = p.ISAAnnualLimit
-			}
-			p.TaxFreeSavings += isaDeposit
+			isaDeposit = math.Min(isaDeposit, p.ISAAllowanceLeft())
+			p.SaveTaxFree(isaDeposit)
 			breakdown.ISADeposits[p.Name] = isaDeposit
 			breakdown.TotalISADeposits += isaDeposit
 			remainingExcess -= isaDeposit
@@ -869,15 +870,17 @@ func ExecutePensionToISAProactiveDrawdown(people []*Person, netNeeded float64, s
 			if remainingExcess <= 0 {
 				break
 			}
-			spaceLeft := p.ISAAnnualLimit - breakdown.ISADeposits[p.Name]
+			spaceLeft := p.ISAAllowanceLeft()
 			if spaceLeft > 0 {
 				additional := math.Min(remainingExcess, spaceLeft)
-				p.TaxFreeSavings += additional
+				p.SaveTaxFree(additional)
 				breakdown.ISADeposits[p.Name] += additional
 				breakdown.TotalISADeposits += additional
 				remainingExcess -= additional
 			}
 		}
+		// Anything over everyone's allowance is saved in the GIA
+		saveExcess(people, remainingExcess, &breakdown)
 	}
 
 	// If we still need income (netNeeded > 0 and excess < 0), cover shortfall from ISA
@@ -886,15 +889,15 @@ func ExecutePensionToISAProactiveDrawdown(people []*Person, netNeeded float64, s
 
 		totalISA := 0.0
 		for _, p := range people {
-			totalISA += p.TaxFreeSavings
+			totalISA += p.Savings()
 		}
 
 		if totalISA > 0 {
 			isaNeeded := math.Min(shortfall, totalISA)
 			for _, p := range people {
-				if p.TaxFreeSavings > 0 {
-					share := p.TaxFreeSavings / totalISA
-					withdrawal := math.Min(isaNeeded*share, p.TaxFreeSavings)
+				if p.Savings() > 0 {
+					share := p.Savings() / totalISA
+					withdrawal := math.Min(isaNeeded*share, p.Savings())
 					actual := WithdrawFromISA(p, withdrawal)
 					breakdown.TaxFreeFromISA[p.Name] += actual
 					breakdown.TotalTaxFree += actual
@@ -951,7 +954,7 @@ func ExecuteFillBasicRateDrawdown(people []*Person, netNeeded float64, strategy
 
 		// Calculate how much we need to crystallise to get this taxable amount
 		var amountToCrystallise float64
-		if p.PCLSTaken {
+		if !p.hasTaxFreeCash() {
 			amountToCrystallise = targetTaxableWithdrawal
 		} else {
 			// 25% tax-free, 75% taxable: to get X taxable, crystallise X/0.75
@@ -1005,20 +1008,22 @@ func ExecuteFillBasicRateDrawdown(people []*Person, netNeeded float64, strategy
 			if excess <= 0 {
 				break
 			}
-			isaDeposit := math.Min(excess, p.ISAAnnualLimit)
-			p.TaxFreeSavings += isaDeposit
+			isaDeposit := math.Min(excess, p.ISAAllowanceLeft())
+			p.SaveTaxFree(isaDeposit)
 			breakdown.ISADeposits[p.Name] = isaDeposit
 			breakdown.TotalISADeposits += isaDeposit
 			excess -= isaDeposit
 		}
+		// Anything over everyone's allowance is saved in the GIA
+		saveExcess(people, excess, &breakdown)
 	} else if netFromPension < netNeeded {
 		// Shortfall - cover from ISA first
 		shortfall := netNeeded - netFromPension
 		for _, p := range people {
-			if shortfall <= 0 || p.TaxFreeSavings <= 0 {
+			if shortfall <= 0 || p.Savings() <= 0 {
 				continue
 			}
-			withdrawal := math.Min(shortfall, p.TaxFreeSavings)
+			withdrawal := math.Min(shortfall, p.Savings())
 			actual := WithdrawFromISA(p, withdrawal)
 			breakdown.TaxFreeFromISA[p.Name] += actual
 			breakdown.TotalTaxFree += actual
@@ -1076,7 +1081,7 @@ func ExecuteStatePensionBridgeDrawdown(people []*Person, netNeeded float64, stra
 			targetTaxableWithdrawal := personalAllowance + (basicRateLimit - personalAllowance)
 
 			var amountToCrystallise float64
-			if p.PCLSTaken {
+			if !p.hasTaxFreeCash() {
 				amountToCrystallise = targetTaxableWithdrawal
 			} else {
 				amountToCrystallise = targetTaxableWithdrawal / 0.75
@@ -1139,20 +1144,22 @@ func ExecuteStatePensionBridgeDrawdown(people []*Person, netNeeded float64, stra
 			if excess <= 0 {
 				break
 			}
-			isaDeposit := math.Min(excess, p.ISAAnnualLimit)
-			p.TaxFreeSavings += isaDeposit
+			isaDeposit := math.Min(excess, p.ISAAllowanceLeft())
+			p.SaveTaxFree(isaDeposit)
 			breakdown.ISADeposits[p.Name] = isaDeposit
 			breakdown.TotalISADeposits += isaDeposit
 			excess -= isaDeposit
 		}
+		// Anything over everyone's allowance is saved in the GIA
+		saveExcess(people, excess, &breakdown)
 	} else if netFromPension < netNeeded {
 		// Shortfall - cover from ISA
 		shortfall := netNeeded - netFromPension
 		for _, p := range people {
-			if shortfall <= 0 || p.TaxFreeSavings <= 0 {
+			if shortfall <= 0 || p.Savings() <= 0 {
 				continue
 			}
-			withdrawal := math.Min(shortfall, p.TaxFreeSavings)
+			withdrawal := math.Min(shortfall, p.Savings())
 			actual := WithdrawFromISA(p, withdrawal)
 			breakdown.TaxFreeFromISA[p.Name] += actual
 			breakdown.TotalTaxFree += actual
@@ -1174,7 +1181,7 @@ func ExecuteStatePensionBridgeDrawdown(people []*Person, netNeeded float64, stra
 func GetStrategiesForConfig(config *Config) []SimulationParams {
 	if !config.HasMortgage() {
 		// No mortgage - only test drawdown order strategies (mortgage options are irrelevant)
-		return []SimulationParams{
+		return withOptimalStrategies(config, withTrancheStrategies(config, []SimulationParams{
 			// Gradual Crystallisation
 			{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: SavingsFirst, MortgageOpt: MortgageNormal},
 			{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: PensionFirst, MortgageOpt: MortgageNormal},
@@ -1189,7 +1196,7 @@ func GetStrategiesForConfig(config *Config) []SimulationParams {
 			{CrystallisationStrategy: UFPLSStrategy, DrawdownOrder: TaxOptimized, MortgageOpt: MortgageNormal},
 			{CrystallisationStrategy: UFPLSStrategy, DrawdownOrder: FillBasicRate, MortgageOpt: MortgageNormal},
 			{CrystallisationStrategy: UFPLSStrategy, DrawdownOrder: StatePensionBridge, MortgageOpt: MortgageNormal},
-		}
+		}))
 	}
 
 	// Has mortgage - build strategies based on allowed mortgage options
@@ -1227,6 +1234,49 @@ func GetStrategiesForConfig(config *Config) []SimulationParams {
 		})
 	}
 
+	return withOptimalStrategies(config, withTrancheStrategies(config, strategies))
+}
+
+// withTrancheStrategies adds tranche crystallisation when strategy.tranche is set
+// Tranches are planned when years are given, otherwise crystallised every year
+func withTrancheStrategies(config *Config, strategies []SimulationParams) []SimulationParams {
+	if config.Strategy.Tranche == nil {
+		return strategies
+	}
+	timing := TrancheAnnual
+	if len(config.Strategy.Tranche.Years) > 0 {
+		timing = TranchePlanned
+	}
+	for _, drawdown := range []DrawdownOrder{SavingsFirst, PensionFirst, TaxOptimized, FillBasicRate, StatePensionBridge} {
+		strategies = append(strategies, SimulationParams{
+			CrystallisationStrategy: TrancheCrystallisation,
+			DrawdownOrder:           drawdown,
+			MortgageOpt:             MortgageNormal,
+			TrancheTiming:           timing,
+		})
+	}
+	return strategies
+}
+
+// withOptimalStrategies adds the Optimal drawdown order when include_optimal is set
+// One Optimal strategy is added per mortgage option (except PCLS payoff, which the plan does not model)
+func withOptimalStrategies(config *Config, strategies []SimulationParams) []SimulationParams {
+	if !config.Strategy.IncludeOptimal {
+		return strategies
+	}
+
+	seen := make(map[MortgageOption]bool)
+	for _, s := range strategies {
+		if s.MortgageOpt == PCLSMortgagePayoff || seen[s.MortgageOpt] {
+			continue
+		}
+		seen[s.MortgageOpt] = true
+		strategies = append(strategies, SimulationParams{
+			CrystallisationStrategy: GradualCrystallisation,
+			DrawdownOrder:           Optimal,
+			MortgageOpt:             s.MortgageOpt,
+		})
+	}
 	return strategies
 }
 
@@ -1359,90 +1409,57 @@ type Constraint struct {
 	Validate    func(combo StrategyCombo) bool // Returns false if invalid
 }
 
+// defaultConstraintRules are the built-in rules for strategy combinations, in ParseConstraint form
+var defaultConstraintRules = []struct {
+	id          string
+	description string
+	rule        string
+}{
+	{
+		id:          "maximize_isa_only_pension_to_isa",
+		description: "MaximizeCoupleISA only applies to PensionToISA strategies",
+		rule:        "if maximize_couple_isa=on then drawdown=pension_to_isa|pension_to_isa_proactive",
+	},
+	{
+		id:          "pension_only_no_maximize_isa",
+		description: "PensionOnly strategy cannot use MaximizeCoupleISA",
+		rule:        "if drawdown=pension_only then maximize_couple_isa=off",
+	},
+	{
+		id:          "isa_to_sipp_not_with_pension_to_isa",
+		description: "ISA to SIPP and PensionToISA are contradictory strategies",
+		rule:        "if isa_to_sipp=on then drawdown!=pension_to_isa|pension_to_isa_proactive",
+	},
+	{
+		id:          "ufpls_not_with_pcls_payoff",
+		description: "UFPLS strategy cannot use PCLS mortgage payoff",
+		rule:        "if crystallisation=ufpls then mortgage!=pcls",
+	},
+	{
+		id:          "tranche_not_with_pcls_payoff",
+		description: "Tranche strategy takes tax-free cash in tranches, not as a PCLS mortgage payoff",
+		rule:        "if crystallisation=tranche then mortgage!=pcls",
+	},
+	{
+		id:          "tranche_timing_only_with_tranche",
+		description: "Tranche timing only varies for the tranche strategy",
+		rule:        "if crystallisation=gradual|ufpls then tranche_timing=annual",
+	},
+}
+
 // DefaultConstraints returns the standard constraints for strategy combinations
 func DefaultConstraints() []Constraint {
-	return []Constraint{
-		{
-			ID:          "maximize_isa_only_pension_to_isa",
-			Description: "MaximizeCoupleISA only applies to PensionToISA strategies",
-			Validate: func(combo StrategyCombo) bool {
-				maxVal, maxOK := combo.Values[FactorMaximizeCoupleISA]
-				if !maxOK {
-					return true
-				}
-				maximize, _ := maxVal.Value.(bool)
-				if !maximize {
-					return true
-				}
-				drawdownVal, drawOK := combo.Values[FactorDrawdown]
-				if !drawOK {
-					return true
-				}
-				drawdown, _ := drawdownVal.Value.(DrawdownOrder)
-				return drawdown == PensionToISA || drawdown == PensionToISAProactive
-			},
-		},
-		{
-			ID:          "pension_only_no_maximize_isa",
-			Description: "PensionOnly strategy cannot use MaximizeCoupleISA",
-			Validate: func(combo StrategyCombo) bool {
-				drawdownVal, drawOK := combo.Values[FactorDrawdown]
-				if !drawOK {
-					return true
-				}
-				drawdown, _ := drawdownVal.Value.(DrawdownOrder)
-				if drawdown != PensionOnly {
-					return true
-				}
-				maxVal, maxOK := combo.Values[FactorMaximizeCoupleISA]
-				if !maxOK {
-					return true
-				}
-				maximize, _ := maxVal.Value.(bool)
-				return !maximize
-			},
-		},
-		{
-			ID:          "isa_to_sipp_not_with_pension_to_isa",
-			Description: "ISA to SIPP and PensionToISA are contradictory strategies",
-			Validate: func(combo StrategyCombo) bool {
-				isaToSippVal, isaOK := combo.Values[FactorISAToSIPP]
-				if !isaOK {
-					return true
-				}
-				isaToSipp, _ := isaToSippVal.Value.(bool)
-				if !isaToSipp {
-					return true
-				}
-				drawdownVal, drawOK := combo.Values[FactorDrawdown]
-				if !drawOK {
-					return true
-				}
-				drawdown, _ := drawdownVal.Value.(DrawdownOrder)
-				return drawdown != PensionToISA && drawdown != PensionToISAProactive
-			},
-		},
-		{
-			ID:          "ufpls_not_with_pcls_payoff",
-			Description: "UFPLS strategy cannot use PCLS mortgage payoff",
-			Validate: func(combo StrategyCombo) bool {
-				crystVal, crystOK := combo.Values[FactorCrystallisation]
-				if !crystOK {
-					return true
-				}
-				cryst, _ := crystVal.Value.(Strategy)
-				if cryst != UFPLSStrategy {
-					return true
-				}
-				mortgageVal, mortOK := combo.Values[FactorMortgage]
-				if !mortOK {
-					return true
-				}
-				mortgage, _ := mortgageVal.Value.(MortgageOption)
-				return mortgage != PCLSMortgagePayoff
-			},
-		},
+	registry := NewFactorRegistry()
+	constraints := make([]Constraint, len(defaultConstraintRules))
+	for i, r := range defaultConstraintRules {
+		c, err := ParseConstraint(r.rule, registry)
+		if err != nil {
+			panic(err) // The built-in rules are fixed, so this is a programming error
+		}
+		c.ID, c.Description = r.id, r.description
+		constraints[i] = c
 	}
+	return constraints
 }
 
 // CombinationGenerator generates valid strategy combinations
@@ -1453,10 +1470,21 @@ type CombinationGenerator struct {
 }
 
 // NewCombinationGenerator creates a new generator for the given config
+// Per-person factors, and factors and constraints declared in the config, are added to the built-in ones
 func NewCombinationGenerator(config *Config) *CombinationGenerator {
+	registry := NewFactorRegistry()
+	registry.RegisterPersonFactors(config)
+	if errs := registry.RegisterUserFactors(config); len(errs) > 0 {
+		log.Printf("Warning: %v", errs)
+	}
+	constraints, errs := UserConstraints(config, registry)
+	if len(errs) > 0 {
+		log.Printf("Warning: %v", errs)
+	}
+	constraints = append(PersonConstraints(config, registry), constraints...)
 	return &CombinationGenerator{
-		registry:    NewFactorRegistry(),
-		constraints: DefaultConstraints(),
+		registry:    registry,
+		constraints: append(DefaultConstraints(), constraints...),
 		config:      config,
 	}
 }
@@ -1481,6 +1509,8 @@ func (g *CombinationGenerator) GenerateCombinations(mode PermutationMode) []Stra
 }
 
 // cartesianProduct generates all combinations of factor values
+// A rule broken by some of the factors stays broken whatever the rest are, so invalid
+// combinations are dropped as each factor is added rather than at the end
 func (g *CombinationGenerator) cartesianProduct(factors []*Factor) []StrategyCombo {
 	if len(factors) == 0 {
 		return []StrategyCombo{{Values: make(map[FactorID]FactorValue)}}
@@ -1502,7 +1532,9 @@ func (g *CombinationGenerator) cartesianProduct(factors []*Factor) []StrategyCom
 			for _, val := range factors[i].Values {
 				newCombo := existingCombo.Clone()
 				newCombo.Values[factors[i].ID] = val
-				newResult = append(newResult, newCombo)
+				if g.isValid(newCombo) {
+					newResult = append(newResult, newCombo)
+				}
 			}
 		}
 		result = newResult
@@ -1543,11 +1575,24 @@ func (combo StrategyCombo) ToSimulationParams() SimulationParams {
 		params.ISAToSIPPEnabled, _ = v.Value.(bool)
 	}
 	if v, ok := combo.Values[FactorGuardrails]; ok {
-		params.GuardrailsEnabled, _ = v.Value.(bool)
+		switch rule := v.Value.(type) {
+		case WithdrawalRule:
+			params.WithdrawalRule = rule
+		case bool:
+			params.GuardrailsEnabled = rule // Legacy on/off value
+		}
 	}
 	if v, ok := combo.Values[FactorStatePensionDefer]; ok {
 		params.StatePensionDeferYears, _ = v.Value.(int)
 	}
+	if v, ok := combo.Values[FactorTrancheTiming]; ok {
+		params.TrancheTiming, _ = v.Value.(TrancheTiming)
+	}
+	if v, ok := combo.Values[FactorTaxFreeCash]; ok {
+		params.KeepTaxFreeCash, _ = v.Value.(bool)
+	}
+	params.ConfigOverrides = configOverrides(combo)
+	params.PersonStrategies = personStrategies(combo, params)
 
 	params.SourceCombo = &combo
 	return params
@@ -1563,7 +1608,7 @@ func GetStrategiesForConfigV2(config *Config, mode PermutationMode) []Simulation
 		params[i] = combo.ToSimulationParams()
 	}
 
-	return params
+	return withOptimalStrategies(config, params)
 }
 
 // GetCombinationCount returns the expected number of combinations for each mode
@@ -1571,7 +1616,7 @@ func GetCombinationCount(config *Config) map[PermutationMode]int {
 	counts := make(map[PermutationMode]int)
 	generator := NewCombinationGenerator(config)
 
-	for _, mode := range []PermutationMode{ModeQuick, ModeStandard, ModeThorough, ModeComprehensive} {
+	for _, mode := range []PermutationMode{ModeQuick, ModeStandard, ModeThorough, ModeComprehensive, ModeAdaptive} {
 		combos := generator.GenerateCombinations(mode)
 		counts[mode] = len(combos)
 	}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// TaxFreeCashUse is tax-free cash taken in a year and what it paid for
+type TaxFreeCashUse struct {
+	Person      string
+	Purpose     string
+	Description string
+	Amount      float64 // Tax-free cash taken
+	Spent       float64 // Part that paid the mortgage or expense
+	Saved       float64 // Part saved in the ISA, or the GIA over the ISA allowance
+}
+
+// lumpSumAllowanceLeft returns how much more tax-free cash the person can take from pensions
+func (p *Person) lumpSumAllowanceLeft() float64 {
+	if p.LumpSumAllowance <= 0 {
+		return math.Inf(1)
+	}
+	return math.Max(0, p.LumpSumAllowance-p.LumpSumAllowanceUsed)
+}
+
+// taxFreePortion returns the tax-free part of crystallising amount: 25%, within the lump sum allowance left
+func (p *Person) taxFreePortion(amount float64) float64 {
+	return math.Min(amount*0.25, p.lumpSumAllowanceLeft())
+}
+
+// hasTaxFreeCash returns true if crystallising the person's pension still pays tax-free cash
+func (p *Person) hasTaxFreeCash() bool {
+	return !p.PCLSTaken && p.lumpSumAllowanceLeft() > 0
+}
+
+// useTaxFree returns the tax-free part of crystallising amount and counts it against the lump sum allowance
+func (p *Person) useTaxFree(amount float64) float64 {
+	taxFree := p.taxFreePortion(amount)
+	p.LumpSumAllowanceUsed += taxFree
+	return taxFree
+}
+
+// TakeTaxFreeCash crystallises enough of person's pension to take amount of tax-free cash
+// An amount of 0 crystallises the whole pot as a PCLS, after which later crystallisations have no tax-free part
+// Only the lump sum allowance left can be taken; a PCLS over it crystallises the rest as taxable
+// The tax-free cash is returned rather than saved
+func TakeTaxFreeCash(person *Person, amount float64) CrystallisationResult {
+	if person.UncrystallisedPot <= 0 || person.PCLSTaken || person.lumpSumAllowanceLeft() <= 0 {
+		return CrystallisationResult{}
+	}
+
+	crystallise := person.UncrystallisedPot
+	if amount > 0 {
+		crystallise = math.Min(crystallise, math.Min(amount, person.lumpSumAllowanceLeft())/0.25)
+	}
+	taxFree := person.useTaxFree(crystallise)
+	person.UncrystallisedPot -= crystallise
+	person.CrystallisedPot += crystallise - taxFree
+	if amount <= 0 {
+		person.PCLSTaken = true
+	}
+
+	return CrystallisationResult{
+		AmountCrystallised: crystallise,
+		TaxFreePortion:     taxFree,
+		TaxablePortion:     crystallise - taxFree,
+	}
+}
+
+// TaxFreeCashPlan returns the tax-free cash the strategy takes: a PCLS from everyone at the early
+// payoff year for PCLS mortgage payoff, then the planned uses unless the strategy keeps the cash
+func (sp SimulationParams) TaxFreeCashPlan(config *Config) []TaxFreeCashConfig {
+	var plan []TaxFreeCashConfig
+	if sp.MortgageOpt == PCLSMortgagePayoff {
+		for _, p := range config.People {
+			plan = append(plan, TaxFreeCashConfig{Person: p.Name, Year: config.Mortgage.EarlyPayoffYear, Purpose: TaxFreeCashMortgage, Description: "PCLS mortgage payoff"})
+		}
+	}
+	if !sp.KeepTaxFreeCash {
+		plan = append(plan, config.TaxFreeCash...)
+	}
+	return plan
+}
+
+// mortgageCashYear returns the first year plan pays off the mortgage with tax-free cash (0 = never)
+func mortgageCashYear(plan []TaxFreeCashConfig) int {
+	year := 0
+	for _, use := range plan {
+		if use.Purpose == TaxFreeCashMortgage && (year == 0 || use.Year < year) {
+			year = use.Year
+		}
+	}
+	return year
+}
+
+// oneOffExpense returns the one-off expenses planned for year, paid whether or not tax-free cash is taken for them
+func oneOffExpense(config *Config, year int) float64 {
+	total := 0.0
+	for _, use := range config.TaxFreeCash {
+		if use.Purpose == TaxFreeCashExpense && use.Year == year {
+			total += use.Amount
+		}
+	}
+	return total
+}
+
+// takePlannedCash takes the tax-free cash planned for year and spends it on the mortgage and
+// one-off expenses in state; cash over their cost, and cash for the ISA, is saved
+// Returns the cash spent, which is recorded as a tax-free pension withdrawal
+func takePlannedCash(state *YearState, people []*Person, plan []TaxFreeCashConfig, year int) float64 {
+	mortgageLeft, expenseLeft := state.MortgageCost, state.OneOffExpense
+	spent := 0.0
+	for _, use := range plan {
+		if use.Year != year {
+			continue
+		}
+		p := findPerson(people, use.Person)
+		if p == nil || !p.CanAccessPension(year) {
+			continue
+		}
+		result := TakeTaxFreeCash(p, use.Amount)
+		if result.TaxFreePortion <= 0 {
+			continue
+		}
+
+		taken := TaxFreeCashUse{Person: p.Name, Purpose: use.Purpose, Description: use.Description, Amount: result.TaxFreePortion}
+		switch use.Purpose {
+		case TaxFreeCashMortgage:
+			taken.Spent = math.Min(taken.Amount, mortgageLeft)
+			mortgageLeft -= taken.Spent
+		case TaxFreeCashExpense:
+			taken.Spent = math.Min(taken.Amount, expenseLeft)
+			expenseLeft -= taken.Spent
+		}
+		taken.Saved = taken.Amount - taken.Spent
+		p.SaveTaxFree(taken.Saved)
+
+		state.Withdrawals.TaxFreeFromPension[p.Name] += taken.Spent
+		state.Withdrawals.TotalTaxFree += taken.Spent
+		state.TaxFreeCash = append(state.TaxFreeCash, taken)
+		spent += taken.Spent
+	}
+	return spent
+}
+
+// findPerson returns the person with the given name, or nil
+func findPerson(people []*Person, name string) *Person {
+	for _, p := range people {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// ValidateTaxFreeCash checks each planned use of tax-free cash names a person, a purpose and a year
+func ValidateTaxFreeCash(config *Config) ConfigErrors {
+	var errs ConfigErrors
+	for i, use := range config.TaxFreeCash {
+		path := fmt.Sprintf("tax_free_cash[%d]", i)
+		if config.FindPerson(use.Person) == nil {
+			errs = append(errs, ConfigError{Path: path + ".person", Message: fmt.Sprintf("unknown person %q", use.Person)})
+		}
+		switch use.Purpose {
+		case TaxFreeCashMortgage, TaxFreeCashISA:
+		case TaxFreeCashExpense:
+			if use.Amount <= 0 {
+				errs = append(errs, ConfigError{Path: path + ".amount", Message: "an expense needs its amount"})
+			}
+		default:
+			errs = append(errs, ConfigError{Path: path + ".purpose", Message: fmt.Sprintf("expected mortgage, expense or isa, got %q", use.Purpose)})
+		}
+		if use.Year <= 0 {
+			errs = append(errs, ConfigError{Path: path + ".year", Message: "expected the tax year to take the cash"})
+		}
+		if use.Amount < 0 {
+			errs = append(errs, ConfigError{Path: path + ".amount", Message: fmt.Sprintf("expected an amount of 0 or more, got %g", use.Amount)})
+		}
+	}
+	return errs
+}
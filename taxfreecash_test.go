@@ -0,0 +1,159 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestTakeTaxFreeCash verifies partial and full tax-free cash, and the lump sum allowance limit
+func TestTakeTaxFreeCash(t *testing.T) {
+	p := &Person{Name: "James", UncrystallisedPot: 400000, LumpSumAllowance: 268275}
+
+	// Part of the cash leaves the rest of the pot with its 25%
+	result := TakeTaxFreeCash(p, 20000)
+	if result.AmountCrystallised != 80000 || result.TaxFreePortion != 20000 || p.LumpSumAllowanceUsed != 20000 {
+		t.Errorf("Expected £80000 crystallised for £20000 tax-free, got £%.0f and £%.0f", result.AmountCrystallised, result.TaxFreePortion)
+	}
+	if p.PCLSTaken || !p.hasTaxFreeCash() || p.TaxFreeSavings != 0 {
+		t.Error("Expected the rest of the pot to keep its tax-free cash, and the cash not saved")
+	}
+
+	// A PCLS takes the rest, and later crystallisations have no tax-free part
+	result = TakeTaxFreeCash(p, 0)
+	if result.AmountCrystallised != 320000 || result.TaxFreePortion != 80000 || !p.PCLSTaken {
+		t.Errorf("Expected a PCLS of £80000 from £320000, got £%.0f from £%.0f", result.AmountCrystallised, result.TaxFreePortion)
+	}
+	if p.CrystallisedPot != 300000 {
+		t.Errorf("Expected £300000 crystallised, got £%.0f", p.CrystallisedPot)
+	}
+	if result := TakeTaxFreeCash(p, 0); result.AmountCrystallised != 0 {
+		t.Errorf("Expected no second PCLS, got £%.0f", result.AmountCrystallised)
+	}
+
+	// The allowance limits the cash, the rest of a PCLS is taxable
+	p = &Person{Name: "Delphine", UncrystallisedPot: 400000, LumpSumAllowance: 268275, LumpSumAllowanceUsed: 238275}
+	result = TakeTaxFreeCash(p, 0)
+	if result.TaxFreePortion != 30000 || result.TaxablePortion != 370000 || p.lumpSumAllowanceLeft() != 0 {
+		t.Errorf("Expected £30000 tax-free within the allowance, got £%.0f and £%.0f taxable", result.TaxFreePortion, result.TaxablePortion)
+	}
+	p = &Person{Name: "Delphine", UncrystallisedPot: 400000, LumpSumAllowance: 268275, LumpSumAllowanceUsed: 238275}
+	if result := TakeTaxFreeCash(p, 50000); result.AmountCrystallised != 120000 || result.TaxFreePortion != 30000 {
+		t.Errorf("Expected £120000 crystallised for the £30000 left, got £%.0f and £%.0f", result.AmountCrystallised, result.TaxFreePortion)
+	}
+}
+
+// TestLumpSumAllowance_Withdrawals verifies withdrawals have no tax-free part once the allowance is used
+func TestLumpSumAllowance_Withdrawals(t *testing.T) {
+	p := &Person{Name: "James", UncrystallisedPot: 400000, LumpSumAllowance: 268275, LumpSumAllowanceUsed: 263275}
+	if result := GradualCrystallise(p, 40000); result.TaxFreePortion != 5000 || result.TaxablePortion != 35000 {
+		t.Errorf("Expected £5000 tax-free, got £%.0f", result.TaxFreePortion)
+	}
+	if p.hasTaxFreeCash() {
+		t.Error("Expected no tax-free cash left")
+	}
+	if result := UFPLSWithdraw(p, 40000); result.TaxFreePortion != 0 || result.TaxablePortion != 40000 {
+		t.Errorf("Expected UFPLS to be all taxable, got £%.0f tax-free", result.TaxFreePortion)
+	}
+}
+
+// TestSimulation_TaxFreeCash verifies planned tax-free cash pays its purpose and the rest is saved
+func TestSimulation_TaxFreeCash(t *testing.T) {
+	config := createTestConfig()
+	config.TaxFreeCash = []TaxFreeCashConfig{
+		{Person: "James", Year: 2027, Purpose: TaxFreeCashMortgage, Amount: 150000},
+		{Person: "James", Year: 2029, Purpose: TaxFreeCashExpense, Amount: 30000, Description: "Car"},
+		{Person: "Delphine", Year: 2032, Purpose: TaxFreeCashISA, Amount: 10000},
+	}
+	params := SimulationParams{CrystallisationStrategy: GradualCrystallisation, DrawdownOrder: SavingsFirst, MortgageOpt: MortgageNormal}
+
+	result := RunSimulationV2(params, config)
+	uses := make(map[int]TaxFreeCashUse)
+	for _, year := range result.Years {
+		for _, use := range year.TaxFreeCash {
+			uses[year.Year] = use
+		}
+		if year.Year > 2027 && year.MortgageCost != 0 {
+			t.Errorf("%d: expected the mortgage paid off in 2027, got £%.0f", year.Year, year.MortgageCost)
+		}
+		if (year.OneOffExpense != 0) != (year.Year == 2029) {
+			t.Errorf("%d: unexpected one-off expense of £%.0f", year.Year, year.OneOffExpense)
+		}
+	}
+
+	mortgage := uses[2027]
+	if mortgage.Amount != 150000 || math.Abs(mortgage.Spent-100000) > 1 || math.Abs(mortgage.Saved-50000) > 1 {
+		t.Errorf("Expected £100000 of £150000 to pay the mortgage, got £%.0f spent and £%.0f saved", mortgage.Spent, mortgage.Saved)
+	}
+	if car := uses[2029]; car.Spent != 30000 || car.Saved != 0 || car.Description != "Car" {
+		t.Errorf("Expected £30000 to pay for the car, got %+v", car)
+	}
+	if isa := uses[2032]; isa.Person != "Delphine" || isa.Spent != 0 || isa.Saved != 10000 {
+		t.Errorf("Expected £10000 saved by Delphine, got %+v", isa)
+	}
+	if used := result.FinalBalances["James"].LumpSumAllowanceUsed; used < 180000 {
+		t.Errorf("Expected James's allowance to count the £180000 taken, got £%.0f", used)
+	}
+
+	// Keeping the cash still pays the expense, from savings
+	params.KeepTaxFreeCash = true
+	kept := RunSimulationV2(params, config)
+	for _, year := range kept.Years {
+		if len(year.TaxFreeCash) != 0 {
+			t.Errorf("%d: expected no tax-free cash taken, got %+v", year.Year, year.TaxFreeCash)
+		}
+		if year.Year == 2029 && year.OneOffExpense != 30000 {
+			t.Errorf("Expected the expense to be paid, got £%.0f", year.OneOffExpense)
+		}
+		if year.Year == 2030 && year.MortgageCost == 0 {
+			t.Error("Expected the mortgage to run to its end")
+		}
+	}
+}
+
+// TestTaxFreeCashFactor verifies taking or keeping the cash is only searched with planned uses
+func TestTaxFreeCashFactor(t *testing.T) {
+	config := createTestConfig()
+	for _, params := range GetStrategiesForConfigV2(config, ModeStandard) {
+		if params.KeepTaxFreeCash {
+			t.Fatalf("Expected no keep strategies without planned uses, got %s", params.ShortName())
+		}
+	}
+
+	config.TaxFreeCash = []TaxFreeCashConfig{{Person: "James", Year: 2029, Purpose: TaxFreeCashExpense, Amount: 30000}}
+	var keep bool
+	for _, params := range GetStrategiesForConfigV2(config, ModeStandard) {
+		if params.KeepTaxFreeCash {
+			keep = true
+			if name := params.ShortName(); !strings.Contains(name, "/KeepTFC") {
+				t.Errorf("Expected the choice in the short name, got %s", name)
+			}
+		}
+	}
+	if !keep {
+		t.Error("Expected keeping the cash to be compared")
+	}
+}
+
+// TestValidateTaxFreeCash verifies planned uses of tax-free cash are checked
+func TestValidateTaxFreeCash(t *testing.T) {
+	config := createTestConfig()
+	config.TaxFreeCash = []TaxFreeCashConfig{
+		{Person: "James", Year: 2029, Purpose: TaxFreeCashMortgage},
+		{Person: "Bob", Year: 2029, Purpose: TaxFreeCashISA},
+		{Person: "James", Year: 2029, Purpose: "holiday"},
+		{Person: "James", Purpose: TaxFreeCashExpense},
+	}
+	err := ValidateTaxFreeCash(config)
+	if err == nil {
+		t.Fatal("Expected errors")
+	}
+	for _, want := range []string{`tax_free_cash[1].person: unknown person "Bob"`, "tax_free_cash[2].purpose", "tax_free_cash[3].amount", "tax_free_cash[3].year"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "tax_free_cash[0]") {
+		t.Errorf("Expected a full PCLS for the mortgage to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import "math"
+
+// TrancheTiming is when the tranche crystallisation strategy crystallises its tranches
+type TrancheTiming int
+
+const (
+	TrancheAnnual      TrancheTiming = iota // Every tax year the pension can be accessed
+	TrancheEvery3Years                      // Every third tax year from first access
+	TrancheAtAccess                         // Once, in the first tax year the pension can be accessed
+	TranchePlanned                          // In the tax years listed in strategy.tranche.years
+)
+
+func (t TrancheTiming) String() string {
+	switch t {
+	case TrancheAnnual:
+		return "Annual"
+	case TrancheEvery3Years:
+		return "Every 3 Years"
+	case TrancheAtAccess:
+		return "At Access"
+	case TranchePlanned:
+		return "Planned"
+	default:
+		return "Unknown"
+	}
+}
+
+// ShortName returns the prefix for tranche strategies in short strategy names
+func (t TrancheTiming) ShortName() string {
+	switch t {
+	case TrancheEvery3Years:
+		return "T3y"
+	case TrancheAtAccess:
+		return "T1"
+	case TranchePlanned:
+		return "TPlan"
+	default:
+		return "T"
+	}
+}
+
+// Description returns the prefix for tranche strategies in descriptive strategy names
+func (t TrancheTiming) Description() string {
+	switch t {
+	case TrancheEvery3Years:
+		return "3-Yearly Tranches"
+	case TrancheAtAccess:
+		return "Single Tranche"
+	case TranchePlanned:
+		return "Planned Tranches"
+	default:
+		return "Annual Tranches"
+	}
+}
+
+// Due returns true if a tranche is crystallised in year, for a pension first accessed in firstYear
+func (t TrancheTiming) Due(year, firstYear int, tranche TrancheConfig) bool {
+	switch t {
+	case TrancheEvery3Years:
+		return (year-firstYear)%3 == 0
+	case TrancheAtAccess:
+		return year == firstYear
+	case TranchePlanned:
+		return containsInt(tranche.Years, year)
+	default:
+		return true
+	}
+}
+
+// usesTranches returns true if anyone follows the tranche crystallisation strategy
+func (sp SimulationParams) usesTranches() bool {
+	if sp.CrystallisationStrategy == TrancheCrystallisation {
+		return true
+	}
+	for _, ps := range sp.PersonStrategies {
+		if ps.Crystallisation == TrancheCrystallisation {
+			return true
+		}
+	}
+	return false
+}
+
+// withdrawalStrategy returns how withdrawals crystallise the pension: tranche strategies
+// crystallise gradually between tranches
+func (s Strategy) withdrawalStrategy() Strategy {
+	if s == TrancheCrystallisation {
+		return GradualCrystallisation
+	}
+	return s
+}
+
+// CrystalliseTranche crystallises a share of person's uncrystallised pot, saving the 25% tax-free
+// cash (within the lump sum allowance) in the ISA up to this year's allowance and the rest in the GIA
+// percent is the share of the pot; 0 crystallises just enough to fill the ISA allowance
+// Returns the crystallisation and the tax-free cash kept in the GIA
+func CrystalliseTranche(person *Person, percent float64) (CrystallisationResult, float64) {
+	if person.UncrystallisedPot <= 0 || person.PCLSTaken {
+		return CrystallisationResult{}, 0
+	}
+
+	amount := person.UncrystallisedPot * percent
+	if percent <= 0 {
+		amount = math.Min(person.UncrystallisedPot, math.Min(person.ISAAllowanceLeft(), person.lumpSumAllowanceLeft())/0.25)
+	}
+	if amount < 1 {
+		return CrystallisationResult{}, 0
+	}
+
+	taxFree := person.useTaxFree(amount)
+	taxable := amount - taxFree
+	person.UncrystallisedPot -= amount
+	person.CrystallisedPot += taxable
+	toGIA := person.SaveTaxFree(taxFree)
+
+	return CrystallisationResult{
+		AmountCrystallised: amount,
+		TaxFreePortion:     taxFree,
+		TaxablePortion:     taxable,
+	}, toGIA
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestCrystalliseTranche verifies the tax-free cash fills the ISA allowance and the rest is kept in the GIA
+func TestCrystalliseTranche(t *testing.T) {
+	p := &Person{Name: "James", UncrystallisedPot: 400000, ISAAnnualLimit: 20000}
+
+	// Sized to fill the allowance
+	result, toGIA := CrystalliseTranche(p, 0)
+	if result.AmountCrystallised != 80000 || result.TaxFreePortion != 20000 || toGIA != 0 {
+		t.Errorf("Expected £80000 crystallised with £20000 into the ISA, got £%.0f, £%.0f tax-free, £%.0f to GIA", result.AmountCrystallised, result.TaxFreePortion, toGIA)
+	}
+	if p.CrystallisedPot != 60000 || p.UncrystallisedPot != 320000 || p.TaxFreeSavings != 20000 {
+		t.Errorf("Expected £60000 crystallised and £320000 left, got £%.0f and £%.0f", p.CrystallisedPot, p.UncrystallisedPot)
+	}
+	if result, _ := CrystalliseTranche(p, 0); result.AmountCrystallised != 0 {
+		t.Errorf("Expected nothing crystallised with the allowance used, got £%.0f", result.AmountCrystallised)
+	}
+
+	// A share of the pot overflows into the GIA
+	p.StartTaxYear()
+	result, toGIA = CrystalliseTranche(p, 0.25)
+	if result.AmountCrystallised != 80000 || toGIA != 0 {
+		t.Errorf("Expected a quarter of £320000 crystallised within the allowance, got £%.0f, £%.0f to GIA", result.AmountCrystallised, toGIA)
+	}
+	p.StartTaxYear()
+	p.UncrystallisedPot = 200000
+	if _, toGIA = CrystalliseTranche(p, 0.5); toGIA != 5000 || p.GIA != 5000 {
+		t.Errorf("Expected £5000 over the allowance in the GIA, got £%.0f", p.GIA)
+	}
+
+	// The GIA moves into next year's ISA and is spent first
+	p.StartTaxYear()
+	if p.GIA != 0 || p.ISAAllowanceLeft() != 15000 {
+		t.Errorf("Expected the GIA moved into the ISA, got £%.0f left and £%.0f allowance", p.GIA, p.ISAAllowanceLeft())
+	}
+	p.SaveTaxFree(18000)
+	p.spendSavings(1000)
+	if p.GIA != 2000 || p.TaxFreeSavings != 80000 {
+		t.Errorf("Expected £2000 GIA and £80000 ISA, got £%.0f and £%.0f", p.GIA, p.TaxFreeSavings)
+	}
+
+	p.PCLSTaken = true
+	if result, _ := CrystalliseTranche(p, 0.5); result.AmountCrystallised != 0 {
+		t.Errorf("Expected no tranche after PCLS, got £%.0f", result.AmountCrystallised)
+	}
+}
+
+// TestTrancheTiming_Due verifies the years each timing crystallises a tranche
+func TestTrancheTiming_Due(t *testing.T) {
+	tranche := TrancheConfig{Years: []int{2030, 2033}}
+	tests := []struct {
+		timing TrancheTiming
+		due    []int
+	}{
+		{TrancheAnnual, []int{2028, 2029, 2030, 2031, 2032, 2033}},
+		{TrancheEvery3Years, []int{2028, 2031}},
+		{TrancheAtAccess, []int{2028}},
+		{TranchePlanned, []int{2030, 2033}},
+	}
+	for _, tt := range tests {
+		var due []int
+		for year := 2028; year <= 2033; year++ {
+			if tt.timing.Due(year, 2028, tranche) {
+				due = append(due, year)
+			}
+		}
+		if fmt.Sprint(due) != fmt.Sprint(tt.due) {
+			t.Errorf("%s: expected tranches in %v, got %v", tt.timing, tt.due, due)
+		}
+	}
+}
+
+// TestSimulation_Tranches verifies tranches are crystallised in the planned years only
+func TestSimulation_Tranches(t *testing.T) {
+	config := createTestConfig()
+	config.Strategy.Tranche = &TrancheConfig{Percent: 0.2, Years: []int{2027, 2030}}
+	params := SimulationParams{CrystallisationStrategy: TrancheCrystallisation, DrawdownOrder: PensionFirst, MortgageOpt: MortgageNormal, TrancheTiming: TranchePlanned}
+
+	result := RunSimulationV2(params, config)
+	var total float64
+	for _, year := range result.Years {
+		crystallised := year.TrancheCrystallised["James"]
+		if (crystallised > 0) != (year.Year == 2027 || year.Year == 2030) {
+			t.Errorf("%d: unexpected tranche of £%.0f", year.Year, crystallised)
+		}
+		if year.Year == 2027 {
+			if gia := year.TrancheToGIA["James"]; math.Abs(gia-(crystallised*0.25-20000)) > 1 {
+				t.Errorf("Expected the tax-free cash over the allowance in the GIA, got £%.0f of £%.0f", gia, crystallised*0.25)
+			}
+		}
+		total += crystallised
+	}
+	if total == 0 {
+		t.Fatal("Expected James to crystallise tranches")
+	}
+}
+
+// TestTrancheFactors verifies tranche timing is only varied for tranche strategies
+func TestTrancheFactors(t *testing.T) {
+	config := createTestConfig()
+	for _, params := range GetStrategiesForConfigV2(config, ModeThorough) {
+		if params.CrystallisationStrategy == TrancheCrystallisation {
+			t.Fatalf("Expected tranches only in comprehensive mode, got %s", params.ShortName())
+		}
+	}
+
+	var found bool
+	for _, params := range GetStrategiesForConfigV2(config, ModeComprehensive) {
+		if params.TrancheTiming == TranchePlanned {
+			t.Fatalf("Expected no planned tranches without years, got %s", params.ShortName())
+		}
+		if params.CrystallisationStrategy != TrancheCrystallisation {
+			if params.TrancheTiming != TrancheAnnual {
+				t.Fatalf("Expected tranche timing only with tranches, got %s", params.ShortName())
+			}
+			continue
+		}
+		if params.MortgageOpt == PCLSMortgagePayoff {
+			t.Fatalf("Expected no PCLS mortgage payoff with tranches, got %s", params.ShortName())
+		}
+		if params.TrancheTiming == TrancheEvery3Years && params.DrawdownOrder == TaxOptimized {
+			found = true
+			if name := params.ShortName(); !strings.HasPrefix(name, "T3y/TaxOpt/") {
+				t.Errorf("Expected the timing in the short name, got %s", name)
+			}
+			if name := params.DescriptiveName(0); !strings.Contains(name, "3-Yearly Tranches") {
+				t.Errorf("Expected the timing in the descriptive name, got %s", name)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Expected 3-yearly tranches to be searched")
+	}
+
+	config.Strategy.Tranche = &TrancheConfig{Years: []int{2030}}
+	var planned bool
+	for _, params := range GetStrategiesForConfigV2(config, ModeComprehensive) {
+		planned = planned || params.TrancheTiming == TranchePlanned
+	}
+	if !planned {
+		t.Error("Expected planned tranches with years configured")
+	}
+
+	config.Strategy.Tranche.Percent = 20
+	if err := ValidateUserStrategy(config); err == nil || !strings.Contains(err.Error(), "strategy.tranche.percent") {
+		t.Errorf("Expected a percent over 1 to be reported, got %v", err)
+	}
+}
@@ -113,6 +113,7 @@ const (
 	OptimizeTax     OptimizationGoal = iota // Minimize total tax paid
 	OptimizeIncome                          // Maximize total net income over period
 	OptimizeBalance                         // Maximize final balance
+	OptimizeCustom                          // Weighted score from strategy.scoring
 )
 
 func (o OptimizationGoal) String() string {
@@ -123,6 +124,8 @@ func (o OptimizationGoal) String() string {
 		return "Total Income"
 	case OptimizeBalance:
 		return "Final Balance"
+	case OptimizeCustom:
+		return "Custom Weights"
 	default:
 		return "Unknown"
 	}
@@ -134,6 +137,7 @@ type Strategy int
 const (
 	GradualCrystallisation Strategy = iota
 	UFPLSStrategy                   // Uncrystallised Funds Pension Lump Sum - each withdrawal is 25% tax-free
+	TrancheCrystallisation          // Gradual, plus planned tranches whose tax-free cash is saved in the ISA
 )
 
 func (s Strategy) String() string {
@@ -142,6 +146,8 @@ func (s Strategy) String() string {
 		return "Gradual Crystallisation"
 	case UFPLSStrategy:
 		return "UFPLS (Flexible Lump Sums)"
+	case TrancheCrystallisation:
+		return "Tranche Crystallisation"
 	default:
 		return "Unknown"
 	}
@@ -159,6 +165,7 @@ const (
 	PensionOnly                                // Only use pension, never touch ISAs (for pension-only depletion)
 	FillBasicRate                              // Withdraw from pension up to basic rate limit, excess to ISA
 	StatePensionBridge                         // Draw heavily before state pension, reduce after
+	Optimal                                    // Follow a year-by-year plan found by dynamic programming
 )
 
 func (d DrawdownOrder) String() string {
@@ -179,6 +186,8 @@ func (d DrawdownOrder) String() string {
 		return "Fill Basic Rate"
 	case StatePensionBridge:
 		return "State Pension Bridge"
+	case Optimal:
+		return "Optimal"
 	default:
 		return "Unknown"
 	}
@@ -194,6 +203,77 @@ const (
 	PCLSMortgagePayoff                       // Use 25% PCLS lump sum to pay mortgage (no further 25% tax-free)
 )
 
+// WithdrawalRule represents a dynamic rule that adjusts retirement spending each year
+type WithdrawalRule int
+
+const (
+	WithdrawalFixed           WithdrawalRule = iota // Inflation-linked spending, no adjustment
+	WithdrawalGuytonKlinger                         // Guyton-Klinger guardrails
+	WithdrawalVanguard                              // Vanguard dynamic spending (ceiling/floor on yearly change)
+	WithdrawalVPW                                   // Variable Percentage Withdrawal
+	WithdrawalConstantPercent                       // Fixed percentage of the current portfolio
+)
+
+func (w WithdrawalRule) String() string {
+	switch w {
+	case WithdrawalFixed:
+		return "Fixed"
+	case WithdrawalGuytonKlinger:
+		return "Guyton-Klinger Guardrails"
+	case WithdrawalVanguard:
+		return "Vanguard Dynamic Spending"
+	case WithdrawalVPW:
+		return "Variable Percentage Withdrawal"
+	case WithdrawalConstantPercent:
+		return "Constant Percentage"
+	default:
+		return "Unknown"
+	}
+}
+
+// ID returns the config identifier for the rule (used by withdrawal_rule)
+func (w WithdrawalRule) ID() string {
+	switch w {
+	case WithdrawalGuytonKlinger:
+		return "guyton_klinger"
+	case WithdrawalVanguard:
+		return "vanguard"
+	case WithdrawalVPW:
+		return "vpw"
+	case WithdrawalConstantPercent:
+		return "constant_percent"
+	default:
+		return "fixed"
+	}
+}
+
+// ShortName returns a compact suffix for strategy names
+func (w WithdrawalRule) ShortName() string {
+	switch w {
+	case WithdrawalGuytonKlinger:
+		return "GR"
+	case WithdrawalVanguard:
+		return "VD"
+	case WithdrawalVPW:
+		return "VPW"
+	case WithdrawalConstantPercent:
+		return "CP"
+	default:
+		return ""
+	}
+}
+
+// ParseWithdrawalRule converts a config identifier to a WithdrawalRule
+// Unknown identifiers fall back to WithdrawalFixed
+func ParseWithdrawalRule(id string) WithdrawalRule {
+	for _, w := range []WithdrawalRule{WithdrawalGuytonKlinger, WithdrawalVanguard, WithdrawalVPW, WithdrawalConstantPercent} {
+		if w.ID() == id {
+			return w
+		}
+	}
+	return WithdrawalFixed
+}
+
 // PermutationMode controls how many strategy combinations to generate
 type PermutationMode int
 
@@ -202,6 +282,7 @@ const (
 	ModeStandard                              // ~100 combinations - common variations
 	ModeThorough                              // ~250 combinations - thorough analysis
 	ModeComprehensive                         // ~4000 combinations - all valid combinations
+	ModeAdaptive                              // All valid combinations, searched adaptively (see AdaptiveSearch)
 )
 
 func (m PermutationMode) String() string {
@@ -214,6 +295,8 @@ func (m PermutationMode) String() string {
 		return "Thorough"
 	case ModeComprehensive:
 		return "Comprehensive"
+	case ModeAdaptive:
+		return "Adaptive"
 	default:
 		return "Unknown"
 	}
@@ -230,6 +313,8 @@ const (
 	FactorISAToSIPP         FactorID = "isa_to_sipp"
 	FactorGuardrails        FactorID = "guardrails"
 	FactorStatePensionDefer FactorID = "state_pension_defer"
+	FactorTrancheTiming     FactorID = "tranche_timing"
+	FactorTaxFreeCash       FactorID = "tax_free_cash"
 )
 
 // FactorValue represents one possible value for a factor
@@ -248,6 +333,8 @@ type Factor struct {
 	Values         []FactorValue  // Available values for this factor
 	DefaultValueID string         // ID of the default value
 	DependsOn      []FactorID     // Other factors this depends on
+	UserDefined    bool           // Declared in config (strategy.factors); varied in every mode
+	Person         string         // Person a per-person factor applies to; varied only in adaptive mode
 	// ApplicableFunc is set by the registry at runtime
 }
 
@@ -270,32 +357,90 @@ type SimulationParams struct {
 	// Existing core factors
 	CrystallisationStrategy Strategy
 	DrawdownOrder           DrawdownOrder
-	EarlyMortgagePayoff     bool           // Deprecated: use MortgageOpt instead
 	MortgageOpt             MortgageOption // How mortgage is handled
 	MaximizeCoupleISA       bool           // For PensionToISA: fill both people's ISA allowances from one pension
 	ISAToSIPPEnabled        bool           // Enable ISA to SIPP transfers while working (pre-retirement optimization)
 
 	// NEW: Dynamic adjustment strategies
-	GuardrailsEnabled bool // Enable Guyton-Klinger guardrails
+	GuardrailsEnabled bool           // Deprecated: use WithdrawalRule instead (true = Guyton-Klinger)
+	WithdrawalRule    WithdrawalRule // Dynamic withdrawal rule (overrides config if set)
 
 	// NEW: State pension deferral (applies to all people)
 	StatePensionDeferYears int // Years to defer state pension (0, 2, or 5)
 
+	// When planned tranches are crystallised (for anyone using TrancheCrystallisation)
+	TrancheTiming TrancheTiming
+
+	// Leave the planned tax-free cash (tax_free_cash) in the pension instead of taking it
+	KeepTaxFreeCash bool
+
+	// Config settings changed by user-defined factors (strategy.factors), in declaration order
+	ConfigOverrides []ConfigOverride
+
+	// Per-person crystallisation and drawdown choices by name (people not listed follow the household)
+	PersonStrategies map[string]PersonStrategy
+
+	// Year-by-year plan for the Optimal drawdown order (built on first use if nil)
+	OptimalPlan *OptimalPlan
+
 	// Metadata for tracking and filtering
 	SourceCombo *StrategyCombo // Original combo this was generated from
 }
 
+// GetWithdrawalRule returns the dynamic withdrawal rule, honouring the deprecated GuardrailsEnabled flag
+func (sp SimulationParams) GetWithdrawalRule() WithdrawalRule {
+	if sp.WithdrawalRule == WithdrawalFixed && sp.GuardrailsEnabled {
+		return WithdrawalGuytonKlinger
+	}
+	return sp.WithdrawalRule
+}
+
+// PaysOffMortgageEarly returns true if the mortgage is cleared before its normal end year
+// (at the early payoff year, or from tax-free cash)
+func (sp SimulationParams) PaysOffMortgageEarly() bool {
+	return sp.MortgageOpt == MortgageEarly || sp.MortgageOpt == PCLSMortgagePayoff
+}
+
+// withdrawalRuleLabel returns the label used for the rule in strategy names
+func (sp SimulationParams) withdrawalRuleLabel() string {
+	switch sp.GetWithdrawalRule() {
+	case WithdrawalGuytonKlinger:
+		return "Guardrails"
+	case WithdrawalVanguard:
+		return "Vanguard"
+	case WithdrawalVPW:
+		return "VPW"
+	case WithdrawalConstantPercent:
+		return "Const%"
+	default:
+		return ""
+	}
+}
+
 func (sp SimulationParams) String() string {
 	base := sp.DrawdownOrder.String()
 	if sp.ISAToSIPPEnabled {
 		base = "ISA→SIPP " + base
 	}
-	if sp.GuardrailsEnabled {
-		base = base + " +Guardrails"
+	if sp.usesTranches() {
+		base = base + " +" + sp.TrancheTiming.Description()
+	}
+	for _, name := range sp.personStrategyNames() {
+		ps := sp.PersonStrategies[name]
+		base = base + " +" + name + " " + ps.crystallisationPrefix("UFPLS ", "Tranche ") + ps.Drawdown.String()
+	}
+	if label := sp.withdrawalRuleLabel(); label != "" {
+		base = base + " +" + label
 	}
 	if sp.StatePensionDeferYears > 0 {
 		base = base + fmt.Sprintf(" +Defer%dy", sp.StatePensionDeferYears)
 	}
+	if sp.KeepTaxFreeCash {
+		base = base + " +Keep Tax-Free Cash"
+	}
+	for _, o := range sp.ConfigOverrides {
+		base = base + " +" + o.ShortName
+	}
 	switch sp.MortgageOpt {
 	case MortgageEarly:
 		return base + " (Early Payoff)"
@@ -309,31 +454,13 @@ func (sp SimulationParams) String() string {
 }
 
 func (sp SimulationParams) ShortName() string {
-	var orderShort string
-	switch sp.DrawdownOrder {
-	case SavingsFirst:
-		orderShort = "ISAFirst"
-	case PensionFirst:
-		orderShort = "PenFirst"
-	case TaxOptimized:
-		orderShort = "TaxOpt"
-	case PensionToISA:
-		orderShort = "Combined"
-	case PensionToISAProactive:
-		orderShort = "Combined+"
-	case PensionOnly:
-		orderShort = "PenOnly"
-	case FillBasicRate:
-		orderShort = "FillBasic"
-	case StatePensionBridge:
-		orderShort = "SPBridge"
-	default:
-		orderShort = "Unknown"
-	}
+	orderShort := drawdownShortName(sp.DrawdownOrder)
 
-	// Prefix with U/ for UFPLS
+	// Prefix with U/ for UFPLS, T/ (or the tranche timing) for tranches
 	if sp.CrystallisationStrategy == UFPLSStrategy {
 		orderShort = "U/" + orderShort
+	} else if sp.CrystallisationStrategy == TrancheCrystallisation {
+		orderShort = sp.TrancheTiming.ShortName() + "/" + orderShort
 	}
 
 	// Prefix with I2S/ for ISA to SIPP
@@ -341,13 +468,25 @@ func (sp SimulationParams) ShortName() string {
 		orderShort = "I2S/" + orderShort
 	}
 
+	// Per-person choices that differ from the household, e.g. Delphine=U+ISAFirst
+	for _, name := range sp.personStrategyNames() {
+		ps := sp.PersonStrategies[name]
+		orderShort = orderShort + "/" + name + "=" + ps.crystallisationPrefix("U+", "T+") + drawdownShortName(ps.Drawdown)
+	}
+
 	// Add new factor suffixes
-	if sp.GuardrailsEnabled {
-		orderShort = orderShort + "/GR"
+	if rule := sp.GetWithdrawalRule(); rule != WithdrawalFixed {
+		orderShort = orderShort + "/" + rule.ShortName()
 	}
 	if sp.StatePensionDeferYears > 0 {
 		orderShort = orderShort + fmt.Sprintf("/D%d", sp.StatePensionDeferYears)
 	}
+	if sp.KeepTaxFreeCash {
+		orderShort = orderShort + "/KeepTFC"
+	}
+	for _, o := range sp.ConfigOverrides {
+		orderShort = orderShort + "/" + o.ShortName
+	}
 
 	switch sp.MortgageOpt {
 	case MortgageEarly:
@@ -363,27 +502,7 @@ func (sp SimulationParams) ShortName() string {
 
 // DescriptiveName returns a human-readable description of the strategy
 func (sp SimulationParams) DescriptiveName(mortgagePayoffYear int) string {
-	var drawdownDesc string
-	switch sp.DrawdownOrder {
-	case SavingsFirst:
-		drawdownDesc = "ISA First, Then Pension"
-	case PensionFirst:
-		drawdownDesc = "Pension First, Then ISA"
-	case TaxOptimized:
-		drawdownDesc = "Tax Optimized Withdrawals"
-	case PensionToISA:
-		drawdownDesc = "Combined ISA And Pension"
-	case PensionToISAProactive:
-		drawdownDesc = "Combined ISA And Pension (Proactive)"
-	case PensionOnly:
-		drawdownDesc = "Pension Only"
-	case FillBasicRate:
-		drawdownDesc = "Fill Basic Rate Band"
-	case StatePensionBridge:
-		drawdownDesc = "State Pension Bridge"
-	default:
-		drawdownDesc = "Unknown Strategy"
-	}
+	drawdownDesc := drawdownDescription(sp.DrawdownOrder)
 
 	// Add crystallisation type if UFPLS
 	if sp.CrystallisationStrategy == UFPLSStrategy {
@@ -397,12 +516,22 @@ func (sp SimulationParams) DescriptiveName(mortgagePayoffYear int) string {
 
 	// Add new factor descriptions
 	var extras []string
-	if sp.GuardrailsEnabled {
-		extras = append(extras, "Guardrails")
+	if sp.usesTranches() {
+		extras = append(extras, sp.TrancheTiming.Description())
+	}
+	extras = append(extras, sp.personStrategyDescriptions()...)
+	if label := sp.withdrawalRuleLabel(); label != "" {
+		extras = append(extras, label)
 	}
 	if sp.StatePensionDeferYears > 0 {
 		extras = append(extras, fmt.Sprintf("SP Defer %dy", sp.StatePensionDeferYears))
 	}
+	if sp.KeepTaxFreeCash {
+		extras = append(extras, "Tax-Free Cash Kept")
+	}
+	for _, o := range sp.ConfigOverrides {
+		extras = append(extras, o.Description)
+	}
 	if len(extras) > 0 {
 		drawdownDesc = drawdownDesc + " (" + joinStrings(extras, ", ") + ")"
 	}
@@ -429,6 +558,58 @@ func (sp SimulationParams) DescriptiveName(mortgagePayoffYear int) string {
 	return drawdownDesc + ", " + mortgageDesc
 }
 
+// drawdownShortName returns the label used for a drawdown order in short strategy names
+func drawdownShortName(order DrawdownOrder) string {
+	switch order {
+	case SavingsFirst:
+		return "ISAFirst"
+	case PensionFirst:
+		return "PenFirst"
+	case TaxOptimized:
+		return "TaxOpt"
+	case PensionToISA:
+		return "Combined"
+	case PensionToISAProactive:
+		return "Combined+"
+	case PensionOnly:
+		return "PenOnly"
+	case FillBasicRate:
+		return "FillBasic"
+	case StatePensionBridge:
+		return "SPBridge"
+	case Optimal:
+		return "Optimal"
+	default:
+		return "Unknown"
+	}
+}
+
+// drawdownDescription returns the label used for a drawdown order in descriptive strategy names
+func drawdownDescription(order DrawdownOrder) string {
+	switch order {
+	case SavingsFirst:
+		return "ISA First, Then Pension"
+	case PensionFirst:
+		return "Pension First, Then ISA"
+	case TaxOptimized:
+		return "Tax Optimized Withdrawals"
+	case PensionToISA:
+		return "Combined ISA And Pension"
+	case PensionToISAProactive:
+		return "Combined ISA And Pension (Proactive)"
+	case PensionOnly:
+		return "Pension Only"
+	case FillBasicRate:
+		return "Fill Basic Rate Band"
+	case StatePensionBridge:
+		return "State Pension Bridge"
+	case Optimal:
+		return "Optimal Withdrawal Plan"
+	default:
+		return "Unknown Strategy"
+	}
+}
+
 // joinStrings joins strings with a separator (helper for DescriptiveName)
 func joinStrings(strs []string, sep string) string {
 	if len(strs) == 0 {
@@ -450,12 +631,21 @@ type Person struct {
 	RetirementAge      int    // Age when income requirements start (stop working)
 	RetirementTaxYear  int    // Tax year when retirement begins (calculated from RetirementDate or RetirementAge)
 	PensionAccessAge   int    // Age when DC pension can be accessed (may be later than RetirementAge)
+	ProtectedPensionAge  int    // Protected pension age below the minimum pension age (0 = none)
 	StatePensionAge    int
 	TaxFreeSavings    float64 // ISA (includes crystallised tax-free lump sums)
 	UncrystallisedPot float64 // Pension not yet accessed
 	CrystallisedPot   float64 // Taxable pension pot
 	PCLSTaken         bool    // True if 25% PCLS lump sum was taken (no further 25% tax-free)
 	ISAAnnualLimit    float64 // Per-person ISA annual contribution limit
+	ISASubscribed     float64 // Paid into the ISA this tax year, from every source
+	GIA               float64 // Savings held outside the ISA, growth taxed; spent first
+	SavedToGIA        float64 // Saved this tax year over the ISA allowance, into the GIA
+	ISAFromGIA        float64 // Moved from the GIA into the ISA at the start of this tax year
+	LISA              float64 // Lifetime ISA, moved into TaxFreeSavings at 60
+	LISAContribution  float64 // Paid into the Lifetime ISA each year from surplus work income
+	LumpSumAllowance     float64 // Most tax-free cash that can be taken from pensions (0 = no limit)
+	LumpSumAllowanceUsed float64 // Tax-free cash taken so far
 
 	// DB Pension Configuration
 	DBPensionAmount        float64 // Annual DB pension at normal retirement age
@@ -491,6 +681,14 @@ type Person struct {
 	EmployerContribution    float64 // Annual employer pension contribution (reduces available allowance)
 	ISAToSIPPMaxPercent     float64 // Max % of remaining allowance to use (default 100%)
 	ISAToSIPPPreserveMonths int     // Months of expenses to preserve in ISA
+
+	// Extra saving before retirement
+	ExtraMonthlySaving   float64 // Additional saving per month until retirement
+	ExtraSavingToPension bool    // Extra saving goes into the pension (with basic-rate relief) instead of the ISA
+
+	// Later-life care
+	CareStartAge   int     // Age when long-term care starts (0 = no care)
+	CareAnnualCost float64 // Annual care cost in today's money
 }
 
 // Clone creates a deep copy of a Person
@@ -503,12 +701,21 @@ func (p *Person) Clone() *Person {
 		RetirementAge:     p.RetirementAge,
 		RetirementTaxYear: p.RetirementTaxYear,
 		PensionAccessAge:  p.PensionAccessAge,
+		ProtectedPensionAge:  p.ProtectedPensionAge,
 		StatePensionAge:   p.StatePensionAge,
 		TaxFreeSavings:    p.TaxFreeSavings,
 		UncrystallisedPot: p.UncrystallisedPot,
 		CrystallisedPot:   p.CrystallisedPot,
 		PCLSTaken:         p.PCLSTaken,
 		ISAAnnualLimit:    p.ISAAnnualLimit,
+		ISASubscribed:     p.ISASubscribed,
+		GIA:               p.GIA,
+		SavedToGIA:        p.SavedToGIA,
+		ISAFromGIA:        p.ISAFromGIA,
+		LISA:              p.LISA,
+		LISAContribution:  p.LISAContribution,
+		LumpSumAllowance:     p.LumpSumAllowance,
+		LumpSumAllowanceUsed: p.LumpSumAllowanceUsed,
 		// DB Pension
 		DBPensionAmount:        p.DBPensionAmount,
 		DBPensionStartAge:      p.DBPensionStartAge,
@@ -538,12 +745,18 @@ func (p *Person) Clone() *Person {
 		EmployerContribution:    p.EmployerContribution,
 		ISAToSIPPMaxPercent:     p.ISAToSIPPMaxPercent,
 		ISAToSIPPPreserveMonths: p.ISAToSIPPPreserveMonths,
+		// Extra saving
+		ExtraMonthlySaving:   p.ExtraMonthlySaving,
+		ExtraSavingToPension: p.ExtraSavingToPension,
+		// Later-life care
+		CareStartAge:   p.CareStartAge,
+		CareAnnualCost: p.CareAnnualCost,
 	}
 }
 
-// AvailableISA returns the ISA balance available for withdrawal after preserving emergency fund
+// AvailableISA returns the savings (GIA and ISA) available for withdrawal after preserving emergency fund
 func (p *Person) AvailableISA() float64 {
-	available := p.TaxFreeSavings - p.EmergencyFundMinimum
+	available := p.Savings() - p.EmergencyFundMinimum
 	if available < 0 {
 		return 0
 	}
@@ -557,11 +770,12 @@ func (p *Person) TotalPension() float64 {
 
 // TotalWealth returns total assets
 func (p *Person) TotalWealth() float64 {
-	return p.TaxFreeSavings + p.TotalPension()
+	return p.Savings() + p.TotalPension() + p.LISA
 }
 
 // CanAccessPension returns true if the person can access their DC pension during this tax year
-// Uses PensionAccessAge (minimum pension age) not RetirementAge (when income needs start)
+// Uses PensionAccessAge not RetirementAge (when income needs start), and never before the
+// minimum pension age in that tax year (see MinimumPensionAge)
 // year is the tax year start (e.g., 2026 for tax year 2026/27)
 func (p *Person) CanAccessPension(year int) bool {
 	// Guard against invalid birth year (would cause incorrect age calculation)
@@ -580,7 +794,7 @@ func (p *Person) CanAccessPension(year int) bool {
 	if accessAge <= 0 {
 		accessAge = p.RetirementAge
 	}
-	return age >= accessAge
+	return age >= accessAge && age >= p.MinimumPensionAge(year)
 }
 
 // EffectiveStatePensionAge returns the age at which state pension starts (after any deferral)
@@ -731,7 +945,7 @@ func (p *Person) IsReceivingPartTimeIncome(year int) bool {
 // IsWorking returns true if the person is still employed (before retirement date/age)
 // year is the tax year start (e.g., 2026 for tax year 2026/27)
 func (p *Person) IsWorking(year int) bool {
-	if p.WorkIncome <= 0 && p.WorkIncomeNet <= 0 {
+	if p.GetAnnualWorkIncome() <= 0 {
 		return false
 	}
 	// Use RetirementTaxYear if set (calculated from RetirementDate or RetirementAge)
@@ -748,6 +962,32 @@ func (p *Person) IsWorking(year int) bool {
 	return age < p.RetirementAge
 }
 
+// pensionBasicRateRelief is the tax relief added at source to net personal pension contributions
+const pensionBasicRateRelief = 0.20
+
+// IsSaving returns true if the person is making extra saving in this tax year (before retirement)
+func (p *Person) IsSaving(year int) bool {
+	return p.ExtraMonthlySaving > 0 && year < p.RetirementTaxYear
+}
+
+// AddSaving adds a net saving to the person's ISA, or to their pension grossed up with basic-rate relief
+// Pension contributions are capped at the annual allowance and the net saving over the cap goes to the ISA
+// Returns the gross amount added to the pension and the amount saved tax-free
+func (p *Person) AddSaving(net float64) (toPension, toISA float64) {
+	if !p.ExtraSavingToPension {
+		p.SaveTaxFree(net)
+		return 0, net
+	}
+	gross := net / (1 - pensionBasicRateRelief)
+	if allowance := p.PensionAnnualAllowance - p.EmployerContribution; allowance > 0 && gross > allowance {
+		gross = allowance
+		toISA = net - gross*(1-pensionBasicRateRelief)
+		p.SaveTaxFree(toISA)
+	}
+	p.UncrystallisedPot += gross
+	return gross, toISA
+}
+
 // GetAnnualWorkIncome returns the effective annual work income
 // Prefers WorkIncomeNet * 12 (take-home) if set, otherwise falls back to WorkIncome (gross)
 // Note: When using WorkIncomeNet, the returned value is already net of tax/NI
@@ -758,11 +998,72 @@ func (p *Person) GetAnnualWorkIncome() float64 {
 	return p.WorkIncome // Legacy: gross annual salary
 }
 
+// HasStartedCare returns true if the person's care has started by this tax year
+// year is the tax year start (e.g., 2026 for tax year 2026/27)
+func (p *Person) HasStartedCare(year int) bool {
+	if p.CareStartAge <= 0 {
+		return false
+	}
+	// Guard against invalid birth year
+	if p.BirthYear < 1900 || p.BirthYear > year {
+		return false
+	}
+	var age int
+	if p.BirthDate != "" {
+		age = GetAgeInTaxYear(p.BirthDate, year)
+	} else {
+		age = year - p.BirthYear
+	}
+	return age >= p.CareStartAge
+}
+
+// IsInCare returns true if the person is in a care episode during this tax year
+// The episode lasts durationYears from CareStartAge
+func (p *Person) IsInCare(year, durationYears int) bool {
+	if !p.HasStartedCare(year) {
+		return false
+	}
+	var age int
+	if p.BirthDate != "" {
+		age = GetAgeInTaxYear(p.BirthDate, year)
+	} else {
+		age = year - p.BirthYear
+	}
+	return age < p.CareStartAge+durationYears
+}
+
 // PersonBalances holds end-of-year balances for a person
 type PersonBalances struct {
 	TaxFreeSavings    float64
 	UncrystallisedPot float64
 	CrystallisedPot   float64
+	GIA               float64 // Savings held outside the ISA
+	LumpSumAllowanceUsed float64 // Tax-free cash taken from pensions so far
+	// ISA subscriptions in the tax year
+	ISASubscribed    float64 // Paid into the ISA
+	ISAAllowanceLeft float64 // ISA allowance not used
+	SavedToGIA       float64 // Saved over the allowance, into the GIA
+	LISA             float64 // Lifetime ISA not yet accessible
+}
+
+// Savings returns the ISA and GIA together
+func (b PersonBalances) Savings() float64 {
+	return b.TaxFreeSavings + b.GIA
+}
+
+// Balances returns the person's balances and this tax year's ISA subscriptions
+func (p *Person) Balances() PersonBalances {
+	return PersonBalances{
+		TaxFreeSavings:       p.TaxFreeSavings,
+		UncrystallisedPot:    p.UncrystallisedPot,
+		CrystallisedPot:      p.CrystallisedPot,
+		GIA:                  p.GIA,
+		LumpSumAllowanceUsed: p.LumpSumAllowanceUsed,
+		ISASubscribed:        p.ISASubscribed,
+		ISAAllowanceLeft:     p.ISAAllowanceLeft(),
+		SavedToGIA:           p.SavedToGIA,
+		LISA:                 p.LISA,
+	}
 }
 
 // WithdrawalBreakdown shows where money came from and where it went
@@ -774,6 +1075,8 @@ type WithdrawalBreakdown struct {
 	TotalTaxable       float64
 	ISADeposits        map[string]float64 // Per person - excess deposited to ISA
 	TotalISADeposits   float64
+	GIADeposits        map[string]float64 // Per person - excess over the ISA allowance, saved in the GIA
+	TotalGIADeposits   float64
 }
 
 // YearState holds the complete state for a simulation tax year
@@ -795,18 +1098,22 @@ type YearState struct {
 	Withdrawals          WithdrawalBreakdown
 	TaxByPerson          map[string]float64
 	TotalTaxPaid         float64
+	GIATax               float64 // Tax on GIA growth, taken from the GIAs (not part of TotalTaxPaid, which is paid from income)
 	NetIncomeReceived    float64 // Actual spendable income (withdrawals - tax + state pension + DB pension)
 	EndBalances          map[string]PersonBalances
 	TotalBalance         float64
 	// Guardrails tracking
 	GuardrailsTriggered   int     // -1 = reduced, 0 = no change, 1 = increased
 	GuardrailsAdjusted    float64 // The adjusted income amount (if guardrails enabled)
+	// Essential vs discretionary spending
+	EssentialIncome  float64 // Essential spending floor within RequiredIncome (never cut)
+	DiscretionaryCut float64 // Discretionary spending cut below plan (guardrails or insufficient funds)
 	PartTimeIncome        float64 // Income from part-time work (phased retirement)
 	// Pre-retirement work income
 	WorkIncomeByPerson    map[string]float64 // Work income per person (before retirement)
 	TotalWorkIncome       float64            // Combined work income from both people
-	ISAContributions      map[string]float64 // Surplus work income added to ISA per person
-	TotalISAContributions float64            // Total surplus added to ISA
+	ISAContributions      map[string]float64 // Surplus work income saved per person (ISA, then GIA)
+	TotalISAContributions float64            // Total surplus saved
 	// Tax band tracking
 	PersonalAllowance    float64 // Inflated personal allowance for this year
 	BasicRateLimit       float64 // Inflated basic rate limit for this year
@@ -818,6 +1125,27 @@ type YearState struct {
 	ISAToSIPPTaxRelief    map[string]float64 // Tax relief received per person
 	TotalISAToSIPP        float64            // Total net transferred from ISA
 	TotalISAToSIPPRelief  float64            // Total tax relief received
+	// Extra saving before retirement
+	ExtraSavingByPerson map[string]float64 // Extra saving added per person (gross for pensions)
+	TotalExtraSaving    float64            // Total extra saving added
+	// Planned crystallisation tranches (TrancheCrystallisation)
+	TrancheCrystallised map[string]float64 // Pension crystallised in a tranche per person
+	TrancheToGIA        map[string]float64 // Tranche tax-free cash over the ISA allowance, kept in the GIA
+	// Pension rules (see CheckCompliance)
+	PensionContributions map[string]float64 // Gross personal pension contributions per person (ISA to SIPP, extra saving)
+	TaxFreeCashTaken     map[string]float64 // Tax-free cash taken from pensions per person, however it was taken
+	// Lifetime ISA
+	LISAContributions map[string]float64 // Paid into the Lifetime ISA per person, from surplus work income
+	LISABonus         map[string]float64 // Government bonus on the contributions per person
+	LISAPenalty       map[string]float64 // Penalty on withdrawals before 60 per person
+	// Planned tax-free cash (tax_free_cash and PCLS mortgage payoff)
+	TaxFreeCash   []TaxFreeCashUse // Tax-free cash taken this year and what it paid for
+	OneOffExpense float64          // One-off expenses planned for this year
+	// Later-life care
+	CareCostByPerson    map[string]float64 // Care cost paid by the household per person in care
+	CareCost            float64            // Total care cost paid by the household
+	CareFundedByCouncil float64            // Care cost met by the local authority (means-tested)
+	HomeSaleProceeds    float64            // Net proceeds from selling the home (saved in ISAs, then GIAs)
 }
 
 // SimulationResult holds the complete results of a simulation run
@@ -829,6 +1157,20 @@ type SimulationResult struct {
 	RanOutOfMoney  bool
 	RanOutYear     int
 	FinalBalances  map[string]PersonBalances
+	TotalCareCost  float64 // Care costs paid by the household across all years
+	HomeSaleYear   int     // Tax year the home was sold to fund care (0 = not sold)
+	// Discretionary cuts are reported separately from running out of money
+	SpendingYears         int // Years with planned spending (retired)
+	DiscretionaryCutYears int // Years where discretionary spending was cut
+	ResultKey             string // Key the result is cached under (empty if not cached)
+}
+
+// CutYearsPercent returns the proportion of spending years with discretionary cuts (0-100)
+func (r *SimulationResult) CutYearsPercent() float64 {
+	if r.SpendingYears == 0 {
+		return 0
+	}
+	return float64(r.DiscretionaryCutYears) / float64(r.SpendingYears) * 100
 }
 
 // NewWithdrawalBreakdown creates a new initialized WithdrawalBreakdown
@@ -838,7 +1180,31 @@ func NewWithdrawalBreakdown() WithdrawalBreakdown {
 		TaxFreeFromPension: make(map[string]float64),
 		TaxableFromPension: make(map[string]float64),
 		ISADeposits:        make(map[string]float64),
+		GIADeposits:        make(map[string]float64),
+	}
+}
+
+// Add adds the withdrawals in other to b
+func (b *WithdrawalBreakdown) Add(other WithdrawalBreakdown) {
+	for name, amount := range other.TaxFreeFromISA {
+		b.TaxFreeFromISA[name] += amount
+	}
+	for name, amount := range other.TaxFreeFromPension {
+		b.TaxFreeFromPension[name] += amount
+	}
+	for name, amount := range other.TaxableFromPension {
+		b.TaxableFromPension[name] += amount
+	}
+	for name, amount := range other.ISADeposits {
+		b.ISADeposits[name] += amount
+	}
+	b.TotalTaxFree += other.TotalTaxFree
+	b.TotalTaxable += other.TotalTaxable
+	for name, amount := range other.GIADeposits {
+		b.GIADeposits[name] += amount
 	}
+	b.TotalISADeposits += other.TotalISADeposits
+	b.TotalGIADeposits += other.TotalGIADeposits
 }
 
 // NewYearState creates a new initialized YearState for a tax year
@@ -857,5 +1223,14 @@ func NewYearState(year int) YearState {
 		ISAContributions:     make(map[string]float64),
 		ISAToSIPPByPerson:    make(map[string]float64),
 		ISAToSIPPTaxRelief:   make(map[string]float64),
+		CareCostByPerson:     make(map[string]float64),
+		ExtraSavingByPerson:  make(map[string]float64),
+		TrancheCrystallised:  make(map[string]float64),
+		TrancheToGIA:         make(map[string]float64),
+		PensionContributions: make(map[string]float64),
+		TaxFreeCashTaken:     make(map[string]float64),
+		LISAContributions:    make(map[string]float64),
+		LISABonus:            make(map[string]float64),
+		LISAPenalty:          make(map[string]float64),
 	}
 }
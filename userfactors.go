@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigOverride is the value of a user-defined factor: a config setting changed before simulating
+type ConfigOverride struct {
+	Factor      FactorID
+	Order       int    // Position of the factor in strategy.factors
+	Path        string // Setting changed, e.g. people.James.retirement_date
+	Value       string // New value, as written in the config
+	ShortName   string // Label in short strategy names, e.g. "ret=2027-01-01"
+	Description string // Label in descriptive names, e.g. "Retirement Date 2027-01-01"
+}
+
+// RegisterUserFactors adds the factors declared in config (strategy.factors)
+// Factors that cannot be applied to config are skipped and reported in the returned errors
+func (r *FactorRegistry) RegisterUserFactors(config *Config) ConfigErrors {
+	var errs ConfigErrors
+	for i, uf := range config.Strategy.Factors {
+		f, err := newUserFactor(i, uf, config)
+		if err == nil && r.Get(f.ID) != nil {
+			err = fmt.Errorf("duplicate factor %q", uf.ID)
+		}
+		if err != nil {
+			errs = append(errs, ConfigError{Path: fmt.Sprintf("strategy.factors[%d]", i), Message: err.Error()})
+			continue
+		}
+		r.Register(f)
+	}
+	return errs
+}
+
+// newUserFactor builds the factor declared by uf, checking each value can be applied to config
+func newUserFactor(order int, uf UserFactorConfig, config *Config) (*Factor, error) {
+	if uf.ID == "" || strings.ContainsAny(uf.ID, " =!|") {
+		return nil, fmt.Errorf("invalid id %q", uf.ID)
+	}
+	if uf.Path == "" {
+		return nil, fmt.Errorf("%s: no path", uf.ID)
+	}
+	if len(uf.Values) == 0 {
+		return nil, fmt.Errorf("%s: no values", uf.ID)
+	}
+	if len(uf.Labels) > 0 && len(uf.Labels) != len(uf.Values) {
+		return nil, fmt.Errorf("%s: %d labels for %d values", uf.ID, len(uf.Labels), len(uf.Values))
+	}
+	name := uf.Name
+	if name == "" {
+		name = uf.ID
+	}
+
+	f := &Factor{
+		ID:          FactorID(uf.ID),
+		Name:        name,
+		Description: "Sets " + uf.Path,
+		UserDefined: true,
+	}
+	for i, v := range uf.Values {
+		text := factorValueText(v)
+		check := *config
+		if err := setConfigPath(&check, uf.Path, text); err != nil {
+			return nil, fmt.Errorf("%s: cannot set %s to %s: %w", uf.ID, uf.Path, text, err)
+		}
+		label := uf.ID + "=" + text
+		if len(uf.Labels) > 0 {
+			label = uf.Labels[i]
+		}
+		f.Values = append(f.Values, FactorValue{
+			ID:        text,
+			Name:      text,
+			ShortName: label,
+			Value: ConfigOverride{
+				Factor:      f.ID,
+				Order:       order,
+				Path:        uf.Path,
+				Value:       text,
+				ShortName:   label,
+				Description: name + " " + text,
+			},
+		})
+	}
+	f.DefaultValueID = f.Values[0].ID
+	return f, nil
+}
+
+// factorValueText returns a factor value as written in the config
+func factorValueText(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		// YAML reads unquoted dates as timestamps
+		return t.Format("2006-01-02")
+	}
+	return fmt.Sprint(v)
+}
+
+// configOverrides returns the user-defined factor values in combo, in declaration order
+func configOverrides(combo StrategyCombo) []ConfigOverride {
+	var overrides []ConfigOverride
+	for _, v := range combo.Values {
+		if o, ok := v.Value.(ConfigOverride); ok {
+			overrides = append(overrides, o)
+		}
+	}
+	sort.Slice(overrides, func(a, b int) bool { return overrides[a].Order < overrides[b].Order })
+	return overrides
+}
+
+// setConfigPath sets the setting at path in config to value
+// Path segments are YAML keys; list items are chosen by index, by name, or all with "*"
+// Lists and pointers on the path are copied first, so a shallow copy of a config can be changed
+// without changing the config it was copied from
+func setConfigPath(config *Config, path, value string) error {
+	return setPathValue(reflect.ValueOf(config).Elem(), strings.Split(path, "."), value)
+}
+
+func setPathValue(v reflect.Value, segments []string, value string) error {
+	if len(segments) == 0 {
+		return setLeafValue(v, value)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		copied := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			copied.Elem().Set(v.Elem())
+		}
+		v.Set(copied)
+		return setPathValue(v.Elem(), segments, value)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if yamlName(v.Type().Field(i)) == segments[0] {
+				return setPathValue(v.Field(i), segments[1:], value)
+			}
+		}
+		return fmt.Errorf("unknown setting %q", segments[0])
+
+	case reflect.Slice:
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(copied, v)
+		v.Set(copied)
+		found := false
+		for i := 0; i < v.Len(); i++ {
+			if listItemMatches(v.Index(i), i, segments[0]) {
+				if err := setPathValue(v.Index(i), segments[1:], value); err != nil {
+					return err
+				}
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("no list item %q", segments[0])
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("%q is not a section", segments[0])
+	}
+}
+
+// setLeafValue sets v from value, read as YAML for anything but text
+func setLeafValue(v reflect.Value, value string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(value)
+		return nil
+	}
+	if v.Kind() == reflect.Struct || v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		return fmt.Errorf("a section, not a setting")
+	}
+	// Decode into a new value so nothing the setting pointed to is changed
+	decoded := reflect.New(v.Type())
+	if err := yaml.Unmarshal([]byte(value), decoded.Interface()); err != nil {
+		return err
+	}
+	v.Set(decoded.Elem())
+	return nil
+}
+
+// yamlName returns the YAML key of a config field ("" if it is not saved)
+func yamlName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	return name
+}
+
+// listItemMatches returns true if the list item at index i is chosen by segment:
+// "*", its index, or its name
+func listItemMatches(item reflect.Value, i int, segment string) bool {
+	if segment == "*" || segment == strconv.Itoa(i) {
+		return true
+	}
+	if item.Kind() == reflect.Struct {
+		if name := item.FieldByName("Name"); name.IsValid() && name.Kind() == reflect.String {
+			return name.String() == segment
+		}
+	}
+	return false
+}
+
+// UserConstraints parses the rules declared in config (strategy.constraints) against the
+// factors in registry
+func UserConstraints(config *Config, registry *FactorRegistry) ([]Constraint, ConfigErrors) {
+	var constraints []Constraint
+	var errs ConfigErrors
+	for i, rule := range config.Strategy.Constraints {
+		c, err := ParseConstraint(rule, registry)
+		if err != nil {
+			errs = append(errs, ConfigError{Path: fmt.Sprintf("strategy.constraints[%d]", i), Message: err.Error()})
+			continue
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, errs
+}
+
+// ruleCondition is one factor=value test in a constraint rule
+type ruleCondition struct {
+	factor FactorID
+	values []string // Any of these value IDs
+	negate bool     // factor!=value
+}
+
+// holds returns whether the condition holds for combo, or ifAbsent if combo lacks the factor
+func (c ruleCondition) holds(combo StrategyCombo, ifAbsent bool) bool {
+	v, ok := combo.Values[c.factor]
+	if !ok {
+		return ifAbsent
+	}
+	return containsString(c.values, v.ID) != c.negate
+}
+
+// ParseConstraint parses a rule such as "if drawdown=pension_only then maximize_couple_isa=off"
+// Conditions are factor=value or factor!=value, with alternatives as a|b, joined by "and"
+// A combination breaks the rule if every "if" condition holds and a "then" condition does not;
+// conditions on factors a combination does not have never hold after "if" and always hold after "then"
+func ParseConstraint(rule string, registry *FactorRegistry) (Constraint, error) {
+	fields := strings.Fields(rule)
+	then := -1
+	for i, f := range fields {
+		if strings.EqualFold(f, "then") {
+			then = i
+			break
+		}
+	}
+	if len(fields) < 4 || !strings.EqualFold(fields[0], "if") || then < 2 || then == len(fields)-1 {
+		return Constraint{}, fmt.Errorf("%q: expected \"if <factor>=<value> then <factor>=<value>\"", rule)
+	}
+
+	when, err := parseConditions(fields[1:then], registry)
+	if err != nil {
+		return Constraint{}, fmt.Errorf("%q: %w", rule, err)
+	}
+	require, err := parseConditions(fields[then+1:], registry)
+	if err != nil {
+		return Constraint{}, fmt.Errorf("%q: %w", rule, err)
+	}
+
+	return Constraint{
+		ID:          rule,
+		Description: rule,
+		Validate: func(combo StrategyCombo) bool {
+			for _, c := range when {
+				if !c.holds(combo, false) {
+					return true
+				}
+			}
+			for _, c := range require {
+				if !c.holds(combo, true) {
+					return false
+				}
+			}
+			return true
+		},
+	}, nil
+}
+
+// parseConditions parses conditions joined by "and", checking factors and values exist
+func parseConditions(fields []string, registry *FactorRegistry) ([]ruleCondition, error) {
+	var conditions []ruleCondition
+	for i, f := range fields {
+		if i%2 == 1 {
+			if !strings.EqualFold(f, "and") {
+				return nil, fmt.Errorf("expected \"and\", got %q", f)
+			}
+			continue
+		}
+
+		var c ruleCondition
+		factor, values, ok := strings.Cut(f, "!=")
+		if ok {
+			c.negate = true
+		} else if factor, values, ok = strings.Cut(f, "="); !ok {
+			return nil, fmt.Errorf("expected <factor>=<value>, got %q", f)
+		}
+		c.factor = FactorID(factor)
+		c.values = strings.Split(values, "|")
+
+		known := registry.Get(c.factor)
+		if known == nil {
+			return nil, fmt.Errorf("unknown factor %q", factor)
+		}
+		for _, v := range c.values {
+			if !factorHasValue(known, v) {
+				return nil, fmt.Errorf("factor %s has no value %q", factor, v)
+			}
+		}
+		conditions = append(conditions, c)
+	}
+	if len(fields)%2 == 0 {
+		return nil, fmt.Errorf("expected a condition after \"and\"")
+	}
+	return conditions, nil
+}
+
+// factorHasValue returns true if f has a value with the given ID
+func factorHasValue(f *Factor, id string) bool {
+	for _, v := range f.Values {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateUserStrategy checks the factors, constraints and tranches declared in config can be used
+func ValidateUserStrategy(config *Config) ConfigErrors {
+	registry := NewFactorRegistry()
+	registry.RegisterPersonFactors(config)
+	errs := registry.RegisterUserFactors(config)
+	_, constraintErrs := UserConstraints(config, registry)
+	errs = append(errs, constraintErrs...)
+	if tranche := config.Strategy.GetTranche(); tranche.Percent < 0 || tranche.Percent > 1 {
+		errs = append(errs, ConfigError{Path: "strategy.tranche.percent",
+			Message: fmt.Sprintf("expected a share of the pot from 0 to 1, got %g", tranche.Percent)})
+	}
+	return errs
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSetConfigPath verifies settings are found by YAML key, list index, name and "*"
+// and that the original config is left unchanged
+func TestSetConfigPath(t *testing.T) {
+	config := createTestConfig()
+	config.People = append(config.People, PersonConfig{Name: "Second"})
+	second := len(config.People) - 1
+	original := config.People[0].RetirementDate
+	maximize := true
+	config.Strategy.MaximizeCoupleISA = &maximize
+
+	changed := *config
+	for path, value := range map[string]string{
+		"people.Second.retirement_date":      "2030-01-01",
+		"people.*.state_pension_defer_years": "3",
+		"people.0.pension":                   "123456",
+		"financial.pension_growth_rate":      "0.02",
+		"strategy.maximize_couple_isa":       "false",
+	} {
+		if err := setConfigPath(&changed, path, value); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+	}
+
+	if changed.People[second].RetirementDate != "2030-01-01" || changed.People[0].RetirementDate != original {
+		t.Errorf("Expected only Second's retirement date to change, got %q and %q",
+			changed.People[0].RetirementDate, changed.People[second].RetirementDate)
+	}
+	if changed.People[0].StatePensionDeferYears != 3 || changed.People[second].StatePensionDeferYears != 3 {
+		t.Error("Expected everyone's state pension deferral to be 3 years")
+	}
+	if changed.People[0].Pension != 123456 || changed.Financial.PensionGrowthRate != 0.02 || changed.Strategy.ShouldMaximizeCoupleISA() {
+		t.Error("Expected the pension, growth rate and couple ISA setting to change")
+	}
+
+	if config.People[second].RetirementDate != "" || config.People[0].StatePensionDeferYears != 0 ||
+		config.Financial.PensionGrowthRate == 0.02 || !*config.Strategy.MaximizeCoupleISA {
+		t.Error("Expected the original config to be unchanged")
+	}
+
+	for _, path := range []string{"people.Nobody.pension", "financial.unknown", "people", "financial.pension_growth_rate.x"} {
+		if err := setConfigPath(&changed, path, "1"); err == nil {
+			t.Errorf("Expected an error for %s", path)
+		}
+	}
+	if err := setConfigPath(&changed, "people.0.pension", "lots"); err == nil {
+		t.Error("Expected an error for a value of the wrong type")
+	}
+}
+
+// TestParseConstraint verifies declarative rules accept and reject the right combinations
+func TestParseConstraint(t *testing.T) {
+	registry := NewFactorRegistry()
+	combo := func(drawdown, maximize string) StrategyCombo {
+		c := StrategyCombo{Values: map[FactorID]FactorValue{FactorDrawdown: {ID: drawdown}}}
+		if maximize != "" {
+			c.Values[FactorMaximizeCoupleISA] = FactorValue{ID: maximize}
+		}
+		return c
+	}
+
+	tests := []struct {
+		rule  string
+		combo StrategyCombo
+		valid bool
+	}{
+		{"if drawdown=pension_only then maximize_couple_isa=off", combo("pension_only", "on"), false},
+		{"if drawdown=pension_only then maximize_couple_isa=off", combo("pension_only", "off"), true},
+		{"if drawdown=pension_only then maximize_couple_isa=off", combo("pension_only", ""), true},
+		{"if drawdown=pension_only then maximize_couple_isa=off", combo("savings_first", "on"), true},
+		{"if maximize_couple_isa=on then drawdown!=savings_first|pension_first", combo("pension_first", "on"), false},
+		{"if maximize_couple_isa=on then drawdown!=savings_first|pension_first", combo("tax_optimized", "on"), true},
+		{"if maximize_couple_isa=on then drawdown!=savings_first|pension_first", combo("pension_first", ""), true},
+		{"IF drawdown=tax_optimized AND maximize_couple_isa=on THEN drawdown=pension_only", combo("tax_optimized", "on"), false},
+		{"IF drawdown=tax_optimized AND maximize_couple_isa=on THEN drawdown=pension_only", combo("tax_optimized", "off"), true},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.rule, registry)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.rule, err)
+		}
+		if got := c.Validate(tt.combo); got != tt.valid {
+			t.Errorf("%s with %v: expected valid=%v, got %v", tt.rule, tt.combo.Values, tt.valid, got)
+		}
+	}
+
+	for _, rule := range []string{
+		"drawdown=pension_only then maximize_couple_isa=off",
+		"if drawdown=pension_only",
+		"if drawdown=pension_only then",
+		"if drawdown pension_only then maximize_couple_isa=off",
+		"if drawdown=pension_only and then maximize_couple_isa=off",
+		"if drawdown=pension_only or drawdown=pension_first then maximize_couple_isa=off",
+		"if drawdown=nonsense then maximize_couple_isa=off",
+		"if unknown=on then maximize_couple_isa=off",
+	} {
+		if _, err := ParseConstraint(rule, registry); err == nil {
+			t.Errorf("Expected an error for %q", rule)
+		}
+	}
+}
+
+// TestUserFactors verifies factors and rules declared in YAML are combined, labelled and applied
+func TestUserFactors(t *testing.T) {
+	config := createTestConfig()
+	baseline := GetCombinationCount(config)[ModeStandard]
+	person := config.People[0].Name
+
+	config.Strategy.Factors = []UserFactorConfig{
+		{ID: "retire", Name: "Retirement Date", Path: "people." + person + ".retirement_date", Values: []interface{}{"2026-01-01", "2027-01-01"}},
+		{ID: "defer", Path: "people.*.state_pension_defer_years", Values: []interface{}{0, 1, 3}, Labels: []string{"SP0", "SP1", "SP3"}},
+	}
+	if n := GetCombinationCount(config)[ModeStandard]; n != baseline*6 {
+		t.Fatalf("Expected %d combinations with both factors, got %d", baseline*6, n)
+	}
+	if n := GetCombinationCount(config)[ModeQuick]; n == 0 || n%6 != 0 {
+		t.Errorf("Expected user factors in quick mode too, got %d combinations", n)
+	}
+
+	config.Strategy.Constraints = []string{"if retire=2026-01-01 then defer=0"}
+	if n := GetCombinationCount(config)[ModeStandard]; n != baseline*4 {
+		t.Fatalf("Expected %d combinations with the rule, got %d", baseline*4, n)
+	}
+
+	var found bool
+	for _, params := range GetStrategiesForConfigV2(config, ModeStandard) {
+		if len(params.ConfigOverrides) != 2 || params.ConfigOverrides[0].Value != "2027-01-01" || params.ConfigOverrides[1].Value != "3" {
+			continue
+		}
+		found = true
+		if name := params.ShortName(); !strings.Contains(name, "/retire=2027-01-01/SP3/") {
+			t.Errorf("Expected the factors in the short name, got %s", name)
+		}
+		if name := params.DescriptiveName(0); !strings.Contains(name, "Retirement Date 2027-01-01, defer 3") {
+			t.Errorf("Expected the factors in the descriptive name, got %s", name)
+		}
+		effective := ApplyParamsToConfig(params, config)
+		if effective.People[0].RetirementDate != "2027-01-01" || effective.People[0].StatePensionDeferYears != 3 {
+			t.Errorf("Expected the factors to be applied, got %s and %d years", effective.People[0].RetirementDate, effective.People[0].StatePensionDeferYears)
+		}
+		if config.People[0].StatePensionDeferYears != 0 {
+			t.Error("Expected the config to be unchanged")
+		}
+		if ResultKey(cacheKindSimulationV2, config, params) == ResultKey(cacheKindSimulationV2, config, GetStrategiesForConfigV2(config, ModeStandard)[0]) {
+			t.Error("Expected a different cache key for a different factor value")
+		}
+	}
+	if !found {
+		t.Fatal("Expected a strategy retiring in 2027 with 3 years' deferral")
+	}
+}
+
+// TestLoadConfig_UserFactors verifies factors are read from YAML and mistakes are reported on load
+func TestLoadConfig_UserFactors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(strategy string) string {
+		config := createTestConfig()
+		config.Strategy = StrategyConfig{}
+		path := filepath.Join(dir, "config.yaml")
+		if err := SaveConfig(config, path); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(path)
+		data = []byte(strings.Replace(string(data), "strategy:", "strategy:\n"+strategy, 1))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	config, err := LoadConfig(write(`    factors:
+        - id: retire
+          path: people.*.retirement_date
+          values: [2026-01-01, 2027-01-01]
+    constraints:
+        - if retire=2027-01-01 then drawdown!=pension_only
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	factor := NewCombinationGenerator(config).registry.Get("retire")
+	if factor == nil || len(factor.Values) != 2 || factor.Values[0].ID != "2026-01-01" {
+		t.Fatalf("Expected the retire factor with dates as written, got %+v", factor)
+	}
+
+	for _, strategy := range []string{
+		"    factors:\n        - {id: retire, path: people.*.retire_date, values: [2026-01-01]}\n",
+		"    factors:\n        - {id: drawdown, path: people.*.pension, values: [1]}\n",
+		"    factors:\n        - {id: defer, path: people.*.state_pension_defer_years, values: [soon]}\n",
+		"    constraints: [\"if retire=2027-01-01 then drawdown=pension_only\"]\n",
+	} {
+		if _, err := LoadConfig(write(strategy)); err == nil {
+			t.Errorf("Expected an error loading:\n%s", strategy)
+		}
+	}
+}
@@ -0,0 +1,582 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is one problem found in a configuration, at its YAML path
+type ConfigError struct {
+	Path    string `json:"path"`           // e.g. people[0].birth_date (empty for the whole file)
+	Line    int    `json:"line,omitempty"` // Line in the YAML file (0 = not known, e.g. for web requests)
+	Message string `json:"message"`
+}
+
+func (e ConfigError) Error() string {
+	switch {
+	case e.Line > 0 && e.Path != "":
+		return fmt.Sprintf("line %d: %s: %s", e.Line, e.Path, e.Message)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	case e.Path != "":
+		return e.Path + ": " + e.Message
+	default:
+		return e.Message
+	}
+}
+
+// ConfigErrors lists every problem found in a configuration, in file order
+type ConfigErrors []ConfigError
+
+func (errs ConfigErrors) Error() string {
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = e.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HasPath returns true if there is already an error for path or a setting inside it
+func (errs ConfigErrors) HasPath(path string) bool {
+	for _, e := range errs {
+		if e.Path == path || strings.HasPrefix(e.Path, path+".") || strings.HasPrefix(e.Path, path+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	yamlTypePattern = regexp.MustCompile("^cannot unmarshal !!(\\w+) `(.*)` into (.+)$")
+)
+
+// ParseConfig reads a YAML configuration and checks it, reporting unknown settings,
+// values of the wrong type and values that make no sense with their line numbers
+// Configs from older releases are upgraded to CurrentSchemaVersion first (see Config.Migrated)
+func ParseConfig(data []byte) (*Config, ConfigErrors) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, ConfigErrors{yamlSyntaxError(err)}
+	}
+	_, migrated, migrateErr := migrateConfigDoc(&doc)
+	if migrateErr != nil {
+		return nil, ConfigErrors{*migrateErr}
+	}
+
+	var config Config
+	var errs ConfigErrors
+	lines := map[string]int{}
+	if len(doc.Content) == 0 {
+		return &config, ValidateConfig(&config) // Empty file
+	}
+	checkKeys(doc.Content[0], reflect.TypeOf(Config{}), "", lines, &errs)
+
+	if err := doc.Decode(&config); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, append(errs, yamlSyntaxError(err))
+		}
+		pathsByLine := map[int]string{}
+		for _, path := range sortedPaths(lines) {
+			pathsByLine[lines[path]] = path
+		}
+		for _, msg := range typeErr.Errors {
+			e := yamlSyntaxError(errors.New(msg))
+			e.Path = pathsByLine[e.Line]
+			if m := yamlTypePattern.FindStringSubmatch(e.Message); m != nil {
+				e.Message = fmt.Sprintf("expected %s, got %q", describeYAMLType(m[3]), m[2])
+				if strings.HasSuffix(m[2], "%") {
+					e.Message += " (write rates unquoted, as 0.05 or 5%)"
+				}
+			}
+			errs = append(errs, e)
+		}
+	}
+	config.Migrated = migrated
+
+	for _, e := range ValidateConfig(&config) {
+		if errs.HasPath(e.Path) {
+			continue // Already reported as unknown or the wrong type
+		}
+		e.Line = lineFor(lines, e.Path)
+		errs = append(errs, e)
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+	return &config, errs
+}
+
+// yamlSyntaxError converts a YAML parser error to a ConfigError with its line number
+func yamlSyntaxError(err error) ConfigError {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return ConfigError{Line: line, Message: m[2]}
+	}
+	return ConfigError{Message: msg}
+}
+
+// describeYAMLType describes a Go type named in a YAML type error
+func describeYAMLType(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "[]"):
+		return "a list"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"):
+		return "a whole number"
+	case strings.HasPrefix(goType, "float"):
+		return "a number"
+	case goType == "bool":
+		return "true or false"
+	case goType == "string":
+		return "text"
+	default:
+		return "a section of settings"
+	}
+}
+
+// checkKeys walks node against the settings of type t, recording the line of each path
+// and reporting keys that are not settings
+func checkKeys(node *yaml.Node, t reflect.Type, path string, lines map[string]int, errs *ConfigErrors) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if _, ok := lines[path]; !ok {
+		lines[path] = node.Line
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return // Reported by the decoder
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			child := joinPath(path, key.Value)
+			lines[child] = key.Line
+			field, ok := fields[key.Value]
+			if !ok {
+				msg := "unknown setting"
+				if suggestion := closestName(key.Value, fields); suggestion != "" {
+					msg += fmt.Sprintf(", did you mean %q?", suggestion)
+				}
+				*errs = append(*errs, ConfigError{Path: child, Line: key.Line, Message: msg})
+				continue
+			}
+			checkKeys(value, field.Type, child, lines, errs)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range node.Content {
+			checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), lines, errs)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			child := joinPath(path, node.Content[i].Value)
+			lines[child] = node.Content[i].Line
+			checkKeys(node.Content[i+1], t.Elem(), child, lines, errs)
+		}
+	}
+}
+
+// yamlFields returns the fields of struct type t by their YAML key
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// closestName returns the setting name within two edits of name, if there is one
+func closestName(name string, fields map[string]reflect.StructField) string {
+	best, bestDistance := "", 3
+	for candidate := range fields {
+		if d := editDistance(name, candidate); d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// joinPath appends a key to a YAML path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedPaths returns the paths in lines in walk order (parents before their settings)
+func sortedPaths(lines map[string]int) []string {
+	paths := make([]string, 0, len(lines))
+	for path := range lines {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if lines[paths[i]] != lines[paths[j]] {
+			return lines[paths[i]] < lines[paths[j]]
+		}
+		return len(paths[i]) < len(paths[j])
+	})
+	return paths
+}
+
+// lineFor returns the line of path, or of the nearest section containing it when the setting is not in the file
+func lineFor(lines map[string]int, path string) int {
+	for path != "" {
+		if line, ok := lines[path]; ok {
+			return line
+		}
+		cut := strings.LastIndexAny(path, ".[")
+		if cut < 0 {
+			break
+		}
+		path = path[:cut]
+	}
+	return 0
+}
+
+// configChecker collects the problems found by ValidateConfig
+type configChecker struct {
+	config *Config
+	errs   ConfigErrors
+}
+
+func (c *configChecker) add(path, format string, args ...interface{}) {
+	c.errs = append(c.errs, ConfigError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// rate checks a rate is from 0 to 1
+func (c *configChecker) rate(path string, v float64) {
+	if v < 0 || v > 1 {
+		c.add(path, "expected a rate from 0 to 1 (0.05 = 5%%), got %g", v)
+	}
+}
+
+// growth checks a growth or inflation rate is from -1 to 1, as markets and prices can fall
+func (c *configChecker) growth(path string, v float64) {
+	if v < -1 || v > 1 {
+		c.add(path, "expected a rate from -1 to 1 (0.05 = 5%%), got %g", v)
+	}
+}
+
+// amount checks an amount of money or a count is not negative
+func (c *configChecker) amount(path string, v float64) {
+	if v < 0 {
+		c.add(path, "expected 0 or more, got %g", v)
+	}
+}
+
+// age checks an age is from 0 to 120
+func (c *configChecker) age(path string, v int) {
+	if v < 0 || v > 120 {
+		c.add(path, "expected an age from 0 to 120, got %d", v)
+	}
+}
+
+// date checks a YYYY-MM-DD date
+func (c *configChecker) date(path, value string, required bool) {
+	if value == "" {
+		if required {
+			c.add(path, "expected a date (YYYY-MM-DD)")
+		}
+		return
+	}
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		c.add(path, "expected a date (YYYY-MM-DD), got %q", value)
+	}
+}
+
+// person checks name is one of the people, if set
+func (c *configChecker) person(path, name string) {
+	if name == "" || c.config.FindPerson(name) != nil {
+		return
+	}
+	names := make([]string, len(c.config.People))
+	for i, pc := range c.config.People {
+		names[i] = pc.Name
+	}
+	c.add(path, "unknown person %q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// oneOf checks value is one of allowed, if set
+func (c *configChecker) oneOf(path, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	c.add(path, "expected one of %s, got %q", strings.Join(allowed, ", "), value)
+}
+
+// ValidateConfig checks every setting in config makes sense, returning the problems found
+func ValidateConfig(config *Config) ConfigErrors {
+	c := &configChecker{config: config}
+
+	if len(config.People) == 0 {
+		c.add("people", "expected at least one person")
+	}
+	seen := map[string]bool{}
+	for i, pc := range config.People {
+		path := fmt.Sprintf("people[%d]", i)
+		switch {
+		case pc.Name == "":
+			c.add(path+".name", "expected a name")
+		case seen[pc.Name]:
+			c.add(path+".name", "%q is used by another person", pc.Name)
+		}
+		seen[pc.Name] = true
+		c.date(path+".birth_date", pc.BirthDate, true)
+		c.date(path+".retirement_date", pc.RetirementDate, false)
+		c.age(path+".retirement_age", pc.RetirementAge)
+		c.age(path+".pension_access_age", pc.PensionAccessAge)
+		c.age(path+".state_pension_age", pc.StatePensionAge)
+		c.age(path+".db_pension_start_age", pc.DBPensionStartAge)
+		c.age(path+".db_pension_normal_age", pc.DBPensionNormalAge)
+		c.age(path+".part_time_start_age", pc.PartTimeStartAge)
+		c.age(path+".part_time_end_age", pc.PartTimeEndAge)
+		c.age(path+".care_start_age", pc.CareStartAge)
+		if pc.PartTimeIncome > 0 && pc.PartTimeEndAge < pc.PartTimeStartAge {
+			c.add(path+".part_time_end_age", "expected an age after part_time_start_age (%d), got %d", pc.PartTimeStartAge, pc.PartTimeEndAge)
+		}
+		for name, v := range map[string]float64{
+			"tax_free_savings":          pc.TaxFreeSavings,
+			"pension":                   pc.Pension,
+			"isa_annual_limit":          pc.ISAAnnualLimit,
+			"db_pension_amount":         pc.DBPensionAmount,
+			"db_pension_commute_factor": pc.DBPensionCommuteFactor,
+			"part_time_income":          pc.PartTimeIncome,
+			"work_income":               pc.WorkIncome,
+			"work_income_net":           pc.WorkIncomeNet,
+			"pension_annual_allowance":  pc.PensionAnnualAllowance,
+			"employer_contribution":     pc.EmployerContribution,
+			"extra_monthly_saving":      pc.ExtraMonthlySaving,
+			"extra_lump_sum":            pc.ExtraLumpSum,
+			"care_annual_cost":          pc.CareAnnualCost,
+			"lump_sum_allowance_used":   pc.LumpSumAllowanceUsed,
+		} {
+			c.amount(path+"."+name, v)
+		}
+		c.amount(path+".state_pension_defer_years", float64(pc.StatePensionDeferYears))
+		c.amount(path+".isa_to_sipp_preserve_months", float64(pc.ISAToSIPPPreserveMonths))
+		c.rate(path+".db_pension_early_factor", pc.DBPensionEarlyFactor)
+		c.rate(path+".db_pension_late_factor", pc.DBPensionLateFactor)
+		c.rate(path+".db_pension_commutation", pc.DBPensionCommutation)
+		c.rate(path+".isa_to_sipp_max_percent", pc.ISAToSIPPMaxPercent)
+		c.oneOf(path+".extra_saving_wrapper", pc.ExtraSavingWrapper, SavingWrapperISA, SavingWrapperPension)
+	}
+
+	f := config.Financial
+	for name, v := range map[string]float64{
+		"pension_growth_rate":     f.PensionGrowthRate,
+		"savings_growth_rate":     f.SavingsGrowthRate,
+		"income_inflation_rate":   f.IncomeInflationRate,
+		"state_pension_inflation": f.StatePensionInflation,
+		"tax_band_inflation":      f.TaxBandInflation,
+		"pension_growth_end_rate": f.PensionGrowthEndRate,
+		"savings_growth_end_rate": f.SavingsGrowthEndRate,
+	} {
+		c.growth("financial."+name, v)
+	}
+	c.rate("financial.state_pension_deferral_rate", f.StatePensionDeferralRate)
+	c.rate("financial.depletion_growth_decline_percent", f.DepletionGrowthDeclinePercent)
+	c.amount("financial.state_pension_amount", f.StatePensionAmount)
+	c.amount("financial.emergency_fund_months", float64(f.EmergencyFundMonths))
+	c.age("financial.growth_decline_target_age", f.GrowthDeclineTargetAge)
+	c.person("financial.growth_decline_reference_person", f.GrowthDeclineReferencePerson)
+
+	ic := config.IncomeRequirements
+	c.person("income_requirements.reference_person", ic.ReferencePerson)
+	c.age("income_requirements.target_depletion_age", ic.TargetDepletionAge)
+	c.age("income_requirements.age_threshold", ic.AgeThreshold)
+	for name, v := range map[string]float64{
+		"monthly_before_age":     ic.MonthlyBeforeAge,
+		"monthly_after_age":      ic.MonthlyAfterAge,
+		"income_ratio_phase1":    ic.IncomeRatioPhase1,
+		"income_ratio_phase2":    ic.IncomeRatioPhase2,
+		"target_legacy":          ic.TargetLegacy,
+		"target_isa_floor":       ic.TargetISAFloor,
+		"target_pension_floor":   ic.TargetPensionFloor,
+		"guardrails_upper_limit": ic.GuardrailsUpperLimit,
+		"guardrails_lower_limit": ic.GuardrailsLowerLimit,
+		"vpw_floor":              ic.VPWFloor,
+		"vpw_ceiling":            ic.VPWCeiling,
+	} {
+		c.amount("income_requirements."+name, v)
+	}
+	for name, v := range map[string]float64{
+		"guardrails_adjustment": ic.GuardrailsAdjustment,
+		"guardrails_cut_rate":   ic.GuardrailsCutRate,
+		"guardrails_raise_rate": ic.GuardrailsRaiseRate,
+		"vanguard_ceiling":      ic.VanguardCeiling,
+		"vanguard_floor":        ic.VanguardFloor,
+		"constant_percent_rate": ic.ConstantPercentRate,
+	} {
+		c.rate("income_requirements."+name, v)
+	}
+	c.growth("income_requirements.vpw_real_return", ic.VPWRealReturn)
+	c.oneOf("income_requirements.withdrawal_rule", ic.WithdrawalRule, WithdrawalFixed.ID(), WithdrawalGuytonKlinger.ID(),
+		WithdrawalVanguard.ID(), WithdrawalVPW.ID(), WithdrawalConstantPercent.ID())
+	c.checkTiers(ic.Tiers)
+	sp := ic.SpendingProfile
+	c.oneOf("income_requirements.spending_profile.type", sp.Type, SpendingProfileFlat, SpendingProfileSmile, SpendingProfileDecline)
+	c.age("income_requirements.spending_profile.decline_start_age", sp.DeclineStartAge)
+	c.rate("income_requirements.spending_profile.decline_rate", sp.DeclineRate)
+	c.rate("income_requirements.spending_profile.essential_fraction", sp.EssentialFraction)
+	c.growth("income_requirements.spending_profile.essential_inflation", sp.EssentialInflation)
+	c.growth("income_requirements.spending_profile.discretionary_inflation", sp.DiscretionaryInflation)
+
+	for i, part := range config.Mortgage.Parts {
+		path := fmt.Sprintf("mortgage.parts[%d]", i)
+		c.amount(path+".principal", part.Principal)
+		c.rate(path+".interest_rate", part.InterestRate)
+		c.amount(path+".term_years", float64(part.TermYears))
+	}
+
+	c.person("simulation.reference_person", config.Simulation.ReferencePerson)
+	if y := config.Simulation.StartYear; y != 0 && (y < 1900 || y > 2200) {
+		c.add("simulation.start_year", "expected a year such as %d, got %d", time.Now().Year()+1, y)
+	}
+	c.age("simulation.end_age", config.Simulation.EndAge)
+	c.amount("simulation.concurrency", float64(config.Simulation.Concurrency))
+
+	s := config.Sensitivity
+	if s.PensionGrowthMax < s.PensionGrowthMin {
+		c.add("sensitivity.pension_growth_max", "expected at least pension_growth_min (%g), got %g", s.PensionGrowthMin, s.PensionGrowthMax)
+	}
+	if s.SavingsGrowthMax < s.SavingsGrowthMin {
+		c.add("sensitivity.savings_growth_max", "expected at least savings_growth_min (%g), got %g", s.SavingsGrowthMin, s.SavingsGrowthMax)
+	}
+	c.amount("sensitivity.step_size", s.StepSize)
+
+	c.oneOf("strategy.optimal_objective", config.Strategy.OptimalObjective, OptimalObjectiveEstate, OptimalObjectiveTax)
+
+	for i, band := range config.TaxBands {
+		path := fmt.Sprintf("tax_bands[%d]", i)
+		c.amount(path+".lower", band.Lower)
+		if band.Upper <= band.Lower {
+			c.add(path+".upper", "expected more than lower (%g), got %g", band.Lower, band.Upper)
+		}
+		c.rate(path+".rate", band.Rate)
+	}
+	c.amount("tax.personal_allowance", config.Tax.PersonalAllowance)
+	c.amount("tax.tapering_threshold", config.Tax.TaperingThreshold)
+	c.rate("tax.tapering_rate", config.Tax.TaperingRate)
+	c.amount("tax.lump_sum_allowance", config.Tax.LumpSumAllowance)
+	c.rate("tax.gia_tax_rate", config.Tax.GIATaxRate)
+
+	cc := config.Care
+	for name, v := range map[string]float64{
+		"annual_cost":            cc.AnnualCost,
+		"home_value":             cc.HomeValue,
+		"self_funding_threshold": cc.SelfFundingThreshold,
+		"lower_capital_limit":    cc.LowerCapitalLimit,
+	} {
+		c.amount("care."+name, v)
+	}
+	if cc.InflationRate != nil {
+		c.growth("care.inflation_rate", *cc.InflationRate)
+	}
+	c.growth("care.home_growth_rate", cc.HomeGrowthRate)
+	c.rate("care.home_sale_costs", cc.HomeSaleCosts)
+	c.amount("care.duration_years", float64(cc.DurationYears))
+	c.oneOf("care.home_sale", cc.HomeSale, HomeSaleNever, HomeSaleFirstInCare, HomeSaleAllInCare)
+
+	c.errs = append(c.errs, ValidateUserStrategy(config)...)
+	c.errs = append(c.errs, ValidateLifetimeISA(config)...)
+	c.errs = append(c.errs, ValidateTaxFreeCash(config)...)
+	c.errs = append(c.errs, ValidatePensionAccess(config)...)
+
+	sort.SliceStable(c.errs, func(i, j int) bool { return c.errs[i].Path < c.errs[j].Path })
+	return c.errs
+}
+
+// checkTiers checks each income tier's ages and amounts, and that no two tiers cover the same age
+func (c *configChecker) checkTiers(tiers []IncomeTier) {
+	bounds := func(t IncomeTier) (int, int) {
+		start, end := 0, 1000
+		if t.StartAge != nil {
+			start = *t.StartAge
+		}
+		if t.EndAge != nil {
+			end = *t.EndAge
+		}
+		return start, end
+	}
+	for i, t := range tiers {
+		path := fmt.Sprintf("income_requirements.tiers[%d]", i)
+		start, end := bounds(t)
+		if t.StartAge != nil {
+			c.age(path+".start_age", start)
+		}
+		if t.EndAge != nil {
+			c.age(path+".end_age", end)
+		}
+		if end <= start {
+			c.add(path+".end_age", "expected an age after start_age (%d), got %d", start, end)
+			continue
+		}
+		c.amount(path+".monthly_amount", t.MonthlyAmount)
+		c.amount(path+".ratio", t.Ratio)
+		c.amount(path+".essential_monthly", t.EssentialMonthly)
+		for j := 0; j < i; j++ {
+			otherStart, otherEnd := bounds(tiers[j])
+			if start < otherEnd && otherStart < end && otherStart < otherEnd {
+				c.add(path, "ages %s overlap tier %d (ages %s)", t.AgeRange(), j+1, tiers[j].AgeRange())
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// findConfigError returns the error for path, if there is one
+func findConfigError(errs ConfigErrors, path string) (ConfigError, bool) {
+	for _, e := range errs {
+		if e.Path == path {
+			return e, true
+		}
+	}
+	return ConfigError{}, false
+}
+
+// TestParseConfig_Errors verifies mistakes are reported with their path, line and a clear message
+func TestParseConfig_Errors(t *testing.T) {
+	_, errs := ParseConfig([]byte(`people:
+  - name: James
+    birth_date: 1971-01-01
+    pensoin: 500000
+    retirement_age: soon
+financial:
+  pension_growth_rate: -1.5
+  savings_growth_rate: "5%"
+income_requirements:
+  reference_person: Jmes
+  tiers:
+    - end_age: 70
+      monthly_amount: 3000
+    - start_age: 65
+      monthly_amount: 2000
+`))
+
+	tests := []struct {
+		path    string
+		line    int
+		message string
+	}{
+		{"people[0].pensoin", 4, `unknown setting, did you mean "pension"?`},
+		{"people[0].retirement_age", 5, `expected a whole number, got "soon"`},
+		{"financial.pension_growth_rate", 7, "expected a rate from -1 to 1 (0.05 = 5%), got -1.5"},
+		{"financial.savings_growth_rate", 8, `expected a number, got "5%" (write rates unquoted, as 0.05 or 5%)`},
+		{"income_requirements.reference_person", 10, `unknown person "Jmes", expected one of James`},
+		{"income_requirements.tiers[1]", 14, "ages 65+ overlap tier 1 (ages until 70)"},
+	}
+	for _, tc := range tests {
+		e, ok := findConfigError(errs, tc.path)
+		if !ok || e.Line != tc.line || e.Message != tc.message {
+			t.Errorf("Expected line %d: %s: %s, got %+v", tc.line, tc.path, tc.message, e)
+		}
+	}
+	if len(errs) != len(tests) {
+		t.Errorf("Expected %d errors, got:\n%v", len(tests), errs)
+	}
+	for i := 1; i < len(errs); i++ {
+		if errs[i].Line < errs[i-1].Line {
+			t.Errorf("Expected errors in file order, got:\n%v", errs)
+		}
+	}
+
+	_, errs = ParseConfig([]byte("people: [\n"))
+	if len(errs) != 1 || errs[0].Line == 0 {
+		t.Errorf("Expected a syntax error with its line, got %v", errs)
+	}
+}
+
+// TestParseConfig_Valid verifies the shipped configs and a saved config have no errors
+func TestParseConfig_Valid(t *testing.T) {
+	data, err := os.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, errs := ParseConfig(data); len(errs) > 0 {
+		t.Errorf("Expected config.yaml to be valid, got:\n%v", errs)
+	}
+	if _, err := LoadDefaultConfig(); err != nil {
+		t.Errorf("Expected default-config.yaml to be valid, got:\n%v", err)
+	}
+
+	// Percentages such as 5% are read wherever a config file is loaded or validated
+	if _, err := LoadConfig("default-config.yaml"); err != nil {
+		t.Errorf("Expected default-config.yaml to load, got:\n%v", err)
+	}
+	if code := runValidate("default-config.yaml"); code != 0 {
+		t.Errorf("Expected -validate to pass default-config.yaml, got exit code %d", code)
+	}
+
+	path := t.TempDir() + "/config.yaml"
+	if err := SaveConfig(createTestConfig(), path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(path); err != nil {
+		t.Errorf("Expected a saved config to load, got:\n%v", err)
+	}
+}
+
+// TestValidateConfig verifies the validators for each section are combined under their paths
+func TestValidateConfig(t *testing.T) {
+	config := createTestConfig()
+	config.People[1].Name = "James"
+	config.Mortgage.Parts = []MortgagePartConfig{{Principal: -1000, InterestRate: 4}}
+	config.Care.HomeSale = "sometimes"
+	config.Financial.PensionGrowthRate = -0.1 // Markets can fall
+	config.Financial.StatePensionDeferralRate = -0.1
+	config.Strategy.Factors = []UserFactorConfig{{ID: "retire", Path: "people.*.retire_date", Values: []interface{}{"2026-01-01"}}}
+	errs := ValidateConfig(config)
+	for _, path := range []string{
+		"people[1].name", "mortgage.parts[0].principal", "mortgage.parts[0].interest_rate", "care.home_sale", "strategy.factors[0]",
+		"financial.state_pension_deferral_rate",
+	} {
+		if _, ok := findConfigError(errs, path); !ok {
+			t.Errorf("Expected an error for %s, got:\n%v", path, errs)
+		}
+	}
+	if e, ok := findConfigError(errs, "financial.pension_growth_rate"); ok {
+		t.Errorf("Expected a negative growth rate to be allowed, got %v", e)
+	}
+}
+
+// TestHandleValidate verifies the API checks both form requests and YAML files
+func TestHandleValidate(t *testing.T) {
+	ws := NewWebServer(createTestConfig(), "")
+	server := httptest.NewServer(http.HandlerFunc(ws.handleValidate))
+	defer server.Close()
+
+	post := func(contentType, body string) APIValidateResponse {
+		res, err := http.Post(server.URL, contentType, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var response APIValidateResponse
+		if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	if response := post("application/json", `{"mode":"fixed"}`); !response.Valid || len(response.Errors) != 0 {
+		t.Errorf("Expected the configured settings to be valid, got %+v", response)
+	}
+	response := post("application/json", `{"mode":"fixed","financial":{"pension_growth_rate":0.05,"savings_growth_rate":-2}}`)
+	if e, ok := findConfigError(response.Errors, "financial.savings_growth_rate"); response.Valid || !ok || e.Line != 0 {
+		t.Errorf("Expected the out of range rate reported without a line, got %+v", response)
+	}
+	data, err := os.ReadFile("default-config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response := post("application/yaml", string(data)); !response.Valid {
+		t.Errorf("Expected default-config.yaml with percentages to be valid, got %+v", response.Errors)
+	}
+	response = post("application/yaml", "people:\n  - name: James\n    birth_date: 1971-01-01\nsimulaton:\n  end_age: 95\n")
+	if e, ok := findConfigError(response.Errors, "simulaton"); response.Valid || !ok || e.Line != 4 {
+		t.Errorf("Expected the unknown section reported on line 4, got %+v", response)
+	}
+}
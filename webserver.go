@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io"
 	"log"
-	"math"
 	"net"
 	"net/http"
 	"os"
@@ -23,21 +23,28 @@ type WebServer struct {
 	config   *Config
 	addr     string
 	template *template.Template
+	jobs     jobStore     // Background simulation jobs
+	cache    *ResultCache // Strategy results shared by simulations and exports
 }
 
 // NewWebServer creates a new web server instance
 func NewWebServer(config *Config, addr string) *WebServer {
+	cacheDir := ""
+	if config != nil {
+		cacheDir = config.Simulation.CacheDir
+	}
 	return &WebServer{
 		config: config,
 		addr:   addr,
+		cache:  NewResultCache(cacheDir),
 	}
 }
 
 // APISimulationRequest represents a request to run a simulation
 type APISimulationRequest struct {
 	Mode              string   `json:"mode"`               // "fixed", "depletion", "pension-only", "pension-to-isa"
-	PermutationMode   string   `json:"permutation_mode"`   // "quick", "standard", "comprehensive" - controls strategy count
-	OptimizationGoal  string   `json:"optimization_goal"`  // "tax", "income", "balance"
+	PermutationMode   string   `json:"permutation_mode"`   // "quick", "standard", "thorough", "comprehensive", "adaptive" - controls strategy count
+	OptimizationGoal  string   `json:"optimization_goal"`  // "tax", "income", "balance", "custom"
 	People            []PersonConfig `json:"people"`
 	Financial         FinancialConfig `json:"financial"`
 	IncomeRequirements IncomeConfig `json:"income_requirements"`
@@ -45,6 +52,8 @@ type APISimulationRequest struct {
 	Simulation        SimulationConfig `json:"simulation"`
 	TaxBands          []TaxBand `json:"tax_bands,omitempty"`
 	Tax               TaxConfig `json:"tax,omitempty"` // Personal allowance tapering settings
+	Care              CareConfig `json:"care,omitempty"` // Later-life care costs and funding rules
+	Strategy          StrategyConfig `json:"strategy,omitempty"` // Strategy options, including custom scoring weights
 }
 
 // APISimulationResponse represents the simulation results
@@ -55,6 +64,28 @@ type APISimulationResponse struct {
 	Best    *APIResultSummary   `json:"best,omitempty"`
 	// Growth decline info (if enabled)
 	GrowthDecline *GrowthDeclineInfo `json:"growth_decline,omitempty"`
+	// Pareto-optimal strategies across tax, income, estate and shortfall (from every strategy run)
+	Frontier []APIFrontierPoint `json:"frontier,omitempty"`
+	// How much of the strategy space an adaptive search ran (adaptive permutation mode only)
+	Search *APISearchInfo `json:"search,omitempty"`
+}
+
+// APISearchInfo reports how many combinations an adaptive search ran out of the full space
+type APISearchInfo struct {
+	Evaluated int `json:"evaluated"`  // Factor combinations simulated
+	SpaceSize int `json:"space_size"` // Factor combinations in the searched space, before constraints
+}
+
+// APIFrontierPoint is one strategy on the Pareto frontier
+type APIFrontierPoint struct {
+	StrategyIdx     int     `json:"strategy_idx"`
+	ShortName       string  `json:"short_name"`
+	DescriptiveName string  `json:"descriptive_name,omitempty"`
+	TotalTaxPaid    float64 `json:"total_tax_paid"`
+	TotalIncome     float64 `json:"total_income"`             // Real terms
+	FinalBalance    float64 `json:"final_balance"`
+	ShortfallYear   int     `json:"shortfall_year,omitempty"` // First year spending is not met (0 = never)
+	MonthlyIncome   float64 `json:"monthly_income,omitempty"`
 }
 
 // GrowthDeclineInfo describes how growth rates decline over time
@@ -72,6 +103,7 @@ type GrowthDeclineInfo struct {
 // APIResultSummary is a simplified simulation result for API responses
 type APIResultSummary struct {
 	StrategyIdx    int                `json:"strategy_idx"`    // Original index for PDF export
+	ResultKey      string             `json:"result_key,omitempty"` // Cache key of the result, for exports
 	Rank           int                `json:"rank"`            // Preference rank (1 = best)
 	Strategy       string             `json:"strategy"`
 	ShortName      string             `json:"short_name"`
@@ -85,6 +117,10 @@ type APIResultSummary struct {
 	// For depletion mode
 	MonthlyIncome  float64            `json:"monthly_income,omitempty"`
 	FinalISA       float64            `json:"final_isa,omitempty"`
+	LegacyCostPer100k float64         `json:"legacy_cost_per_100k,omitempty"` // Monthly income given up per extra £100k of legacy
+	TerminalTarget    float64         `json:"terminal_target,omitempty"`      // Nominal total balance left at target age
+	TierAmounts       []float64       `json:"tier_amounts,omitempty"`         // Monthly amount (today's money) for each income tier
+	MinimumsUnmet     bool            `json:"minimums_unmet,omitempty"`       // Tier minimums can't be paid to the target age
 	// Diagnostic fields
 	ISADepletedYear     int     `json:"isa_depleted_year,omitempty"`
 	PensionDepletedYear int     `json:"pension_depleted_year,omitempty"`
@@ -94,6 +130,14 @@ type APIResultSummary struct {
 	EarlyPayoff         bool    `json:"early_payoff"`
 	MortgageOptionName  string  `json:"mortgage_option_name,omitempty"`
 	DescriptiveName     string  `json:"descriptive_name,omitempty"`
+	// Later-life care
+	TotalCareCost float64 `json:"total_care_cost,omitempty"`
+	HomeSaleYear  int     `json:"home_sale_year,omitempty"`
+	// Essential vs discretionary spending
+	DiscretionaryCutYears int     `json:"discretionary_cut_years,omitempty"`
+	CutYearsPercent       float64 `json:"cut_years_percent,omitempty"`
+	// Pension and ISA rules the plan breaks or risks breaking
+	Warnings []ComplianceWarning `json:"warnings,omitempty"`
 }
 
 // APIYearSummary provides year-by-year data
@@ -116,9 +160,17 @@ type APIYearSummary struct {
 	PensionWithdrawal float64 `json:"pension_withdrawal"`
 	TaxFreeWithdrawal float64 `json:"tax_free_withdrawal"`
 	ISADeposit        float64 `json:"isa_deposit"` // Excess income deposited to ISA
+	GIADeposit        float64 `json:"gia_deposit,omitempty"` // Excess income over the ISA allowance, saved in the GIA
+	GIATax            float64 `json:"gia_tax,omitempty"`     // Tax on GIA growth, taken from the GIAs
 	// Tax band info (inflated for year)
 	PersonalAllowance float64 `json:"personal_allowance"`
 	BasicRateLimit    float64 `json:"basic_rate_limit"`
+	// Later-life care
+	CareCost            float64 `json:"care_cost,omitempty"`
+	CareFundedByCouncil float64 `json:"care_funded_by_council,omitempty"`
+	// Essential vs discretionary spending
+	EssentialIncome  float64 `json:"essential_income,omitempty"`
+	DiscretionaryCut float64 `json:"discretionary_cut,omitempty"`
 }
 
 // APIPersonBalance holds person balance info
@@ -127,6 +179,12 @@ type APIPersonBalance struct {
 	UncrystallisedPot float64 `json:"uncrystallised_pot"`
 	CrystallisedPot   float64 `json:"crystallised_pot"`
 	Total             float64 `json:"total"`
+	// ISA subscriptions in the tax year
+	GIA              float64 `json:"gia,omitempty"` // Held in the GIA, outside the ISA balance (included in total)
+	ISASubscribed    float64 `json:"isa_subscribed"`
+	ISAAllowanceLeft float64 `json:"isa_allowance_left"`
+	SavedToGIA       float64 `json:"saved_to_gia,omitempty"`
+	LISA             float64 `json:"lisa,omitempty"` // Lifetime ISA not yet accessible
 }
 
 // Start starts the web server
@@ -142,13 +200,21 @@ func (ws *WebServer) Start() error {
 	mux.HandleFunc("/api/simulate/depletion", ws.handleSimulateDepletion)
 	mux.HandleFunc("/api/simulate/pension-only", ws.handleSimulatePensionOnly)
 	mux.HandleFunc("/api/simulate/pension-to-isa", ws.handleSimulatePensionToISA)
+	mux.HandleFunc("/api/simulate/retirement-date", ws.handleRetirementSearch)
+	mux.HandleFunc("/api/simulate/required-saving", ws.handleSavingsSearch)
 	mux.HandleFunc("/api/simulate/sensitivity", ws.handleSensitivityGrid)
+	mux.HandleFunc("/api/jobs/simulate", ws.handleStartSimulationJob)
+	mux.HandleFunc("/api/jobs/sensitivity", ws.handleStartSensitivityJob)
+	mux.HandleFunc("/api/jobs/{id}", ws.handleJobStatus)
+	mux.HandleFunc("/api/jobs/{id}/events", ws.handleJobEvents)
+	mux.HandleFunc("/api/jobs/{id}/cancel", ws.handleCancelJob)
 	mux.HandleFunc("/api/export-csv", ws.handleExportCSV)
 	mux.HandleFunc("/api/export-pdf", ws.handleExportPDF)
 	mux.HandleFunc("/api/download-pdf", ws.handleDownloadPDF)
 	mux.HandleFunc("/api/open-folder", ws.handleOpenFolder)
 	mux.HandleFunc("/api/open-file", ws.handleOpenFile)
 	mux.HandleFunc("/api/stock-indices", ws.handleStockIndices)
+	mux.HandleFunc("/api/validate", ws.handleValidate)
 
 	// Listen on the address (use :0 for auto-assign)
 	listener, err := net.Listen("tcp", ws.addr)
@@ -190,13 +256,21 @@ func (ws *WebServer) StartForEmbedded() (url string, cleanup func(), err error)
 	mux.HandleFunc("/api/simulate/depletion", ws.handleSimulateDepletion)
 	mux.HandleFunc("/api/simulate/pension-only", ws.handleSimulatePensionOnly)
 	mux.HandleFunc("/api/simulate/pension-to-isa", ws.handleSimulatePensionToISA)
+	mux.HandleFunc("/api/simulate/retirement-date", ws.handleRetirementSearch)
+	mux.HandleFunc("/api/simulate/required-saving", ws.handleSavingsSearch)
 	mux.HandleFunc("/api/simulate/sensitivity", ws.handleSensitivityGrid)
+	mux.HandleFunc("/api/jobs/simulate", ws.handleStartSimulationJob)
+	mux.HandleFunc("/api/jobs/sensitivity", ws.handleStartSensitivityJob)
+	mux.HandleFunc("/api/jobs/{id}", ws.handleJobStatus)
+	mux.HandleFunc("/api/jobs/{id}/events", ws.handleJobEvents)
+	mux.HandleFunc("/api/jobs/{id}/cancel", ws.handleCancelJob)
 	mux.HandleFunc("/api/export-csv", ws.handleExportCSV)
 	mux.HandleFunc("/api/export-pdf", ws.handleExportPDF)
 	mux.HandleFunc("/api/download-pdf", ws.handleDownloadPDF)
 	mux.HandleFunc("/api/open-folder", ws.handleOpenFolder)
 	mux.HandleFunc("/api/open-file", ws.handleOpenFile)
 	mux.HandleFunc("/api/stock-indices", ws.handleStockIndices)
+	mux.HandleFunc("/api/validate", ws.handleValidate)
 
 	// Listen on the address (use :0 for auto-assign)
 	listener, err := net.Listen("tcp", ws.addr)
@@ -539,16 +613,17 @@ Income = (Pension × pension_growth) + (ISA × savings_growth) - (Total × infla
                 <tr><td>Lower Limit</td><td>If withdrawal rate rises above this, decrease spending</td><td>80% (rate rose = portfolio fell)</td></tr>
                 <tr><td>Adjustment</td><td>How much to adjust by</td><td>10%</td></tr>
             </table>
+            <p>The full Guyton-Klinger rules also skip the inflation rise after a year with a negative return (if the withdrawal rate is above its initial level) and make no cuts in the final 15 years of the plan. Only discretionary spending is adjusted.</p>
 
             <h3>VPW (Variable Percentage Withdrawal)</h3>
             <p>Withdrawal rate increases with age. Based on actuarial tables - withdraw more as life expectancy decreases.</p>
             <table>
                 <tr><th>Age</th><th>VPW Rate</th><th>On £500k</th></tr>
-                <tr><td>55</td><td>3.0%</td><td>£15,000</td></tr>
-                <tr><td>65</td><td>4.0%</td><td>£20,000</td></tr>
-                <tr><td>75</td><td>5.5%</td><td>£27,500</td></tr>
-                <tr><td>85</td><td>8.0%</td><td>£40,000</td></tr>
-                <tr><td>95</td><td>15.0%</td><td>£75,000</td></tr>
+                <tr><td>55</td><td>3.9%</td><td>£19,600</td></tr>
+                <tr><td>65</td><td>4.4%</td><td>£22,200</td></tr>
+                <tr><td>75</td><td>5.4%</td><td>£27,200</td></tr>
+                <tr><td>85</td><td>7.7%</td><td>£38,600</td></tr>
+                <tr><td>95</td><td>17.9%</td><td>£89,600</td></tr>
             </table>
 
             <h3>Emergency Fund</h3>
@@ -740,22 +815,32 @@ func (ws *WebServer) handleSimulate(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Warning: failed to save config: %v", err)
 	}
 
-	var response APISimulationResponse
+	// Stop simulating if the client goes away
+	config.Control = NewRunControl(r.Context())
+	response := ws.runSimulationRequest(config, &req)
+	if err := config.Control.Err(); err != nil {
+		log.Printf("Simulation cancelled: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// runSimulationRequest runs the simulation mode a request asks for
+func (ws *WebServer) runSimulationRequest(config *Config, req *APISimulationRequest) APISimulationResponse {
 	goal := parseOptimizationGoal(req.OptimizationGoal)
 
 	switch req.Mode {
 	case "depletion":
-		response = ws.runDepletionSimulation(config, goal)
+		return ws.runDepletionSimulation(config, goal)
 	case "pension-only":
-		response = ws.runPensionOnlySimulation(config, goal, req.PermutationMode)
+		return ws.runPensionOnlySimulation(config, goal, req.PermutationMode)
 	case "pension-to-isa":
-		response = ws.runPensionToISASimulation(config, goal, req.PermutationMode)
+		return ws.runPensionToISASimulation(config, goal, req.PermutationMode)
 	default: // "fixed" or empty
-		response = ws.runFixedSimulation(config, goal, req.PermutationMode)
+		return ws.runFixedSimulation(config, goal, req.PermutationMode)
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
 }
 
 // handleSimulateFixed runs fixed income mode simulation
@@ -779,7 +864,12 @@ func (ws *WebServer) handleSimulateFixed(w http.ResponseWriter, r *http.Request)
 	}
 
 	goal := parseOptimizationGoal(req.OptimizationGoal)
+	config.Control = NewRunControl(r.Context())
 	response := ws.runFixedSimulation(config, goal, req.PermutationMode)
+	if err := config.Control.Err(); err != nil {
+		log.Printf("Simulation cancelled: %v", err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -806,7 +896,12 @@ func (ws *WebServer) handleSimulateDepletion(w http.ResponseWriter, r *http.Requ
 	}
 
 	goal := parseOptimizationGoal(req.OptimizationGoal)
+	config.Control = NewRunControl(r.Context())
 	response := ws.runDepletionSimulation(config, goal)
+	if err := config.Control.Err(); err != nil {
+		log.Printf("Simulation cancelled: %v", err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -833,7 +928,12 @@ func (ws *WebServer) handleSimulatePensionOnly(w http.ResponseWriter, r *http.Re
 	}
 
 	goal := parseOptimizationGoal(req.OptimizationGoal)
+	config.Control = NewRunControl(r.Context())
 	response := ws.runPensionOnlySimulation(config, goal, req.PermutationMode)
+	if err := config.Control.Err(); err != nil {
+		log.Printf("Simulation cancelled: %v", err)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -860,9 +960,200 @@ func (ws *WebServer) handleSimulatePensionToISA(w http.ResponseWriter, r *http.R
 	}
 
 	goal := parseOptimizationGoal(req.OptimizationGoal)
+	config.Control = NewRunControl(r.Context())
 	response := ws.runPensionToISASimulation(config, goal, req.PermutationMode)
+	if err := config.Control.Err(); err != nil {
+		log.Printf("Simulation cancelled: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// APIRetirementSearchRequest extends the simulation request with retirement date search fields
+type APIRetirementSearchRequest struct {
+	APISimulationRequest
+	SearchPeople []string `json:"search_people"` // People whose retirement date moves (empty = everyone jointly)
+	TargetAge    int      `json:"target_age"`    // Age spending must last to (default: target_depletion_age or end_age)
+}
+
+// APIRetirementSearchResult is the earliest viable retirement date for one strategy
+type APIRetirementSearchResult struct {
+	Strategy        string            `json:"strategy"`
+	ShortName       string            `json:"short_name"`
+	Found           bool              `json:"found"`
+	RetirementDates map[string]string `json:"retirement_dates,omitempty"`
+	RetirementAges  map[string]int    `json:"retirement_ages,omitempty"`
+	YearsEarlier    int               `json:"years_earlier"`
+	Summary         *APIResultSummary `json:"summary,omitempty"`
+}
+
+// APIRetirementSearchResponse returns the earliest retirement date for every strategy
+type APIRetirementSearchResponse struct {
+	Success   bool                        `json:"success"`
+	Error     string                      `json:"error,omitempty"`
+	TargetAge int                         `json:"target_age"`
+	Results   []APIRetirementSearchResult `json:"results,omitempty"`
+	Best      *APIRetirementSearchResult  `json:"best,omitempty"`
+}
+
+// handleRetirementSearch finds the earliest viable retirement date for each strategy
+func (ws *WebServer) handleRetirementSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req APIRetirementSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APIRetirementSearchResponse{Success: false, Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	config := ws.buildConfig(&req.APISimulationRequest)
+
+	// Save config to config.yaml for persistence
+	if err := SaveConfig(config, "config.yaml"); err != nil {
+		log.Printf("Warning: failed to save config: %v", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	for _, name := range req.SearchPeople {
+		if config.FindPerson(name) == nil {
+			json.NewEncoder(w).Encode(APIRetirementSearchResponse{Success: false, Error: "Unknown person in search_people: " + name})
+			return
+		}
+	}
+
+	targetAge := req.TargetAge
+	if targetAge <= 0 {
+		targetAge = GetRetirementSearchTargetAge(config)
+	}
+
+	config.Control = NewRunControl(r.Context())
+	searchResults := RunAllRetirementSearches(config, req.SearchPeople, targetAge)
+	if err := config.Control.Err(); err != nil {
+		log.Printf("Retirement date search cancelled: %v", err)
+		return
+	}
+	response := APIRetirementSearchResponse{Success: true, TargetAge: targetAge}
+	for _, sr := range searchResults {
+		result := APIRetirementSearchResult{
+			Strategy:        sr.Params.String(),
+			ShortName:       sr.Params.ShortName(),
+			Found:           sr.Found,
+			RetirementDates: sr.RetirementDates,
+			RetirementAges:  sr.RetirementAges,
+			YearsEarlier:    sr.YearsEarlier,
+		}
+		if sr.Found {
+			summary := convertToAPISummary(sr.SimulationResult, false, config.Financial.IncomeInflationRate)
+			summary.Warnings = CheckCompliance(sr.SimulationResult, config)
+			result.Summary = &summary
+		}
+		response.Results = append(response.Results, result)
+	}
+	if bestIdx := FindBestRetirementSearch(searchResults); bestIdx >= 0 {
+		response.Best = &response.Results[bestIdx]
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// APISavingsSearchRequest extends the simulation request with required saving search fields
+type APISavingsSearchRequest struct {
+	APISimulationRequest
+	SavingPerson  string `json:"saving_person"`  // Person making the extra saving (default: simulation reference person)
+	SavingType    string `json:"saving_type"`    // "monthly" (default) or "lump_sum"
+	SavingWrapper string `json:"saving_wrapper"` // "isa" (default) or "pension"
+}
+
+// APISavingsSearchResult is the minimum extra saving for one strategy
+type APISavingsSearchResult struct {
+	Strategy        string            `json:"strategy"`
+	ShortName       string            `json:"short_name"`
+	Found           bool              `json:"found"`
+	AlreadyViable   bool              `json:"already_viable"`
+	Amount          float64           `json:"amount"`
+	TotalSaved      float64           `json:"total_saved"`
+	BaselineOutYear int               `json:"baseline_out_year,omitempty"`
+	Summary         *APIResultSummary `json:"summary,omitempty"`
+}
+
+// APISavingsSearchResponse returns the minimum extra saving for every strategy
+type APISavingsSearchResponse struct {
+	Success       bool                     `json:"success"`
+	Error         string                   `json:"error,omitempty"`
+	SavingPerson  string                   `json:"saving_person"`
+	SavingType    string                   `json:"saving_type"`
+	SavingWrapper string                   `json:"saving_wrapper"`
+	SavingYears   int                      `json:"saving_years"`
+	Results       []APISavingsSearchResult `json:"results,omitempty"`
+	Best          *APISavingsSearchResult  `json:"best,omitempty"`
+}
+
+// handleSavingsSearch finds the minimum extra saving before retirement for each strategy
+func (ws *WebServer) handleSavingsSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req APISavingsSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(APISavingsSearchResponse{Success: false, Error: "Invalid request body: " + err.Error()})
+		return
+	}
+
+	config := ws.buildConfig(&req.APISimulationRequest)
+
+	// Save config to config.yaml for persistence
+	if err := SaveConfig(config, "config.yaml"); err != nil {
+		log.Printf("Warning: failed to save config: %v", err)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
+	if req.SavingPerson != "" && config.FindPerson(req.SavingPerson) == nil {
+		json.NewEncoder(w).Encode(APISavingsSearchResponse{Success: false, Error: "Unknown saving_person: " + req.SavingPerson})
+		return
+	}
+
+	config.Control = NewRunControl(r.Context())
+	searchResults := RunAllSavingsSearches(config, req.SavingPerson, req.SavingType, req.SavingWrapper)
+	if err := config.Control.Err(); err != nil {
+		log.Printf("Required saving search cancelled: %v", err)
+		return
+	}
+	response := APISavingsSearchResponse{Success: true}
+	for _, sr := range searchResults {
+		response.SavingPerson = sr.Person
+		response.SavingType = sr.SavingType
+		response.SavingWrapper = sr.Wrapper
+		response.SavingYears = sr.SavingYears
+
+		result := APISavingsSearchResult{
+			Strategy:        sr.Params.String(),
+			ShortName:       sr.Params.ShortName(),
+			Found:           sr.Found,
+			AlreadyViable:   sr.AlreadyViable,
+			Amount:          sr.Amount,
+			TotalSaved:      totalExtraSaving(sr),
+			BaselineOutYear: sr.BaselineOutYear,
+		}
+		if sr.Found {
+			summary := convertToAPISummary(sr.SimulationResult, false, config.Financial.IncomeInflationRate)
+			summary.Warnings = CheckCompliance(sr.SimulationResult, config)
+			result.Summary = &summary
+		}
+		response.Results = append(response.Results, result)
+	}
+	if bestIdx := FindBestSavingsSearch(searchResults); bestIdx >= 0 {
+		response.Best = &response.Results[bestIdx]
+	}
+
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -909,6 +1200,27 @@ func (ws *WebServer) handleSensitivityGrid(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	config := ws.buildSensitivityConfig(&req)
+
+	// Save config to config.yaml for persistence
+	if err := SaveConfig(config, "config.yaml"); err != nil {
+		log.Printf("Warning: failed to save config: %v", err)
+	}
+
+	goal := parseOptimizationGoal(req.OptimizationGoal)
+	config.Control = NewRunControl(r.Context())
+	response := ws.runSensitivityGrid(config, req.Mode, goal)
+	if err := config.Control.Err(); err != nil {
+		log.Printf("Sensitivity analysis cancelled: %v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// buildSensitivityConfig creates a Config from the API request with its growth rate ranges
+func (ws *WebServer) buildSensitivityConfig(req *APISensitivityRequest) *Config {
 	config := ws.buildConfig(&req.APISimulationRequest)
 
 	// Set sensitivity ranges
@@ -930,17 +1242,7 @@ func (ws *WebServer) handleSensitivityGrid(w http.ResponseWriter, r *http.Reques
 		config.Sensitivity.SavingsGrowthMin = 0.04
 		config.Sensitivity.SavingsGrowthMax = 0.12
 	}
-
-	// Save config to config.yaml for persistence
-	if err := SaveConfig(config, "config.yaml"); err != nil {
-		log.Printf("Warning: failed to save config: %v", err)
-	}
-
-	goal := parseOptimizationGoal(req.OptimizationGoal)
-	response := ws.runSensitivityGrid(config, req.Mode, goal)
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	return config
 }
 
 // runSensitivityGrid runs sensitivity analysis based on mode
@@ -1025,6 +1327,7 @@ func (ws *WebServer) buildConfig(req *APISimulationRequest) *Config {
 		Simulation:         req.Simulation,
 		TaxBands:           req.TaxBands,
 		Tax:                req.Tax,
+		Care:               req.Care,
 	}
 
 	// Use defaults for missing values
@@ -1047,6 +1350,16 @@ func (ws *WebServer) buildConfig(req *APISimulationRequest) *Config {
 			config.Simulation.EndAge = 95
 		}
 	}
+	// Concurrency and the cache directory are server settings, not sent by the UI
+	if ws.config != nil {
+		if config.Simulation.Concurrency == 0 {
+			config.Simulation.Concurrency = ws.config.Simulation.Concurrency
+		}
+		if config.Simulation.CacheDir == "" {
+			config.Simulation.CacheDir = ws.config.Simulation.CacheDir
+		}
+	}
+	config.Cache = ws.cache
 	if len(config.TaxBands) == 0 {
 		if ws.config != nil && len(ws.config.TaxBands) > 0 {
 			config.TaxBands = ws.config.TaxBands
@@ -1064,6 +1377,37 @@ func (ws *WebServer) buildConfig(req *APISimulationRequest) *Config {
 		}
 	}
 
+	// Use configured care settings if none were sent
+	if config.Care == (CareConfig{}) && ws.config != nil {
+		config.Care = ws.config.Care
+	}
+
+	// Strategy options sent override the configured ones
+	if ws.config != nil {
+		config.Strategy = ws.config.Strategy
+	}
+	if req.Strategy.MaximizeCoupleISA != nil {
+		config.Strategy.MaximizeCoupleISA = req.Strategy.MaximizeCoupleISA
+	}
+	if req.Strategy.IncludeOptimal {
+		config.Strategy.IncludeOptimal = true
+	}
+	if req.Strategy.OptimalObjective != "" {
+		config.Strategy.OptimalObjective = req.Strategy.OptimalObjective
+	}
+	if len(req.Strategy.Factors) > 0 {
+		config.Strategy.Factors = req.Strategy.Factors
+	}
+	if len(req.Strategy.Constraints) > 0 {
+		config.Strategy.Constraints = req.Strategy.Constraints
+	}
+	// Scoring weights sent replace the configured ones; choosing a built-in goal drops them
+	if req.Strategy.Scoring.IsSet() {
+		config.Strategy.Scoring = req.Strategy.Scoring
+	} else if goal := parseOptimizationGoal(req.OptimizationGoal); req.OptimizationGoal != "" && goal != OptimizeCustom {
+		config.Strategy.Scoring = nil
+	}
+
 	// Debug: log TaxBandInflation value
 	log.Printf("DEBUG: TaxBandInflation = %.4f, StartYear = %d", config.Financial.TaxBandInflation, config.Simulation.StartYear)
 
@@ -1283,10 +1627,49 @@ func (ws *WebServer) handleStockIndices(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
+// APIValidateResponse lists the problems found in a configuration
+type APIValidateResponse struct {
+	Valid  bool         `json:"valid"`
+	Errors ConfigErrors `json:"errors"`
+}
+
+// handleValidate checks a configuration without running it
+// The body is either a simulation request (JSON) or a config file (Content-Type application/yaml)
+func (ws *WebServer) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var errs ConfigErrors
+	if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			sendJSONError(w, "Invalid request body: "+err.Error())
+			return
+		}
+		_, errs = ParseConfig([]byte(preprocessPercentages(string(data))))
+	} else {
+		var req APISimulationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			sendJSONError(w, "Invalid request body: "+err.Error())
+			return
+		}
+		errs = ValidateConfig(ws.buildConfig(&req))
+	}
+	if errs == nil {
+		errs = ConfigErrors{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(APIValidateResponse{Valid: len(errs) == 0, Errors: errs})
+}
+
 // APIPDFExportRequest extends APISimulationRequest with strategy index for PDF export
 type APIPDFExportRequest struct {
 	APISimulationRequest
-	StrategyIdx int `json:"strategy_idx"` // Index of the strategy to export
+	StrategyIdx int    `json:"strategy_idx"`         // Index of the strategy to export
+	ResultKey   string `json:"result_key,omitempty"` // Cache key of the displayed result (preferred over strategy_idx)
 }
 
 // PDFExportResponse represents the response from PDF export
@@ -1319,62 +1702,16 @@ func (ws *WebServer) handleExportPDF(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build config using the standard method
-	config := ws.buildConfig(&req.APISimulationRequest)
-
-	// Get the strategies using the SAME function as the original simulation
-	// This is critical - using a different function would give different strategy indices
-	var strategies []SimulationParams
-	switch req.Mode {
-	case "depletion":
-		strategies = GetDepletionStrategiesForConfig(config)
-	case "pension-only":
-		strategies = GetPensionOnlyStrategiesForConfig(config)
-	case "pension-to-isa":
-		strategies = GetPensionToISAStrategiesForConfig(config)
-	default: // "fixed"
-		strategies = getStrategiesWithMode(config, req.PermutationMode)
-	}
-	if req.StrategyIdx < 0 || req.StrategyIdx >= len(strategies) {
+	pdfConfig, result, err := ws.exportResult(&req)
+	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(PDFExportResponse{
 			Success: false,
-			Message: fmt.Sprintf("Invalid strategy index: %d (max: %d)", req.StrategyIdx, len(strategies)-1),
+			Message: err.Error(),
 		})
 		return
 	}
 
-	// Apply config settings
-	maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
-	strategies[req.StrategyIdx].MaximizeCoupleISA = maximizeCoupleISA
-
-	// Run the simulation for this strategy
-	// In depletion mode, we need to run the depletion calculation to get the sustainable income
-	var result SimulationResult
-	var pdfConfig *Config = config
-
-	if req.Mode == "depletion" || req.Mode == "pension-only" || req.Mode == "pension-to-isa" {
-		// For depletion modes, run the appropriate calculation
-		var depletionResult DepletionResult
-		switch req.Mode {
-		case "pension-only":
-			depletionResult = CalculatePensionOnlyDepletionIncome(strategies[req.StrategyIdx], config)
-		case "pension-to-isa":
-			depletionResult = CalculateDepletionIncome(strategies[req.StrategyIdx], config)
-		default: // "depletion"
-			depletionResult = CalculateDepletionIncome(strategies[req.StrategyIdx], config)
-		}
-		result = depletionResult.SimulationResult
-
-		// Create a modified config with the calculated income for PDF display
-		pdfConfig = &Config{}
-		*pdfConfig = *config
-		pdfConfig.IncomeRequirements.MonthlyBeforeAge = depletionResult.MonthlyBeforeAge
-		pdfConfig.IncomeRequirements.MonthlyAfterAge = depletionResult.MonthlyAfterAge
-	} else {
-		result = RunSimulationV2(strategies[req.StrategyIdx], config)
-	}
-
 	// Generate the PDF
 	pdfBytes, err := GenerateStrategyPDFReport(pdfConfig, result)
 	if err != nil {
@@ -1426,25 +1763,35 @@ func (ws *WebServer) handleExportPDF(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleDownloadPDF returns PDF content directly for browser download
-func (ws *WebServer) handleDownloadPDF(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	// Parse the request
-	var req APIPDFExportRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request: "+err.Error(), http.StatusBadRequest)
-		return
+// exportResult returns the result to export and the config to show it with
+// The displayed result is taken from the cache by its key, with the config it was calculated for;
+// otherwise the strategy is found by index and run again (through the cache, so later exports are instant)
+func (ws *WebServer) exportResult(req *APIPDFExportRequest) (*Config, SimulationResult, error) {
+	if entry, ok := ws.cache.Lookup(req.ResultKey); ok {
+		config, err := entry.Config()
+		if err != nil {
+			return nil, SimulationResult{}, err
+		}
+		if entry.Depletion != nil {
+			return depletionPDFConfig(config, *entry.Depletion), entry.Depletion.SimulationResult, nil
+		}
+		return config, *entry.Simulation, nil
 	}
 
 	// Build config using the standard method
 	config := ws.buildConfig(&req.APISimulationRequest)
 
-	// Get the strategies based on mode and permutation mode
-	// Must use the same strategy retrieval function as the original simulation
+	// Adaptive indices number the search space, which is never listed
+	if (req.Mode == "fixed" || req.Mode == "") && parsePermutationMode(req.PermutationMode) == ModeAdaptive {
+		params, ok := AdaptiveStrategy(config, req.StrategyIdx)
+		if !ok {
+			return nil, SimulationResult{}, fmt.Errorf("Invalid strategy index: %d", req.StrategyIdx)
+		}
+		return config, runCachedSimulationV2(params, config), nil
+	}
+
+	// Get the strategies using the SAME function as the original simulation
+	// This is critical - using a different function would give different strategy indices
 	var strategies []SimulationParams
 	switch req.Mode {
 	case "depletion":
@@ -1457,39 +1804,54 @@ func (ws *WebServer) handleDownloadPDF(w http.ResponseWriter, r *http.Request) {
 		strategies = getStrategiesWithMode(config, req.PermutationMode)
 	}
 	if req.StrategyIdx < 0 || req.StrategyIdx >= len(strategies) {
-		http.Error(w, fmt.Sprintf("Invalid strategy index: %d (max: %d)", req.StrategyIdx, len(strategies)-1), http.StatusBadRequest)
+		return nil, SimulationResult{}, fmt.Errorf("Invalid strategy index: %d (max: %d)", req.StrategyIdx, len(strategies)-1)
+	}
+	params := strategies[req.StrategyIdx]
+
+	// Apply config settings as the original simulation did
+	if req.Mode != "pension-only" {
+		params.MaximizeCoupleISA = config.Strategy.ShouldMaximizeCoupleISA()
+	}
+
+	// In depletion modes, run the depletion calculation to get the sustainable income
+	switch req.Mode {
+	case "pension-only":
+		dr := runCachedDepletion(cacheKindPensionOnly, params, config, CalculatePensionOnlyDepletionIncome)
+		return depletionPDFConfig(config, dr), dr.SimulationResult, nil
+	case "depletion", "pension-to-isa":
+		dr := runCachedDepletion(cacheKindDepletion, params, config, CalculateDepletionIncome)
+		return depletionPDFConfig(config, dr), dr.SimulationResult, nil
+	default:
+		return config, runCachedSimulationV2(params, config), nil
+	}
+}
+
+// depletionPDFConfig returns a copy of config with the calculated income for PDF display
+func depletionPDFConfig(config *Config, dr DepletionResult) *Config {
+	pdfConfig := *config
+	pdfConfig.IncomeRequirements.MonthlyBeforeAge = dr.MonthlyBeforeAge
+	pdfConfig.IncomeRequirements.MonthlyAfterAge = dr.MonthlyAfterAge
+	return &pdfConfig
+}
+
+// handleDownloadPDF returns PDF content directly for browser download
+func (ws *WebServer) handleDownloadPDF(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// Apply config settings
-	maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
-	strategies[req.StrategyIdx].MaximizeCoupleISA = maximizeCoupleISA
-
-	// Run the simulation for this strategy
-	// In depletion mode, we need to run the depletion calculation to get the sustainable income
-	var result SimulationResult
-	var pdfConfig *Config = config
-
-	if req.Mode == "depletion" || req.Mode == "pension-only" || req.Mode == "pension-to-isa" {
-		// For depletion modes, run the appropriate calculation
-		var depletionResult DepletionResult
-		switch req.Mode {
-		case "pension-only":
-			depletionResult = CalculatePensionOnlyDepletionIncome(strategies[req.StrategyIdx], config)
-		case "pension-to-isa":
-			depletionResult = CalculateDepletionIncome(strategies[req.StrategyIdx], config)
-		default: // "depletion"
-			depletionResult = CalculateDepletionIncome(strategies[req.StrategyIdx], config)
-		}
-		result = depletionResult.SimulationResult
+	// Parse the request
+	var req APIPDFExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
 
-		// Create a modified config with the calculated income for PDF display
-		pdfConfig = &Config{}
-		*pdfConfig = *config
-		pdfConfig.IncomeRequirements.MonthlyBeforeAge = depletionResult.MonthlyBeforeAge
-		pdfConfig.IncomeRequirements.MonthlyAfterAge = depletionResult.MonthlyAfterAge
-	} else {
-		result = RunSimulationV2(strategies[req.StrategyIdx], config)
+	pdfConfig, result, err := ws.exportResult(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	// Generate the PDF
@@ -1516,6 +1878,8 @@ func parseOptimizationGoal(s string) OptimizationGoal {
 		return OptimizeIncome
 	case "balance":
 		return OptimizeBalance
+	case "custom":
+		return OptimizeCustom
 	default:
 		return OptimizeTax // Default to tax efficiency
 	}
@@ -1529,6 +1893,8 @@ func parsePermutationMode(s string) PermutationMode {
 		return ModeThorough
 	case "comprehensive":
 		return ModeComprehensive
+	case "adaptive":
+		return ModeAdaptive
 	default:
 		return ModeStandard // Default to standard
 	}
@@ -1540,106 +1906,65 @@ type topNTracker struct {
 	maxResults     int
 	results        []APIResultSummary
 	scores         []float64 // Combined score for comparison (higher is better)
-	goal           OptimizationGoal
+	weights        ScoringWeights
 	inflationRate  float64
-	isFixedMode    bool // True for fixed income mode (ignores optimization goal)
-}
-
-// newTopNTracker creates a tracker that keeps only the top N results
-func newTopNTracker(n int, goal OptimizationGoal, inflationRate float64) *topNTracker {
-	return &topNTracker{
-		maxResults:    n,
-		results:       make([]APIResultSummary, 0, n+1),
-		scores:        make([]float64, 0, n+1),
-		goal:          goal,
-		inflationRate: inflationRate,
-		isFixedMode:   false,
-	}
+	points         []APIFrontierPoint // Frontier objectives of every result added, kept or not
 }
 
-// newTopNTrackerFixed creates a tracker for fixed income mode
-// In fixed mode, ranking is: doesn't run out → highest balance → lowest tax
-// For strategies that run out: longest duration wins
-func newTopNTrackerFixed(n int, inflationRate float64) *topNTracker {
+// newTopNTracker creates a tracker that keeps only the top N results, ranked by the weights
+func newTopNTracker(n int, weights ScoringWeights, inflationRate float64) *topNTracker {
 	return &topNTracker{
 		maxResults:    n,
 		results:       make([]APIResultSummary, 0, n+1),
 		scores:        make([]float64, 0, n+1),
-		goal:          OptimizeBalance, // Not used in fixed mode
+		weights:       weights,
 		inflationRate: inflationRate,
-		isFixedMode:   true,
 	}
 }
 
 // calcScore calculates a single score for comparison (higher is always better)
-func (t *topNTracker) calcScore(summary APIResultSummary, simResult SimulationResult) float64 {
-	// Fixed income mode: income is the same for all strategies that don't run out
-	// So rank by: doesn't run out → highest balance → lowest tax
-	// For strategies that run out: longest duration wins
-	if t.isFixedMode {
-		if simResult.RanOutOfMoney {
-			// Running out: rank by how long it lasts (later year = better)
-			// Use negative base so all running-out are worse than any non-running-out
-			return -1e12 + float64(simResult.RanOutYear)
-		}
-		// Not running out: rank by balance, with lower tax as tiebreaker
-		// Tax is typically 0-500k range, so scale down to be a small tiebreaker
-		return summary.FinalBalance - simResult.TotalTaxPaid*0.0001
-	}
-
-	// Depletion modes: use optimization goal
-	ranOutPenalty := 0.0
-	if simResult.RanOutOfMoney {
-		ranOutPenalty = -1e12 // Large penalty
-	}
+func (t *topNTracker) calcScore(simResult SimulationResult) float64 {
+	return t.weights.ScoreResult(simResult, t.inflationRate)
+}
 
-	taxEfficiency := 1.0
-	if simResult.TotalWithdrawn > 0 {
-		taxEfficiency = simResult.TotalTaxPaid / simResult.TotalWithdrawn
+// add tries to add a result to the tracker, checking compliance only for results it keeps
+// Returns true if the result was kept, false if it was discarded
+func (t *topNTracker) add(summary APIResultSummary, simResult SimulationResult, config *Config) bool {
+	score := t.calcScore(simResult)
+	if t.admits(score) {
+		summary.Warnings = CheckCompliance(simResult, config)
 	}
+	return t.addScored(summary, score)
+}
 
-	switch t.goal {
-	case OptimizeIncome:
-		// Higher income better, lower tax efficiency better (as tiebreaker)
-		return summary.TotalIncome - taxEfficiency*1000 + ranOutPenalty
-	case OptimizeBalance:
-		// Higher balance better, higher income better (as tiebreaker)
-		return summary.FinalBalance + summary.TotalIncome*0.001 + ranOutPenalty
-	default: // OptimizeTax
-		// Lower tax better (negate for higher=better), higher income better (as tiebreaker)
-		return -summary.TotalTaxPaid + summary.TotalIncome*0.0001 + ranOutPenalty
-	}
+// admits returns true if a result with the score would be kept
+func (t *topNTracker) admits(score float64) bool {
+	return len(t.results) < t.maxResults || score > t.scores[len(t.scores)-1]
 }
 
-// add tries to add a result to the tracker
-// Returns true if the result was kept, false if it was discarded
-func (t *topNTracker) add(summary APIResultSummary, simResult SimulationResult) bool {
-	score := t.calcScore(summary, simResult)
+// addScored tries to add a result with a precomputed score
+func (t *topNTracker) addScored(summary APIResultSummary, score float64) bool {
+	t.points = append(t.points, newAPIFrontierPoint(summary))
 
-	// If we have room, just add it
-	if len(t.results) < t.maxResults {
-		t.insertSorted(summary, score)
-		return true
+	if !t.admits(score) {
+		return false // Not good enough - discard
 	}
 
-	// Check if better than the worst (last) result
-	if score > t.scores[len(t.scores)-1] {
-		// Remove the worst and insert the new one
+	// Remove the worst (last) result if full, and insert the new one
+	if len(t.results) == t.maxResults {
 		t.results = t.results[:len(t.results)-1]
 		t.scores = t.scores[:len(t.scores)-1]
-		t.insertSorted(summary, score)
-		return true
 	}
-
-	// Not good enough - discard
-	return false
+	t.insertSorted(summary, score)
+	return true
 }
 
 // insertSorted inserts a result in the correct position to maintain sorted order
 func (t *topNTracker) insertSorted(summary APIResultSummary, score float64) {
 	// Find insertion point (binary search would be faster but N is small)
+	// Equal scores go after existing results, so ties keep the earlier strategy first as FindBestResult does
 	pos := 0
-	for pos < len(t.scores) && t.scores[pos] > score {
+	for pos < len(t.scores) && t.scores[pos] >= score {
 		pos++
 	}
 
@@ -1669,53 +1994,15 @@ func (t *topNTracker) getBest() *APIResultSummary {
 	return &t.results[0]
 }
 
-// calcScoreDepletion calculates score using monthly income (for depletion modes)
-func (t *topNTracker) calcScoreDepletion(summary APIResultSummary, simResult SimulationResult, monthlyIncome float64) float64 {
-	// Penalty for running out of money (applies to all optimization goals)
-	ranOutPenalty := 0.0
-	if simResult.RanOutOfMoney {
-		ranOutPenalty = -1e12 // Large penalty
-	}
-
-	taxEfficiency := 1.0
-	if simResult.TotalWithdrawn > 0 {
-		taxEfficiency = simResult.TotalTaxPaid / simResult.TotalWithdrawn
-	}
-
-	switch t.goal {
-	case OptimizeIncome:
-		// Higher monthly income better, lower tax efficiency better (as tiebreaker)
-		return monthlyIncome - taxEfficiency*10 + ranOutPenalty
-	case OptimizeBalance:
-		// Higher balance better, higher monthly income better (as tiebreaker)
-		return summary.FinalBalance + monthlyIncome*0.1 + ranOutPenalty
-	default: // OptimizeTax
-		// Lower tax better (negate for higher=better), higher monthly income better (as tiebreaker)
-		return -summary.TotalTaxPaid + monthlyIncome*0.001 + ranOutPenalty
-	}
-}
-
-// addDepletion tries to add a depletion result using monthly income for scoring
-func (t *topNTracker) addDepletion(summary APIResultSummary, simResult SimulationResult, monthlyIncome float64) bool {
-	score := t.calcScoreDepletion(summary, simResult, monthlyIncome)
-
-	// If we have room, just add it
-	if len(t.results) < t.maxResults {
-		t.insertSorted(summary, score)
-		return true
-	}
-
-	// Check if better than the worst (last) result
-	if score > t.scores[len(t.scores)-1] {
-		// Remove the worst and insert the new one
-		t.results = t.results[:len(t.results)-1]
-		t.scores = t.scores[:len(t.scores)-1]
-		t.insertSorted(summary, score)
-		return true
+// response returns the ranked results, best result and Pareto frontier of everything added
+func (t *topNTracker) response(config *Config) APISimulationResponse {
+	return APISimulationResponse{
+		Success:       true,
+		Results:       t.getResults(),
+		Best:          t.getBest(),
+		GrowthDecline: buildGrowthDeclineInfo(config),
+		Frontier:      apiFrontier(t.points),
 	}
-
-	// Not good enough - discard
-	return false
 }
 
 // getStrategiesWithMode returns strategies based on the permutation mode
@@ -1731,46 +2018,55 @@ func getStrategiesWithMode(config *Config, permMode string) []SimulationParams {
 func (ws *WebServer) runFixedSimulation(config *Config, goal OptimizationGoal, permMode string) APISimulationResponse {
 	const maxResults = 40 // Only keep top 40 results to save memory
 
-	// Get strategies based on permutation mode
-	strategies := getStrategiesWithMode(config, permMode)
-
-	// Apply config settings to strategies
-	maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
-	for i := range strategies {
-		strategies[i].MaximizeCoupleISA = maximizeCoupleISA
-	}
-
-	// Use fixed mode tracker: rank by balance (not income, since income is fixed)
+	// Rank by balance (not income, since income is fixed) unless scoring weights are set
 	// For strategies that run out: rank by longest duration
-	tracker := newTopNTrackerFixed(maxResults, config.Financial.IncomeInflationRate)
+	weights := config.Strategy.GetScoringWeights(OptimizeBalance)
+	tracker := newTopNTracker(maxResults, weights, config.Financial.IncomeInflationRate)
 
-	// Run all strategies, but only keep top N results
-	for i, params := range strategies {
-		result := RunSimulationV2(params, config)
+	record := func(i int, result SimulationResult) {
 		summary := convertToAPISummary(result, true, config.Financial.IncomeInflationRate)
 		summary.StrategyIdx = i // Track original index for PDF export
 
 		// Add to tracker - it will discard if not in top N
-		tracker.add(summary, result)
+		tracker.add(summary, result, config)
 		// result is now eligible for garbage collection if not kept
 	}
 
-	// Get the final results with ranks assigned
-	results := tracker.getResults()
-	bestResult := tracker.getBest()
+	var search *APISearchInfo
+	if parsePermutationMode(permMode) == ModeAdaptive {
+		// Only the combinations the search visits are generated and run, recorded in strategy order
+		found := AdaptiveSearch(config, weights)
+		for j, i := range found.Indices {
+			record(i, found.Results[j])
+		}
+		search = &APISearchInfo{Evaluated: found.Evaluated, SpaceSize: found.SpaceSize}
+	} else {
+		// Get strategies based on permutation mode
+		strategies := getStrategiesWithMode(config, permMode)
 
-	return APISimulationResponse{
-		Success:       true,
-		Results:       results,
-		Best:          bestResult,
-		GrowthDecline: buildGrowthDeclineInfo(config),
+		// Apply config settings to strategies
+		maximizeCoupleISA := config.Strategy.ShouldMaximizeCoupleISA()
+		for i := range strategies {
+			strategies[i].MaximizeCoupleISA = maximizeCoupleISA
+		}
+
+		// Run all strategies concurrently, but only keep top N results
+		// Results arrive in strategy order, so ranking ties break the same way every run
+		if err := parallelOrdered(config.Control, len(strategies), config.Simulation.GetConcurrency(), func(i int) SimulationResult {
+			return runCachedSimulationV2(strategies[i], config)
+		}, record); err != nil {
+			return APISimulationResponse{Success: false, Error: "Simulation cancelled"}
+		}
 	}
+
+	// Get the final results with ranks assigned
+	response := tracker.response(config)
+	response.Search = search
+	return response
 }
 
 // runDepletionSimulation runs depletion mode
 func (ws *WebServer) runDepletionSimulation(config *Config, goal OptimizationGoal) APISimulationResponse {
-	const maxResults = 40 // Only keep top 40 results to save memory
-
 	if config.IncomeRequirements.TargetDepletionAge <= 0 {
 		return APISimulationResponse{
 			Success: false,
@@ -1778,33 +2074,11 @@ func (ws *WebServer) runDepletionSimulation(config *Config, goal OptimizationGoa
 		}
 	}
 
-	depletionResults := RunAllDepletionCalculations(config)
-
-	// Use tracker to keep only top results
-	tracker := newTopNTracker(maxResults, goal, config.Financial.IncomeInflationRate)
-
-	for i, dr := range depletionResults {
-		summary := convertToAPISummary(dr.SimulationResult, true, config.Financial.IncomeInflationRate)
-		summary.StrategyIdx = i // Track original index for PDF export
-		summary.MonthlyIncome = dr.MonthlyBeforeAge
-		tracker.addDepletion(summary, dr.SimulationResult, dr.MonthlyBeforeAge)
-	}
-
-	results := tracker.getResults()
-	bestResult := tracker.getBest()
-
-	return APISimulationResponse{
-		Success:       true,
-		Results:       results,
-		Best:          bestResult,
-		GrowthDecline: buildGrowthDeclineInfo(config),
-	}
+	return depletionResponse(config, goal, RunAllDepletionCalculations(config))
 }
 
 // runPensionOnlySimulation runs pension-only depletion mode
 func (ws *WebServer) runPensionOnlySimulation(config *Config, goal OptimizationGoal, permMode string) APISimulationResponse {
-	const maxResults = 40 // Only keep top 40 results to save memory
-
 	// Note: permMode is currently unused as pension-only uses specialized GetPensionOnlyStrategiesForConfig
 	// This could be extended in the future to use V2 with filtered drawdown orders
 	if config.IncomeRequirements.TargetDepletionAge <= 0 {
@@ -1814,35 +2088,11 @@ func (ws *WebServer) runPensionOnlySimulation(config *Config, goal OptimizationG
 		}
 	}
 
-	depletionResults := RunPensionOnlyDepletionCalculations(config)
-
-	// Use tracker to keep only top results
-	tracker := newTopNTracker(maxResults, goal, config.Financial.IncomeInflationRate)
-
-	for i, dr := range depletionResults {
-		summary := convertToAPISummary(dr.SimulationResult, true, config.Financial.IncomeInflationRate)
-		summary.StrategyIdx = i // Track original index for PDF export
-		summary.MonthlyIncome = dr.MonthlyBeforeAge
-		// Calculate final ISA from simulation result
-		summary.FinalISA = calculateFinalISA(dr.SimulationResult)
-		tracker.addDepletion(summary, dr.SimulationResult, dr.MonthlyBeforeAge)
-	}
-
-	results := tracker.getResults()
-	bestResult := tracker.getBest()
-
-	return APISimulationResponse{
-		Success:       true,
-		Results:       results,
-		Best:          bestResult,
-		GrowthDecline: buildGrowthDeclineInfo(config),
-	}
+	return depletionResponse(config, goal, RunPensionOnlyDepletionCalculations(config))
 }
 
 // runPensionToISASimulation runs pension-to-ISA mode
 func (ws *WebServer) runPensionToISASimulation(config *Config, goal OptimizationGoal, permMode string) APISimulationResponse {
-	const maxResults = 40 // Only keep top 40 results to save memory
-
 	// Note: permMode is currently unused as pension-to-ISA uses specialized GetPensionToISAStrategiesForConfig
 	// This could be extended in the future to use V2 with filtered drawdown orders
 	if config.IncomeRequirements.TargetDepletionAge <= 0 {
@@ -1852,29 +2102,71 @@ func (ws *WebServer) runPensionToISASimulation(config *Config, goal Optimization
 		}
 	}
 
-	depletionResults := RunPensionToISADepletionCalculations(config)
+	return depletionResponse(config, goal, RunPensionToISADepletionCalculations(config))
+}
 
-	// Use tracker to keep only top results
-	tracker := newTopNTracker(maxResults, goal, config.Financial.IncomeInflationRate)
+// depletionResponse ranks depletion results by the goal's weights, keeping the top results
+// and the Pareto frontier of every strategy
+func depletionResponse(config *Config, goal OptimizationGoal, depletionResults []DepletionResult) APISimulationResponse {
+	const maxResults = 40 // Only keep top 40 results to save memory
 
+	weights := config.Strategy.GetScoringWeights(goal)
+	tracker := newTopNTracker(maxResults, weights, config.Financial.IncomeInflationRate)
 	for i, dr := range depletionResults {
 		summary := convertToAPISummary(dr.SimulationResult, true, config.Financial.IncomeInflationRate)
 		summary.StrategyIdx = i // Track original index for PDF export
 		summary.MonthlyIncome = dr.MonthlyBeforeAge
-		// Calculate final ISA from simulation result
+		summary.LegacyCostPer100k = dr.LegacyCostPer100k
 		summary.FinalISA = calculateFinalISA(dr.SimulationResult)
-		tracker.addDepletion(summary, dr.SimulationResult, dr.MonthlyBeforeAge)
+		summary.TerminalTarget = dr.TerminalTarget.Total
+		summary.TierAmounts = dr.TierAmounts
+		summary.MinimumsUnmet = dr.MinimumsUnmet
+		score := weights.ScoreDepletionResult(dr, config)
+		if tracker.admits(score) {
+			summary.Warnings = CheckCompliance(dr.SimulationResult, config)
+		}
+		tracker.addScored(summary, score)
 	}
+	return tracker.response(config)
+}
 
-	results := tracker.getResults()
-	bestResult := tracker.getBest()
+// newAPIFrontierPoint copies the frontier objectives from a result summary
+func newAPIFrontierPoint(summary APIResultSummary) APIFrontierPoint {
+	point := APIFrontierPoint{
+		StrategyIdx:     summary.StrategyIdx,
+		ShortName:       summary.ShortName,
+		DescriptiveName: summary.DescriptiveName,
+		TotalTaxPaid:    summary.TotalTaxPaid,
+		TotalIncome:     summary.TotalIncome,
+		FinalBalance:    summary.FinalBalance,
+		MonthlyIncome:   summary.MonthlyIncome,
+	}
+	if summary.RanOutOfMoney {
+		point.ShortfallYear = summary.RanOutYear
+	}
+	return point
+}
 
-	return APISimulationResponse{
-		Success:       true,
-		Results:       results,
-		Best:          bestResult,
-		GrowthDecline: buildGrowthDeclineInfo(config),
+// apiFrontier returns the points on the Pareto frontier
+// Points are collected for every strategy, so the frontier is not limited to the top N results
+func apiFrontier(points []APIFrontierPoint) []APIFrontierPoint {
+	candidates := make([]FrontierPoint, len(points))
+	for i, p := range points {
+		candidates[i] = FrontierPoint{
+			Index:         i,
+			Name:          p.ShortName,
+			TotalTax:      p.TotalTaxPaid,
+			TotalIncome:   p.TotalIncome,
+			FinalBalance:  p.FinalBalance,
+			ShortfallYear: p.ShortfallYear,
+		}
 	}
+
+	var frontier []APIFrontierPoint
+	for _, fp := range ParetoFrontier(candidates) {
+		frontier = append(frontier, points[fp.Index])
+	}
+	return frontier
 }
 
 // calculateFinalISA calculates the final ISA balance from a simulation result
@@ -1883,7 +2175,7 @@ func calculateFinalISA(result SimulationResult) float64 {
 	if len(result.Years) > 0 {
 		lastYear := result.Years[len(result.Years)-1]
 		for _, balances := range lastYear.EndBalances {
-			total += balances.TaxFreeSavings
+			total += balances.Savings()
 		}
 	}
 	return total
@@ -1974,11 +2266,17 @@ func convertToAPISummary(result SimulationResult, includeYears bool, incomeInfla
 	summary := APIResultSummary{
 		Strategy:       result.Params.String(),
 		ShortName:      result.Params.ShortName(),
+		ResultKey:      result.ResultKey,
 		TotalTaxPaid:   result.TotalTaxPaid,
 		TotalWithdrawn: result.TotalWithdrawn,
 		RanOutOfMoney:  result.RanOutOfMoney,
 		RanOutYear:     result.RanOutYear,
-		EarlyPayoff:    result.Params.MortgageOpt == MortgageEarly || result.Params.MortgageOpt == PCLSMortgagePayoff,
+		EarlyPayoff:    result.Params.PaysOffMortgageEarly(),
+		TotalCareCost:  result.TotalCareCost,
+		HomeSaleYear:   result.HomeSaleYear,
+
+		DiscretionaryCutYears: result.DiscretionaryCutYears,
+		CutYearsPercent:       result.CutYearsPercent(),
 	}
 
 	// Set mortgage option name
@@ -1995,20 +2293,12 @@ func convertToAPISummary(result SimulationResult, includeYears bool, incomeInfla
 
 	// Calculate final balance and final ISA
 	for _, bal := range result.FinalBalances {
-		summary.FinalBalance += bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot
-		summary.FinalISA += bal.TaxFreeSavings
+		summary.FinalBalance += bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot
+		summary.FinalISA += bal.Savings()
 	}
 
 	// Calculate total income across all years (deflated to today's purchasing power)
-	startYear := 0
-	if len(result.Years) > 0 {
-		startYear = result.Years[0].Year
-	}
-	for _, year := range result.Years {
-		yearsFromStart := year.Year - startYear
-		deflator := math.Pow(1+incomeInflationRate, float64(yearsFromStart))
-		summary.TotalIncome += year.NetIncomeReceived / deflator // Real value
-	}
+	summary.TotalIncome = realTotalIncome(result, incomeInflationRate)
 	if len(result.Years) > 0 {
 		summary.MonthlyIncome = summary.TotalIncome / float64(len(result.Years)) / 12.0
 	}
@@ -2022,7 +2312,7 @@ func convertToAPISummary(result SimulationResult, includeYears bool, incomeInfla
 		totalISA := 0.0
 		totalPension := 0.0
 		for _, bal := range year.EndBalances {
-			totalISA += bal.TaxFreeSavings
+			totalISA += bal.Savings()
 			totalPension += bal.UncrystallisedPot + bal.CrystallisedPot
 		}
 
@@ -2094,15 +2384,26 @@ func convertToAPISummary(result SimulationResult, includeYears bool, incomeInfla
 				PensionWithdrawal:   pensionWithdrawal,
 				TaxFreeWithdrawal:   taxFreeWithdrawal,
 				ISADeposit:          year.Withdrawals.TotalISADeposits,
+				GIADeposit:          year.Withdrawals.TotalGIADeposits,
+				GIATax:              year.GIATax,
 				PersonalAllowance:   year.PersonalAllowance,
 				BasicRateLimit:      year.BasicRateLimit,
+				CareCost:            year.CareCost,
+				CareFundedByCouncil: year.CareFundedByCouncil,
+				EssentialIncome:     year.EssentialIncome,
+				DiscretionaryCut:    year.DiscretionaryCut,
 			}
 			for name, bal := range year.EndBalances {
 				yearSummary.Balances[name] = APIPersonBalance{
 					ISA:               bal.TaxFreeSavings,
 					UncrystallisedPot: bal.UncrystallisedPot,
 					CrystallisedPot:   bal.CrystallisedPot,
-					Total:             bal.TaxFreeSavings + bal.CrystallisedPot + bal.UncrystallisedPot,
+					Total:             bal.Savings() + bal.CrystallisedPot + bal.UncrystallisedPot + bal.LISA,
+					GIA:               bal.GIA,
+					ISASubscribed:     bal.ISASubscribed,
+					ISAAllowanceLeft:  bal.ISAAllowanceLeft,
+					SavedToGIA:        bal.SavedToGIA,
+					LISA:              bal.LISA,
 				}
 			}
 			summary.Years = append(summary.Years, yearSummary)
@@ -2278,6 +2579,8 @@ const webUIHTML = `<!DOCTYPE html>
             border-color: var(--primary);
             box-shadow: 0 0 0 3px rgba(37, 99, 235, 0.1);
         }
+        .form-group input.field-error, .form-group select.field-error, input.field-error { border-color: var(--danger); }
+        .field-error-msg { color: var(--danger); font-size: 0.7rem; margin-top: 0.2rem; }
         .form-row { display: grid; grid-template-columns: repeat(2, 1fr); gap: 0.5rem; align-items: start; }
         .form-row-3 { display: grid; grid-template-columns: repeat(3, 1fr); gap: 0.5rem; align-items: start; }
         .form-row-4 { display: grid; grid-template-columns: repeat(4, 1fr); gap: 0.5rem; align-items: start; }
@@ -2494,6 +2797,9 @@ const webUIHTML = `<!DOCTYPE html>
             margin: 0 auto 1rem;
         }
         @keyframes spin { to { transform: rotate(360deg); } }
+        .job-progress { max-width: 360px; margin: 0 auto 0.75rem; height: 8px; background: var(--border); border-radius: 4px; overflow: hidden; }
+        .job-progress-fill { height: 100%; width: 0; background: var(--primary); transition: width 0.3s; }
+        .job-progress-text { font-size: 0.85rem; margin-bottom: 0.75rem; }
         .hidden { display: none !important; }
         .add-person-btn {
             display: flex;
@@ -2792,6 +3098,15 @@ const webUIHTML = `<!DOCTYPE html>
         .compare-stat.better .value { color: var(--success); }
         .compare-stat.worse .value { color: var(--danger); }
         .compare-years { max-height: 400px; overflow-y: auto; }
+        .frontier { background: var(--bg-darker); border: 1px solid var(--border); border-radius: 6px; padding: 0.75rem; margin-bottom: 0.75rem; }
+        .frontier-chart { width: 100%; max-width: 420px; display: block; margin-bottom: 0.5rem; }
+        .frontier-chart line { stroke: var(--border); }
+        .frontier-chart text { fill: var(--text-muted); font-size: 10px; }
+        .frontier-chart circle { fill: var(--success); cursor: pointer; }
+        .frontier-chart circle.shortfall { fill: var(--danger); }
+        .frontier-table { width: 100%; font-size: 0.75rem; border-collapse: collapse; }
+        .frontier-table th, .frontier-table td { padding: 0.25rem 0.5rem; text-align: right; border-bottom: 1px solid var(--border); }
+        .frontier-table th:first-child, .frontier-table td:first-child { text-align: left; }
         .compare-btn { margin-left: auto; background: var(--primary); color: white; border: none; padding: 0.5rem 1rem; border-radius: 6px; cursor: pointer; font-size: 0.8rem; }
         .compare-btn:hover { background: var(--primary-dark); }
 
@@ -2869,7 +3184,7 @@ const webUIHTML = `<!DOCTYPE html>
             <div style="text-align:left;font-size:0.85rem;line-height:1.6;">
                 <h4 style="color:var(--primary);margin:1rem 0 0.5rem;">Income Strategies</h4>
                 <p><strong>Guardrails (Guyton-Klinger):</strong> Automatically adjusts withdrawals when portfolio drifts too far from your initial withdrawal rate. Upper limit triggers a reduction, lower limit triggers an increase.</p>
-                <p><strong>VPW (Variable Percentage Withdrawal):</strong> Withdrawal rate increases with age (3% at 55 to 35% at 100), naturally adjusting to life expectancy. Optional floor/ceiling limits.</p>
+                <p><strong>VPW (Variable Percentage Withdrawal):</strong> Withdrawal rate increases with age (3.9% at 55 to 17.9% at 95, planning to age 100 at a 3% real return), naturally adjusting to life expectancy. Optional floor/ceiling limits.</p>
                 <p><strong>Emergency Fund Protection:</strong> Preserves a minimum ISA balance (X months of expenses) before drawing from other sources.</p>
                 <h4 style="color:var(--primary);margin:1rem 0 0.5rem;">DB Pension Options</h4>
                 <p><strong>Early/Late Retirement Factors:</strong> Takes your DB pension early? Reduced by the early factor per year. Taking it late? Enhanced by the late factor per year.</p>
@@ -2932,14 +3247,48 @@ const webUIHTML = `<!DOCTYPE html>
                     </div>
                 </div>
                 <div class="form-group" style="margin-top:1rem;" id="optimization-goal-group">
-                    <label>Optimization Goal <span class="depletion-only-hint">(depletion modes only)</span></label>
-                    <select id="optimization-goal" style="width:100%;padding:0.5rem;border-radius:4px;border:1px solid var(--border);">
+                    <label>Optimization Goal <span class="depletion-only-hint">(depletion modes only, except custom)</span></label>
+                    <select id="optimization-goal" onchange="updateCustomWeights()" style="width:100%;padding:0.5rem;border-radius:4px;border:1px solid var(--border);">
                         <option value="tax">Tax Efficiency (minimize tax paid)</option>
                         <option value="income" selected>Total Income (maximize withdrawals)</option>
                         <option value="balance">Final Balance (maximize end wealth)</option>
+                        <option value="custom">Custom Weights (score below)</option>
                     </select>
                     <div class="form-hint" id="fixed-mode-hint" style="display:none;">
-                        Fixed income mode ranks by: highest final balance, then lowest tax
+                        Fixed income mode ranks by: highest final balance, then lowest tax (unless Custom Weights is chosen)
+                    </div>
+                    <div id="custom-weights" style="display:none;margin-top:0.5rem;">
+                        <div class="form-row">
+                            <div class="form-group">
+                                <label>Tax (per £)</label>
+                                <input type="number" id="score-tax" value="1" step="0.1">
+                            </div>
+                            <div class="form-group">
+                                <label>Income (per £)</label>
+                                <input type="number" id="score-income" value="0" step="0.1">
+                            </div>
+                        </div>
+                        <div class="form-row">
+                            <div class="form-group">
+                                <label>Final Balance (per £)</label>
+                                <input type="number" id="score-final-balance" value="1" step="0.1">
+                            </div>
+                            <div class="form-group">
+                                <label><abbr title="Individual Savings Account">ISA</abbr> Share (£ for 100%)</label>
+                                <input type="number" id="score-isa-share" value="0" step="1000">
+                            </div>
+                        </div>
+                        <div class="form-row">
+                            <div class="form-group">
+                                <label>Lowest Year Income (per £)</label>
+                                <input type="number" id="score-min-year-income" value="0" step="0.1">
+                            </div>
+                            <div class="form-group">
+                                <label>Shortfall Year (£ each)</label>
+                                <input type="number" id="score-shortfall-years" value="1000000000" step="1000">
+                            </div>
+                        </div>
+                        <div class="form-hint">Score = income, balance and ISA terms minus tax and shortfall terms, all in today's money. The highest score is best in every mode.</div>
                     </div>
                 </div>
                 <div class="form-group" style="margin-top:1rem;">
@@ -2949,6 +3298,7 @@ const webUIHTML = `<!DOCTYPE html>
                         <option value="standard" selected>Standard (~100 strategies)</option>
                         <option value="thorough">Thorough (~600 strategies)</option>
                         <option value="comprehensive">Comprehensive (~1000 strategies)</option>
+                        <option value="adaptive">Adaptive (every combination and per-person choice, runs a fraction)</option>
                     </select>
                     <div class="form-hint">More strategies = more analysis time but better coverage</div>
                 </div>
@@ -3361,7 +3711,7 @@ const webUIHTML = `<!DOCTYPE html>
                                 <input type="checkbox" id="vpw-enabled">
                                 VPW (Variable Percentage)
                             </label>
-                            <div class="form-hint">Rate increases with age: 3% at 55 → 35% at 100</div>
+                            <div class="form-hint">Rate increases with age: 3.9% at 55 → 17.9% at 95</div>
                             <div id="vpw-options" class="hidden" style="margin-top: 0.5rem;">
                                 <div class="form-row">
                                     <div class="form-group" style="margin-bottom: 0;">
@@ -3738,6 +4088,9 @@ const webUIHTML = `<!DOCTYPE html>
                 <div class="loading" id="loading">
                     <div class="spinner"></div>
                     <p>Running simulation...</p>
+                    <div class="job-progress"><div class="job-progress-fill" id="job-progress-fill"></div></div>
+                    <div class="job-progress-text" id="job-progress-text"></div>
+                    <button class="btn btn-secondary" id="cancel-btn" onclick="cancelJob()">Cancel</button>
                 </div>
                 <div id="results-content">
                     <p style="color: var(--text-muted); text-align: center; padding: 2rem;">
@@ -4001,9 +4354,11 @@ const webUIHTML = `<!DOCTYPE html>
                 const bestName = data.best.descriptive_name || data.best.short_name;
                 const isDepletion = ['depletion', 'pension-only', 'pension-to-isa'].includes(currentMode);
                 if (isDepletion) {
-                    const goalNames = { 'tax': 'Min Tax', 'income': 'Max Income', 'balance': 'Max Balance' };
+                    const goalNames = { 'tax': 'Min Tax', 'income': 'Max Income', 'balance': 'Max Balance', 'custom': 'Custom Weights' };
                     const goal = document.getElementById('optimization-goal').value;
                     document.getElementById('summary-best').textContent = bestName + ' (' + (goalNames[goal] || goal) + ')';
+                } else if (document.getElementById('optimization-goal').value === 'custom') {
+                    document.getElementById('summary-best').textContent = bestName + ' (Custom Weights)';
                 } else {
                     // Fixed mode: always ranked by balance
                     document.getElementById('summary-best').textContent = bestName + ' (Max Balance)';
@@ -4024,6 +4379,27 @@ const webUIHTML = `<!DOCTYPE html>
             });
         });
 
+        // Show the scoring weight inputs when the custom goal is chosen
+        function updateCustomWeights() {
+            const goal = document.getElementById('optimization-goal');
+            document.getElementById('custom-weights').style.display = goal.value === 'custom' ? 'block' : 'none';
+            updateModeFields();
+        }
+
+        // Scoring weights from the custom weight inputs (null unless the custom goal is chosen)
+        function getScoringWeights() {
+            if (document.getElementById('optimization-goal').value !== 'custom') return null;
+            const num = id => parseFloat(document.getElementById(id).value) || 0;
+            return {
+                tax: num('score-tax'),
+                income: num('score-income'),
+                final_balance: num('score-final-balance'),
+                isa_share: num('score-isa-share'),
+                min_year_income: num('score-min-year-income'),
+                shortfall_years: num('score-shortfall-years')
+            };
+        }
+
         function updateModeFields() {
             const isDepletion = ['depletion', 'pension-only', 'pension-to-isa'].includes(currentMode);
             document.getElementById('fixed-income-fields').classList.toggle('hidden', isDepletion);
@@ -4034,17 +4410,10 @@ const webUIHTML = `<!DOCTYPE html>
             const optimizationGoal = document.getElementById('optimization-goal');
             const fixedModeHint = document.getElementById('fixed-mode-hint');
             const depletionHint = document.querySelector('.depletion-only-hint');
-            if (isDepletion) {
-                optimizationGoal.disabled = false;
-                optimizationGoal.style.opacity = '1';
-                if (fixedModeHint) fixedModeHint.style.display = 'none';
-                if (depletionHint) depletionHint.style.display = 'none';
-            } else {
-                optimizationGoal.disabled = true;
-                optimizationGoal.style.opacity = '0.5';
-                if (fixedModeHint) fixedModeHint.style.display = 'block';
-                if (depletionHint) depletionHint.style.display = 'inline';
-            }
+            // Custom weights rank every mode, so the goal stays selectable
+            if (fixedModeHint) fixedModeHint.style.display = isDepletion ? 'none' : 'block';
+            if (depletionHint) depletionHint.style.display = isDepletion ? 'none' : 'inline';
+            optimizationGoal.style.opacity = isDepletion || optimizationGoal.value === 'custom' ? '1' : '0.5';
 
             // Auto-expand Income Requirements section when switching to depletion mode
             if (isDepletion) {
@@ -4487,7 +4856,8 @@ const webUIHTML = `<!DOCTYPE html>
                     reference_person: document.getElementById('sim-ref-person').value
                 },
                 strategy: {
-                    maximize_couple_isa: document.getElementById('maximize-couple-isa').checked
+                    maximize_couple_isa: document.getElementById('maximize-couple-isa').checked,
+                    scoring: getScoringWeights()
                 },
                 tax: {
                     personal_allowance: parseMoney(document.getElementById('tax-personal-allowance').value),
@@ -4512,7 +4882,188 @@ const webUIHTML = `<!DOCTYPE html>
             return bands;
         }
 
+        // Background job being followed (null when idle)
+        let currentJobId = null;
+
+        // Start a background job and follow its progress until it finishes
+        // Resolves with the job's result, or an error response if it was cancelled or failed
+        async function runJob(url, req) {
+            showJobProgress({ done: 0, total: 0 });
+            const res = await fetch(url, {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify(req)
+            });
+            const start = await res.json();
+            if (!start.success) {
+                return start;
+            }
+
+            currentJobId = start.job_id;
+            showJobProgress(start);
+            return new Promise((resolve, reject) => {
+                const events = new EventSource('/api/jobs/' + start.job_id + '/events');
+                const finish = (data) => {
+                    events.close();
+                    currentJobId = null;
+                    resolve(data);
+                };
+                events.addEventListener('progress', e => showJobProgress(JSON.parse(e.data)));
+                events.addEventListener('done', e => finish(JSON.parse(e.data).result));
+                events.addEventListener('cancelled', () => finish({ success: false, error: 'Simulation cancelled' }));
+                events.addEventListener('failed', e => finish(JSON.parse(e.data)));
+                events.onerror = () => {
+                    // EventSource reconnects by itself unless the connection is closed for good
+                    if (events.readyState === EventSource.CLOSED) {
+                        currentJobId = null;
+                        reject(new Error('Lost connection to the simulation'));
+                    }
+                };
+            });
+        }
+
+        // Show a job's progress: strategies done, current best and time remaining
+        function showJobProgress(status) {
+            const pct = status.total > 0 ? Math.min(100, status.done / status.total * 100) : 0;
+            document.getElementById('job-progress-fill').style.width = pct.toFixed(1) + '%';
+            let text = status.total > 0 ? status.done + ' of ' + status.total + ' strategies' : 'Starting...';
+            if (status.best) {
+                text += ' · best so far: ' + status.best;
+            }
+            if (status.eta_seconds > 0) {
+                text += ' · about ' + formatDuration(status.eta_seconds) + ' left';
+            }
+            document.getElementById('job-progress-text').textContent = text;
+        }
+
+        // Format seconds as "45s" or "3m 20s"
+        function formatDuration(seconds) {
+            seconds = Math.ceil(seconds);
+            return seconds < 60 ? seconds + 's' : Math.floor(seconds / 60) + 'm ' + (seconds % 60) + 's';
+        }
+
+        // Stop the running job
+        function cancelJob() {
+            if (currentJobId) {
+                fetch('/api/jobs/' + currentJobId + '/cancel', { method: 'POST' });
+            }
+        }
+
         // Run simulation function (called on mode change and button click)
+        // Form fields for each person's settings, by config key (ids are p1-<field> and p2-<field>)
+        const personFieldIds = {
+            name: 'name', birth_date: 'birth', retirement_date: 'retire', pension_access_age: 'pension-age',
+            state_pension_age: 'spa', pension: 'pension', tax_free_savings: 'isa', db_pension_name: 'db-name',
+            db_pension_amount: 'db-amount', db_pension_start_age: 'db-age', isa_annual_limit: 'isa-limit',
+            work_income_net: 'work-income-net', state_pension_defer_years: 'sp-defer', db_pension_normal_age: 'db-normal-age',
+            db_pension_early_factor: 'db-early-factor', db_pension_late_factor: 'db-late-factor',
+            db_pension_commutation: 'db-commute', db_pension_commute_factor: 'db-commute-factor',
+            part_time_income: 'parttime-income', part_time_start_age: 'parttime-start', part_time_end_age: 'parttime-end'
+        };
+
+        // Form fields for the other settings, by config path
+        const configFieldIds = {
+            'financial.pension_growth_rate': 'pension-growth', 'financial.savings_growth_rate': 'savings-growth',
+            'financial.income_inflation_rate': 'income-inflation', 'financial.state_pension_amount': 'state-pension',
+            'financial.state_pension_inflation': 'sp-inflation', 'financial.tax_band_inflation': 'tax-band-inflation',
+            'financial.emergency_fund_months': 'emergency-months', 'financial.pension_growth_end_rate': 'pension-growth-end',
+            'financial.savings_growth_end_rate': 'savings-growth-end', 'financial.growth_decline_target_age': 'growth-decline-target-age',
+            'financial.growth_decline_reference_person': 'growth-decline-ref-person',
+            'financial.depletion_growth_decline_percent': 'depletion-growth-decline-percent',
+            'income_requirements.target_depletion_age': 'depletion-age', 'income_requirements.income_ratio_phase1': 'ratio-phase1',
+            'income_requirements.income_ratio_phase2': 'ratio-phase2', 'income_requirements.age_threshold': 'age-threshold',
+            'income_requirements.reference_person': 'ref-person', 'income_requirements.guardrails_upper_limit': 'guardrails-upper',
+            'income_requirements.guardrails_lower_limit': 'guardrails-lower', 'income_requirements.guardrails_adjustment': 'guardrails-adjust',
+            'income_requirements.vpw_floor': 'vpw-floor', 'income_requirements.vpw_ceiling': 'vpw-ceiling',
+            'mortgage.early_payoff_year': 'mortgage-early', 'simulation.start_year': 'sim-start', 'simulation.end_age': 'sim-end',
+            'simulation.reference_person': 'sim-ref-person', 'tax.personal_allowance': 'tax-personal-allowance',
+            'tax.tapering_threshold': 'tax-tapering-threshold', 'tax.tapering_rate': 'tax-tapering-rate'
+        };
+
+        // Find the form field for a config path such as people[0].birth_date, or null if it has none
+        function fieldForPath(path) {
+            let m = path.match(/^people\[(\d+)\]\.(\w+)$/);
+            if (m) {
+                return personFieldIds[m[2]] ? document.getElementById('p' + (parseInt(m[1]) + 1) + '-' + personFieldIds[m[2]]) : null;
+            }
+            m = path.match(/^income_requirements\.tiers\[(\d+)\](?:\.(\w+))?$/);
+            if (m) {
+                const row = document.querySelectorAll('.income-tier-row')[parseInt(m[1])];
+                const cls = { end_age: '.tier-end-age', monthly_amount: '.tier-amount' }[m[2]] || '.tier-start-age';
+                return row ? row.querySelector(cls) : null;
+            }
+            m = path.match(/^mortgage\.parts\[(\d+)\]\.(\w+)$/);
+            if (m) {
+                const part = document.getElementById('mortgage-parts-container').children[parseInt(m[1])];
+                const field = { principal: 'principal', interest_rate: 'rate', start_year: 'start', term_years: 'term' }[m[2]] || 'name';
+                return part ? document.getElementById('mortgage-' + field + '-' + part.id.replace('mortgage-part-', '')) : null;
+            }
+            m = path.match(/^tax_bands\[(\d+)\]\.(\w+)$/);
+            if (m) {
+                const row = document.querySelectorAll('.tax-band-row')[parseInt(m[1])];
+                return row ? row.querySelector('.tax-band-' + m[2]) : null;
+            }
+            return configFieldIds[path] ? document.getElementById(configFieldIds[path]) : null;
+        }
+
+        // Mark the fields with errors, returning the errors that have no field on the form
+        function showValidationErrors(errors) {
+            document.querySelectorAll('.field-error').forEach(el => el.classList.remove('field-error'));
+            document.querySelectorAll('.field-error-msg').forEach(el => el.remove());
+            const unmatched = [];
+            errors.forEach(e => {
+                const field = fieldForPath(e.path);
+                if (!field) {
+                    unmatched.push(e);
+                    return;
+                }
+                field.classList.add('field-error');
+                const msg = document.createElement('div');
+                msg.className = 'field-error-msg';
+                msg.textContent = e.message;
+                field.insertAdjacentElement('afterend', msg);
+            });
+            return unmatched;
+        }
+
+        // Check the form's settings on the server and mark any mistakes
+        // Resolves with the errors found (empty when the settings are valid)
+        async function validateRequest(req) {
+            const response = await fetch('/api/validate', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify(req)
+            });
+            const data = await response.json();
+            const errors = data.errors || [];
+            showValidationErrors(errors);
+            return errors;
+        }
+
+        // Show the problems found before a run in the results area
+        function renderValidationErrors(content, errors) {
+            const unmatched = showValidationErrors(errors);
+            content.innerHTML = '<p style="color: var(--danger);">Please fix the settings marked in red before running.</p>';
+            if (unmatched.length > 0) {
+                const list = document.createElement('ul');
+                list.style.color = 'var(--danger)';
+                unmatched.forEach(e => {
+                    const item = document.createElement('li');
+                    item.textContent = e.path + ': ' + e.message;
+                    list.appendChild(item);
+                });
+                content.appendChild(list);
+            }
+        }
+
+        // Re-check the settings shortly after each change, once a run has found mistakes
+        let validateTimer = null;
+        document.getElementById('config-panel').addEventListener('change', () => {
+            if (!document.querySelector('.field-error-msg')) return;
+            clearTimeout(validateTimer);
+            validateTimer = setTimeout(() => validateRequest(buildRequest()).catch(() => {}), 400);
+        });
+
         async function runSimulation() {
             const loading = document.getElementById('loading');
             const content = document.getElementById('results-content');
@@ -4524,13 +5075,14 @@ const webUIHTML = `<!DOCTYPE html>
 
             try {
                 const req = buildRequest();
-                const res = await fetch('/api/simulate', {
-                    method: 'POST',
-                    headers: { 'Content-Type': 'application/json' },
-                    body: JSON.stringify(req)
-                });
-
-                const data = await res.json();
+                const errors = await validateRequest(req);
+                if (errors.length > 0) {
+                    loading.classList.remove('show');
+                    btn.disabled = false;
+                    renderValidationErrors(content, errors);
+                    return;
+                }
+                const data = await runJob('/api/jobs/simulate', req);
                 loading.classList.remove('show');
                 btn.disabled = false;
 
@@ -4575,13 +5127,7 @@ const webUIHTML = `<!DOCTYPE html>
                 req.savings_growth_max = parseFloat(document.getElementById('savings-growth-max').value) / 100;
                 req.step_size = 0.01; // 1% steps as per plan
 
-                const res = await fetch('/api/simulate/sensitivity', {
-                    method: 'POST',
-                    headers: { 'Content-Type': 'application/json' },
-                    body: JSON.stringify(req)
-                });
-
-                const data = await res.json();
+                const data = await runJob('/api/jobs/sensitivity', req);
                 loading.classList.remove('show');
                 btn.disabled = false;
 
@@ -4778,10 +5324,14 @@ const webUIHTML = `<!DOCTYPE html>
         // Scroll to and expand the best strategy
         function scrollToBestStrategy() {
             if (!lastResults || !lastResults.best) return;
-            const best = lastResults.best;
-            // Find the accordion item matching the best strategy_idx
+            scrollToStrategy(lastResults.best.strategy_idx);
+        }
+
+        // Expand and scroll to a strategy by its original index (if it is in the results shown)
+        function scrollToStrategy(strategyIdx) {
+            if (!lastResults || !lastResults.results) return;
             const sortedResults = sortStrategies(lastResults.results, strategySortField, strategySortDir);
-            const bestIdx = sortedResults.findIndex(r => r.strategy_idx === best.strategy_idx);
+            const bestIdx = sortedResults.findIndex(r => r.strategy_idx === strategyIdx);
             if (bestIdx >= 0) {
                 // Expand the accordion
                 toggleAccordion(bestIdx);
@@ -4808,7 +5358,7 @@ const webUIHTML = `<!DOCTYPE html>
             if (isDepletion && best) {
                 const depletionAge = document.getElementById('depletion-age').value;
                 html += '<div class="metric"><div class="metric-value">' + depletionAge + '</div><div class="metric-label">Target Depletion Age</div></div>';
-                html += '<div class="metric success"><div class="metric-value">' + formatMoney(best.monthly_income) + '/mo</div><div class="metric-label">Sustainable Income</div></div>';
+                html += '<div class="metric ' + (best.minimums_unmet ? 'danger' : 'success') + '"><div class="metric-value">' + formatMoney(best.monthly_income) + '/mo</div><div class="metric-label">' + (best.minimums_unmet ? 'Tier Minimums Unmet' : 'Sustainable Income') + '</div></div>';
             }
 
             if (best) {
@@ -4843,6 +5393,14 @@ const webUIHTML = `<!DOCTYPE html>
                 html += '</div></div>';
             }
 
+            // Adaptive search coverage
+            if (data.search) {
+                html += '<div style="font-size:0.75rem;color:var(--text-muted);margin-bottom:0.75rem;">🔎 Adaptive search ran ' + data.search.evaluated + ' of ' + data.search.space_size + ' combinations</div>';
+            }
+
+            // Trade-offs between strategies no other strategy beats on every objective
+            html += renderFrontier(data.frontier, isDepletion);
+
             // Strategy accordion
             html += '<div style="display:flex;align-items:center;margin:1rem 0 0.5rem;"><h3 style="margin:0;">All Strategies</h3><span style="font-weight:normal;font-size:0.75rem;color:var(--text-muted);margin-left:0.5rem;">(click to expand, click headers to sort)</span><button class="compare-btn" onclick="showCompareModal()">Compare Early vs Normal vs Extended</button></div>';
 
@@ -4890,7 +5448,9 @@ const webUIHTML = `<!DOCTYPE html>
                     // In depletion mode, hitting the target age is SUCCESS
                     const depletionAge = parseInt(document.getElementById('depletion-age').value);
                     const targetYear = p1BirthYear + depletionAge;
-                    if (!r.ran_out_of_money) {
+                    if (r.minimums_unmet) {
+                        html += '<span class="badge badge-danger">Minimums unmet</span>';
+                    } else if (!r.ran_out_of_money) {
                         html += '<span class="badge badge-info">Surplus</span>';
                     } else if (Math.abs(r.ran_out_year - targetYear) <= 1) {
                         html += '<span class="badge badge-success">' + displayName + '</span>';
@@ -4946,6 +5506,53 @@ const webUIHTML = `<!DOCTYPE html>
             content.innerHTML = html;
         }
 
+        // Render the Pareto frontier: a tax vs final balance chart and a table of trade-offs
+        function renderFrontier(frontier, isDepletion) {
+            if (!frontier || frontier.length === 0) return '';
+            const shown = new Set((lastResults.results || []).map(r => r.strategy_idx));
+
+            let html = '<div class="frontier">';
+            html += '<div style="display:flex;align-items:baseline;gap:0.5rem;margin-bottom:0.5rem;"><h3 style="margin:0;">Trade-offs</h3>';
+            html += '<span style="font-size:0.75rem;color:var(--text-muted);">' + frontier.length + ' strategies where no other strategy is better on tax, income, final balance and shortfall together</span></div>';
+
+            // Scatter chart: lower tax to the left, higher final balance to the top
+            const w = 420, h = 180, pad = 30;
+            const taxes = frontier.map(p => p.total_tax_paid);
+            const finals = frontier.map(p => p.final_balance);
+            const minTax = Math.min(...taxes), maxTax = Math.max(...taxes);
+            const minFinal = Math.min(...finals), maxFinal = Math.max(...finals);
+            const x = v => pad + (maxTax > minTax ? (v - minTax) / (maxTax - minTax) : 0.5) * (w - 2 * pad);
+            const y = v => h - pad - (maxFinal > minFinal ? (v - minFinal) / (maxFinal - minFinal) : 0.5) * (h - 2 * pad);
+            html += '<svg class="frontier-chart" viewBox="0 0 ' + w + ' ' + h + '">';
+            html += '<line x1="' + pad + '" y1="' + (h - pad) + '" x2="' + (w - pad) + '" y2="' + (h - pad) + '" />';
+            html += '<line x1="' + pad + '" y1="' + pad + '" x2="' + pad + '" y2="' + (h - pad) + '" />';
+            html += '<text x="' + (w / 2) + '" y="' + (h - 8) + '" text-anchor="middle">Total tax →</text>';
+            html += '<text x="10" y="' + (h / 2) + '" text-anchor="middle" transform="rotate(-90 10 ' + (h / 2) + ')">Final balance →</text>';
+            frontier.forEach(p => {
+                const cls = p.shortfall_year ? 'shortfall' : '';
+                html += '<circle class="' + cls + '" cx="' + x(p.total_tax_paid).toFixed(1) + '" cy="' + y(p.final_balance).toFixed(1) + '" r="5" onclick="scrollToStrategy(' + p.strategy_idx + ')">';
+                html += '<title>' + (p.descriptive_name || p.short_name) + ': tax ' + formatMoney(p.total_tax_paid) + ', final ' + formatMoney(p.final_balance) + '</title></circle>';
+            });
+            html += '</svg>';
+
+            // Table of frontier strategies
+            html += '<table class="frontier-table"><tr><th>Strategy</th><th>Tax</th><th>Income (real)</th>';
+            if (isDepletion) html += '<th>Monthly</th>';
+            html += '<th>Final</th><th>Shortfall</th></tr>';
+            frontier.forEach(p => {
+                const clickable = shown.has(p.strategy_idx);
+                html += '<tr' + (clickable ? ' style="cursor:pointer;" onclick="scrollToStrategy(' + p.strategy_idx + ')"' : '') + '>';
+                html += '<td>' + (p.descriptive_name || p.short_name) + '</td>';
+                html += '<td>' + formatMoney(p.total_tax_paid) + '</td>';
+                html += '<td>' + formatMoney(p.total_income) + '</td>';
+                if (isDepletion) html += '<td>' + formatMoney(p.monthly_income) + '</td>';
+                html += '<td>' + formatMoney(p.final_balance) + '</td>';
+                html += '<td>' + (p.shortfall_year || 'Never') + '</td></tr>';
+            });
+            html += '</table></div>';
+            return html;
+        }
+
         // Toggle accordion
         function toggleAccordion(idx) {
             const header = document.getElementById('accordion-header-' + idx);
@@ -5000,7 +5607,7 @@ const webUIHTML = `<!DOCTYPE html>
                     Object.keys(y.balances).forEach(name => {
                         const bal = y.balances[name];
                         const pensionTotal = (bal.uncrystallised_pot || 0) + (bal.crystallised_pot || 0);
-                        const isaTotal = bal.isa || 0;
+                        const isaTotal = (bal.isa || 0) + (bal.gia || 0);
                         const prev = prevBalances[name] || { pension: -1, isa: -1 };
 
                         // Detect pension depletion for this person
@@ -5107,6 +5714,7 @@ const webUIHTML = `<!DOCTYPE html>
                     Object.keys(y.balances).forEach(name => {
                         const bal = y.balances[name];
                         html += '<div class="year-detail-item"><span>' + name + ' ISA</span><span>' + formatMoney(bal.isa) + '</span></div>';
+                        if (bal.gia > 0) html += '<div class="year-detail-item"><span>' + name + ' GIA</span><span>' + formatMoney(bal.gia) + '</span></div>';
                         html += '<div class="year-detail-item"><span>' + name + ' Pension</span><span>' + formatMoney(bal.uncrystallised_pot + bal.crystallised_pot) + '</span></div>';
                     });
                     html += '</div>';
@@ -5179,7 +5787,7 @@ const webUIHTML = `<!DOCTYPE html>
                     let isaTotal = 0;
                     Object.values(y.balances).forEach(bal => {
                         pensionTotal += (bal.uncrystallised_pot || 0) + (bal.crystallised_pot || 0);
-                        isaTotal += bal.isa || 0;
+                        isaTotal += (bal.isa || 0) + (bal.gia || 0);
                     });
                     if (prevPensionTotal > 0 && pensionTotal <= 0 && !pensionDepletedYear) {
                         pensionDepletedYear = y.year;
@@ -5271,6 +5879,7 @@ const webUIHTML = `<!DOCTYPE html>
                     Object.keys(y.balances).forEach(name => {
                         const bal = y.balances[name];
                         html += '<div class="year-detail-item"><span>' + name + ' ISA</span><span>' + formatMoney(bal.isa) + '</span></div>';
+                        if (bal.gia > 0) html += '<div class="year-detail-item"><span>' + name + ' GIA</span><span>' + formatMoney(bal.gia) + '</span></div>';
                         html += '<div class="year-detail-item"><span>' + name + ' Pension</span><span>' + formatMoney(bal.uncrystallised_pot + bal.crystallised_pot) + '</span></div>';
                     });
                     html += '</div>';
@@ -5431,6 +6040,18 @@ const webUIHTML = `<!DOCTYPE html>
                 if (config.strategy) {
                     // Default to true if not specified
                     document.getElementById('maximize-couple-isa').checked = config.strategy.maximize_couple_isa !== false;
+                    // Configured scoring weights select the custom goal
+                    const sc = config.strategy.scoring;
+                    if (sc && Object.values(sc).some(v => v)) {
+                        document.getElementById('optimization-goal').value = 'custom';
+                        document.getElementById('score-tax').value = sc.tax || 0;
+                        document.getElementById('score-income').value = sc.income || 0;
+                        document.getElementById('score-final-balance').value = sc.final_balance || 0;
+                        document.getElementById('score-isa-share').value = sc.isa_share || 0;
+                        document.getElementById('score-min-year-income').value = sc.min_year_income || 0;
+                        document.getElementById('score-shortfall-years').value = sc.shortfall_years || 1000000000;
+                        document.getElementById('custom-weights').style.display = 'block';
+                    }
                 }
 
                 // Load tax settings
@@ -5849,8 +6470,13 @@ const webUIHTML = `<!DOCTYPE html>
 
             try {
             // Reuse the existing buildRequest function and add strategy_idx
+            // The result key lets the server export exactly the displayed result
             const requestBody = buildRequest();
             requestBody.strategy_idx = strategyIdx;
+            const shown = lastResults && (lastResults.results || []).find(r => r.strategy_idx === strategyIdx);
+            if (shown && shown.result_key) {
+                requestBody.result_key = shown.result_key;
+            }
 
             console.log('Sending PDF request:', JSON.stringify(requestBody).substring(0, 200) + '...');
 